@@ -32,6 +32,17 @@ type Admin interface {
 	// Chain provide handlers chain for gin
 	Chain() gin.HandlersChain
 
+	ChangeTopicStatus(*web.ChangeTopicStatusReq) error
+	MergeTopic(*web.MergeTopicReq) error
+	ArbitrateAttachmentRefund(*web.ReviewAttachmentRefundReq) error
+	ListAttachmentRefunds(*web.ListAttachmentRefundsReq) (*web.ListAttachmentRefundsResp, error)
+	WalletReconcile(*web.WalletReconcileReq) (*web.WalletReconcileResp, error)
+	PayoutWithdrawal(*web.PayoutWithdrawalReq) error
+	ReviewWithdrawal(*web.ReviewWithdrawalReq) error
+	ListWithdrawals(*web.ListWithdrawalsReq) (*web.ListWithdrawalsResp, error)
+	ResetUserStorageQuota(*web.ResetUserStorageQuotaReq) error
+	ChangeUserStorageQuota(*web.ChangeUserStorageQuotaReq) error
+	UserStorageQuota(*web.UserStorageQuotaReq) (*web.UserStorageQuotaResp, error)
 	SiteInfo(*web.SiteInfoReq) (*web.SiteInfoResp, error)
 	ChangeUserStatus(*web.ChangeUserStatusReq) error
 
@@ -46,6 +57,153 @@ func RegisterAdminServant(e *gin.Engine, s Admin) {
 	router.Use(middlewares...)
 
 	// register routes info to router
+	router.Handle("POST", "admin/topic/status", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ChangeTopicStatusReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ChangeTopicStatus(req))
+	})
+	router.Handle("POST", "admin/topic/merge", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.MergeTopicReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.MergeTopic(req))
+	})
+	router.Handle("POST", "/admin/attachment/refund/review", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ReviewAttachmentRefundReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ArbitrateAttachmentRefund(req))
+	})
+	router.Handle("GET", "/admin/attachment/refunds", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ListAttachmentRefundsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.ListAttachmentRefunds(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("GET", "admin/wallet/reconcile", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.WalletReconcileReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.WalletReconcile(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "admin/wallet/withdrawal/payout", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.PayoutWithdrawalReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.PayoutWithdrawal(req))
+	})
+	router.Handle("POST", "admin/wallet/withdrawal/review", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ReviewWithdrawalReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ReviewWithdrawal(req))
+	})
+	router.Handle("GET", "admin/wallet/withdrawals", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ListWithdrawalsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.ListWithdrawals(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("DELETE", "admin/user/quota", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ResetUserStorageQuotaReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ResetUserStorageQuota(req))
+	})
+	router.Handle("POST", "admin/user/quota", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ChangeUserStorageQuotaReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ChangeUserStorageQuota(req))
+	})
+	router.Handle("GET", "admin/user/quota", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UserStorageQuotaReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UserStorageQuota(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("GET", "admin/site/status", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -82,6 +240,50 @@ func (UnimplementedAdminServant) Chain() gin.HandlersChain {
 	return nil
 }
 
+func (UnimplementedAdminServant) ChangeTopicStatus(req *web.ChangeTopicStatusReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) MergeTopic(req *web.MergeTopicReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ArbitrateAttachmentRefund(req *web.ReviewAttachmentRefundReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ListAttachmentRefunds(req *web.ListAttachmentRefundsReq) (*web.ListAttachmentRefundsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) WalletReconcile(req *web.WalletReconcileReq) (*web.WalletReconcileResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) PayoutWithdrawal(req *web.PayoutWithdrawalReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ReviewWithdrawal(req *web.ReviewWithdrawalReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ListWithdrawals(req *web.ListWithdrawalsReq) (*web.ListWithdrawalsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ResetUserStorageQuota(req *web.ResetUserStorageQuotaReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) ChangeUserStorageQuota(req *web.ChangeUserStorageQuotaReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedAdminServant) UserStorageQuota(req *web.UserStorageQuotaReq) (*web.UserStorageQuotaResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedAdminServant) SiteInfo(req *web.SiteInfoReq) (*web.SiteInfoResp, error) {
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
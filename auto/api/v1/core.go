@@ -18,10 +18,18 @@ type Core interface {
 	// Chain provide handlers chain for gin
 	Chain() gin.HandlersChain
 
+	UpdateCollectionNote(*web.CollectionNoteReq) error
+	MoveCollections(*web.MoveCollectionsReq) error
+	ShareCollectionFolder(*web.ShareCollectionFolderReq) (*web.ShareCollectionFolderResp, error)
+	DeleteCollectionFolder(*web.DeleteCollectionFolderReq) error
+	UpdateCollectionFolder(*web.UpdateCollectionFolderReq) error
+	CreateCollectionFolder(*web.CreateCollectionFolderReq) (*web.CreateCollectionFolderResp, error)
+	ListCollectionFolders(*web.ListCollectionFoldersReq) (*web.ListCollectionFoldersResp, error)
 	TweetCollectionStatus(*web.TweetCollectionStatusReq) (*web.TweetCollectionStatusResp, error)
 	TweetStarStatus(*web.TweetStarStatusReq) (*web.TweetStarStatusResp, error)
 	SuggestTags(*web.SuggestTagsReq) (*web.SuggestTagsResp, error)
 	SuggestUsers(*web.SuggestUsersReq) (*web.SuggestUsersResp, error)
+	GetStorageUsage(*web.GetStorageUsageReq) (*web.StorageUsageResp, error)
 	ChangeAvatar(*web.ChangeAvatarReq) error
 	ChangeNickname(*web.ChangeNicknameReq) error
 	ChangePassword(*web.ChangePasswordReq) error
@@ -46,6 +54,100 @@ func RegisterCoreServant(e *gin.Engine, s Core) {
 	router.Use(middlewares...)
 
 	// register routes info to router
+	router.Handle("POST", "user/collection/note", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CollectionNoteReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.UpdateCollectionNote(req))
+	})
+	router.Handle("POST", "user/collection/move", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.MoveCollectionsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.MoveCollections(req))
+	})
+	router.Handle("POST", "user/collection/folder/share", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ShareCollectionFolderReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.ShareCollectionFolder(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("DELETE", "user/collection/folder", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.DeleteCollectionFolderReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.DeleteCollectionFolder(req))
+	})
+	router.Handle("POST", "user/collection/folder/update", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UpdateCollectionFolderReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.UpdateCollectionFolder(req))
+	})
+	router.Handle("POST", "user/collection/folder", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CreateCollectionFolderReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.CreateCollectionFolder(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("GET", "user/collection/folders", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ListCollectionFoldersReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.ListCollectionFolders(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("GET", "post/collection", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -106,6 +208,20 @@ func RegisterCoreServant(e *gin.Engine, s Core) {
 		resp, err := s.SuggestUsers(req)
 		s.Render(c, resp, err)
 	})
+	router.Handle("GET", "user/storage", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.GetStorageUsageReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.GetStorageUsage(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("POST", "user/avatar", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -283,6 +399,34 @@ func (UnimplementedCoreServant) Chain() gin.HandlersChain {
 	return nil
 }
 
+func (UnimplementedCoreServant) UpdateCollectionNote(req *web.CollectionNoteReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) MoveCollections(req *web.MoveCollectionsReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) ShareCollectionFolder(req *web.ShareCollectionFolderReq) (*web.ShareCollectionFolderResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) DeleteCollectionFolder(req *web.DeleteCollectionFolderReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) UpdateCollectionFolder(req *web.UpdateCollectionFolderReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) CreateCollectionFolder(req *web.CreateCollectionFolderReq) (*web.CreateCollectionFolderResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedCoreServant) ListCollectionFolders(req *web.ListCollectionFoldersReq) (*web.ListCollectionFoldersResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedCoreServant) TweetCollectionStatus(req *web.TweetCollectionStatusReq) (*web.TweetCollectionStatusResp, error) {
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
@@ -299,6 +443,10 @@ func (UnimplementedCoreServant) SuggestUsers(req *web.SuggestUsersReq) (*web.Sug
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
 
+func (UnimplementedCoreServant) GetStorageUsage(req *web.GetStorageUsageReq) (*web.StorageUsageResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedCoreServant) ChangeAvatar(req *web.ChangeAvatarReq) error {
 	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
@@ -22,6 +22,30 @@ type Loose interface {
 	// 返回用于此服务的中间件处理链
 	Chain() gin.HandlersChain
 
+	// SharedCollections 查看分享的收藏夹
+	// 通过分享链接获取收藏夹信息及其中的公开推文，支持分页
+	SharedCollections(*web.SharedCollectionsReq) (*web.SharedCollectionsResp, error)
+
+	// TopicTweets 获取话题下的推文
+	// 获取引用了指定话题或其别名的公开推文，支持分页
+	TopicTweets(*web.TopicTweetsReq) (*web.TopicTweetsResp, error)
+
+	// TopicDetail 获取话题主页
+	// 根据话题ID或名称获取话题描述、主持人及置顶推文
+	TopicDetail(*web.TopicDetailReq) (*web.TopicDetailResp, error)
+
+	// TweetTips 获取动态的充电记录
+	// 获取为指定动态充电的用户及其留言，支持分页
+	TweetTips(*web.TweetTipsReq) (*web.TweetTipsResp, error)
+
+	// TweetPollVotes 获取动态投票的投票人
+	// 获取指定动态中公开投票的投票人及其选项，支持分页
+	TweetPollVotes(*web.TweetPollVotesReq) (*web.TweetPollVotesResp, error)
+
+	// TweetRevisions 获取动态修订历史
+	// 获取指定动态每次编辑前的内容，支持分页
+	TweetRevisions(*web.TweetRevisionsReq) (*web.TweetRevisionsResp, error)
+
 	// TweetDetail 获取动态详情
 	// 根据动态ID获取单条动态的详细信息
 	TweetDetail(*web.TweetDetailReq) (*web.TweetDetailResp, error)
@@ -60,6 +84,102 @@ func RegisterLooseServant(e *gin.Engine, s Loose) {
 
 	// 注册路由信息到路由器
 
+	// GET /v1/collection/shared - 查看分享的收藏夹
+	router.Handle("GET", "collection/shared", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.SharedCollectionsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.SharedCollections(req)
+		s.Render(c, resp, err)
+	})
+
+	// GET /v1/topic/tweets - 获取话题下的推文
+	router.Handle("GET", "topic/tweets", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TopicTweetsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TopicTweets(req)
+		s.Render(c, resp, err)
+	})
+
+	// GET /v1/topic - 获取话题主页
+	router.Handle("GET", "topic", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TopicDetailReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TopicDetail(req)
+		s.Render(c, resp, err)
+	})
+
+	// GET /v1/post/tips - 获取动态的充电记录
+	router.Handle("GET", "post/tips", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TweetTipsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TweetTips(req)
+		s.Render(c, resp, err)
+	})
+
+	// GET /v1/post/poll/votes - 获取动态投票的投票人
+	router.Handle("GET", "post/poll/votes", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TweetPollVotesReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TweetPollVotes(req)
+		s.Render(c, resp, err)
+	})
+
+	// GET /v1/post/revisions - 获取动态修订历史
+	router.Handle("GET", "post/revisions", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TweetRevisionsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TweetRevisions(req)
+		s.Render(c, resp, err)
+	})
+
 	// GET /v1/post - 获取单条动态详情
 	router.Handle("GET", "post", func(c *gin.Context) {
 		select {
@@ -174,6 +294,7 @@ func RegisterLooseServant(e *gin.Engine, s Loose) {
 	//     * "newest" (默认): 全站最新动态，按发布时间倒序
 	//     * "hots": 全站热门动态，按热度排序(点赞、评论、分享综合)
 	//     * "following": 关注用户的动态流(需要登录)
+	//     * "recommend": 个性化推荐动态流，综合关注、话题、互动及热度排序(未登录时降级为hots)
 	//   - page: 页码(从1开始)
 	//   - page_size: 每页条数
 	// 【权限说明】：
@@ -220,6 +341,42 @@ func (UnimplementedLooseServant) Chain() gin.HandlersChain {
 	return nil
 }
 
+// SharedCollections 查看分享收藏夹的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) SharedCollections(req *web.SharedCollectionsReq) (*web.SharedCollectionsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+// TopicTweets 获取话题推文的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) TopicTweets(req *web.TopicTweetsReq) (*web.TopicTweetsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+// TopicDetail 获取话题主页的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) TopicDetail(req *web.TopicDetailReq) (*web.TopicDetailResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+// TweetTips 获取动态充电记录的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) TweetTips(req *web.TweetTipsReq) (*web.TweetTipsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+// TweetPollVotes 获取动态投票人的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) TweetPollVotes(req *web.TweetPollVotesReq) (*web.TweetPollVotesResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+// TweetRevisions 获取动态修订历史的未实现版本
+// 返回HTTP 501 Not Implemented错误
+func (UnimplementedLooseServant) TweetRevisions(req *web.TweetRevisionsReq) (*web.TweetRevisionsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 // TweetDetail 获取动态详情的未实现版本
 // 返回HTTP 501 Not Implemented错误
 func (UnimplementedLooseServant) TweetDetail(req *web.TweetDetailReq) (*web.TweetDetailResp, error) {
@@ -18,6 +18,13 @@ type Priv interface {
 	// Chain provide handlers chain for gin
 	Chain() gin.HandlersChain
 
+	CancelWithdrawal(*web.CancelWithdrawalReq) error
+	UserWithdrawals(*web.UserWithdrawalsReq) (*web.UserWithdrawalsResp, error)
+	CreateWithdrawal(*web.CreateWithdrawalReq) (*web.CreateWithdrawalResp, error)
+	RemoveTopicModerator(*web.TopicModeratorReq) error
+	AddTopicModerator(*web.TopicModeratorReq) error
+	PinTopicTweet(*web.PinTopicTweetReq) (*web.PinTopicTweetResp, error)
+	UpdateTopic(*web.UpdateTopicReq) error
 	UnfollowTopic(*web.UnfollowTopicReq) error
 	FollowTopic(*web.FollowTopicReq) error
 	PinTopic(*web.PinTopicReq) (*web.PinTopicResp, error)
@@ -37,16 +44,37 @@ type Priv interface {
 	LockTweet(*web.LockTweetReq) (*web.LockTweetResp, error)
 	CollectionTweet(*web.CollectionTweetReq) (*web.CollectionTweetResp, error)
 	StarTweet(*web.StarTweetReq) (*web.StarTweetResp, error)
+	TipTweet(*web.TipTweetReq) (*web.TipTweetResp, error)
+	VotePoll(*web.VotePollReq) (*web.VotePollResp, error)
+	PublishDraft(*web.PublishDraftReq) (*web.CreateTweetResp, error)
+	ListDrafts(*web.ListDraftsReq) (*web.ListDraftsResp, error)
+	DeleteDraft(*web.DeleteDraftReq) error
+	UpdateDraft(*web.UpdateDraftReq) (*web.UpdateDraftResp, error)
+	CreateDraft(*web.CreateDraftReq) (*web.CreateDraftResp, error)
+	CancelScheduledTweet(*web.CancelScheduledTweetReq) error
+	UpdateScheduledTweet(*web.UpdateScheduledTweetReq) (*web.UpdateScheduledTweetResp, error)
 	DeleteTweet(*web.DeleteTweetReq) error
+	EditTweet(*web.EditTweetReq) (*web.EditTweetResp, error)
 	CreateTweet(*web.CreateTweetReq) (*web.CreateTweetResp, error)
+	UserAttachmentRefunds(*web.UserAttachmentRefundsReq) (*web.UserAttachmentRefundsResp, error)
+	ReviewAttachmentRefund(*web.ReviewAttachmentRefundReq) error
+	DisputeAttachmentRefund(*web.DisputeAttachmentRefundReq) error
+	RequestAttachmentRefund(*web.RequestAttachmentRefundReq) (*web.RequestAttachmentRefundResp, error)
 	DownloadAttachment(*web.DownloadAttachmentReq) (*web.DownloadAttachmentResp, error)
 	DownloadAttachmentPrecheck(*web.DownloadAttachmentPrecheckReq) (*web.DownloadAttachmentPrecheckResp, error)
+	CancelUpload(*web.CancelUploadReq) error
+	CompleteUpload(*web.CompleteUploadReq) (*web.UploadAttachmentResp, error)
+	UploadProgress(*web.UploadProgressReq) (*web.UploadProgressResp, error)
+	UploadChunk(*web.UploadChunkReq) (*web.UploadChunkResp, error)
+	CreateUpload(*web.CreateUploadReq) (*web.CreateUploadResp, error)
 	UploadAttachment(*web.UploadAttachmentReq) (*web.UploadAttachmentResp, error)
 
 	mustEmbedUnimplementedPrivServant()
 }
 
 type PrivChain interface {
+	ChainPublishDraft() gin.HandlersChain
+	ChainEditTweet() gin.HandlersChain
 	ChainCreateTweet() gin.HandlersChain
 
 	mustEmbedUnimplementedPrivChain()
@@ -66,6 +94,100 @@ func RegisterPrivServant(e *gin.Engine, s Priv, m ...PrivChain) {
 	router.Use(middlewares...)
 
 	// register routes info to router
+	router.Handle("POST", "user/wallet/withdrawal/cancel", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CancelWithdrawalReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.CancelWithdrawal(req))
+	})
+	router.Handle("GET", "user/wallet/withdrawals", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UserWithdrawalsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UserWithdrawals(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "user/wallet/withdrawal", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CreateWithdrawalReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.CreateWithdrawal(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("DELETE", "topic/moderator", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TopicModeratorReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.RemoveTopicModerator(req))
+	})
+	router.Handle("POST", "topic/moderator", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TopicModeratorReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.AddTopicModerator(req))
+	})
+	router.Handle("POST", "topic/tweet/pin", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.PinTopicTweetReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.PinTopicTweet(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "topic/update", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UpdateTopicReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.UpdateTopic(req))
+	})
 	router.Handle("POST", "topic/unfollow", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -326,6 +448,137 @@ func RegisterPrivServant(e *gin.Engine, s Priv, m ...PrivChain) {
 		resp, err := s.StarTweet(req)
 		s.Render(c, resp, err)
 	})
+	router.Handle("POST", "post/tip", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.TipTweetReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.TipTweet(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "post/poll/vote", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.VotePollReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.VotePoll(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "draft/publish", append(cc.ChainPublishDraft(), func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.PublishDraftReq)
+		var bv _binding_ = req
+		if err := bv.Bind(c); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.PublishDraft(req)
+		if err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		var rv _render_ = resp
+		rv.Render(c)
+	})...)
+	router.Handle("GET", "drafts", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ListDraftsReq)
+		var bv _binding_ = req
+		if err := bv.Bind(c); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.ListDrafts(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("DELETE", "draft", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.DeleteDraftReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.DeleteDraft(req))
+	})
+	router.Handle("POST", "draft/update", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UpdateDraftReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UpdateDraft(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "draft", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CreateDraftReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.CreateDraft(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("DELETE", "post/schedule", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CancelScheduledTweetReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.CancelScheduledTweet(req))
+	})
+	router.Handle("POST", "post/schedule", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UpdateScheduledTweetReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UpdateScheduledTweet(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("DELETE", "post", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -339,6 +592,25 @@ func RegisterPrivServant(e *gin.Engine, s Priv, m ...PrivChain) {
 		}
 		s.Render(c, nil, s.DeleteTweet(req))
 	})
+	router.Handle("POST", "post/edit", append(cc.ChainEditTweet(), func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.EditTweetReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.EditTweet(req)
+		if err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		var rv _render_ = resp
+		rv.Render(c)
+	})...)
 	router.Handle("POST", "post", append(cc.ChainCreateTweet(), func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -359,6 +631,60 @@ func RegisterPrivServant(e *gin.Engine, s Priv, m ...PrivChain) {
 		var rv _render_ = resp
 		rv.Render(c)
 	})...)
+	router.Handle("GET", "/attachment/refunds", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UserAttachmentRefundsReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UserAttachmentRefunds(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("POST", "/attachment/refund/review", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.ReviewAttachmentRefundReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.ReviewAttachmentRefund(req))
+	})
+	router.Handle("POST", "/attachment/refund/dispute", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.DisputeAttachmentRefundReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.DisputeAttachmentRefund(req))
+	})
+	router.Handle("POST", "/attachment/refund", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.RequestAttachmentRefundReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.RequestAttachmentRefund(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("GET", "attachment", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -389,6 +715,87 @@ func RegisterPrivServant(e *gin.Engine, s Priv, m ...PrivChain) {
 		resp, err := s.DownloadAttachmentPrecheck(req)
 		s.Render(c, resp, err)
 	})
+	router.Handle("DELETE", "attachment/upload", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CancelUploadReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		s.Render(c, nil, s.CancelUpload(req))
+	})
+	router.Handle("POST", "attachment/upload/complete", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CompleteUploadReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.CompleteUpload(req)
+		s.Render(c, resp, err)
+	})
+	router.Handle("HEAD", "attachment/upload", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UploadProgressReq)
+		if err := s.Bind(c, req); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UploadProgress(req)
+		if err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		var rv _render_ = resp
+		rv.Render(c)
+	})
+	router.Handle("PATCH", "attachment/upload", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.UploadChunkReq)
+		var bv _binding_ = req
+		if err := bv.Bind(c); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.UploadChunk(req)
+		if err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		var rv _render_ = resp
+		rv.Render(c)
+	})
+	router.Handle("POST", "attachment/upload", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		default:
+		}
+		req := new(web.CreateUploadReq)
+		var bv _binding_ = req
+		if err := bv.Bind(c); err != nil {
+			s.Render(c, nil, err)
+			return
+		}
+		resp, err := s.CreateUpload(req)
+		s.Render(c, resp, err)
+	})
 	router.Handle("POST", "attachment", func(c *gin.Context) {
 		select {
 		case <-c.Request.Context().Done():
@@ -413,6 +820,34 @@ func (UnimplementedPrivServant) Chain() gin.HandlersChain {
 	return nil
 }
 
+func (UnimplementedPrivServant) CancelWithdrawal(req *web.CancelWithdrawalReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UserWithdrawals(req *web.UserWithdrawalsReq) (*web.UserWithdrawalsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) CreateWithdrawal(req *web.CreateWithdrawalReq) (*web.CreateWithdrawalResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) RemoveTopicModerator(req *web.TopicModeratorReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) AddTopicModerator(req *web.TopicModeratorReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) PinTopicTweet(req *web.PinTopicTweetReq) (*web.PinTopicTweetResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UpdateTopic(req *web.UpdateTopicReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedPrivServant) UnfollowTopic(req *web.UnfollowTopicReq) error {
 	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
@@ -489,14 +924,70 @@ func (UnimplementedPrivServant) StarTweet(req *web.StarTweetReq) (*web.StarTweet
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
 
+func (UnimplementedPrivServant) TipTweet(req *web.TipTweetReq) (*web.TipTweetResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) VotePoll(req *web.VotePollReq) (*web.VotePollResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) PublishDraft(req *web.PublishDraftReq) (*web.CreateTweetResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) ListDrafts(req *web.ListDraftsReq) (*web.ListDraftsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) DeleteDraft(req *web.DeleteDraftReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UpdateDraft(req *web.UpdateDraftReq) (*web.UpdateDraftResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) CreateDraft(req *web.CreateDraftReq) (*web.CreateDraftResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) CancelScheduledTweet(req *web.CancelScheduledTweetReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UpdateScheduledTweet(req *web.UpdateScheduledTweetReq) (*web.UpdateScheduledTweetResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedPrivServant) DeleteTweet(req *web.DeleteTweetReq) error {
 	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
 
+func (UnimplementedPrivServant) EditTweet(req *web.EditTweetReq) (*web.EditTweetResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedPrivServant) CreateTweet(req *web.CreateTweetReq) (*web.CreateTweetResp, error) {
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
 
+func (UnimplementedPrivServant) UserAttachmentRefunds(req *web.UserAttachmentRefundsReq) (*web.UserAttachmentRefundsResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) ReviewAttachmentRefund(req *web.ReviewAttachmentRefundReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) DisputeAttachmentRefund(req *web.DisputeAttachmentRefundReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) RequestAttachmentRefund(req *web.RequestAttachmentRefundReq) (*web.RequestAttachmentRefundResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedPrivServant) DownloadAttachment(req *web.DownloadAttachmentReq) (*web.DownloadAttachmentResp, error) {
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
@@ -505,6 +996,26 @@ func (UnimplementedPrivServant) DownloadAttachmentPrecheck(req *web.DownloadAtta
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
 
+func (UnimplementedPrivServant) CancelUpload(req *web.CancelUploadReq) error {
+	return mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) CompleteUpload(req *web.CompleteUploadReq) (*web.UploadAttachmentResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UploadProgress(req *web.UploadProgressReq) (*web.UploadProgressResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) UploadChunk(req *web.UploadChunkReq) (*web.UploadChunkResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
+func (UnimplementedPrivServant) CreateUpload(req *web.CreateUploadReq) (*web.CreateUploadResp, error) {
+	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
+
 func (UnimplementedPrivServant) UploadAttachment(req *web.UploadAttachmentReq) (*web.UploadAttachmentResp, error) {
 	return nil, mir.Errorln(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
 }
@@ -514,6 +1025,14 @@ func (UnimplementedPrivServant) mustEmbedUnimplementedPrivServant() {}
 // UnimplementedPrivChain can be embedded to have forward compatible implementations.
 type UnimplementedPrivChain struct{}
 
+func (b *UnimplementedPrivChain) ChainPublishDraft() gin.HandlersChain {
+	return nil
+}
+
+func (b *UnimplementedPrivChain) ChainEditTweet() gin.HandlersChain {
+	return nil
+}
+
 func (b *UnimplementedPrivChain) ChainCreateTweet() gin.HandlersChain {
 	return nil
 }
@@ -0,0 +1,51 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/dao"
+	"github.com/spf13/cobra"
+)
+
+func auditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "audit",
+		Short: "audit wallet ledger",
+		Long:  "detect unbalanced journals and drift between user balances, ledger accounts and wallet statements, exit with status 1 if any found",
+		Run:   auditRun,
+	}
+}
+
+func auditRun(_cmd *cobra.Command, _args []string) {
+	conf.Initial(features, noDefaultFeatures)
+	defer conf.CloseDB()
+
+	ds := dao.DataService()
+	ledger, err := ds.AuditLedger()
+	if err != nil {
+		exitf("audit ledger failed: %s", err)
+	}
+	wallet, err := ds.ReconcileWallets()
+	if err != nil {
+		exitf("reconcile wallets failed: %s", err)
+	}
+	for _, id := range ledger.UnbalancedJournals {
+		fmt.Printf("unbalanced journal\t%d\n", id)
+	}
+	for _, d := range ledger.Drifts {
+		fmt.Printf("ledger drift\t%s\tuser:%d\texpected:%d\tactual:%d\t%s\n", d.Code, d.UserID, d.Expected, d.Actual, d.Reason)
+	}
+	for _, m := range wallet.Mismatches {
+		fmt.Printf("statement drift\tuser:%d\tbalance:%d\tstatements:%d\n", m.UserID, m.Balance, m.StatementSum)
+	}
+	fmt.Printf("accounts: %d, journals: %d, total balance: %d, users: %d, unpaid withdrawal: %d\n",
+		ledger.Accounts, ledger.Journals, ledger.TotalBalance, wallet.Users, wallet.UnpaidWithdrawal)
+	if !ledger.Clean() || len(wallet.Mismatches) > 0 {
+		exitf("ledger drift detected")
+	}
+}
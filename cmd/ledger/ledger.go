@@ -0,0 +1,36 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ledger
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rocboss/paopao-ce/cmd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	noDefaultFeatures bool
+	features          []string
+)
+
+func init() {
+	ledgerCmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "wallet ledger maintenance",
+		Long:  "wallet ledger maintenance, such as audit drift between balances, ledger entries and statements",
+	}
+	ledgerCmd.PersistentFlags().BoolVar(&noDefaultFeatures, "no-default-features", false, "whether not use default features")
+	ledgerCmd.PersistentFlags().StringSliceVarP(&features, "features", "f", []string{}, "use special features")
+
+	ledgerCmd.AddCommand(auditCmd())
+	cmd.Register(ledgerCmd)
+}
+
+func exitf(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(1)
+}
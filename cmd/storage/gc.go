@@ -0,0 +1,48 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/dao"
+	"github.com/rocboss/paopao-ce/internal/infra/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun bool
+)
+
+func gcCmd() *cobra.Command {
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "collect orphan objects",
+		Long:  "delete objects that not referenced by any tweet, comment, avatar and so on",
+		Run:   gcRun,
+	}
+	gcCmd.Flags().BoolVar(&dryRun, "dry-run", false, "only report orphan objects but not delete")
+	return gcCmd
+}
+
+func gcRun(_cmd *cobra.Command, _args []string) {
+	conf.Initial(features, noDefaultFeatures)
+	defer conf.CloseDB()
+
+	collector := storage.NewCollector(dao.DataService(), dao.ObjectStorageService())
+	report, err := collector.Collect(dryRun, func(obj *core.ObjectInfo) {
+		fmt.Printf("%s\t%d\t%s\n", obj.Key, obj.Size, obj.LastModified.Format(time.DateTime))
+	})
+	if report != nil {
+		fmt.Printf("scanned: %d, in grace period: %d, orphans: %d (%d bytes), deleted: %d\n",
+			report.Scanned, report.Recent, report.Orphans, report.OrphanSize, report.Deleted)
+	}
+	if err != nil {
+		exitf("collect orphan objects failed: %s", err)
+	}
+}
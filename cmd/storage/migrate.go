@@ -0,0 +1,82 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/dao"
+	"github.com/rocboss/paopao-ce/internal/infra/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom     string
+	migrateTo       string
+	migratePrefixes []string
+	migrateState    string
+)
+
+func migrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "migrate objects between object storages",
+		Long:  "copy objects from one object storage to another, verify checksums and rewrite stored urls, rerun to resume when interrupted",
+		Run:   migrateRun,
+	}
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "source object storage, such as AliOSS/COS/HuaweiOBS/MinIO/S3/NativeOBS/LocalOSS")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "target object storage, such as AliOSS/COS/HuaweiOBS/MinIO/S3/NativeOBS/LocalOSS")
+	migrateCmd.Flags().StringSliceVar(&migratePrefixes, "prefix", []string{""}, "only migrate objects with special prefixes")
+	migrateCmd.Flags().StringVar(&migrateState, "state", "", "state file that record migrated objects (default custom/data/paopao-ce/storage-migrate-<from>-<to>.state)")
+	migrateCmd.MarkFlagRequired("from")
+	migrateCmd.MarkFlagRequired("to")
+	return migrateCmd
+}
+
+func migrateRun(_cmd *cobra.Command, _args []string) {
+	if strings.EqualFold(migrateFrom, migrateTo) {
+		exitf("source and target object storage must be different")
+	}
+	conf.Initial(features, noDefaultFeatures)
+	defer conf.CloseDB()
+
+	from, err := dao.NewObjectStorageService(migrateFrom)
+	if err != nil {
+		exitf("%s", err)
+	}
+	to, err := dao.NewObjectStorageService(migrateTo)
+	if err != nil {
+		exitf("%s", err)
+	}
+	if migrateState == "" {
+		name := fmt.Sprintf("storage-migrate-%s-%s.state", strings.ToLower(migrateFrom), strings.ToLower(migrateTo))
+		migrateState = filepath.Join("custom/data/paopao-ce", name)
+	}
+	migrator := storage.NewMigrator(dao.DataService(), from, to, migrateState)
+	report, err := migrator.Migrate(migratePrefixes, func(obj *core.ObjectInfo, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\tfailed: %s\n", obj.Key, err)
+		} else {
+			fmt.Printf("%s\t%d\n", obj.Key, obj.Size)
+		}
+	})
+	if report != nil {
+		fmt.Printf("scanned: %d, skipped: %d, copied: %d (%d bytes), failed: %d, rewritten records: %d\n",
+			report.Scanned, report.Skipped, report.Copied, report.Size, report.Failed, report.Rewritten)
+	}
+	if err != nil {
+		exitf("migrate objects failed: %s", err)
+	}
+}
+
+func exitf(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(1)
+}
@@ -0,0 +1,28 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"github.com/rocboss/paopao-ce/cmd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	noDefaultFeatures bool
+	features          []string
+)
+
+func init() {
+	storageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "object storage maintenance",
+		Long:  "object storage maintenance, such as collect orphan objects or migrate objects between object storages",
+	}
+	storageCmd.PersistentFlags().BoolVar(&noDefaultFeatures, "no-default-features", false, "whether not use default features")
+	storageCmd.PersistentFlags().StringSliceVarP(&features, "features", "f", []string{}, "use special features")
+
+	storageCmd.AddCommand(gcCmd(), migrateCmd())
+	cmd.Register(storageCmd)
+}
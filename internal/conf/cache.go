@@ -29,6 +29,9 @@ const (
 	PrefixIdxTweetsNewest    = "paopao:index:tweets:newest:"
 	PrefixIdxTweetsHots      = "paopao:index:tweets:hots:"
 	PrefixIdxTweetsFollowing = "paopao:index:tweets:following:"
+	PrefixIdxTweetsRecommend = "paopao:index:tweets:recommend:"
+	PrefixRecommendTweets    = "paopao:recommendtweets:"
+	PrefixTimeline           = "paopao:timeline:"
 	PrefixIdxTrends          = "paopao:index:trends:"
 	PrefixMessages           = "paopao:messages:"
 	PrefixUserInfo           = "paopao:user:info:"
@@ -47,6 +47,18 @@ var (
 	RedisCacheIndexSetting  *redisCacheIndexConf
 	SmsJuheSetting          *smsJuheConf
 	AlipaySetting           *alipayConf
+	WechatPaySetting        *wechatPayConf
+	WithdrawalSetting       *withdrawalConf
+	SubscriptionSetting     *subscriptionConf
+	RecommendSetting        *recommendConf
+	FanoutTimelineSetting   *fanoutTimelineConf
+	TrendingTagsSetting     *trendingTagsConf
+	LinkPreviewSetting      *linkPreviewConf
+	ImageProcessSetting     *imageProcessConf
+	VideoTranscodeSetting   *videoTranscodeConf
+	ResumableUploadSetting  *resumableUploadConf
+	StorageQuotaSetting     *storageQuotaConf
+	ObjectGCSetting         *objectGCConf
 	TweetSearchSetting      *tweetSearchConf
 	ZincSetting             *zincConf
 	MeiliSetting            *meiliConf
@@ -57,6 +69,7 @@ var (
 	MinIOSetting            *minioConf
 	S3Setting               *s3Conf
 	LocalOSSSetting         *localossConf
+	NativeOBSSetting        *nativeobsConf
 	JWTSetting              *jwtConf
 	WebProfileSetting       *WebProfileConf
 )
@@ -95,7 +108,19 @@ func setupSetting(suite []string, noDefault bool) error {
 		"BigCacheIndex":     &BigCacheIndexSetting,
 		"RedisCacheIndex":   &RedisCacheIndexSetting,
 		"Alipay":            &AlipaySetting,
+		"WechatPay":         &WechatPaySetting,
+		"Withdrawal":        &WithdrawalSetting,
+		"Subscription":      &SubscriptionSetting,
+		"Recommend":         &RecommendSetting,
+		"FanoutTimeline":    &FanoutTimelineSetting,
+		"TrendingTags":      &TrendingTagsSetting,
 		"SmsJuhe":           &SmsJuheSetting,
+		"LinkPreview":       &LinkPreviewSetting,
+		"ImageProcess":      &ImageProcessSetting,
+		"VideoTranscode":    &VideoTranscodeSetting,
+		"ResumableUpload":   &ResumableUploadSetting,
+		"StorageQuota":      &StorageQuotaSetting,
+		"ObjectGC":          &ObjectGCSetting,
 		"Pyroscope":         &PyroscopeSetting,
 		"Sentry":            &sentrySetting,
 		"Logger":            &loggerSetting,
@@ -119,6 +144,7 @@ func setupSetting(suite []string, noDefault bool) error {
 		"HuaweiOBS":         &HuaweiOBSSetting,
 		"MinIO":             &MinIOSetting,
 		"LocalOSS":          &LocalOSSSetting,
+		"NativeOBS":         &NativeOBSSetting,
 		"S3":                &S3Setting,
 		"WebProfile":        &WebProfileSetting,
 	}
@@ -138,6 +164,11 @@ func setupSetting(suite []string, noDefault bool) error {
 	BigCacheIndexSetting.ExpireInSecond *= time.Second
 	RedisCacheIndexSetting.ExpireInSecond *= time.Second
 	redisSetting.ConnWriteTimeout *= time.Second
+	LinkPreviewSetting.Timeout *= time.Second
+	VideoTranscodeSetting.Timeout *= time.Second
+	ResumableUploadSetting.Expire *= time.Second
+	ObjectGCSetting.GracePeriod *= time.Hour
+	WechatPaySetting.Timeout *= time.Second
 
 	return nil
 }
@@ -170,6 +201,11 @@ func GetOssDomain() string {
 		}
 		// TODO: will not work well need test in real world
 		return uri + S3Setting.Domain + "/" + S3Setting.Bucket + "/"
+	} else if cfg.If("NativeOBS") {
+		if !NativeOBSSetting.Secure {
+			uri = "http://"
+		}
+		return uri + NativeOBSSetting.Domain + "/" + NativeOBSSetting.Bucket + "/"
 	} else if cfg.If("LocalOSS") {
 		if !LocalOSSSetting.Secure {
 			uri = "http://"
@@ -0,0 +1,44 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"sync"
+
+	"github.com/rocboss/paopao-ce/pkg/media"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_transcoder     media.Transcoder
+	_onceTranscoder sync.Once
+)
+
+// MustTranscoder 获取基于本地ffmpeg的视频转码器
+func MustTranscoder() media.Transcoder {
+	_onceTranscoder.Do(func() {
+		s := VideoTranscodeSetting
+		transcoder, err := media.NewFFmpegTranscoder(s.FFmpeg, s.FFprobe)
+		if err != nil {
+			logrus.Fatalf("conf.MustTranscoder create ffmpeg transcoder failed: %s", err)
+		}
+		_transcoder = transcoder
+	})
+	return _transcoder
+}
+
+// VideoRenditions 视频转码的HLS清晰度列表
+func VideoRenditions() []*media.Rendition {
+	renditions := make([]*media.Rendition, 0, len(VideoTranscodeSetting.Renditions))
+	for _, r := range VideoTranscodeSetting.Renditions {
+		renditions = append(renditions, &media.Rendition{
+			Name:         r.Name,
+			Height:       r.Height,
+			VideoBitrate: r.VideoBitrate,
+			AudioBitrate: r.AudioBitrate,
+		})
+	}
+	return renditions
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"sync"
+
+	"github.com/rocboss/paopao-ce/pkg/obs"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_nativeObs     obs.Storage
+	_onceNativeObs sync.Once
+)
+
+// MustNativeObs 获取内置对象存储，对象存储服务与NativeOBS子服务共用同一实例
+func MustNativeObs() obs.Storage {
+	_onceNativeObs.Do(func() {
+		s := NativeOBSSetting
+		storage, err := obs.NewStorage(s.SavePath, s.Bucket)
+		if err != nil {
+			logrus.Fatalf("conf.MustNativeObs create native object storage failed: %s", err)
+		}
+		_nativeObs = storage
+	})
+	return _nativeObs
+}
+
+// NativeObsConfig NativeOBS子服务S3兼容接口的配置
+func NativeObsConfig() *obs.Config {
+	s := NativeOBSSetting
+	return &obs.Config{
+		Region:         s.Region,
+		AccessKey:      s.AccessKey,
+		SecretKey:      s.SecretKey,
+		PublicPrefixes: s.PublicPrefixes,
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"sync"
+
+	"github.com/rocboss/paopao-ce/pkg/picture"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_pictureOptions     *picture.Options
+	_oncePictureOptions sync.Once
+)
+
+// MustPictureOptions 获取上传图片处理选项，找不到WebP编码器时只生成普通缩略图
+func MustPictureOptions() *picture.Options {
+	_oncePictureOptions.Do(func() {
+		s := ImageProcessSetting
+		_pictureOptions = &picture.Options{
+			Quality:         s.Quality,
+			ThumbnailWidths: s.ThumbnailWidths,
+			BlurhashX:       s.BlurhashX,
+			BlurhashY:       s.BlurhashY,
+			MaxPixels:       s.MaxPixels,
+		}
+		if s.WebpEncoder != "" {
+			encoder, err := picture.NewCwebpEncoder(s.WebpEncoder, s.WebpQuality)
+			if err != nil {
+				logrus.Warnf("conf.MustPictureOptions disable webp variants because not found webp encoder: %s", err)
+			} else {
+				_pictureOptions.Webp = encoder
+			}
+		}
+	})
+	return _pictureOptions
+}
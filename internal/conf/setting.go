@@ -8,10 +8,12 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	pyroscope "github.com/grafana/pyroscope-go"
+	"github.com/rocboss/paopao-ce/pkg/recommend"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/gorm/logger"
@@ -103,9 +105,16 @@ type appConf struct {
 	MaxWhisperDaily       int64
 	MaxCaptchaTimes       int
 	AttachmentIncomeRate  float64
+	AttachmentRefundDays  int64
+	TipIncomeRate         float64
+	MinTipAmount          int64
+	ChargeThreshold       int64
 	DefaultContextTimeout time.Duration
 	DefaultPageSize       int
 	MaxPageSize           int
+	TweetEditWindow       int64
+	MaxTopicPins          int
+	MaxCollectionFolders  int
 }
 
 type cacheConf struct {
@@ -140,8 +149,16 @@ type metricManagerConf struct {
 }
 
 type jobManagerConf struct {
-	MaxOnlineInterval     string
-	UpdateMetricsInterval string
+	MaxOnlineInterval             string
+	UpdateMetricsInterval         string
+	PublishScheduledTweetInterval string
+	ClosePollInterval             string
+	ProcessMediaInterval          string
+	CleanUploadSessionInterval    string
+	CollectOrphanObjectsInterval  string
+	ReconcileWalletInterval       string
+	RenewSubscriptionInterval     string
+	RefreshTrendingTagsInterval   string
 }
 
 type cacheIndexConf struct {
@@ -176,6 +193,126 @@ type alipayConf struct {
 	InProduction      bool
 }
 
+type wechatPayConf struct {
+	AppID          string
+	MchID          string
+	MchSerialNo    string
+	PrivateKeyFile string
+	APIv3Key       string
+	PublicKeyFile  string
+	PublicKeyID    string
+	Gateway        string
+	Timeout        time.Duration
+	NotifyURL      string
+}
+
+type withdrawalConf struct {
+	MinAmount    int64
+	FeeRate      float64
+	MinFee       int64
+	MaxPending   int64
+	AccountTypes []string
+}
+
+type subscriptionConf struct {
+	MaxTiers   int64
+	MinPrice   int64
+	MaxPrice   int64
+	Period     int64
+	IncomeRate float64
+}
+
+type fanoutTimelineConf struct {
+	MaxSize            int64
+	CelebrityFollowers int64
+	BackfillSize       int
+	Expire             int64
+}
+
+type recommendConf struct {
+	CandidateDays  int64
+	MaxCandidates  int
+	MaxTopics      int
+	Expire         int64
+	Following      float64
+	Friend         float64
+	Topic          float64
+	FriendOfFriend float64
+	Engagement     float64
+	Popularity     float64
+	HalfLife       float64
+	MaxPerAuthor   int
+}
+
+type trendingTagsConf struct {
+	MinUsage    int64
+	Smoothing   float64
+	MaxSize     int
+	BlockedTags []string
+}
+
+type linkPreviewConf struct {
+	MinWorker     int
+	MaxRequestBuf int
+	Timeout       time.Duration
+	MaxRedirects  int
+	MaxBodySize   int64
+	MaxImageSize  int64
+	CacheExpire   int64
+	UserAgent     string
+}
+
+type imageProcessConf struct {
+	Quality         int
+	ThumbnailWidths []int
+	WebpEncoder     string
+	WebpQuality     int
+	BlurhashX       int
+	BlurhashY       int
+	MaxPixels       int64
+}
+
+type videoTranscodeConf struct {
+	FFmpeg      string
+	FFprobe     string
+	WorkDir     string
+	SegmentTime int
+	Timeout     time.Duration
+	MaxBatch    int
+	Renditions  []*renditionConf
+}
+
+type renditionConf struct {
+	Name         string
+	Height       int
+	VideoBitrate string
+	AudioBitrate string
+}
+
+type resumableUploadConf struct {
+	WorkDir      string
+	MaxSize      int64
+	MaxChunkSize int64
+	MaxSessions  int64
+	Expire       time.Duration
+}
+
+type storageQuotaConf struct {
+	User  *roleQuotaConf
+	Admin *roleQuotaConf
+}
+
+type roleQuotaConf struct {
+	MaxSize  int64
+	MaxFiles int64
+}
+
+type objectGCConf struct {
+	GracePeriod time.Duration
+	MaxDelete   int
+	Prefixes    []string
+}
+
 type smsJuheConf struct {
 	Gateway string
 	Key     string
@@ -279,6 +416,17 @@ type localossConf struct {
 	Domain   string
 }
 
+type nativeobsConf struct {
+	SavePath       string
+	Secure         bool
+	Bucket         string
+	Domain         string
+	Region         string
+	AccessKey      string
+	SecretKey      string
+	PublicPrefixes []string
+}
+
 type redisConf struct {
 	InitAddress      []string
 	Username         string
@@ -522,3 +670,44 @@ func featuresInfoFrom(vp *viper.Viper, k string) (map[string][]string, map[strin
 	}
 	return suites, kv
 }
+
+// Fee 提现手续费，按费率计算且不低于最低手续费
+func (s *withdrawalConf) Fee(amount int64) int64 {
+	return max(s.MinFee, int64(math.Ceil(float64(amount)*s.FeeRate)))
+}
+
+// NextExpiredOn 从from开始续费一期后的到期时间
+func (s *subscriptionConf) NextExpiredOn(from int64) int64 {
+	return from + s.Period*24*3600
+}
+
+// Income 订阅收入中创作者所得部分
+func (s *subscriptionConf) Income(price int64) int64 {
+	return int64(float64(price) * s.IncomeRate)
+}
+
+// Weights 推荐打分的各项权重
+func (s *recommendConf) Weights() *recommend.Weights {
+	return &recommend.Weights{
+		Following:      s.Following,
+		Friend:         s.Friend,
+		Topic:          s.Topic,
+		FriendOfFriend: s.FriendOfFriend,
+		Engagement:     s.Engagement,
+		Popularity:     s.Popularity,
+		HalfLife:       s.HalfLife,
+		MaxPerAuthor:   s.MaxPerAuthor,
+	}
+}
+
+// RoleQuota 按角色获取存储配额，存储空间单位为字节，0表示不限制
+func (s *storageQuotaConf) RoleQuota(isAdmin bool) (maxSize int64, maxFiles int64) {
+	quota := s.User
+	if isAdmin {
+		quota = s.Admin
+	}
+	if quota == nil {
+		return 0, 0
+	}
+	return quota.MaxSize << 20, quota.MaxFiles
+}
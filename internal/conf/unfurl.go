@@ -0,0 +1,41 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"sync"
+	"time"
+
+	hx "github.com/rocboss/paopao-ce/pkg/http"
+	"github.com/rocboss/paopao-ce/pkg/unfurl"
+)
+
+var (
+	_unfurler     unfurl.Unfurler
+	_onceUnfurler sync.Once
+)
+
+// MustUnfurler 获取链接预览抓取器，网络请求禁止访问内网地址
+func MustUnfurler() unfurl.Unfurler {
+	_onceUnfurler.Do(func() {
+		s := LinkPreviewSetting
+		client := hx.NewSafeClient(&hx.SafeClientConf{
+			Timeout:      s.Timeout,
+			MaxRedirects: s.MaxRedirects,
+		})
+		acc := &hx.AsyncClientConf{
+			MinWorker:         s.MinWorker,
+			MaxRequestBuf:     s.MaxRequestBuf,
+			MaxRequestTempBuf: 100,
+			MaxIdleTime:       60 * time.Second,
+		}
+		_unfurler = unfurl.NewUnfurler(hx.NewAsyncClient(client, acc), &unfurl.Config{
+			MaxBodySize:  s.MaxBodySize,
+			MaxImageSize: s.MaxImageSize,
+			UserAgent:    s.UserAgent,
+		})
+	})
+	return _unfurler
+}
@@ -0,0 +1,53 @@
+package conf
+
+import (
+	"os"
+	"sync"
+
+	"github.com/rocboss/paopao-ce/pkg/wxpay"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_wechatPayClient *wxpay.Client
+	_onceWechatPay   sync.Once
+)
+
+func MustWechatPayClient() *wxpay.Client {
+	_onceWechatPay.Do(func() {
+		s := WechatPaySetting
+		// 加载商户API私钥
+		data, err := os.ReadFile(s.PrivateKeyFile)
+		if err != nil {
+			logrus.Fatalf("read wechat pay private key file err: %s", err)
+		}
+		privateKey, err := wxpay.ParsePrivateKey(data)
+		if err != nil {
+			logrus.Fatalf("wxpay.ParsePrivateKey err: %s", err)
+		}
+		// 加载微信支付公钥或平台证书
+		if data, err = os.ReadFile(s.PublicKeyFile); err != nil {
+			logrus.Fatalf("read wechat pay public key file err: %s", err)
+		}
+		publicKey, err := wxpay.ParsePublicKey(data)
+		if err != nil {
+			logrus.Fatalf("wxpay.ParsePublicKey err: %s", err)
+		}
+		client, err := wxpay.New(wxpay.Config{
+			AppID:      s.AppID,
+			MchID:      s.MchID,
+			SerialNo:   s.MchSerialNo,
+			PrivateKey: privateKey,
+			APIv3Key:   s.APIv3Key,
+			PublicKey:  publicKey,
+			PublicID:   s.PublicKeyID,
+			Gateway:    s.Gateway,
+			Timeout:    s.Timeout,
+		})
+		if err != nil {
+			logrus.Fatalf("wxpay.New err: %s", err)
+		}
+		_wechatPayClient = client
+	})
+	return _wechatPayClient
+}
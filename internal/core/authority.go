@@ -14,4 +14,5 @@ type AuthorizationManageService interface {
 	BeFriendFilter(userId int64) ms.FriendFilter
 	BeFriendIds(userId int64) ([]int64, error)
 	MyFriendSet(userId int64) ms.FriendSet
+	SubscribedIds(userId int64) ([]int64, error)
 }
@@ -98,6 +98,18 @@ type RedisCache interface {
 	DelRechargeStatus(ctx context.Context, tradeNo string) error
 }
 
+// TimelineCache 写扩散的关注动态时间线存储，每个用户一条按发布时间排序的推文时间线
+type TimelineCache interface {
+	// PushTweets 将推文写入各用户已建立的时间线，未建立的时间线留待读取时重建
+	PushTweets(userIds []int64, items ...*cs.TimelineItem) error
+	RemoveTweets(userIds []int64, tweetIds ...int64) error
+	// ResetTimeline 以给定推文重建用户的时间线
+	ResetTimeline(userId int64, items []*cs.TimelineItem) error
+	// ListTimeline 按发布时间倒序获取时间线中的推文，时间线未建立时exist为false
+	ListTimeline(userId int64, offset int, limit int) (items []*cs.TimelineItem, total int64, exist bool, err error)
+	DeleteTimeline(userIds ...int64) error
+}
+
 type AppCache interface {
 	Get(key string) ([]byte, error)
 	Set(key string, data []byte, ex int64) error
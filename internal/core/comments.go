@@ -12,6 +12,7 @@ import (
 // CommentService 评论检索服务
 type CommentService interface {
 	GetComments(tweetId int64, style cs.StyleCommentType, limit int, offset int) ([]*ms.Comment, int64, error)
+	GetCommentsByCursor(tweetId int64, style cs.StyleCommentType, cursor *cs.Cursor, limit int) ([]*ms.Comment, *cs.Cursor, error)
 	GetCommentByID(id int64) (*ms.Comment, error)
 	GetCommentReplyByID(id int64) (*ms.CommentReply, error)
 	GetCommentContentsByIDs(ids []int64) ([]*ms.CommentContent, error)
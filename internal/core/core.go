@@ -8,17 +8,37 @@ package core
 type DataService interface {
 	// 钱包服务
 	WalletService
+	WithdrawalService
+	LedgerService
+	SubscriptionService
+	TipService
+	RefundService
 
 	// 消息服务
 	MessageService
 
 	// 话题服务
 	TopicService
+	TopicManageService
 
 	// 推文服务
 	TweetService
 	TweetManageService
 	TweetHelpService
+	TweetScheduleService
+	TweetDraftService
+	CollectionFolderService
+	TweetRevisionService
+	TweetPollService
+	TweetRecommendService
+	LinkPreviewService
+
+	// 媒体处理及上传存储服务
+	MediaProcessService
+	UploadSessionService
+	StorageQuotaService
+	ObjectBlobService
+	ObjectReferenceService
 
 	// 推文指标服务
 	UserMetricServantA
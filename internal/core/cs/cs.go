@@ -6,3 +6,8 @@
 // model define
 
 package cs
+
+import "github.com/rocboss/paopao-ce/pkg/cursor"
+
+// Cursor 键集分页的游标，指向上一页最后一条记录的(created_on, id)
+type Cursor = cursor.Cursor
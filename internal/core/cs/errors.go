@@ -10,4 +10,8 @@ import "errors"
 var (
 	ErrNotImplemented = errors.New("not implemented")
 	ErrNoPermission   = errors.New("no permission")
+	ErrNotExist       = errors.New("record not exist")
+	ErrNoBalance      = errors.New("insufficient balance")
+	ErrSubscribed     = errors.New("already subscribed")
+	ErrTooManyPins    = errors.New("too many pins")
 )
@@ -4,8 +4,19 @@
 
 package cs
 
+import "github.com/rocboss/paopao-ce/pkg/recommend"
+
+// TweetCandidate 个性化推荐的候选推文
+type TweetCandidate = recommend.Candidate
+
 // TweetBox 推文列表盒子，包含其他一些关于推文列表的信息
 type TweetBox struct {
 	Tweets TweetList
 	Total  int64
 }
+
+// TimelineItem 关注动态时间线中的一条推文
+type TimelineItem struct {
+	ID        int64
+	CreatedOn int64
+}
@@ -11,6 +11,7 @@ const (
 	TagTypeFollow    TagType = "follow"
 	TagTypePin       TagType = "pin"
 	TagTypeHotExtral TagType = "hot_extral"
+	TagTypeTrending  TagType = "trending"
 )
 
 type (
@@ -44,6 +45,18 @@ type TagItem struct {
 	IsPin       int8      `json:"is_pin"`
 }
 
+// TopicInfo 话题主页信息
+type TopicInfo struct {
+	ID          int64  `json:"id"`
+	UserID      int64  `json:"user_id"`
+	Tag         string `json:"tag"`
+	QuoteNum    int64  `json:"quote_num"`
+	Description string `json:"description"`
+	Cover       string `json:"cover"`
+	IsLocked    int8   `json:"is_locked"`
+	IsHidden    int8   `json:"is_hidden"`
+}
+
 func (t *TagInfo) Format() *TagItem {
 	return &TagItem{
 		ID:          t.ID,
@@ -14,12 +14,15 @@ const (
 	TweetBlockLink
 	TweetBlockAttachment
 	TweetBlockChargeAttachment
+	TweetBlockPoll
 
 	// 推文可见性
 	TweetVisitPublic    TweetVisibleType = 90
 	TweetVisitPrivate   TweetVisibleType = 0
 	TweetVisitFriend    TweetVisibleType = 50
 	TweetVisitFollowing TweetVisibleType = 60
+	TweetVisitSubscribe TweetVisibleType = 20
+	TweetVisitCharge    TweetVisibleType = 10
 
 	// 用户推文列表样式
 	StyleUserTweetsGuest uint8 = iota
@@ -35,7 +38,7 @@ const (
 )
 
 type (
-	// TweetBlockType 推文内容分块类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源
+	// TweetBlockType 推文内容分块类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源，9投票
 	// TODO: 优化一下类型为 uint8， 需要底层数据库同步修改
 	TweetBlockType int
 
@@ -110,6 +113,28 @@ type TweetItem struct {
 	Tags            map[string]int8  `json:"tags"`
 	AttachmentPrice int64            `json:"attachment_price"`
 	IPLoc           string           `json:"ip_loc"`
+	Poll            *TweetPoll       `json:"poll,omitempty"`
+}
+
+// TweetPoll 推文投票
+type TweetPoll struct {
+	ID        int64              `json:"id"`
+	TweetID   int64              `json:"post_id"`
+	Multiple  bool               `json:"multiple"`
+	Anonymous bool               `json:"anonymous"`
+	Options   []*TweetPollOption `json:"options"`
+	VoteCount int64              `json:"vote_count"`
+	ExpiredOn int64              `json:"expired_on"`
+	ClosedOn  int64              `json:"closed_on"`
+	Voted     []int64            `json:"voted"`
+}
+
+// TweetPollOption 推文投票选项
+type TweetPollOption struct {
+	ID        int64  `json:"id"`
+	Content   string `json:"content"`
+	Sort      int64  `json:"sort"`
+	VoteCount int64  `json:"vote_count"`
 }
 
 type Attachment struct {
@@ -157,6 +182,10 @@ func (t TweetVisibleType) ToOutValue() (res uint8) {
 		res = 2
 	case TweetVisitFollowing:
 		res = 3
+	case TweetVisitSubscribe:
+		res = 4
+	case TweetVisitCharge:
+		res = 5
 	default:
 		res = 1
 	}
@@ -22,9 +22,10 @@ type (
 	RelationTyp uint8
 
 	VistUser struct {
-		Username string
-		UserId   int64
-		RelTyp   RelationTyp
+		Username     string
+		UserId       int64
+		RelTyp       RelationTyp
+		IsSubscriber bool
 	}
 )
 
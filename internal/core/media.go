@@ -0,0 +1,16 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+)
+
+// MediaProcessService 媒体处理队列服务，以附件的处理状态作为队列
+type MediaProcessService interface {
+	ListPendingMedia(staleBefore int64, limit int) ([]*ms.Attachment, error)
+	ClaimMedia(attachment *ms.Attachment) (bool, error)
+	UpdateMedia(attachment *ms.Attachment) error
+}
@@ -17,4 +17,5 @@ type MessageService interface {
 	ReadMessage(message *ms.Message) error
 	ReadAllMessage(userId int64) error
 	GetMessages(userId int64, style cs.MessageStyle, limit, offset int) ([]*ms.MessageFormated, int64, error)
+	GetMessagesByCursor(userId int64, style cs.MessageStyle, cursor *cs.Cursor, limit int) ([]*ms.MessageFormated, *cs.Cursor, error)
 }
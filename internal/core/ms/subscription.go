@@ -0,0 +1,15 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ms
+
+import (
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+)
+
+type (
+	SubscriptionTier     = dbr.SubscriptionTier
+	Subscription         = dbr.Subscription
+	SubscriptionFormated = dbr.SubscriptionFormated
+)
@@ -13,7 +13,7 @@ const (
 	AttachmentTypeVideo = dbr.AttachmentTypeVideo
 	AttachmentTypeOther = dbr.AttachmentTypeOther
 
-	// 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源
+	// 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源，9投票
 	ContentTypeTitle            = dbr.ContentTypeTitle
 	ContentTypeText             = dbr.ContentTypeText
 	ContentTypeImage            = dbr.ContentTypeImage
@@ -22,6 +22,12 @@ const (
 	ContentTypeLink             = dbr.ContentTypeLink
 	ContentTypeAttachment       = dbr.ContentTypeAttachment
 	ContentTypeChargeAttachment = dbr.ContentTypeChargeAttachment
+	ContentTypePoll             = dbr.ContentTypePoll
+)
+
+const (
+	CollectionFolderAll     = dbr.CollectionFolderAll
+	CollectionFolderDefault = dbr.CollectionFolderDefault
 )
 
 const (
@@ -29,15 +35,67 @@ const (
 	PostVisitPrivate   = dbr.PostVisitPrivate
 	PostVisitFriend    = dbr.PostVisitFriend
 	PostVisitFollowing = dbr.PostVisitFollowing
+	PostVisitSubscribe = dbr.PostVisitSubscribe
+	PostVisitCharge    = dbr.PostVisitCharge
+)
+
+const (
+	LinkPreviewStatusSuccess = dbr.LinkPreviewStatusSuccess
+	LinkPreviewStatusFailed  = dbr.LinkPreviewStatusFailed
+)
+
+const (
+	MediaStatusReady      = dbr.MediaStatusReady
+	MediaStatusPending    = dbr.MediaStatusPending
+	MediaStatusProcessing = dbr.MediaStatusProcessing
+	MediaStatusFailed     = dbr.MediaStatusFailed
+)
+
+const (
+	PostScheduleStatusPending    = dbr.PostScheduleStatusPending
+	PostScheduleStatusPublished  = dbr.PostScheduleStatusPublished
+	PostScheduleStatusCanceled   = dbr.PostScheduleStatusCanceled
+	PostScheduleStatusFailed     = dbr.PostScheduleStatusFailed
+	PostScheduleStatusPublishing = dbr.PostScheduleStatusPublishing
 )
 
 type (
 	PostStar           = dbr.PostStar
 	PostCollection     = dbr.PostCollection
+	CollectionFolder   = dbr.CollectionFolder
 	PostAttachmentBill = dbr.PostAttachmentBill
 	PostContent        = dbr.PostContent
 	Attachment         = dbr.Attachment
 	AttachmentType     = dbr.AttachmentType
 	PostContentT       = dbr.PostContentT
 	PostVisibleT       = dbr.PostVisibleT
+	PostSchedule       = dbr.PostSchedule
+	PostScheduleStatus = dbr.PostScheduleStatus
+
+	PostScheduleFormated     = dbr.PostScheduleFormated
+	PostDraft                = dbr.PostDraft
+	PostDraftFormated        = dbr.PostDraftFormated
+	CollectionFolderFormated = dbr.CollectionFolderFormated
+	PostRevision             = dbr.PostRevision
+	PostRevisionFormated     = dbr.PostRevisionFormated
+	PostPoll                 = dbr.PostPoll
+	PostPollFormated         = dbr.PostPollFormated
+	PostPollOption           = dbr.PostPollOption
+	PostPollVote             = dbr.PostPollVote
+
+	PostPollOptionFormated = dbr.PostPollOptionFormated
+	PostPollVoteFormated   = dbr.PostPollVoteFormated
+	LinkPreview            = dbr.LinkPreview
+	LinkPreviewFormated    = dbr.LinkPreviewFormated
+	ImageVariant           = dbr.ImageVariant
+
+	MediaStatus   = dbr.MediaStatus
+	UploadSession = dbr.UploadSession
+
+	StorageUsage     = dbr.StorageUsage
+	UserStorageQuota = dbr.UserStorageQuota
+	ObjectBlob       = dbr.ObjectBlob
+
+	AttachmentImageFormated = dbr.AttachmentImageFormated
+	AttachmentVideoFormated = dbr.AttachmentVideoFormated
 )
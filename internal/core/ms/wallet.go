@@ -8,7 +8,66 @@ import (
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
 )
 
+const (
+	WithdrawalStatusPending  = dbr.WithdrawalStatusPending
+	WithdrawalStatusApproved = dbr.WithdrawalStatusApproved
+	WithdrawalStatusPaid     = dbr.WithdrawalStatusPaid
+	WithdrawalStatusRejected = dbr.WithdrawalStatusRejected
+	WithdrawalStatusFailed   = dbr.WithdrawalStatusFailed
+	WithdrawalStatusCanceled = dbr.WithdrawalStatusCanceled
+
+	RefundStatusPending  = dbr.RefundStatusPending
+	RefundStatusRefunded = dbr.RefundStatusRefunded
+	RefundStatusRejected = dbr.RefundStatusRejected
+	RefundStatusDisputed = dbr.RefundStatusDisputed
+)
+
 type (
-	WalletStatement = dbr.WalletStatement
-	WalletRecharge  = dbr.WalletRecharge
+	WalletStatement  = dbr.WalletStatement
+	WalletRecharge   = dbr.WalletRecharge
+	WalletWithdrawal = dbr.WalletWithdrawal
+	WithdrawalStatus = dbr.WithdrawalStatus
+	PostTip          = dbr.PostTip
+	PostTipFormated  = dbr.PostTipFormated
+	AttachmentRefund = dbr.AttachmentRefund
+	RefundStatus     = dbr.RefundStatus
 )
+
+// WalletMismatch 余额与账单合计不一致的用户
+type WalletMismatch struct {
+	UserID       int64 `json:"user_id"`
+	Balance      int64 `json:"balance"`
+	StatementSum int64 `json:"statement_sum"`
+}
+
+// WalletReconcileReport 钱包对账结果
+type WalletReconcileReport struct {
+	Users            int64             `json:"users"`
+	TotalBalance     int64             `json:"total_balance"`
+	TotalStatement   int64             `json:"total_statement"`
+	UnpaidWithdrawal int64             `json:"unpaid_withdrawal"`
+	Mismatches       []*WalletMismatch `json:"mismatches"`
+}
+
+// LedgerDrift 余额与记账分录不一致的账户
+type LedgerDrift struct {
+	Code     string `json:"code"`
+	UserID   int64  `json:"user_id"`
+	Expected int64  `json:"expected"`
+	Actual   int64  `json:"actual"`
+	Reason   string `json:"reason"`
+}
+
+// LedgerAuditReport 复式记账审计结果，TotalBalance为所有账户余额之和，应恒为0
+type LedgerAuditReport struct {
+	Accounts           int64          `json:"accounts"`
+	Journals           int64          `json:"journals"`
+	TotalBalance       int64          `json:"total_balance"`
+	UnbalancedJournals []int64        `json:"unbalanced_journals"`
+	Drifts             []*LedgerDrift `json:"drifts"`
+}
+
+// Clean 账本是否无任何偏差
+func (r *LedgerAuditReport) Clean() bool {
+	return r.TotalBalance == 0 && len(r.UnbalancedJournals) == 0 && len(r.Drifts) == 0
+}
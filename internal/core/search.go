@@ -19,6 +19,8 @@ const (
 	PostVisitPrivate   = dbr.PostVisitPrivate
 	PostVisitFriend    = dbr.PostVisitFriend
 	PostVisitFollowing = dbr.PostVisitFollowing
+	PostVisitSubscribe = dbr.PostVisitSubscribe
+	PostVisitCharge    = dbr.PostVisitCharge
 )
 
 type (
@@ -6,12 +6,23 @@ package core
 
 import (
 	"io"
+	"time"
 )
 
+// ObjectInfo 对象存储中的对象信息
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
 // ObjectStorageService storage service interface that implement base AliOSS、MINIO or other
 type ObjectStorageService interface {
 	OssCreateService
 	OssDeleteService
+	OssListService
+	OssReadService
 
 	SignURL(objectKey string, expiredInSec int64) (string, error)
 	ObjectURL(objetKey string) string
@@ -30,3 +41,14 @@ type OssDeleteService interface {
 	DeleteObjects(objectKeys []string) error
 	IsObjectExist(objectKey string) (bool, error)
 }
+
+// OssListService Object Storage System Object List service
+type OssListService interface {
+	// ListObjects 遍历前缀下的所有对象，fn返回错误时停止遍历
+	ListObjects(prefix string, fn func(obj *ObjectInfo) error) error
+}
+
+// OssReadService Object Storage System Object Read service
+type OssReadService interface {
+	GetObject(objectKey string) (io.ReadCloser, *ObjectInfo, error)
+}
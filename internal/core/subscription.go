@@ -0,0 +1,27 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+)
+
+// SubscriptionService 创作者订阅服务
+type SubscriptionService interface {
+	CreateSubscriptionTier(tier *ms.SubscriptionTier) (*ms.SubscriptionTier, error)
+	UpdateSubscriptionTier(tier *ms.SubscriptionTier) error
+	DeleteSubscriptionTier(tier *ms.SubscriptionTier) error
+	GetSubscriptionTier(id int64) (*ms.SubscriptionTier, error)
+	ListSubscriptionTiers(userId int64) ([]*ms.SubscriptionTier, error)
+	CountSubscriptionTiers(userId int64) (int64, error)
+	GetSubscription(userId int64, creatorId int64) (*ms.Subscription, error)
+	IsSubscriber(userId int64, creatorId int64) bool
+	Subscribe(userId int64, tier *ms.SubscriptionTier) (*ms.Subscription, error)
+	RenewSubscription(subscription *ms.Subscription) (bool, error)
+	SetSubscriptionAutoRenew(subscription *ms.Subscription, autoRenew bool) error
+	ListSubscribers(creatorId int64, offset, limit int) ([]*ms.Subscription, int64, error)
+	ListUserSubscriptions(userId int64, offset, limit int) ([]*ms.Subscription, int64, error)
+	ListRenewableSubscriptions(now int64, limit int) ([]*ms.Subscription, error)
+}
@@ -14,6 +14,11 @@ type IndexPostsService interface {
 	IndexPosts(user *ms.User, offset int, limit int) (*ms.IndexTweetList, error)
 }
 
+// TweetRecommendService 个性化推荐候选推文服务
+type TweetRecommendService interface {
+	ListRecommendCandidates(userId int64, tags []string, since int64, limit int) ([]*cs.TweetCandidate, error)
+}
+
 // IndexPostsServantA 广场首页推文列表服务(版本A)
 type IndexPostsServantA interface {
 	IndexPosts(user *ms.User, limit int, offset int) (*cs.TweetBox, error)
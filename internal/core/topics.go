@@ -6,6 +6,7 @@ package core
 
 import (
 	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
 )
 
 // TopicService 话题服务
@@ -14,15 +15,37 @@ type TopicService interface {
 	DecrTagsById(ids []int64) error
 	ListTags(typ cs.TagType, limit int, offset int) (cs.TagList, error)
 	TagsByKeyword(keyword string) (cs.TagInfoList, error)
+	TagsByNames(names []string) (cs.TagInfoList, error)
 	GetHotTags(userId int64, limit int, offset int) (cs.TagList, error)
 	GetNewestTags(userId int64, limit int, offset int) (cs.TagList, error)
+	GetNewestTagsByCursor(userId int64, cursor *cs.Cursor, limit int) (cs.TagList, *cs.Cursor, error)
 	GetFollowTags(userId int64, isPin bool, limit int, offset int) (cs.TagList, error)
+	GetTrendingTags(userId int64, limit int, offset int) (cs.TagList, error)
+	RefreshTrendingTags() error
 	FollowTopic(userId int64, topicId int64) error
 	UnfollowTopic(userId int64, topicId int64) error
 	StickTopic(userId int64, topicId int64) (int8, error)
 	PinTopic(userId int64, topicId int64) (int8, error)
 }
 
+// TopicManageService 话题主页管理服务
+type TopicManageService interface {
+	GetTopic(topicId int64) (*cs.TopicInfo, error)
+	GetTopicByName(name string) (*cs.TopicInfo, error)
+	GetTopicAliases(topicId int64) ([]string, error)
+	IsFollowingTopic(userId int64, topicId int64) bool
+	UpdateTopic(topicId int64, description string, cover string) error
+	ChangeTopicStatus(topicId int64, isLocked int8, isHidden int8) error
+	MergeTopic(sourceId int64, targetId int64) error
+	ListTopicModerators(topicId int64) ([]int64, error)
+	IsTopicModerator(topicId int64, userId int64) bool
+	AddTopicModerator(topicId int64, userId int64) error
+	RemoveTopicModerator(topicId int64, userId int64) error
+	ListTopicPins(topicId int64) ([]int64, error)
+	PinTopicTweet(topicId int64, postId int64, userId int64, maxPins int) (bool, error)
+	ListTopicTweets(topicId int64, limit int, offset int) ([]*ms.Post, int64, error)
+}
+
 // TopicServantA 话题服务(版本A)
 type TopicServantA interface {
 	UpsertTags(userId int64, tags []string) (cs.TagInfoList, error)
@@ -18,17 +18,21 @@ type TweetService interface {
 	GetUserPostStars(userID int64, limit int, offset int) ([]*ms.PostStar, error)
 	GetUserPostStarCount(userID int64) (int64, error)
 	GetUserPostCollection(postID, userID int64) (*ms.PostCollection, error)
-	GetUserPostCollections(userID int64, offset, limit int) ([]*ms.PostCollection, error)
-	GetUserPostCollectionCount(userID int64) (int64, error)
+	GetUserPostCollections(userID int64, folderID int64, offset, limit int) ([]*ms.PostCollection, error)
+	GetUserPostCollectionCount(userID int64, folderID int64) (int64, error)
 	GetPostAttatchmentBill(postID, userID int64) (*ms.PostAttachmentBill, error)
 	GetPostContentsByIDs(ids []int64) ([]*ms.PostContent, error)
 	GetPostContentByID(id int64) (*ms.PostContent, error)
+	GetAttachmentsByContents(contents []string) ([]*ms.Attachment, error)
 	ListUserStarTweets(user *cs.VistUser, limit int, offset int) ([]*ms.PostStar, int64, error)
 	ListUserMediaTweets(user *cs.VistUser, limit int, offset int) ([]*ms.Post, int64, error)
 	ListUserCommentTweets(user *cs.VistUser, limit int, offset int) ([]*ms.Post, int64, error)
-	ListUserTweets(userId int64, style uint8, justEssence bool, limit, offset int) ([]*ms.Post, int64, error)
+	ListUserTweets(userId int64, style uint8, subscribed bool, justEssence bool, limit, offset int) ([]*ms.Post, int64, error)
 	ListFollowingTweets(userId int64, limit, offset int) ([]*ms.Post, int64, error)
 	ListIndexNewestTweets(limit, offset int) ([]*ms.Post, int64, error)
+	ListUserTweetsByCursor(userId int64, style uint8, subscribed bool, justEssence bool, cursor *cs.Cursor, limit int) ([]*ms.Post, *cs.Cursor, error)
+	ListFollowingTweetsByCursor(userId int64, cursor *cs.Cursor, limit int) ([]*ms.Post, *cs.Cursor, error)
+	ListIndexNewestTweetsByCursor(cursor *cs.Cursor, limit int) ([]*ms.Post, *cs.Cursor, error)
 	ListIndexHotsTweets(limit, offset int) ([]*ms.Post, int64, error)
 	ListSyncSearchTweets(limit, offset int) ([]*ms.Post, int64, error)
 }
@@ -56,6 +60,65 @@ type TweetHelpService interface {
 	MergePosts(posts []*ms.Post) ([]*ms.PostFormated, error)
 }
 
+// TweetScheduleService 定时推文服务
+type TweetScheduleService interface {
+	CreateScheduledTweet(schedule *ms.PostSchedule) (*ms.PostSchedule, error)
+	GetScheduledTweet(id int64) (*ms.PostSchedule, error)
+	// UpdateScheduledTweet 仅当定时推文仍处于from状态时更新，返回是否更新成功
+	UpdateScheduledTweet(schedule *ms.PostSchedule, from ms.PostScheduleStatus) (bool, error)
+	ListUserScheduledTweets(userId int64, limit, offset int) ([]*ms.PostSchedule, int64, error)
+	ListDueScheduledTweets(now int64, limit int) ([]*ms.PostSchedule, error)
+	ClaimScheduledTweet(schedule *ms.PostSchedule) (bool, error)
+}
+
+// TweetDraftService 推文草稿服务
+type TweetDraftService interface {
+	CreateDraft(draft *ms.PostDraft) (*ms.PostDraft, error)
+	GetDraft(id int64) (*ms.PostDraft, error)
+	UpdateDraft(draft *ms.PostDraft) error
+	DeleteDraft(draft *ms.PostDraft) error
+	ListUserDrafts(userId int64, limit, offset int) ([]*ms.PostDraft, int64, error)
+}
+
+// CollectionFolderService 收藏夹服务
+type CollectionFolderService interface {
+	CreateCollectionFolder(folder *ms.CollectionFolder) (*ms.CollectionFolder, error)
+	GetCollectionFolder(id int64) (*ms.CollectionFolder, error)
+	GetCollectionFolderByShareKey(shareKey string) (*ms.CollectionFolder, error)
+	ListCollectionFolders(userId int64) ([]*ms.CollectionFolderFormated, error)
+	UpdateCollectionFolder(folder *ms.CollectionFolder) error
+	DeleteCollectionFolder(folder *ms.CollectionFolder) error
+	MovePostCollections(userId int64, folderId int64, postIds []int64) error
+	UpdatePostCollectionNote(userId int64, postId int64, note string) error
+	ListSharedPostCollections(folder *ms.CollectionFolder, limit, offset int) ([]*ms.PostCollection, int64, error)
+}
+
+// TweetRevisionService 推文编辑与修订历史服务
+type TweetRevisionService interface {
+	EditPost(post *ms.Post, tags []string, contents []*ms.PostContent) error
+	ListPostRevisions(postId int64, limit, offset int) ([]*ms.PostRevision, int64, error)
+}
+
+// TweetPollService 推文投票服务
+type TweetPollService interface {
+	CreatePoll(poll *ms.PostPoll, options []*ms.PostPollOption) (*ms.PostPoll, error)
+	GetPollByPostId(postId int64) (*ms.PostPoll, error)
+	PollsByPostIds(ids []int64) ([]*ms.PostPollFormated, error)
+	UserPollVotes(userId int64, pollIds []int64) ([]*ms.PostPollVote, error)
+	GetUserPollVote(pollId, userId int64) (*ms.PostPollVote, error)
+	VotePoll(vote *ms.PostPollVote, optionIds []int64) error
+	ListPollVotes(pollId int64, limit, offset int) ([]*ms.PostPollVote, int64, error)
+	ListExpiredPolls(now int64, limit int) ([]*ms.PostPoll, error)
+	ClosePoll(poll *ms.PostPoll) error
+}
+
+// LinkPreviewService 链接预览服务，以链接地址为键缓存预览信息
+type LinkPreviewService interface {
+	GetLinkPreview(url string) (*ms.LinkPreview, error)
+	LinkPreviewsByURLs(urls []string) ([]*ms.LinkPreview, error)
+	SaveLinkPreview(preview *ms.LinkPreview) error
+}
+
 // TweetServantA 推文检索服务(版本A)
 type TweetServantA interface {
 	TweetInfoById(id int64) (*cs.TweetInfo, error)
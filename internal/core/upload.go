@@ -0,0 +1,44 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+)
+
+// UploadSessionService 断点续传上传会话服务
+type UploadSessionService interface {
+	CreateUploadSession(session *ms.UploadSession) (*ms.UploadSession, error)
+	GetUploadSession(id int64) (*ms.UploadSession, error)
+	UpdateUploadReceived(session *ms.UploadSession, received int64) (bool, error)
+	DeleteUploadSession(session *ms.UploadSession) error
+	CountUserUploadSessions(userId int64) (int64, error)
+	ListExpiredUploadSessions(limit int) ([]*ms.UploadSession, error)
+}
+
+// StorageQuotaService 用户存储配额服务，使用量以未删除的附件统计
+type StorageQuotaService interface {
+	GetUserStorageUsage(userId int64) (*ms.StorageUsage, error)
+	GetUserStorageQuota(userId int64) (*ms.UserStorageQuota, error)
+	SaveUserStorageQuota(quota *ms.UserStorageQuota) error
+	DeleteUserStorageQuota(userId int64) error
+	DeleteAttachmentsByContents(contents []string) error
+}
+
+// ObjectBlobService 上传内容去重服务，相同内容的上传共用一个对象，以上传次数作为引用计数
+type ObjectBlobService interface {
+	GetObjectBlob(sha256 string, uploadType string) (*ms.ObjectBlob, error)
+	CreateObjectBlob(blob *ms.ObjectBlob) (*ms.ObjectBlob, error)
+	AcquireObjectBlob(blob *ms.ObjectBlob) (bool, error)
+	ReleaseObjectBlobs(userId int64, contents []string) ([]string, error)
+	ReferencedObjectBlobs(contents []string) ([]string, error)
+}
+
+// ObjectReferenceService 对象存储引用服务，遍历或改写数据库中引用的媒体内容地址
+type ObjectReferenceService interface {
+	WalkObjectReferences(fn func(contents []string) error) error
+	WalkRecentAttachments(since int64, fn func(contents []string) error) error
+	RewriteObjectReferences(rewrite func(content string) string) (int64, error)
+}
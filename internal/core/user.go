@@ -29,6 +29,7 @@ type ContactManageService interface {
 	RejectFriend(userId int64, friendId int64) error
 	DeleteFriend(userId int64, friendId int64) error
 	GetContacts(userId int64, offset int, limit int) (*ms.ContactList, error)
+	GetContactsByCursor(userId int64, cursor *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error)
 	IsFriend(userID int64, friendID int64) bool
 }
 
@@ -38,6 +39,8 @@ type FollowingManageService interface {
 	UnfollowUser(userId int64, followId int64) error
 	ListFollows(userId int64, limit, offset int) (*ms.ContactList, error)
 	ListFollowings(userId int64, limit, offset int) (*ms.ContactList, error)
+	ListFollowsByCursor(userId int64, cursor *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error)
+	ListFollowingsByCursor(userId int64, cursor *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error)
 	GetFollowCount(userId int64) (int64, int64, error)
 	IsFollow(userId int64, followId int64) bool
 }
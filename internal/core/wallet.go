@@ -13,7 +13,40 @@ type WalletService interface {
 	GetUserWalletBills(userID int64, offset, limit int) ([]*ms.WalletStatement, error)
 	GetUserWalletBillCount(userID int64) (int64, error)
 	GetRechargeByID(id int64) (*ms.WalletRecharge, error)
-	CreateRecharge(userId, amount int64) (*ms.WalletRecharge, error)
+	CreateRecharge(userId, amount int64, provider string) (*ms.WalletRecharge, error)
 	HandleRechargeSuccess(recharge *ms.WalletRecharge, tradeNo string) error
 	HandlePostAttachmentBought(post *ms.Post, user *ms.User) error
 }
+
+// WithdrawalService 钱包提现服务
+type WithdrawalService interface {
+	CreateWithdrawal(withdrawal *ms.WalletWithdrawal) (*ms.WalletWithdrawal, error)
+	GetWithdrawal(id int64) (*ms.WalletWithdrawal, error)
+	CountUnpaidWithdrawals(userId int64) (int64, error)
+	ListWithdrawals(userId int64, status ms.WithdrawalStatus, offset, limit int) ([]*ms.WalletWithdrawal, int64, error)
+	UpdateWithdrawalStatus(withdrawal *ms.WalletWithdrawal, from ms.WithdrawalStatus) (bool, error)
+	ReconcileWallets() (*ms.WalletReconcileReport, error)
+}
+
+// TipService 推文充电服务
+type TipService interface {
+	TipPost(user *ms.User, post *ms.Post, amount int64, message string, requestId string) (*ms.PostTip, error)
+	ListPostTips(postId int64, offset, limit int) ([]*ms.PostTip, int64, error)
+	IsPostCharger(userId int64, postId int64) bool
+}
+
+// RefundService 收费附件退款服务
+type RefundService interface {
+	CreateAttachmentRefund(refund *ms.AttachmentRefund) (*ms.AttachmentRefund, error)
+	GetAttachmentRefund(id int64) (*ms.AttachmentRefund, error)
+	GetBillAttachmentRefund(billId int64) (*ms.AttachmentRefund, error)
+	ListAttachmentRefunds(userId int64, authorId int64, status ms.RefundStatus, offset, limit int) ([]*ms.AttachmentRefund, int64, error)
+	UpdateAttachmentRefundStatus(refund *ms.AttachmentRefund, from ms.RefundStatus) (bool, error)
+	RefundAttachment(refund *ms.AttachmentRefund, from ms.RefundStatus) (bool, error)
+	ListRefundableAttachmentBills(postId int64, since int64) ([]*ms.PostAttachmentBill, error)
+}
+
+// LedgerService 复式记账服务
+type LedgerService interface {
+	AuditLedger() (*ms.LedgerAuditReport, error)
+}
@@ -89,6 +89,7 @@ func OnExpireIndexTweetEvent(userId int64) {
 			conf.PrefixIdxTweetsNewest + "*",
 			conf.PrefixIdxTweetsHots + "*",
 			conf.PrefixIdxTweetsFollowing + "*",
+			conf.PrefixIdxTweetsRecommend + "*",
 			fmt.Sprintf("%s%d:*", conf.PrefixUserTweets, userId),
 		},
 	})
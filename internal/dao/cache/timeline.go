@@ -0,0 +1,189 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/rueidis"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+)
+
+var (
+	_ core.TimelineCache = (*redisTimelineCache)(nil)
+)
+
+const (
+	// _timelineSentinel 时间线已建立的标记成员，分数大于任何推文的发布时间，始终排在最前
+	_timelineSentinel      = "0"
+	_timelineSentinelScore = float64(1 << 53)
+)
+
+// _pushTimelineScript 仅向已建立的时间线写入推文，并按ARGV[2]的排名裁剪到最大长度，参数见pushTimelineArgs
+var _pushTimelineScript = rueidis.NewLuaScript(`
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	return 0
+end
+for i = 4, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i+1])
+end
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+type redisTimelineCache struct {
+	c       rueidis.Client
+	maxSize int64
+	expire  int64
+}
+
+func (s *redisTimelineCache) PushTweets(userIds []int64, items ...*cs.TimelineItem) error {
+	if len(userIds) == 0 || len(items) == 0 {
+		return nil
+	}
+	args := pushTimelineArgs(s.maxSize, s.expire, items)
+	execs := make([]rueidis.LuaExec, 0, len(userIds))
+	for _, userId := range userIds {
+		execs = append(execs, rueidis.LuaExec{
+			Keys: []string{timelineKey(userId)},
+			Args: args,
+		})
+	}
+	for _, res := range _pushTimelineScript.ExecMulti(context.Background(), s.c, execs...) {
+		if err := res.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redisTimelineCache) RemoveTweets(userIds []int64, tweetIds ...int64) error {
+	if len(userIds) == 0 || len(tweetIds) == 0 {
+		return nil
+	}
+	members := make([]string, 0, len(tweetIds))
+	for _, id := range tweetIds {
+		members = append(members, strconv.FormatInt(id, 10))
+	}
+	cmds := make(rueidis.Commands, 0, len(userIds))
+	for _, userId := range userIds {
+		cmds = append(cmds, s.c.B().Zrem().Key(timelineKey(userId)).Member(members...).Build())
+	}
+	for _, res := range s.c.DoMulti(context.Background(), cmds...) {
+		if err := res.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redisTimelineCache) ResetTimeline(userId int64, items []*cs.TimelineItem) error {
+	key := timelineKey(userId)
+	zadd := s.c.B().Zadd().Key(key).ScoreMember().ScoreMember(_timelineSentinelScore, _timelineSentinel)
+	for _, item := range items[:min(int64(len(items)), s.maxSize)] {
+		zadd = zadd.ScoreMember(float64(item.CreatedOn), strconv.FormatInt(item.ID, 10))
+	}
+	cmds := rueidis.Commands{
+		s.c.B().Multi().Build(),
+		s.c.B().Del().Key(key).Build(),
+		zadd.Build(),
+		s.c.B().Expire().Key(key).Seconds(s.expire).Build(),
+		s.c.B().Exec().Build(),
+	}
+	for _, res := range s.c.DoMulti(context.Background(), cmds...) {
+		if err := res.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redisTimelineCache) ListTimeline(userId int64, offset int, limit int) (items []*cs.TimelineItem, total int64, exist bool, err error) {
+	key := timelineKey(userId)
+	start, stop := timelineRange(offset, limit)
+	res := s.c.DoMulti(context.Background(),
+		s.c.B().Zscore().Key(key).Member(_timelineSentinel).Build(),
+		s.c.B().Zcard().Key(key).Build(),
+		s.c.B().Zrevrange().Key(key).Start(start).Stop(stop).Withscores().Build(),
+	)
+	if err = res[0].Error(); rueidis.IsRedisNil(err) {
+		return nil, 0, false, nil
+	} else if err != nil {
+		return
+	}
+	if total, err = res[1].AsInt64(); err != nil {
+		return
+	}
+	scores, err := res[2].AsZScores()
+	if err != nil {
+		return
+	}
+	return timelineItemsFrom(scores), total - 1, true, nil
+}
+
+func (s *redisTimelineCache) DeleteTimeline(userIds ...int64) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	cmds := make(rueidis.Commands, 0, len(userIds))
+	for _, userId := range userIds {
+		cmds = append(cmds, s.c.B().Del().Key(timelineKey(userId)).Build())
+	}
+	for _, res := range s.c.DoMulti(context.Background(), cmds...) {
+		if err := res.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushTimelineArgs _pushTimelineScript的参数：标记成员、裁剪的截止排名、过期时间及推文的分数与成员；
+// 按分数升序排名，保留排在最前的标记成员及最新的maxSize条推文
+func pushTimelineArgs(maxSize int64, expire int64, items []*cs.TimelineItem) []string {
+	args := make([]string, 0, 3+len(items)*2)
+	args = append(args, _timelineSentinel, strconv.FormatInt(-maxSize-2, 10), strconv.FormatInt(expire, 10))
+	for _, item := range items {
+		args = append(args, strconv.FormatInt(item.CreatedOn, 10), strconv.FormatInt(item.ID, 10))
+	}
+	return args
+}
+
+// timelineRange 分页对应的倒序排名范围，跳过排在最前的标记成员
+func timelineRange(offset int, limit int) (start int64, stop int64) {
+	return int64(offset) + 1, int64(offset + limit)
+}
+
+// timelineItemsFrom 转换时间线中的成员，忽略标记成员
+func timelineItemsFrom(scores []rueidis.ZScore) []*cs.TimelineItem {
+	items := make([]*cs.TimelineItem, 0, len(scores))
+	for _, score := range scores {
+		id, err := strconv.ParseInt(score.Member, 10, 64)
+		if err != nil || score.Member == _timelineSentinel {
+			continue
+		}
+		items = append(items, &cs.TimelineItem{
+			ID:        id,
+			CreatedOn: int64(score.Score),
+		})
+	}
+	return items
+}
+
+func timelineKey(userId int64) string {
+	return conf.PrefixTimeline + strconv.FormatInt(userId, 10)
+}
+
+func NewRedisTimelineCache() core.TimelineCache {
+	s := conf.FanoutTimelineSetting
+	return &redisTimelineCache{
+		c:       conf.MustRedisClient(),
+		maxSize: s.MaxSize,
+		expire:  s.Expire,
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"sort"
+	"strconv"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/redis/rueidis"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+)
+
+// sortedSet 按redis有序集合的语义模拟时间线，用于验证脚本参数与分页范围
+type sortedSet []rueidis.ZScore
+
+func (z sortedSet) sorted() sortedSet {
+	sort.SliceStable(z, func(i, j int) bool {
+		if z[i].Score != z[j].Score {
+			return z[i].Score < z[j].Score
+		}
+		return z[i].Member < z[j].Member
+	})
+	return z
+}
+
+func (z sortedSet) rank(start, stop int64) (int64, int64) {
+	n := int64(len(z))
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	return start, min(stop, n-1)
+}
+
+func (z sortedSet) zadd(score float64, member string) sortedSet {
+	for i := range z {
+		if z[i].Member == member {
+			z[i].Score = score
+			return z.sorted()
+		}
+	}
+	return append(z, rueidis.ZScore{Member: member, Score: score}).sorted()
+}
+
+func (z sortedSet) zremrangebyrank(start, stop int64) sortedSet {
+	if start, stop = z.rank(start, stop); start > stop {
+		return z
+	}
+	return append(z[:start:start], z[stop+1:]...)
+}
+
+func (z sortedSet) zrevrange(start, stop int64) []rueidis.ZScore {
+	rev := make([]rueidis.ZScore, 0, len(z))
+	for i := len(z) - 1; i >= 0; i-- {
+		rev = append(rev, z[i])
+	}
+	if start, stop = sortedSet(rev).rank(start, stop); start > stop {
+		return nil
+	}
+	return rev[start : stop+1]
+}
+
+// push 按_pushTimelineScript的步骤写入推文
+func (z sortedSet) push(args []string) sortedSet {
+	exist := false
+	for _, s := range z {
+		exist = exist || s.Member == args[0]
+	}
+	if !exist {
+		return z
+	}
+	for i := 3; i < len(args); i += 2 {
+		score, _ := strconv.ParseFloat(args[i], 64)
+		z = z.zadd(score, args[i+1])
+	}
+	stop, _ := strconv.ParseInt(args[1], 10, 64)
+	return z.zremrangebyrank(0, stop)
+}
+
+func itemIds(items []*cs.TimelineItem) []int64 {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+var _ = g.Describe("Timeline", func() {
+	newItems := func(ids ...int64) []*cs.TimelineItem {
+		items := make([]*cs.TimelineItem, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, &cs.TimelineItem{ID: id, CreatedOn: id * 100})
+		}
+		return items
+	}
+
+	g.It("build push script args", func() {
+		args := pushTimelineArgs(3, 60, newItems(7, 8))
+		m.Expect(args).To(m.Equal([]string{_timelineSentinel, "-5", "60", "700", "7", "800", "8"}))
+	})
+
+	g.It("skip timeline that not built", func() {
+		var z sortedSet
+		m.Expect(z.push(pushTimelineArgs(3, 60, newItems(1)))).To(m.BeEmpty())
+	})
+
+	g.It("trim to max size and keep sentinel", func() {
+		z := sortedSet{}.zadd(_timelineSentinelScore, _timelineSentinel)
+		for _, item := range newItems(1, 2, 3, 4, 5) {
+			z = z.push(pushTimelineArgs(3, 60, []*cs.TimelineItem{item}))
+		}
+		m.Expect(z).To(m.HaveLen(4))
+		start, stop := timelineRange(0, 10)
+		m.Expect(itemIds(timelineItemsFrom(z.zrevrange(start, stop)))).To(m.Equal([]int64{5, 4, 3}))
+	})
+
+	g.It("page timeline without sentinel", func() {
+		z := sortedSet{}.zadd(_timelineSentinelScore, _timelineSentinel)
+		z = z.push(pushTimelineArgs(10, 60, newItems(1, 2, 3, 4, 5)))
+		start, stop := timelineRange(0, 2)
+		m.Expect(itemIds(timelineItemsFrom(z.zrevrange(start, stop)))).To(m.Equal([]int64{5, 4}))
+		start, stop = timelineRange(2, 2)
+		m.Expect(itemIds(timelineItemsFrom(z.zrevrange(start, stop)))).To(m.Equal([]int64{3, 2}))
+		start, stop = timelineRange(4, 2)
+		items := timelineItemsFrom(z.zrevrange(start, stop))
+		m.Expect(itemIds(items)).To(m.Equal([]int64{1}))
+		m.Expect(items[0].CreatedOn).To(m.Equal(int64(100)))
+		start, stop = timelineRange(6, 2)
+		m.Expect(timelineItemsFrom(z.zrevrange(start, stop))).To(m.BeEmpty())
+	})
+})
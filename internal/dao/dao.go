@@ -5,6 +5,8 @@
 package dao
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/alimy/tryst/cfg"
@@ -41,6 +43,25 @@ func ObjectStorageService() core.ObjectStorageService {
 	return oss
 }
 
+// NewObjectStorageService 按名称创建对象存储服务，名称同对应的功能项，如AliOSS、MinIO等
+func NewObjectStorageService(name string) (core.ObjectStorageService, error) {
+	newFns := map[string]func() (core.ObjectStorageService, core.VersionInfo){
+		"alioss":    storage.MustAliossService,
+		"cos":       storage.NewCosService,
+		"huaweiobs": storage.MustHuaweiobsService,
+		"minio":     storage.MustMinioService,
+		"s3":        storage.MustS3Service,
+		"nativeobs": storage.MustNativeobsService,
+		"localoss":  storage.MustLocalossService,
+	}
+	newFn, exist := newFns[strings.ToLower(name)]
+	if !exist {
+		return nil, fmt.Errorf("unknown object storage: %s", name)
+	}
+	s, _ := newFn()
+	return s, nil
+}
+
 func TweetSearchService() core.TweetSearchService {
 	lazyInitial()
 	return ts
@@ -64,6 +85,8 @@ func lazyInitial() {
 	_onceInitial.Do(func() {
 		initDsX()
 		initOSS()
+		// 使用内容去重时，对象在最后一个引用释放后才删除
+		oss = storage.NewBlobGuardService(oss, ds)
 		initTsX()
 	})
 }
@@ -102,6 +125,8 @@ func initOSS() {
 		oss, v = storage.MustS3Service()
 		logrus.Infof("use S3 as object storage by version %s", v.Version())
 		return
+	} else if cfg.If("NativeOBS") {
+		oss, v = storage.MustNativeobsService()
 	} else if cfg.If("LocalOSS") {
 		oss, v = storage.MustLocalossService()
 	} else {
@@ -5,6 +5,8 @@
 package jinzhu
 
 import (
+	"time"
+
 	"github.com/rocboss/paopao-ce/internal/core"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
@@ -64,6 +66,11 @@ func (s *authorizationManageSrv) BeFriendIds(userId int64) ([]int64, error) {
 	return (&dbr.Contact{FriendId: userId}).BeFriendIds(s.db)
 }
 
+// SubscribedIds 用户当前有效订阅的创作者
+func (s *authorizationManageSrv) SubscribedIds(userId int64) ([]int64, error) {
+	return (&dbr.Subscription{UserID: userId}).ActiveCreatorIds(s.db, time.Now().Unix())
+}
+
 func (s *authorizationManageSrv) isFriend(userId int64, friendId int64) bool {
 	contact, err := (&dbr.Contact{UserId: friendId, FriendId: userId}).GetByUserFriend(s.db)
 	if err == nil || contact.Status == dbr.ContactStatusAgree {
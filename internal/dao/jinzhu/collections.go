@@ -0,0 +1,115 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ core.CollectionFolderService = (*collectionFolderSrv)(nil)
+)
+
+type collectionFolderSrv struct {
+	db *gorm.DB
+}
+
+func newCollectionFolderService(db *gorm.DB) core.CollectionFolderService {
+	return &collectionFolderSrv{
+		db: db,
+	}
+}
+
+func (s *collectionFolderSrv) CreateCollectionFolder(folder *ms.CollectionFolder) (*ms.CollectionFolder, error) {
+	return folder.Create(s.db)
+}
+
+func (s *collectionFolderSrv) GetCollectionFolder(id int64) (*ms.CollectionFolder, error) {
+	folder := &dbr.CollectionFolder{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return folder.Get(s.db)
+}
+
+func (s *collectionFolderSrv) GetCollectionFolderByShareKey(shareKey string) (*ms.CollectionFolder, error) {
+	folder := &dbr.CollectionFolder{
+		ShareKey: shareKey,
+	}
+	return folder.Get(s.db)
+}
+
+// ListCollectionFolders 用户的收藏夹及其中的收藏数
+func (s *collectionFolderSrv) ListCollectionFolders(userId int64) ([]*ms.CollectionFolderFormated, error) {
+	folders, err := (&dbr.CollectionFolder{UserID: userId}).List(s.db)
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		FolderID int64
+		Total    int64
+	}
+	if err = s.db.Model(&dbr.PostCollection{}).Select("folder_id, count(*) as total").
+		Where("user_id = ? AND is_del = 0", userId).Group("folder_id").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	countMap := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		countMap[c.FolderID] = c.Total
+	}
+	res := make([]*ms.CollectionFolderFormated, 0, len(folders))
+	for _, folder := range folders {
+		item := folder.Format()
+		item.ItemCount = countMap[folder.ID]
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (s *collectionFolderSrv) UpdateCollectionFolder(folder *ms.CollectionFolder) error {
+	return folder.Update(s.db)
+}
+
+func (s *collectionFolderSrv) DeleteCollectionFolder(folder *ms.CollectionFolder) error {
+	return folder.Delete(s.db)
+}
+
+// MovePostCollections 将用户收藏的推文移动到指定收藏夹
+func (s *collectionFolderSrv) MovePostCollections(userId int64, folderId int64, postIds []int64) error {
+	return s.db.Model(&dbr.PostCollection{}).Where("user_id = ? AND post_id IN ? AND is_del = 0", userId, postIds).
+		Update("folder_id", folderId).Error
+}
+
+// UpdatePostCollectionNote 更新收藏的私人备注
+func (s *collectionFolderSrv) UpdatePostCollectionNote(userId int64, postId int64, note string) error {
+	db := s.db.Model(&dbr.PostCollection{}).Where("user_id = ? AND post_id = ? AND is_del = 0", userId, postId).
+		Update("note", note)
+	if db.Error == nil && db.RowsAffected == 0 {
+		return cs.ErrNotExist
+	}
+	return db.Error
+}
+
+// ListSharedPostCollections 分享的收藏夹中的公开推文
+func (s *collectionFolderSrv) ListSharedPostCollections(folder *ms.CollectionFolder, limit, offset int) (res []*ms.PostCollection, total int64, err error) {
+	tn := s.db.NamingStrategy.TableName("PostCollection") + "."
+	db := s.db.Model(&dbr.PostCollection{}).Joins("Post").
+		Where(tn+"user_id = ? AND "+tn+"folder_id = ? AND "+tn+"is_del = 0", folder.UserID, folder.ID).
+		Where("? = ? AND ? = 0", clause.Column{Table: "Post", Name: "visibility"}, dbr.PostVisitPublic, clause.Column{Table: "Post", Name: "is_del"})
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	err = db.Order(tn + "id DESC").Find(&res).Error
+	return
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/rocboss/paopao-ce/pkg/types"
 	"gorm.io/gorm"
 )
@@ -84,6 +85,16 @@ func (s *commentSrv) GetComments(tweetId int64, style cs.StyleCommentType, limit
 	return
 }
 
+// GetCommentsByCursor 按发布时间排序的评论，最新排序时倒序，其余按默认的正序，不区分精选
+func (s *commentSrv) GetCommentsByCursor(tweetId int64, style cs.StyleCommentType, c *cs.Cursor, limit int) (res []*ms.Comment, next *cs.Cursor, err error) {
+	db := s.db.Table(_comment_).Where("post_id=?", tweetId)
+	if err = dbr.KeysetPage(db, "", c, style == cs.StyleCommentNewest, limit).Find(&res).Error; err != nil {
+		return
+	}
+	res, next = cursor.Next(res, limit, (*ms.Comment).Cursor)
+	return
+}
+
 func (s *commentSrv) GetCommentByID(id int64) (*ms.Comment, error) {
 	comment := &dbr.Comment{
 		Model: &dbr.Model{
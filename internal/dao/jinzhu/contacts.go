@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -260,6 +262,33 @@ func (s *contactManageSrv) GetContacts(userId int64, offset int, limit int) (*ms
 	return resp, nil
 }
 
+func (s *contactManageSrv) GetContactsByCursor(userId int64, c *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error) {
+	contacts, err := (&dbr.Contact{}).ListByCursor(s.db, dbr.ConditionsT{
+		"user_id": userId,
+		"status":  dbr.ContactStatusAgree,
+	}, c, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	contacts, next := cursor.Next(contacts, limit, (*dbr.Contact).Cursor)
+	resp := &ms.ContactList{
+		Contacts: make([]ms.ContactItem, 0, len(contacts)),
+	}
+	for _, contact := range contacts {
+		if contact.User != nil {
+			resp.Contacts = append(resp.Contacts, ms.ContactItem{
+				UserId:    contact.FriendId,
+				Username:  contact.User.Username,
+				Nickname:  contact.User.Nickname,
+				Avatar:    contact.User.Avatar,
+				Phone:     contact.User.Phone,
+				CreatedOn: contact.User.CreatedOn,
+			})
+		}
+	}
+	return resp, next, nil
+}
+
 func (s *contactManageSrv) IsFriend(userId int64, friendId int64) bool {
 	contact := &dbr.Contact{
 		UserId:   friendId,
@@ -4,24 +4,119 @@
 
 package dbr
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"gorm.io/gorm"
+)
 
 type AttachmentType int
 
+// 媒体处理状态，0就绪，1等待处理，2处理中，3处理失败
+type MediaStatus int8
+
 const (
 	AttachmentTypeImage AttachmentType = iota + 1
 	AttachmentTypeVideo
 	AttachmentTypeOther
 )
 
+const (
+	MediaStatusReady MediaStatus = iota
+	MediaStatusPending
+	MediaStatusProcessing
+	MediaStatusFailed
+)
+
 type Attachment struct {
 	*Model
-	UserID    int64          `json:"user_id"`
-	FileSize  int64          `json:"file_size"`
-	ImgWidth  int            `json:"img_width"`
-	ImgHeight int            `json:"img_height"`
-	Type      AttachmentType `json:"type"`
-	Content   string         `json:"content"`
+	UserID        int64          `json:"user_id"`
+	FileSize      int64          `json:"file_size"`
+	ImgWidth      int            `json:"img_width"`
+	ImgHeight     int            `json:"img_height"`
+	Type          AttachmentType `json:"type"`
+	Content       string         `json:"content"`
+	Blurhash      string         `json:"blurhash"`
+	DominantColor string         `json:"dominant_color"`
+	Variants      string         `json:"-"`
+	Duration      int64          `json:"duration"`
+	Poster        string         `json:"poster"`
+	Playlist      string         `json:"playlist"`
+	Status        MediaStatus    `json:"status"`
+}
+
+// ImageVariant 图片的缩略图/WebP等版本
+type ImageVariant struct {
+	Name        string `json:"name"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+// AttachmentImageFormated 图片附件的尺寸、占位信息及各版本，供前端使用srcset
+type AttachmentImageFormated struct {
+	Width         int             `json:"width"`
+	Height        int             `json:"height"`
+	Blurhash      string          `json:"blurhash,omitempty"`
+	DominantColor string          `json:"dominant_color,omitempty"`
+	Variants      []*ImageVariant `json:"variants,omitempty"`
+}
+
+// AttachmentVideoFormated 视频附件的尺寸、时长、封面及HLS播放列表，处理完成前只有处理状态
+type AttachmentVideoFormated struct {
+	Width    int         `json:"width"`
+	Height   int         `json:"height"`
+	Duration int64       `json:"duration"`
+	Poster   string      `json:"poster,omitempty"`
+	Playlist string      `json:"playlist,omitempty"`
+	Status   MediaStatus `json:"status"`
+}
+
+// SetVariants 设置图片的各版本
+func (a *Attachment) SetVariants(variants []*ImageVariant) {
+	if len(variants) == 0 {
+		a.Variants = ""
+		return
+	}
+	data, _ := json.Marshal(variants)
+	a.Variants = string(data)
+}
+
+// ImageVariants 获取图片的各版本
+func (a *Attachment) ImageVariants() (variants []*ImageVariant) {
+	if a.Variants != "" {
+		json.Unmarshal([]byte(a.Variants), &variants)
+	}
+	return
+}
+
+func (a *Attachment) ImageFormat() *AttachmentImageFormated {
+	if a.Model == nil || a.Type != AttachmentTypeImage {
+		return nil
+	}
+	return &AttachmentImageFormated{
+		Width:         a.ImgWidth,
+		Height:        a.ImgHeight,
+		Blurhash:      a.Blurhash,
+		DominantColor: a.DominantColor,
+		Variants:      a.ImageVariants(),
+	}
+}
+
+func (a *Attachment) VideoFormat() *AttachmentVideoFormated {
+	if a.Model == nil || a.Type != AttachmentTypeVideo {
+		return nil
+	}
+	return &AttachmentVideoFormated{
+		Width:    a.ImgWidth,
+		Height:   a.ImgHeight,
+		Duration: a.Duration,
+		Poster:   a.Poster,
+		Playlist: a.Playlist,
+		Status:   a.Status,
+	}
 }
 
 func (a *Attachment) Create(db *gorm.DB) (*Attachment, error) {
@@ -29,3 +124,72 @@ func (a *Attachment) Create(db *gorm.DB) (*Attachment, error) {
 
 	return a, err
 }
+
+func (a *Attachment) ListByContents(db *gorm.DB, contents []string) (res []*Attachment, err error) {
+	err = db.Model(a).Where("content IN ? AND is_del = 0", contents).Find(&res).Error
+	return
+}
+
+// deleteOneByContent 删除用户一个内容对应的附件，用于去重的对象仍被其他上传引用时
+func (a *Attachment) deleteOneByContent(db *gorm.DB, content string) error {
+	var ids []int64
+	err := db.Model(&Attachment{}).Where("user_id = ? AND content = ? AND is_del = 0", a.UserID, content).Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return db.Model(&Attachment{}).Where("id = ?", ids[0]).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
+
+// DeleteByContents 删除内容对应的附件，释放占用的存储配额
+func (a *Attachment) DeleteByContents(db *gorm.DB, contents []string) error {
+	return db.Model(&Attachment{}).Where("content IN ? AND is_del = 0", contents).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
+
+// UsageOf 用户未删除附件的总大小及文件数
+func (a *Attachment) UsageOf(db *gorm.DB, userId int64) (*StorageUsage, error) {
+	var usage StorageUsage
+	err := db.Model(&Attachment{}).Select("COALESCE(SUM(file_size), 0) AS used_size, COUNT(*) AS used_files").
+		Where("user_id = ? AND is_del = 0", userId).Scan(&usage).Error
+	return &usage, err
+}
+
+// ListPendingMedia 获取等待处理的媒体附件，处理中但超时未完成的也会重新处理
+func (a *Attachment) ListPendingMedia(db *gorm.DB, staleBefore int64, limit int) (res []*Attachment, err error) {
+	err = db.Model(a).Where("(status = ? OR (status = ? AND modified_on < ?)) AND is_del = 0", MediaStatusPending, MediaStatusProcessing, staleBefore).
+		Order("id ASC").Limit(limit).Find(&res).Error
+	return
+}
+
+// Claim 以修改时间为版本号将媒体附件标记为处理中，返回是否成功领取
+func (a *Attachment) Claim(db *gorm.DB) (bool, error) {
+	nowTime := time.Now().Unix()
+	res := db.Model(&Attachment{}).Where("id = ? AND status = ? AND modified_on = ? AND is_del = 0", a.ID, a.Status, a.ModifiedOn).
+		Updates(map[string]any{
+			"status":      MediaStatusProcessing,
+			"modified_on": nowTime,
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	a.Status, a.ModifiedOn = MediaStatusProcessing, nowTime
+	return true, nil
+}
+
+// UpdateMedia 更新媒体附件的处理结果
+func (a *Attachment) UpdateMedia(db *gorm.DB) error {
+	return db.Model(&Attachment{}).Where("id = ? AND is_del = 0", a.ID).Updates(map[string]any{
+		"img_width":  a.ImgWidth,
+		"img_height": a.ImgHeight,
+		"duration":   a.Duration,
+		"poster":     a.Poster,
+		"playlist":   a.Playlist,
+		"variants":   a.Variants,
+		"status":     a.Status,
+	}).Error
+}
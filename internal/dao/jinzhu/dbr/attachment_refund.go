@@ -0,0 +1,94 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"gorm.io/gorm"
+)
+
+// RefundStatus 附件退款状态
+type RefundStatus int8
+
+const (
+	RefundStatusPending RefundStatus = iota
+	RefundStatusRefunded
+	RefundStatusRejected
+	RefundStatusDisputed
+)
+
+// AttachmentRefund 收费附件退款申请，作者拒绝后购买者可申诉由管理员处理
+type AttachmentRefund struct {
+	*Model
+	BillID     int64        `json:"bill_id"`
+	PostID     int64        `json:"post_id"`
+	UserID     int64        `json:"user_id"`
+	AuthorID   int64        `json:"author_id"`
+	Amount     int64        `json:"amount"`
+	Reason     string       `json:"reason"`
+	Status     RefundStatus `json:"status"`
+	ReviewerID int64        `json:"reviewer_id"`
+	Remark     string       `json:"remark"`
+	ReviewedOn int64        `json:"reviewed_on"`
+}
+
+func (r *AttachmentRefund) Create(db *gorm.DB) (*AttachmentRefund, error) {
+	err := db.Create(&r).Error
+	return r, err
+}
+
+func (r *AttachmentRefund) Get(db *gorm.DB) (*AttachmentRefund, error) {
+	var refund AttachmentRefund
+	if r.Model != nil && r.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", r.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&refund).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// GetByBill 购买记录的退款申请，不存在时返回nil
+func (r *AttachmentRefund) GetByBill(db *gorm.DB) (*AttachmentRefund, error) {
+	var res []*AttachmentRefund
+	err := db.Where("bill_id = ? AND is_del = 0", r.BillID).Limit(1).Find(&res).Error
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// UpdateStatus 以当前状态作为条件更新，避免并发处理时重复退款
+func (r *AttachmentRefund) UpdateStatus(db *gorm.DB, from RefundStatus) (bool, error) {
+	res := db.Model(&AttachmentRefund{}).Where("id = ? AND status = ? AND is_del = 0", r.ID, from).
+		Updates(map[string]any{
+			"status":      r.Status,
+			"reason":      r.Reason,
+			"reviewer_id": r.ReviewerID,
+			"remark":      r.Remark,
+			"reviewed_on": r.ReviewedOn,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// List userId、authorId为0时不限购买者、作者，status小于0时不限状态
+func (r *AttachmentRefund) List(db *gorm.DB, status RefundStatus, offset, limit int) (res []*AttachmentRefund, total int64, err error) {
+	db = db.Model(&AttachmentRefund{}).Where("is_del = 0")
+	if r.UserID > 0 {
+		db = db.Where("user_id = ?", r.UserID)
+	}
+	if r.AuthorID > 0 {
+		db = db.Where("author_id = ?", r.AuthorID)
+	}
+	if status >= 0 {
+		db = db.Where("status = ?", status)
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return
+}
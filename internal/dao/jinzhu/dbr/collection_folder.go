@@ -0,0 +1,95 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// CollectionFolderAll 全部收藏
+	CollectionFolderAll int64 = -1
+	// CollectionFolderDefault 未归入收藏夹的收藏
+	CollectionFolderDefault int64 = 0
+)
+
+// CollectionFolder 收藏夹，ShareKey非空时可通过分享链接公开访问
+type CollectionFolder struct {
+	*Model
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	ShareKey string `json:"share_key"`
+}
+
+type CollectionFolderFormated struct {
+	ID         int64  `json:"id"`
+	UserID     int64  `json:"user_id"`
+	Name       string `json:"name"`
+	ShareKey   string `json:"share_key,omitempty"`
+	ItemCount  int64  `json:"item_count"`
+	CreatedOn  int64  `json:"created_on"`
+	ModifiedOn int64  `json:"modified_on"`
+}
+
+func (f *CollectionFolder) Format() *CollectionFolderFormated {
+	if f.Model == nil {
+		return &CollectionFolderFormated{}
+	}
+	return &CollectionFolderFormated{
+		ID:         f.ID,
+		UserID:     f.UserID,
+		Name:       f.Name,
+		ShareKey:   f.ShareKey,
+		CreatedOn:  f.CreatedOn,
+		ModifiedOn: f.ModifiedOn,
+	}
+}
+
+func (f *CollectionFolder) Get(db *gorm.DB) (*CollectionFolder, error) {
+	var folder CollectionFolder
+	if f.Model != nil && f.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", f.ID, 0)
+	}
+	if f.ShareKey != "" {
+		db = db.Where("share_key = ? AND is_del = ?", f.ShareKey, 0)
+	}
+	if err := db.First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (f *CollectionFolder) Create(db *gorm.DB) (*CollectionFolder, error) {
+	err := db.Create(&f).Error
+	return f, err
+}
+
+func (f *CollectionFolder) Update(db *gorm.DB) error {
+	return db.Model(&CollectionFolder{}).Where("id = ? AND is_del = ?", f.ID, 0).Updates(map[string]any{
+		"name":      f.Name,
+		"share_key": f.ShareKey,
+	}).Error
+}
+
+// Delete 删除收藏夹，其中的收藏移回未归入收藏夹的收藏
+func (f *CollectionFolder) Delete(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&PostCollection{}).Where("folder_id = ?", f.ID).Update("folder_id", CollectionFolderDefault).Error; err != nil {
+			return err
+		}
+		return tx.Model(&CollectionFolder{}).Where("id = ? AND is_del = ?", f.ID, 0).Updates(map[string]any{
+			"deleted_on": time.Now().Unix(),
+			"is_del":     1,
+		}).Error
+	})
+}
+
+// List 用户的收藏夹，按创建时间排序
+func (f *CollectionFolder) List(db *gorm.DB) (res []*CollectionFolder, err error) {
+	err = db.Where("user_id = ? AND is_del = ?", f.UserID, 0).Order("id ASC").Find(&res).Error
+	return
+}
@@ -50,9 +50,18 @@ func (c *CommentContent) Create(db *gorm.DB) (*CommentContent, error) {
 	return c, err
 }
 
-func (c *CommentContent) MediaContentsByCommentId(db *gorm.DB, commentIds []int64) (contents []string, err error) {
-	err = db.Model(c).Where("comment_id IN ? AND type = ?", commentIds, ContentTypeImage).Select("content").Find(&contents).Error
-	return
+// MediaContentsByCommentId 评论的媒体内容，按评论者分组
+func (c *CommentContent) MediaContentsByCommentId(db *gorm.DB, commentIds []int64) (map[int64][]string, error) {
+	var items []*CommentContent
+	err := db.Model(c).Where("comment_id IN ? AND type = ?", commentIds, ContentTypeImage).Select("user_id", "content").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[int64][]string)
+	for _, item := range items {
+		contents[item.UserID] = append(contents[item.UserID], item.Content)
+	}
+	return contents, nil
 }
 
 func (c *CommentContent) DeleteByCommentIds(db *gorm.DB, commentIds []int64) error {
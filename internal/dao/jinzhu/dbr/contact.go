@@ -5,6 +5,7 @@
 package dbr
 
 import (
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -60,6 +61,18 @@ func (c *Contact) FetchByUserFriendAll(db *gorm.DB) ([]*Contact, error) {
 	return contacts, nil
 }
 
+// ListByCursor 按成为联系人的时间倒序的联系人
+func (c *Contact) ListByCursor(db *gorm.DB, conditions ConditionsT, cur *cursor.Cursor, limit int) (contacts []*Contact, err error) {
+	tn := db.NamingStrategy.TableName("Contact")
+	for k, v := range conditions {
+		if k != "ORDER" {
+			db = db.Where(tn+"."+k, v)
+		}
+	}
+	err = KeysetPage(db.Joins("User"), tn, cur, true, limit).Find(&contacts).Error
+	return
+}
+
 func (c *Contact) List(db *gorm.DB, conditions ConditionsT, offset, limit int) ([]*Contact, error) {
 	var contacts []*Contact
 	var err error
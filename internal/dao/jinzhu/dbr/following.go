@@ -5,6 +5,7 @@
 package dbr
 
 import (
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -46,6 +47,20 @@ func (f *Following) ListFollows(db *gorm.DB, userId int64, limit int, offset int
 	return
 }
 
+// ListFollowsByCursor 按关注时间倒序的关注记录
+func (f *Following) ListFollowsByCursor(db *gorm.DB, userId int64, c *cursor.Cursor, limit int) (res []*Following, err error) {
+	db = db.Model(f).Joins("User").Where(db.NamingStrategy.TableName("Following")+".user_id=?", userId)
+	err = KeysetPage(db, db.NamingStrategy.TableName("Following"), c, true, limit).Find(&res).Error
+	return
+}
+
+// ListFollowingsByCursor 按关注时间倒序的粉丝记录
+func (f *Following) ListFollowingsByCursor(db *gorm.DB, userId int64, c *cursor.Cursor, limit int) (res []*Following, err error) {
+	db = db.Model(f).Omit("User").Where("follow_id=?", userId)
+	err = KeysetPage(db, "", c, true, limit).Find(&res).Error
+	return
+}
+
 func (f *Following) ListFollowingIds(db *gorm.DB, userId int64, limit, offset int) (ids []int64, total int64, err error) {
 	db = db.Model(f).Where("follow_id=?", userId)
 	if err = db.Count(&total).Error; err != nil {
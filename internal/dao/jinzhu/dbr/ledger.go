@@ -0,0 +1,92 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerAccount 复式记账账户，余额为该账户所有分录金额之和，所有账户余额之和恒为0
+type LedgerAccount struct {
+	*Model
+	Code    string `json:"code"`
+	UserID  int64  `json:"user_id"`
+	Balance int64  `json:"balance"`
+	Version int64  `json:"version"`
+}
+
+// LedgerJournal 记账凭证，相同幂等键的业务只记账一次
+type LedgerJournal struct {
+	*Model
+	IdempotencyKey string `json:"idempotency_key"`
+	Kind           string `json:"kind"`
+	Memo           string `json:"memo"`
+}
+
+// LedgerEntry 记账分录，同一凭证的分录金额之和为0
+type LedgerEntry struct {
+	*Model
+	JournalID       int64 `json:"journal_id"`
+	AccountID       int64 `json:"account_id"`
+	Amount          int64 `json:"amount"`
+	BalanceSnapshot int64 `json:"balance_snapshot"`
+}
+
+func (a *LedgerAccount) Create(db *gorm.DB) (*LedgerAccount, error) {
+	err := db.Create(&a).Error
+	return a, err
+}
+
+// GetByCode 按编码获取账户，不存在时返回nil
+func (a *LedgerAccount) GetByCode(db *gorm.DB) (*LedgerAccount, error) {
+	var accounts []*LedgerAccount
+	if err := db.Where("code = ? AND is_del = 0", a.Code).Limit(1).Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	return accounts[0], nil
+}
+
+// LockByCode 按编码获取并锁定账户直到事务结束，不存在时返回nil
+func (a *LedgerAccount) LockByCode(db *gorm.DB) (*LedgerAccount, error) {
+	return a.GetByCode(db.Clauses(clause.Locking{Strength: "UPDATE"}))
+}
+
+// UpdateBalance 以版本号作为条件更新余额，版本已变更时返回false
+func (a *LedgerAccount) UpdateBalance(db *gorm.DB, balance int64) (bool, error) {
+	res := db.Model(&LedgerAccount{}).Where("id = ? AND version = ? AND is_del = 0", a.ID, a.Version).
+		Updates(map[string]any{
+			"balance":     balance,
+			"version":     a.Version + 1,
+			"modified_on": time.Now().Unix(),
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	a.Balance, a.Version = balance, a.Version+1
+	return true, nil
+}
+
+func (j *LedgerJournal) Create(db *gorm.DB) (*LedgerJournal, error) {
+	err := db.Create(&j).Error
+	return j, err
+}
+
+// Exist 幂等键是否已经记账
+func (j *LedgerJournal) Exist(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Model(&LedgerJournal{}).Where("idempotency_key = ? AND is_del = 0", j.IdempotencyKey).Count(&count).Error
+	return count > 0, err
+}
+
+func (e *LedgerEntry) Create(db *gorm.DB) (*LedgerEntry, error) {
+	err := db.Create(&e).Error
+	return e, err
+}
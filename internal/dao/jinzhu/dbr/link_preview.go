@@ -0,0 +1,92 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"gorm.io/gorm"
+)
+
+// 链接预览抓取状态，0成功，1失败
+type LinkPreviewStatus int8
+
+const (
+	LinkPreviewStatusSuccess LinkPreviewStatus = iota
+	LinkPreviewStatusFailed
+)
+
+// LinkPreview 链接预览缓存，以链接地址为键
+type LinkPreview struct {
+	*Model
+	URLHash     string            `json:"url_hash"`
+	URL         string            `json:"url"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	SiteName    string            `json:"site_name"`
+	Status      LinkPreviewStatus `json:"status"`
+	FetchedOn   int64             `json:"fetched_on"`
+}
+
+type LinkPreviewFormated struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+	SiteName    string `json:"site_name"`
+}
+
+// LinkURLHash 链接地址的SHA256
+func LinkURLHash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetURL 设置链接地址及其SHA256
+func (p *LinkPreview) SetURL(url string) {
+	p.URL, p.URLHash = url, LinkURLHash(url)
+}
+
+func (p *LinkPreview) Format() *LinkPreviewFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &LinkPreviewFormated{
+		URL:         p.URL,
+		Title:       p.Title,
+		Description: p.Description,
+		Image:       p.Image,
+		SiteName:    p.SiteName,
+	}
+}
+
+func (p *LinkPreview) Create(db *gorm.DB) (*LinkPreview, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *LinkPreview) Get(db *gorm.DB) (*LinkPreview, error) {
+	var preview LinkPreview
+	if p.URLHash != "" {
+		db = db.Where("url_hash = ? AND is_del = ?", p.URLHash, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&preview).Error; err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+func (p *LinkPreview) Update(db *gorm.DB) error {
+	return db.Model(&LinkPreview{}).Where("id = ? AND is_del = ?", p.Model.ID, 0).Save(p).Error
+}
+
+func (p *LinkPreview) ListByHashes(db *gorm.DB, hashes []string) (res []*LinkPreview, err error) {
+	err = db.Where("url_hash IN ? AND status = ? AND is_del = ?", hashes, LinkPreviewStatusSuccess, 0).Find(&res).Error
+	return
+}
@@ -5,8 +5,10 @@
 package dbr
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
@@ -38,3 +40,29 @@ func (m *Model) BeforeUpdate(tx *gorm.DB) (err error) {
 
 	return
 }
+
+// Cursor 以该记录为上一页最后一条记录的游标
+func (m *Model) Cursor() *cursor.Cursor {
+	return &cursor.Cursor{
+		CreatedOn: m.CreatedOn,
+		ID:        m.ID,
+	}
+}
+
+// KeysetPage 按(created_on, id)键集分页，table为列名前缀，c为空或为第一页的游标时从第一条开始，
+// 多取一条记录用于判断是否还有下一页
+func KeysetPage(db *gorm.DB, table string, c *cursor.Cursor, desc bool, limit int) *gorm.DB {
+	createdOn, id := "created_on", "id"
+	if table != "" {
+		createdOn, id = table+".created_on", table+".id"
+	}
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+	if c != nil && !c.IsStart() {
+		query := fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", createdOn, op, createdOn, id, op)
+		db = db.Where(query, c.CreatedOn, c.CreatedOn, c.ID)
+	}
+	return db.Order(fmt.Sprintf("%s %s, %s %s", createdOn, order, id, order)).Limit(limit + 1)
+}
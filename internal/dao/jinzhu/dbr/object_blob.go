@@ -0,0 +1,100 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ObjectBlob 按内容SHA256去重的对象，相同内容的上传共用一个对象，引用计数为上传次数
+type ObjectBlob struct {
+	*Model
+	Sha256     string `json:"sha256"`
+	UploadType string `json:"upload_type"`
+	Content    string `json:"content"`
+	FileSize   int64  `json:"file_size"`
+	RefCount   int64  `json:"ref_count"`
+}
+
+func (b *ObjectBlob) Create(db *gorm.DB) (*ObjectBlob, error) {
+	err := db.Create(&b).Error
+	return b, err
+}
+
+// Get 获取仍被引用的对象，并发上传时可能存在多个相同内容的对象，使用最早的一个
+func (b *ObjectBlob) Get(db *gorm.DB) (*ObjectBlob, error) {
+	var blob ObjectBlob
+	if b.Sha256 != "" {
+		db = db.Where("sha256 = ? AND upload_type = ? AND ref_count > 0 AND is_del = 0", b.Sha256, b.UploadType)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.Order("id ASC").First(&blob).Error; err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// Acquire 增加引用计数，对象已释放时返回false
+func (b *ObjectBlob) Acquire(db *gorm.DB) (bool, error) {
+	res := db.Model(&ObjectBlob{}).Where("id = ? AND ref_count > 0 AND is_del = 0", b.ID).
+		Update("ref_count", gorm.Expr("ref_count + 1"))
+	return res.RowsAffected > 0, res.Error
+}
+
+// Release 每个内容释放一次引用，返回不再被引用的内容；非去重的内容直接视为不再被引用。
+// 仍被引用时删除该用户的一个附件记录以释放其存储配额
+func (b *ObjectBlob) Release(db *gorm.DB, userId int64, contents []string) ([]string, error) {
+	released := make([]string, 0, len(contents))
+	for _, content := range slices.Compact(slices.Sorted(slices.Values(contents))) {
+		var blob ObjectBlob
+		res := db.Where("content = ? AND is_del = 0", content).Limit(1).Find(&blob)
+		if res.Error != nil {
+			return nil, res.Error
+		} else if res.RowsAffected == 0 {
+			released = append(released, content)
+			continue
+		}
+		res = db.Model(&ObjectBlob{}).Where("id = ? AND ref_count > 1 AND is_del = 0", blob.ID).
+			Update("ref_count", gorm.Expr("ref_count - 1"))
+		if res.Error != nil {
+			return nil, res.Error
+		} else if res.RowsAffected > 0 {
+			if err := (&Attachment{UserID: userId}).deleteOneByContent(db, content); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// 最后一个引用，同时并发释放时只有一方删除对象
+		res = db.Model(&ObjectBlob{}).Where("id = ? AND is_del = 0", blob.ID).Updates(map[string]any{
+			"ref_count":  0,
+			"deleted_on": time.Now().Unix(),
+			"is_del":     1,
+		})
+		if res.Error != nil {
+			return nil, res.Error
+		} else if res.RowsAffected > 0 {
+			released = append(released, content)
+		}
+	}
+	return released, nil
+}
+
+// Referenced 仍被引用的内容
+func (b *ObjectBlob) Referenced(db *gorm.DB, contents []string) (res []string, err error) {
+	err = db.Model(&ObjectBlob{}).Where("content IN ? AND ref_count > 0 AND is_del = 0", contents).Pluck("content", &res).Error
+	return
+}
+
+func (b *ObjectBlob) DeleteByContents(db *gorm.DB, contents []string) error {
+	return db.Model(&ObjectBlob{}).Where("content IN ? AND is_del = 0", contents).Updates(map[string]any{
+		"ref_count":  0,
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
@@ -19,6 +19,8 @@ const (
 	PostVisitPrivate   PostVisibleT = 0
 	PostVisitFriend    PostVisibleT = 50
 	PostVisitFollowing PostVisibleT = 60
+	PostVisitSubscribe PostVisibleT = 20
+	PostVisitCharge    PostVisibleT = 10
 )
 
 type PostByMedia = Post
@@ -41,6 +43,7 @@ type Post struct {
 	AttachmentPrice int64        `json:"attachment_price"`
 	IP              string       `json:"ip"`
 	IPLoc           string       `json:"ip_loc"`
+	EditedOn        int64        `json:"edited_on"`
 }
 
 type PostFormated struct {
@@ -59,9 +62,12 @@ type PostFormated struct {
 	LatestRepliedOn int64                  `json:"latest_replied_on"`
 	CreatedOn       int64                  `json:"created_on"`
 	ModifiedOn      int64                  `json:"modified_on"`
+	EditedOn        int64                  `json:"edited_on"`
 	Tags            map[string]int8        `json:"tags"`
 	AttachmentPrice int64                  `json:"attachment_price"`
 	IPLoc           string                 `json:"ip_loc"`
+	Poll            *PostPollFormated      `json:"poll,omitempty"`
+	ChargeLocked    bool                   `json:"charge_locked,omitempty"`
 }
 
 func (t PostVisibleT) ToOutValue() (res uint8) {
@@ -74,6 +80,10 @@ func (t PostVisibleT) ToOutValue() (res uint8) {
 		res = 2
 	case PostVisitFollowing:
 		res = 3
+	case PostVisitSubscribe:
+		res = 4
+	case PostVisitCharge:
+		res = 5
 	default:
 		res = 1
 	}
@@ -102,6 +112,7 @@ func (p *Post) Format() *PostFormated {
 			LatestRepliedOn: p.LatestRepliedOn,
 			CreatedOn:       p.CreatedOn,
 			ModifiedOn:      p.ModifiedOn,
+			EditedOn:        p.EditedOn,
 			AttachmentPrice: p.AttachmentPrice,
 			Tags:            tagsMap,
 			IPLoc:           p.IPLoc,
@@ -227,6 +238,10 @@ func (p PostVisibleT) String() string {
 		return "private"
 	case PostVisitFriend:
 		return "friend"
+	case PostVisitSubscribe:
+		return "subscribe"
+	case PostVisitCharge:
+		return "charge"
 	default:
 		return "unknow"
 	}
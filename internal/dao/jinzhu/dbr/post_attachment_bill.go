@@ -4,7 +4,11 @@
 
 package dbr
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type PostAttachmentBill struct {
 	*Model
@@ -38,3 +42,29 @@ func (p *PostAttachmentBill) Create(db *gorm.DB) (*PostAttachmentBill, error) {
 
 	return p, err
 }
+
+// Refund 退款后删除购买记录，已退款时返回false
+func (p *PostAttachmentBill) Refund(db *gorm.DB) (bool, error) {
+	res := db.Model(&PostAttachmentBill{}).Where("id = ? AND is_del = 0", p.ID).
+		Updates(map[string]any{
+			"deleted_on": time.Now().Unix(),
+			"is_del":     1,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Generation 用户此前购买同一附件的次数，包括已退款删除的购买记录
+func (p *PostAttachmentBill) Generation(db *gorm.DB) (res int64, err error) {
+	db = db.Unscoped().Model(&PostAttachmentBill{}).Where("post_id = ? AND user_id = ?", p.PostID, p.UserID)
+	if p.Model != nil && p.ID > 0 {
+		db = db.Where("id < ?", p.ID)
+	}
+	err = db.Count(&res).Error
+	return
+}
+
+// ListSince 推文在指定时间之后且未退款的购买记录
+func (p *PostAttachmentBill) ListSince(db *gorm.DB, since int64) (res []*PostAttachmentBill, err error) {
+	err = db.Where("post_id = ? AND created_on >= ? AND is_del = 0", p.PostID, since).Find(&res).Error
+	return
+}
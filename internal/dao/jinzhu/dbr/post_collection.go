@@ -13,9 +13,11 @@ import (
 
 type PostCollection struct {
 	*Model
-	Post   *Post `json:"-"`
-	PostID int64 `db:"post_id" json:"post_id"`
-	UserID int64 `db:"user_id" json:"user_id"`
+	Post     *Post  `json:"-"`
+	PostID   int64  `db:"post_id" json:"post_id"`
+	UserID   int64  `db:"user_id" json:"user_id"`
+	FolderID int64  `db:"folder_id" json:"folder_id"`
+	Note     string `db:"note" json:"note"`
 }
 
 func (p *PostCollection) Get(db *gorm.DB) (*PostCollection, error) {
@@ -10,7 +10,7 @@ import (
 	"gorm.io/gorm"
 )
 
-// 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源
+// 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源，9投票
 type PostContentT int
 
 const (
@@ -22,6 +22,7 @@ const (
 	ContentTypeLink
 	ContentTypeAttachment
 	ContentTypeChargeAttachment
+	ContentTypePoll
 )
 
 var (
@@ -44,11 +45,14 @@ type PostContent struct {
 }
 
 type PostContentFormated struct {
-	ID      int64        `db:"id" json:"id"`
-	PostID  int64        `json:"post_id"`
-	Content string       `json:"content"`
-	Type    PostContentT `json:"type"`
-	Sort    int64        `json:"sort"`
+	ID      int64                    `db:"id" json:"id"`
+	PostID  int64                    `json:"post_id"`
+	Content string                   `json:"content"`
+	Type    PostContentT             `json:"type"`
+	Sort    int64                    `json:"sort"`
+	Preview *LinkPreviewFormated     `db:"-" json:"preview,omitempty"`
+	Image   *AttachmentImageFormated `db:"-" json:"image,omitempty"`
+	Video   *AttachmentVideoFormated `db:"-" json:"video,omitempty"`
 }
 
 func (p *PostContent) DeleteByPostId(db *gorm.DB, postId int64) error {
@@ -0,0 +1,140 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"gorm.io/gorm"
+)
+
+// PostDraft 推文草稿
+type PostDraft struct {
+	*Model
+	UserID          int64        `json:"user_id"`
+	Contents        string       `json:"contents"`
+	Tags            string       `json:"tags"`
+	Users           string       `json:"users"`
+	AttachmentPrice int64        `json:"attachment_price"`
+	Visibility      PostVisibleT `json:"visibility"`
+}
+
+type PostDraftFormated struct {
+	ID              int64                  `json:"id"`
+	UserID          int64                  `json:"user_id"`
+	Contents        []*PostContentFormated `json:"contents"`
+	Tags            []string               `json:"tags"`
+	Users           []string               `json:"users"`
+	AttachmentPrice int64                  `json:"attachment_price"`
+	Visibility      PostVisibleT           `json:"visibility"`
+	CreatedOn       int64                  `json:"created_on"`
+	ModifiedOn      int64                  `json:"modified_on"`
+}
+
+// ContentItems 解析存储的草稿内容
+func (p *PostDraft) ContentItems() (items []*PostContentFormated) {
+	if p.Contents != "" {
+		json.Unmarshal([]byte(p.Contents), &items)
+	}
+	return
+}
+
+// SetContentItems 序列化草稿内容用于存储
+func (p *PostDraft) SetContentItems(items []*PostContentFormated) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	p.Contents = string(data)
+	return nil
+}
+
+// TagList 标签列表
+func (p *PostDraft) TagList() []string {
+	return splitNonEmpty(p.Tags)
+}
+
+// UserList @用户列表
+func (p *PostDraft) UserList() []string {
+	return splitNonEmpty(p.Users)
+}
+
+func (p *PostDraft) Format() *PostDraftFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &PostDraftFormated{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		Contents:        p.ContentItems(),
+		Tags:            p.TagList(),
+		Users:           p.UserList(),
+		AttachmentPrice: p.AttachmentPrice,
+		Visibility:      p.Visibility,
+		CreatedOn:       p.CreatedOn,
+		ModifiedOn:      p.ModifiedOn,
+	}
+}
+
+func (p *PostDraft) Create(db *gorm.DB) (*PostDraft, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostDraft) Get(db *gorm.DB) (*PostDraft, error) {
+	var draft PostDraft
+	if p.Model != nil && p.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", p.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&draft).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (p *PostDraft) Update(db *gorm.DB) error {
+	return db.Model(&PostDraft{}).Where("id = ? AND is_del = ?", p.Model.ID, 0).Save(p).Error
+}
+
+func (p *PostDraft) Delete(db *gorm.DB) error {
+	return db.Model(p).Where("id = ?", p.Model.ID).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
+
+func (p *PostDraft) List(db *gorm.DB, conditions *ConditionsT, offset, limit int) (res []*PostDraft, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	if p.UserID > 0 {
+		db = db.Where("user_id = ?", p.UserID)
+	}
+	for k, v := range *conditions {
+		if k == "ORDER" {
+			db = db.Order(v)
+		} else {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Where("is_del = ?", 0).Find(&res).Error
+	return
+}
+
+func (p *PostDraft) Count(db *gorm.DB, conditions *ConditionsT) (res int64, err error) {
+	if p.UserID > 0 {
+		db = db.Where("user_id = ?", p.UserID)
+	}
+	for k, v := range *conditions {
+		if k != "ORDER" {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Model(p).Where("is_del = ?", 0).Count(&res).Error
+	return
+}
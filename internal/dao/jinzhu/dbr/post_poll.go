@@ -0,0 +1,237 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PostPoll 推文投票
+type PostPoll struct {
+	*Model
+	PostID    int64 `json:"post_id"`
+	UserID    int64 `json:"user_id"`
+	Multiple  int8  `json:"multiple"`
+	Anonymous int8  `json:"anonymous"`
+	VoteCount int64 `json:"vote_count"`
+	ExpiredOn int64 `json:"expired_on"`
+	ClosedOn  int64 `json:"closed_on"`
+}
+
+// PostPollOption 推文投票选项
+type PostPollOption struct {
+	*Model
+	PollID    int64  `json:"poll_id"`
+	Content   string `json:"content"`
+	Sort      int64  `json:"sort"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+// PostPollVote 用户投票记录，每个用户在一个投票中只有一条记录
+type PostPollVote struct {
+	*Model
+	PollID    int64  `json:"poll_id"`
+	UserID    int64  `json:"user_id"`
+	OptionIds string `json:"option_ids"`
+}
+
+type PostPollFormated struct {
+	ID        int64                     `json:"id"`
+	PostID    int64                     `json:"post_id"`
+	UserID    int64                     `json:"user_id"`
+	Multiple  bool                      `json:"multiple"`
+	Anonymous bool                      `json:"anonymous"`
+	Options   []*PostPollOptionFormated `json:"options"`
+	VoteCount int64                     `json:"vote_count"`
+	ExpiredOn int64                     `json:"expired_on"`
+	ClosedOn  int64                     `json:"closed_on"`
+	Voted     []int64                   `json:"voted"`
+}
+
+type PostPollOptionFormated struct {
+	ID        int64  `json:"id"`
+	Content   string `json:"content"`
+	Sort      int64  `json:"sort"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+type PostPollVoteFormated struct {
+	ID        int64         `json:"id"`
+	UserID    int64         `json:"user_id"`
+	User      *UserFormated `json:"user"`
+	OptionIds []int64       `json:"option_ids"`
+	CreatedOn int64         `json:"created_on"`
+}
+
+// IsClosed 投票是否已结束
+func (p *PostPoll) IsClosed(now int64) bool {
+	return p.ClosedOn > 0 || (p.ExpiredOn > 0 && p.ExpiredOn <= now)
+}
+
+func (p *PostPoll) Format() *PostPollFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &PostPollFormated{
+		ID:        p.ID,
+		PostID:    p.PostID,
+		UserID:    p.UserID,
+		Multiple:  p.Multiple > 0,
+		Anonymous: p.Anonymous > 0,
+		Options:   []*PostPollOptionFormated{},
+		VoteCount: p.VoteCount,
+		ExpiredOn: p.ExpiredOn,
+		ClosedOn:  p.ClosedOn,
+		Voted:     []int64{},
+	}
+}
+
+func (p *PostPoll) Create(db *gorm.DB) (*PostPoll, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostPoll) Get(db *gorm.DB) (*PostPoll, error) {
+	var poll PostPoll
+	if p.Model != nil && p.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", p.ID, 0)
+	} else if p.PostID > 0 {
+		db = db.Where("post_id = ? AND is_del = ?", p.PostID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&poll).Error; err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// Close 结束投票，仅更新结束时间以免覆盖并发投票的计数
+func (p *PostPoll) Close(db *gorm.DB) error {
+	return db.Model(&PostPoll{}).Where("id = ? AND is_del = ?", p.Model.ID, 0).Update("closed_on", p.ClosedOn).Error
+}
+
+func (p *PostPoll) List(db *gorm.DB, conditions *ConditionsT, offset, limit int) (res []*PostPoll, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	for k, v := range *conditions {
+		if k == "ORDER" {
+			db = db.Order(v)
+		} else {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Where("is_del = ?", 0).Find(&res).Error
+	return
+}
+
+func (p *PostPollOption) Format() *PostPollOptionFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &PostPollOptionFormated{
+		ID:        p.ID,
+		Content:   p.Content,
+		Sort:      p.Sort,
+		VoteCount: p.VoteCount,
+	}
+}
+
+func (p *PostPollOption) Create(db *gorm.DB) (*PostPollOption, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostPollOption) ListByPollIds(db *gorm.DB, pollIds []int64) (res []*PostPollOption, err error) {
+	err = db.Where("poll_id IN ? AND is_del = ?", pollIds, 0).Order("sort ASC").Find(&res).Error
+	return
+}
+
+// IncrVoteCount 投票选项计数加一，返回实际更新的选项数量
+func (p *PostPollOption) IncrVoteCount(db *gorm.DB, pollId int64, optionIds []int64) (int64, error) {
+	res := db.Model(p).Where("poll_id = ? AND id IN ? AND is_del = ?", pollId, optionIds, 0).Update("vote_count", gorm.Expr("vote_count + 1"))
+	return res.RowsAffected, res.Error
+}
+
+// OptionIdList 投票选中的选项ID列表
+func (p *PostPollVote) OptionIdList() []int64 {
+	items := splitNonEmpty(p.OptionIds)
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		if id, err := strconv.ParseInt(item, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SetOptionIds 序列化投票选中的选项ID列表
+func (p *PostPollVote) SetOptionIds(ids []int64) {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.FormatInt(id, 10))
+	}
+	p.OptionIds = strings.Join(items, ",")
+}
+
+func (p *PostPollVote) Format() *PostPollVoteFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &PostPollVoteFormated{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		OptionIds: p.OptionIdList(),
+		CreatedOn: p.CreatedOn,
+	}
+}
+
+func (p *PostPollVote) Create(db *gorm.DB) (*PostPollVote, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostPollVote) Get(db *gorm.DB) (*PostPollVote, error) {
+	var vote PostPollVote
+	if p.PollID > 0 && p.UserID > 0 {
+		db = db.Where("poll_id = ? AND user_id = ? AND is_del = ?", p.PollID, p.UserID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&vote).Error; err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+func (p *PostPollVote) List(db *gorm.DB, conditions *ConditionsT, offset, limit int) (res []*PostPollVote, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	if p.PollID > 0 {
+		db = db.Where("poll_id = ?", p.PollID)
+	}
+	for k, v := range *conditions {
+		if k == "ORDER" {
+			db = db.Order(v)
+		} else {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Where("is_del = ?", 0).Find(&res).Error
+	return
+}
+
+func (p *PostPollVote) Count(db *gorm.DB) (res int64, err error) {
+	if p.PollID > 0 {
+		db = db.Where("poll_id = ?", p.PollID)
+	}
+	err = db.Model(p).Where("is_del = ?", 0).Count(&res).Error
+	return
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"gorm.io/gorm"
+)
+
+// PostRevision 推文修订历史，保存每次编辑前的推文内容
+type PostRevision struct {
+	*Model
+	PostID   int64  `json:"post_id"`
+	UserID   int64  `json:"user_id"`
+	Revision int64  `json:"revision"`
+	Contents string `json:"contents"`
+	Tags     string `json:"tags"`
+}
+
+type PostRevisionFormated struct {
+	ID        int64                  `json:"id"`
+	PostID    int64                  `json:"post_id"`
+	Revision  int64                  `json:"revision"`
+	Contents  []*PostContentFormated `json:"contents"`
+	Tags      []string               `json:"tags"`
+	CreatedOn int64                  `json:"created_on"`
+}
+
+// ContentItems 解析修订前的推文内容
+func (p *PostRevision) ContentItems() (items []*PostContentFormated) {
+	if p.Contents != "" {
+		json.Unmarshal([]byte(p.Contents), &items)
+	}
+	return
+}
+
+// SetContentItems 序列化修订前的推文内容用于存储
+func (p *PostRevision) SetContentItems(items []*PostContentFormated) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	p.Contents = string(data)
+	return nil
+}
+
+func (p *PostRevision) Format() *PostRevisionFormated {
+	if p.Model == nil {
+		return nil
+	}
+	return &PostRevisionFormated{
+		ID:        p.ID,
+		PostID:    p.PostID,
+		Revision:  p.Revision,
+		Contents:  p.ContentItems(),
+		Tags:      splitNonEmpty(p.Tags),
+		CreatedOn: p.CreatedOn,
+	}
+}
+
+func (p *PostRevision) Create(db *gorm.DB) (*PostRevision, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostRevision) Count(db *gorm.DB) (res int64, err error) {
+	err = db.Model(p).Where("post_id = ? AND is_del = ?", p.PostID, 0).Count(&res).Error
+	return
+}
+
+func (p *PostRevision) List(db *gorm.DB, offset, limit int) (res []*PostRevision, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	err = db.Where("post_id = ? AND is_del = ?", p.PostID, 0).Order("revision DESC").Find(&res).Error
+	return
+}
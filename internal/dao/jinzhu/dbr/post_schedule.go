@@ -0,0 +1,207 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"gorm.io/gorm"
+)
+
+// PostScheduleStatus 定时发布状态: 0待发布 1已发布 2已取消 3发布失败 4发布中
+type PostScheduleStatus int8
+
+const (
+	PostScheduleStatusPending PostScheduleStatus = iota
+	PostScheduleStatusPublished
+	PostScheduleStatusCanceled
+	PostScheduleStatusFailed
+	PostScheduleStatusPublishing
+)
+
+// PostSchedule 定时发布的推文，发布前对外不可见
+type PostSchedule struct {
+	*Model
+	UserID          int64              `json:"user_id"`
+	Contents        string             `json:"contents"`
+	Tags            string             `json:"tags"`
+	Users           string             `json:"users"`
+	AttachmentPrice int64              `json:"attachment_price"`
+	Visibility      PostVisibleT       `json:"visibility"`
+	IP              string             `json:"ip"`
+	IPLoc           string             `json:"ip_loc"`
+	PublishAt       int64              `json:"publish_at"`
+	Status          PostScheduleStatus `json:"status"`
+	PostID          int64              `json:"post_id"`
+}
+
+type PostScheduleFormated struct {
+	ID              int64                  `json:"id"`
+	UserID          int64                  `json:"user_id"`
+	User            *UserFormated          `json:"user"`
+	Contents        []*PostContentFormated `json:"contents"`
+	Tags            map[string]int8        `json:"tags"`
+	Users           []string               `json:"users"`
+	AttachmentPrice int64                  `json:"attachment_price"`
+	Visibility      PostVisibleT           `json:"visibility"`
+	PublishAt       int64                  `json:"publish_at"`
+	Status          PostScheduleStatus     `json:"status"`
+	PostID          int64                  `json:"post_id"`
+	CreatedOn       int64                  `json:"created_on"`
+	ModifiedOn      int64                  `json:"modified_on"`
+}
+
+// ContentItems 解析存储的推文内容
+func (p *PostSchedule) ContentItems() (items []*PostContentFormated) {
+	if p.Contents != "" {
+		json.Unmarshal([]byte(p.Contents), &items)
+	}
+	return
+}
+
+// SetContentItems 序列化推文内容用于存储
+func (p *PostSchedule) SetContentItems(items []*PostContentFormated) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	p.Contents = string(data)
+	return nil
+}
+
+// TagList 标签列表
+func (p *PostSchedule) TagList() []string {
+	return splitNonEmpty(p.Tags)
+}
+
+// UserList @用户列表
+func (p *PostSchedule) UserList() []string {
+	return splitNonEmpty(p.Users)
+}
+
+func (p *PostSchedule) Format() *PostScheduleFormated {
+	if p.Model == nil {
+		return nil
+	}
+	tagsMap := map[string]int8{}
+	for _, tag := range p.TagList() {
+		tagsMap[tag] = 1
+	}
+	return &PostScheduleFormated{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		User:            &UserFormated{},
+		Contents:        p.ContentItems(),
+		Tags:            tagsMap,
+		Users:           p.UserList(),
+		AttachmentPrice: p.AttachmentPrice,
+		Visibility:      p.Visibility,
+		PublishAt:       p.PublishAt,
+		Status:          p.Status,
+		PostID:          p.PostID,
+		CreatedOn:       p.CreatedOn,
+		ModifiedOn:      p.ModifiedOn,
+	}
+}
+
+func (p *PostSchedule) Create(db *gorm.DB) (*PostSchedule, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+func (p *PostSchedule) Get(db *gorm.DB) (*PostSchedule, error) {
+	var schedule PostSchedule
+	if p.Model != nil && p.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", p.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if p.UserID > 0 {
+		db = db.Where("user_id = ?", p.UserID)
+	}
+	if err := db.First(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Update 仅当定时推文仍处于from状态时更新，返回是否更新成功，避免覆盖并发的状态变更
+func (p *PostSchedule) Update(db *gorm.DB, from PostScheduleStatus) (bool, error) {
+	res := db.Model(&PostSchedule{}).Where("id = ? AND status = ? AND is_del = 0", p.ID, from).
+		Updates(map[string]any{
+			"contents":         p.Contents,
+			"tags":             p.Tags,
+			"users":            p.Users,
+			"attachment_price": p.AttachmentPrice,
+			"visibility":       p.Visibility,
+			"publish_at":       p.PublishAt,
+			"status":           p.Status,
+			"post_id":          p.PostID,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Claim 将待发布的定时推文标记为发布中，返回是否成功领取，避免同一推文被重复发布
+func (p *PostSchedule) Claim(db *gorm.DB) (bool, error) {
+	res := db.Model(&PostSchedule{}).Where("id = ? AND status = ? AND is_del = 0", p.ID, PostScheduleStatusPending).
+		Update("status", PostScheduleStatusPublishing)
+	if res.Error != nil || res.RowsAffected != 1 {
+		return false, res.Error
+	}
+	p.Status = PostScheduleStatusPublishing
+	return true, nil
+}
+
+func (p *PostSchedule) Delete(db *gorm.DB) error {
+	return db.Model(p).Where("id = ?", p.Model.ID).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
+
+func (p *PostSchedule) List(db *gorm.DB, conditions *ConditionsT, offset, limit int) (res []*PostSchedule, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	if p.UserID > 0 {
+		db = db.Where("user_id = ?", p.UserID)
+	}
+	for k, v := range *conditions {
+		if k == "ORDER" {
+			db = db.Order(v)
+		} else {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Where("is_del = ?", 0).Find(&res).Error
+	return
+}
+
+func (p *PostSchedule) Count(db *gorm.DB, conditions *ConditionsT) (res int64, err error) {
+	if p.UserID > 0 {
+		db = db.Where("user_id = ?", p.UserID)
+	}
+	for k, v := range *conditions {
+		if k != "ORDER" {
+			db = db.Where(k, v)
+		}
+	}
+	err = db.Model(p).Where("is_del = ?", 0).Count(&res).Error
+	return
+}
+
+func splitNonEmpty(s string) (res []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return
+}
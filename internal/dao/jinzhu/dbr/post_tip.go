@@ -0,0 +1,75 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import "gorm.io/gorm"
+
+// PostTip 用户为推文作者充电的记录
+type PostTip struct {
+	*Model
+	PostID    int64  `json:"post_id"`
+	UserID    int64  `json:"user_id"`
+	AuthorID  int64  `json:"author_id"`
+	Amount    int64  `json:"amount"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
+type PostTipFormated struct {
+	ID        int64         `json:"id"`
+	PostID    int64         `json:"post_id"`
+	UserID    int64         `json:"user_id"`
+	User      *UserFormated `json:"user"`
+	Amount    int64         `json:"amount"`
+	Message   string        `json:"message"`
+	CreatedOn int64         `json:"created_on"`
+}
+
+func (p *PostTip) Format() *PostTipFormated {
+	if p.Model == nil {
+		return &PostTipFormated{}
+	}
+	return &PostTipFormated{
+		ID:        p.ID,
+		PostID:    p.PostID,
+		UserID:    p.UserID,
+		Amount:    p.Amount,
+		Message:   p.Message,
+		CreatedOn: p.CreatedOn,
+	}
+}
+
+func (p *PostTip) Create(db *gorm.DB) (*PostTip, error) {
+	err := db.Create(&p).Error
+	return p, err
+}
+
+// GetByRequest 按客户端请求ID获取用户为推文的充电记录
+func (p *PostTip) GetByRequest(db *gorm.DB) (*PostTip, error) {
+	var tip PostTip
+	err := db.Model(&PostTip{}).Where("user_id = ? AND post_id = ? AND request_id = ? AND is_del = 0", p.UserID, p.PostID, p.RequestID).
+		First(&tip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tip, nil
+}
+
+// ListByPost 推文的充电记录，按时间倒序
+func (p *PostTip) ListByPost(db *gorm.DB, offset, limit int) (res []*PostTip, total int64, err error) {
+	db = db.Model(&PostTip{}).Where("post_id = ? AND is_del = 0", p.PostID)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return
+}
+
+// SumByUserPost 用户为推文充电的总金额
+func (p *PostTip) SumByUserPost(db *gorm.DB) (res int64, err error) {
+	err = db.Model(&PostTip{}).Where("user_id = ? AND post_id = ? AND is_del = 0", p.UserID, p.PostID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&res).Error
+	return
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StorageUsage 用户存储使用量，以附件统计
+type StorageUsage struct {
+	UsedSize  int64 `json:"used_size"`
+	UsedFiles int64 `json:"used_files"`
+}
+
+// UserStorageQuota 管理员为用户单独设置的存储配额，0表示不限制
+type UserStorageQuota struct {
+	*Model
+	UserID   int64 `json:"user_id"`
+	MaxSize  int64 `json:"max_size"`
+	MaxFiles int64 `json:"max_files"`
+}
+
+func (q *UserStorageQuota) Get(db *gorm.DB) (*UserStorageQuota, error) {
+	var quota UserStorageQuota
+	if q.UserID > 0 {
+		db = db.Where("user_id = ? AND is_del = ?", q.UserID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&quota).Error; err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// Save 存在则更新，否则创建(含已删除的记录，以免违反唯一索引)
+func (q *UserStorageQuota) Save(db *gorm.DB) error {
+	var quota UserStorageQuota
+	res := db.Unscoped().Where("user_id = ?", q.UserID).Limit(1).Find(&quota)
+	if res.Error != nil {
+		return res.Error
+	} else if res.RowsAffected == 0 {
+		return db.Create(q).Error
+	}
+	return db.Unscoped().Model(&UserStorageQuota{}).Where("id = ?", quota.ID).Updates(map[string]any{
+		"max_size":   q.MaxSize,
+		"max_files":  q.MaxFiles,
+		"deleted_on": 0,
+		"is_del":     0,
+	}).Error
+}
+
+func (q *UserStorageQuota) Delete(db *gorm.DB) error {
+	return db.Model(&UserStorageQuota{}).Where("user_id = ? AND is_del = 0", q.UserID).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubscriptionTier 创作者的订阅档位，按期从钱包扣费
+type SubscriptionTier struct {
+	*Model
+	UserID      int64  `json:"user_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"`
+}
+
+// Subscription 用户对创作者的订阅，到期前有效，每个用户对同一创作者只有一条订阅记录
+type Subscription struct {
+	*Model
+	UserID    int64 `json:"user_id"`
+	CreatorID int64 `json:"creator_id"`
+	TierID    int64 `json:"tier_id"`
+	Price     int64 `json:"price"`
+	AutoRenew int8  `json:"auto_renew"`
+	ExpiredOn int64 `json:"expired_on"`
+}
+
+// SubscriptionFormated 订阅信息，User为订阅者或创作者
+type SubscriptionFormated struct {
+	*Subscription
+	User *UserFormated `json:"user"`
+}
+
+func (t *SubscriptionTier) Create(db *gorm.DB) (*SubscriptionTier, error) {
+	err := db.Create(&t).Error
+	return t, err
+}
+
+func (t *SubscriptionTier) Get(db *gorm.DB) (*SubscriptionTier, error) {
+	var tier SubscriptionTier
+	if t.Model != nil && t.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", t.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if t.UserID > 0 {
+		db = db.Where("user_id = ?", t.UserID)
+	}
+	if err := db.First(&tier).Error; err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// Update 仅更新档位名称、说明及价格，已有订阅在下次续费时按新价格扣费
+func (t *SubscriptionTier) Update(db *gorm.DB) error {
+	return db.Model(&SubscriptionTier{}).Where("id = ? AND user_id = ? AND is_del = 0", t.ID, t.UserID).
+		Updates(map[string]any{
+			"title":       t.Title,
+			"description": t.Description,
+			"price":       t.Price,
+			"modified_on": time.Now().Unix(),
+		}).Error
+}
+
+func (t *SubscriptionTier) Delete(db *gorm.DB) error {
+	return db.Model(&SubscriptionTier{}).Where("id = ? AND user_id = ? AND is_del = 0", t.ID, t.UserID).
+		Updates(map[string]any{
+			"deleted_on": time.Now().Unix(),
+			"is_del":     1,
+		}).Error
+}
+
+func (t *SubscriptionTier) List(db *gorm.DB) (res []*SubscriptionTier, err error) {
+	err = db.Where("user_id = ? AND is_del = 0", t.UserID).Order("price ASC, id ASC").Find(&res).Error
+	return
+}
+
+func (t *SubscriptionTier) Count(db *gorm.DB) (res int64, err error) {
+	err = db.Model(&SubscriptionTier{}).Where("user_id = ? AND is_del = 0", t.UserID).Count(&res).Error
+	return
+}
+
+// IsActive 订阅是否在有效期内
+func (s *Subscription) IsActive(now int64) bool {
+	return s.ExpiredOn > now
+}
+
+func (s *Subscription) Create(db *gorm.DB) (*Subscription, error) {
+	err := db.Create(&s).Error
+	return s, err
+}
+
+// GetByUserCreator 获取用户对创作者的订阅，不存在时返回nil
+func (s *Subscription) GetByUserCreator(db *gorm.DB) (*Subscription, error) {
+	var res []*Subscription
+	if err := db.Where("user_id = ? AND creator_id = ? AND is_del = 0", s.UserID, s.CreatorID).Limit(1).Find(&res).Error; err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res[0], nil
+}
+
+// Renew 以当前到期时间作为条件更新订阅，避免并发续费时重复扣费
+func (s *Subscription) Renew(db *gorm.DB, expiredOn int64) (bool, error) {
+	res := db.Model(&Subscription{}).Where("id = ? AND expired_on = ? AND is_del = 0", s.ID, expiredOn).
+		Updates(map[string]any{
+			"tier_id":     s.TierID,
+			"price":       s.Price,
+			"auto_renew":  s.AutoRenew,
+			"expired_on":  s.ExpiredOn,
+			"modified_on": time.Now().Unix(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// UpdateAutoRenew 开启或关闭自动续费
+func (s *Subscription) UpdateAutoRenew(db *gorm.DB) error {
+	return db.Model(&Subscription{}).Where("id = ? AND is_del = 0", s.ID).
+		Updates(map[string]any{
+			"auto_renew":  s.AutoRenew,
+			"modified_on": time.Now().Unix(),
+		}).Error
+}
+
+// ActiveCreatorIds 用户当前有效订阅的创作者
+func (s *Subscription) ActiveCreatorIds(db *gorm.DB, now int64) (ids []int64, err error) {
+	err = db.Model(&Subscription{}).Where("user_id = ? AND expired_on > ? AND is_del = 0", s.UserID, now).
+		Pluck("creator_id", &ids).Error
+	return
+}
+
+// ActiveSubscriberIds 创作者当前有效的订阅者
+func (s *Subscription) ActiveSubscriberIds(db *gorm.DB, now int64) (ids []int64, err error) {
+	err = db.Model(&Subscription{}).Where("creator_id = ? AND expired_on > ? AND is_del = 0", s.CreatorID, now).
+		Pluck("user_id", &ids).Error
+	return
+}
+
+// ListSubscribers 创作者当前有效的订阅者
+func (s *Subscription) ListSubscribers(db *gorm.DB, now int64, offset, limit int) (res []*Subscription, total int64, err error) {
+	db = db.Model(&Subscription{}).Where("creator_id = ? AND expired_on > ? AND is_del = 0", s.CreatorID, now)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return
+}
+
+// ListByUser 用户的订阅记录，包括已到期的
+func (s *Subscription) ListByUser(db *gorm.DB, offset, limit int) (res []*Subscription, total int64, err error) {
+	db = db.Model(&Subscription{}).Where("user_id = ? AND is_del = 0", s.UserID)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Order("expired_on DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return
+}
+
+// ListRenewable 到期需要自动续费的订阅
+func (s *Subscription) ListRenewable(db *gorm.DB, now int64, limit int) (res []*Subscription, err error) {
+	err = db.Where("auto_renew = 1 AND expired_on <= ? AND is_del = 0", now).
+		Order("expired_on ASC").Limit(limit).Find(&res).Error
+	return
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import "gorm.io/gorm"
+
+// TagUsage 话题按小时分桶统计的引用次数
+type TagUsage struct {
+	*Model
+	TagID    int64 `json:"tag_id"`
+	Bucket   int64 `json:"bucket"`
+	QuoteNum int64 `json:"quote_num"`
+}
+
+// TagUsageStat 话题在各时间窗口内的引用次数
+type TagUsageStat struct {
+	TagID   int64
+	HourNum int64
+	DayNum  int64
+	WeekNum int64
+}
+
+// TagTrending 定时计算的热议话题排行
+type TagTrending struct {
+	*Model
+	TagID   int64   `json:"tag_id"`
+	Score   float64 `json:"score"`
+	HourNum int64   `json:"hour_num"`
+	DayNum  int64   `json:"day_num"`
+	WeekNum int64   `json:"week_num"`
+}
+
+// Incr 累加统计桶内的引用次数，统计桶不存在时创建
+func (u *TagUsage) Incr(db *gorm.DB) error {
+	incr := func() *gorm.DB {
+		return db.Model(&TagUsage{}).Where("tag_id = ? AND bucket = ? AND is_del = 0", u.TagID, u.Bucket).
+			Update("quote_num", gorm.Expr("quote_num + ?", u.QuoteNum))
+	}
+	if res := incr(); res.Error != nil || res.RowsAffected > 0 {
+		return res.Error
+	}
+	if err := db.Create(u).Error; err != nil {
+		// 并发创建同一统计桶时唯一索引冲突，改为累加
+		return incr().Error
+	}
+	return nil
+}
+
+// StatsSince 各话题自weekSince起的引用次数，其中hourSince与daySince起的引用次数分别统计
+func (u *TagUsage) StatsSince(db *gorm.DB, hourSince, daySince, weekSince int64) (res []*TagUsageStat, err error) {
+	err = db.Model(&TagUsage{}).
+		Select("tag_id, SUM(CASE WHEN bucket >= ? THEN quote_num ELSE 0 END) AS hour_num, SUM(CASE WHEN bucket >= ? THEN quote_num ELSE 0 END) AS day_num, SUM(quote_num) AS week_num", hourSince, daySince).
+		Where("bucket >= ? AND is_del = 0", weekSince).
+		Group("tag_id").
+		Scan(&res).Error
+	return
+}
+
+// DeleteBefore 清理早于bucket的统计桶
+func (u *TagUsage) DeleteBefore(db *gorm.DB, bucket int64) error {
+	return db.Unscoped().Where("bucket < ?", bucket).Delete(&TagUsage{}).Error
+}
+
+// List 按热议分从高到低的话题排行
+func (t *TagTrending) List(db *gorm.DB, offset, limit int) (res []*TagTrending, err error) {
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	err = db.Model(&TagTrending{}).Where("is_del = 0").Order("score DESC, id ASC").Find(&res).Error
+	return
+}
+
+// Replace 用新计算的排行替换原有的话题排行
+func (t *TagTrending) Replace(db *gorm.DB, items []*TagTrending) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("1 = 1").Delete(&TagTrending{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+}
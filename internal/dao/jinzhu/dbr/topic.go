@@ -12,9 +12,14 @@ import (
 
 type Tag struct {
 	*Model
-	UserID   int64  `json:"user_id"`
-	Tag      string `json:"tag"`
-	QuoteNum int64  `json:"quote_num"`
+	UserID      int64  `json:"user_id"`
+	Tag         string `json:"tag"`
+	QuoteNum    int64  `json:"quote_num"`
+	Description string `json:"description"`
+	Cover       string `json:"cover"`
+	AliasOf     int64  `json:"alias_of"`
+	IsLocked    int8   `json:"is_locked"`
+	IsHidden    int8   `json:"is_hidden"`
 }
 
 type TopicUser struct {
@@ -103,7 +108,8 @@ func (t *Tag) List(db *gorm.DB, conditions *ConditionsT, offset, limit int) (tag
 			db = db.Where(k, v)
 		}
 	}
-	err = db.Where("is_del = 0 and quote_num > 0").Find(&tags).Error
+	// 合并到其他话题的别名及隐藏的话题不出现在列表及检索建议中
+	err = db.Where("is_del = 0 and quote_num > 0 and alias_of = 0 and is_hidden = 0").Find(&tags).Error
 	return
 }
 
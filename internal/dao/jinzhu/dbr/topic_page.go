@@ -0,0 +1,58 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import "gorm.io/gorm"
+
+// TopicModerator 话题主持人
+type TopicModerator struct {
+	*Model
+	TopicID int64 `json:"topic_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+// TopicPin 话题内置顶的推文
+type TopicPin struct {
+	*Model
+	TopicID int64 `json:"topic_id"`
+	PostID  int64 `json:"post_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+// UserIds 话题的主持人，按添加时间排序
+func (m *TopicModerator) UserIds(db *gorm.DB) (res []int64, err error) {
+	err = db.Model(&TopicModerator{}).Where("topic_id = ? AND is_del = 0", m.TopicID).Order("id ASC").Pluck("user_id", &res).Error
+	return
+}
+
+func (m *TopicModerator) Exist(db *gorm.DB) bool {
+	var count int64
+	err := db.Model(&TopicModerator{}).Where("topic_id = ? AND user_id = ? AND is_del = 0", m.TopicID, m.UserID).Count(&count).Error
+	return err == nil && count > 0
+}
+
+func (m *TopicModerator) Create(db *gorm.DB) error {
+	return db.Create(m).Error
+}
+
+func (m *TopicModerator) Delete(db *gorm.DB) error {
+	return db.Unscoped().Where("topic_id = ? AND user_id = ?", m.TopicID, m.UserID).Delete(&TopicModerator{}).Error
+}
+
+// PostIds 话题内置顶的推文，最近置顶的在前
+func (p *TopicPin) PostIds(db *gorm.DB) (res []int64, err error) {
+	err = db.Model(&TopicPin{}).Where("topic_id = ? AND is_del = 0", p.TopicID).Order("id DESC").Pluck("post_id", &res).Error
+	return
+}
+
+func (p *TopicPin) Create(db *gorm.DB) error {
+	return db.Create(p).Error
+}
+
+// Delete 取消置顶，返回是否存在置顶记录
+func (p *TopicPin) Delete(db *gorm.DB) (bool, error) {
+	res := db.Unscoped().Where("topic_id = ? AND post_id = ?", p.TopicID, p.PostID).Delete(&TopicPin{})
+	return res.RowsAffected > 0, res.Error
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadSession 断点续传上传会话，已接收的内容暂存在本地磁盘
+type UploadSession struct {
+	*Model
+	UserID       int64  `json:"user_id"`
+	UploadType   string `json:"upload_type"`
+	ContentType  string `json:"content_type"`
+	FileExt      string `json:"file_ext"`
+	FileSize     int64  `json:"file_size"`
+	ReceivedSize int64  `json:"received_size"`
+	ExpiredOn    int64  `json:"expired_on"`
+}
+
+// IsExpired 会话是否已过期
+func (s *UploadSession) IsExpired() bool {
+	return s.ExpiredOn <= time.Now().Unix()
+}
+
+func (s *UploadSession) Create(db *gorm.DB) (*UploadSession, error) {
+	err := db.Create(&s).Error
+	return s, err
+}
+
+func (s *UploadSession) Get(db *gorm.DB) (*UploadSession, error) {
+	var session UploadSession
+	if s.Model != nil && s.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", s.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := db.First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateReceived 以当前已接收大小作为条件更新，避免并发写入时覆盖
+func (s *UploadSession) UpdateReceived(db *gorm.DB, received int64) (bool, error) {
+	res := db.Model(&UploadSession{}).Where("id = ? AND received_size = ? AND is_del = 0", s.ID, s.ReceivedSize).
+		Updates(map[string]any{
+			"received_size": received,
+			"modified_on":   time.Now().Unix(),
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	s.ReceivedSize = received
+	return true, nil
+}
+
+func (s *UploadSession) Delete(db *gorm.DB) error {
+	return db.Model(s).Where("id = ?", s.Model.ID).Updates(map[string]any{
+		"deleted_on": time.Now().Unix(),
+		"is_del":     1,
+	}).Error
+}
+
+// CountActive 用户未过期的上传会话数
+func (s *UploadSession) CountActive(db *gorm.DB, now int64) (res int64, err error) {
+	err = db.Model(&UploadSession{}).Where("user_id = ? AND expired_on > ? AND is_del = 0", s.UserID, now).Count(&res).Error
+	return
+}
+
+// ListExpired 已过期的上传会话
+func (s *UploadSession) ListExpired(db *gorm.DB, now int64, limit int) (res []*UploadSession, err error) {
+	err = db.Where("expired_on <= ? AND is_del = 0", now).Order("id ASC").Limit(limit).Find(&res).Error
+	return
+}
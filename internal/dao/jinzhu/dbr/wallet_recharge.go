@@ -10,6 +10,7 @@ type WalletRecharge struct {
 	*Model
 	UserID      int64  `json:"user_id"`
 	Amount      int64  `json:"amount"`
+	Provider    string `json:"provider"`
 	TradeNo     string `json:"trade_no"`
 	TradeStatus string `json:"trade_status"`
 }
@@ -0,0 +1,115 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbr
+
+import (
+	"gorm.io/gorm"
+)
+
+// WithdrawalStatus 提现状态
+type WithdrawalStatus int8
+
+const (
+	WithdrawalStatusPending WithdrawalStatus = iota
+	WithdrawalStatusApproved
+	WithdrawalStatusPaid
+	WithdrawalStatusRejected
+	WithdrawalStatusFailed
+	WithdrawalStatusCanceled
+)
+
+// WalletWithdrawal 钱包提现申请，申请时即从余额中扣除，拒绝、取消或打款失败时退回
+type WalletWithdrawal struct {
+	*Model
+	UserID      int64            `json:"user_id"`
+	Amount      int64            `json:"amount"`
+	Fee         int64            `json:"fee"`
+	AccountType string           `json:"account_type"`
+	Account     string           `json:"account"`
+	AccountName string           `json:"account_name"`
+	Status      WithdrawalStatus `json:"status"`
+	ReviewerID  int64            `json:"reviewer_id"`
+	TradeNo     string           `json:"trade_no"`
+	Remark      string           `json:"remark"`
+	ReviewedOn  int64            `json:"reviewed_on"`
+	PaidOn      int64            `json:"paid_on"`
+}
+
+// IsClosed 提现已结束，不能再变更状态
+func (w *WalletWithdrawal) IsClosed() bool {
+	return w.Status == WithdrawalStatusPaid || w.Refundable()
+}
+
+// Refundable 提现结束且未打款，金额需要退回余额
+func (w *WalletWithdrawal) Refundable() bool {
+	return w.Status == WithdrawalStatusRejected || w.Status == WithdrawalStatusFailed || w.Status == WithdrawalStatusCanceled
+}
+
+func (w *WalletWithdrawal) Create(db *gorm.DB) (*WalletWithdrawal, error) {
+	err := db.Create(&w).Error
+	return w, err
+}
+
+func (w *WalletWithdrawal) Get(db *gorm.DB) (*WalletWithdrawal, error) {
+	var withdrawal WalletWithdrawal
+	if w.Model != nil && w.ID > 0 {
+		db = db.Where("id = ? AND is_del = ?", w.ID, 0)
+	} else {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if w.UserID > 0 {
+		db = db.Where("user_id = ?", w.UserID)
+	}
+	if err := db.First(&withdrawal).Error; err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+// UpdateStatus 以当前状态作为条件更新，避免并发审核时重复处理
+func (w *WalletWithdrawal) UpdateStatus(db *gorm.DB, from WithdrawalStatus) (bool, error) {
+	res := db.Model(&WalletWithdrawal{}).Where("id = ? AND status = ? AND is_del = 0", w.ID, from).
+		Updates(map[string]any{
+			"status":      w.Status,
+			"reviewer_id": w.ReviewerID,
+			"trade_no":    w.TradeNo,
+			"remark":      w.Remark,
+			"reviewed_on": w.ReviewedOn,
+			"paid_on":     w.PaidOn,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// List userId为0时列出所有用户的提现，status小于0时不限状态
+func (w *WalletWithdrawal) List(db *gorm.DB, status WithdrawalStatus, offset, limit int) (res []*WalletWithdrawal, total int64, err error) {
+	db = db.Model(&WalletWithdrawal{}).Where("is_del = 0")
+	if w.UserID > 0 {
+		db = db.Where("user_id = ?", w.UserID)
+	}
+	if status >= 0 {
+		db = db.Where("status = ?", status)
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return
+}
+
+// CountUnpaid 用户未完成的提现申请数
+func (w *WalletWithdrawal) CountUnpaid(db *gorm.DB) (res int64, err error) {
+	err = db.Model(&WalletWithdrawal{}).
+		Where("user_id = ? AND status IN ? AND is_del = 0", w.UserID, []WithdrawalStatus{WithdrawalStatusPending, WithdrawalStatusApproved}).
+		Count(&res).Error
+	return
+}
+
+// SumUnpaid 已从余额扣除但尚未打款的提现总额
+func (w *WalletWithdrawal) SumUnpaid(db *gorm.DB) (res int64, err error) {
+	err = db.Model(&WalletWithdrawal{}).
+		Where("status IN ? AND is_del = 0", []WithdrawalStatus{WithdrawalStatusPending, WithdrawalStatusApproved}).
+		Select("COALESCE(SUM(amount), 0)").Scan(&res).Error
+	return
+}
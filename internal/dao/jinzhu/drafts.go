@@ -0,0 +1,61 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TweetDraftService = (*tweetDraftSrv)(nil)
+)
+
+type tweetDraftSrv struct {
+	db *gorm.DB
+}
+
+func newTweetDraftService(db *gorm.DB) core.TweetDraftService {
+	return &tweetDraftSrv{
+		db: db,
+	}
+}
+
+func (s *tweetDraftSrv) CreateDraft(draft *ms.PostDraft) (*ms.PostDraft, error) {
+	return draft.Create(s.db)
+}
+
+func (s *tweetDraftSrv) GetDraft(id int64) (*ms.PostDraft, error) {
+	draft := &dbr.PostDraft{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return draft.Get(s.db)
+}
+
+func (s *tweetDraftSrv) UpdateDraft(draft *ms.PostDraft) error {
+	return draft.Update(s.db)
+}
+
+func (s *tweetDraftSrv) DeleteDraft(draft *ms.PostDraft) error {
+	return draft.Delete(s.db)
+}
+
+func (s *tweetDraftSrv) ListUserDrafts(userId int64, limit, offset int) (res []*ms.PostDraft, total int64, err error) {
+	draft := &dbr.PostDraft{
+		UserID: userId,
+	}
+	conditions := &ms.ConditionsT{
+		"ORDER": "modified_on DESC, id DESC",
+	}
+	if total, err = draft.Count(s.db, conditions); err != nil || total == 0 {
+		return
+	}
+	res, err = draft.List(s.db, conditions, offset, limit)
+	return
+}
@@ -0,0 +1,426 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimy/tryst/event"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/internal/infra/events"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.CacheIndexService      = (*fanoutCacheIndexSrv)(nil)
+	_ core.TweetService           = (*fanoutTweetSrv)(nil)
+	_ core.FollowingManageService = (*fanoutFollowingSrv)(nil)
+	_ core.ContactManageService   = (*fanoutContactSrv)(nil)
+	_ core.SubscriptionService    = (*fanoutSubscriptionSrv)(nil)
+)
+
+// fanoutTimeline 写扩散的关注动态时间线，推文发布时写入作者各受众的时间线，
+// 粉丝数较多的作者的推文不写扩散，由其粉丝读取时拉取后合并
+type fanoutTimeline struct {
+	db                 *gorm.DB
+	tc                 core.TimelineCache
+	maxSize            int
+	backfillSize       int
+	celebrityFollowers int64
+}
+
+type fanoutCacheIndexSrv struct {
+	core.CacheIndexService
+	ftl *fanoutTimeline
+}
+
+type fanoutTweetSrv struct {
+	core.TweetService
+	ftl *fanoutTimeline
+}
+
+type fanoutFollowingSrv struct {
+	core.FollowingManageService
+	ftl *fanoutTimeline
+}
+
+type fanoutContactSrv struct {
+	core.ContactManageService
+	ftl *fanoutTimeline
+}
+
+type fanoutSubscriptionSrv struct {
+	core.SubscriptionService
+	ftl *fanoutTimeline
+}
+
+type fanoutTweetEvent struct {
+	event.UnimplementedEvent
+	ftl  *fanoutTimeline
+	act  core.IdxAct
+	post *ms.Post
+}
+
+func (e *fanoutTweetEvent) Name() string {
+	return "fanoutTweetEvent"
+}
+
+func (e *fanoutTweetEvent) Action() error {
+	switch e.act {
+	case core.IdxActCreatePost:
+		return e.ftl.pushTweet(e.post)
+	case core.IdxActDeletePost:
+		return e.ftl.removeTweet(e.post)
+	case core.IdxActVisiblePost:
+		if err := e.ftl.removeTweet(e.post); err != nil {
+			return err
+		}
+		return e.ftl.pushTweet(e.post)
+	}
+	return nil
+}
+
+func (s *fanoutCacheIndexSrv) SendAction(act core.IdxAct, post *ms.Post) {
+	s.CacheIndexService.SendAction(act, post)
+	switch act {
+	case core.IdxActCreatePost, core.IdxActDeletePost, core.IdxActVisiblePost:
+		events.OnEvent(&fanoutTweetEvent{
+			ftl:  s.ftl,
+			act:  act,
+			post: post,
+		})
+	}
+}
+
+func (s *fanoutTweetSrv) ListFollowingTweets(userId int64, limit, offset int) ([]*ms.Post, int64, error) {
+	return s.ftl.listTweets(userId, offset, limit)
+}
+
+func (s *fanoutFollowingSrv) FollowUser(userId int64, followId int64) error {
+	if err := s.FollowingManageService.FollowUser(userId, followId); err != nil {
+		return err
+	}
+	if err := s.ftl.backfill(userId, followId); err != nil {
+		logrus.Errorf("fanoutTimeline.backfill err: %s", err)
+	}
+	return nil
+}
+
+func (s *fanoutFollowingSrv) UnfollowUser(userId int64, followId int64) error {
+	if err := s.FollowingManageService.UnfollowUser(userId, followId); err != nil {
+		return err
+	}
+	if err := s.ftl.unfill(userId, followId); err != nil {
+		logrus.Errorf("fanoutTimeline.unfill err: %s", err)
+	}
+	return nil
+}
+
+func (s *fanoutContactSrv) AddFriend(userId int64, friendId int64) error {
+	if err := s.ContactManageService.AddFriend(userId, friendId); err != nil {
+		return err
+	}
+	// 好友可见的推文变多了，读取时重建双方的时间线
+	return s.ftl.tc.DeleteTimeline(userId, friendId)
+}
+
+func (s *fanoutContactSrv) DeleteFriend(userId int64, friendId int64) error {
+	if err := s.ContactManageService.DeleteFriend(userId, friendId); err != nil {
+		return err
+	}
+	return s.ftl.tc.DeleteTimeline(userId, friendId)
+}
+
+func (s *fanoutSubscriptionSrv) Subscribe(userId int64, tier *ms.SubscriptionTier) (*ms.Subscription, error) {
+	subscription, err := s.SubscriptionService.Subscribe(userId, tier)
+	if err == nil {
+		if err := s.ftl.tc.DeleteTimeline(userId); err != nil {
+			logrus.Errorf("fanoutTimeline.DeleteTimeline err: %s", err)
+		}
+	}
+	return subscription, err
+}
+
+// listTweets 获取用户关注动态时间线中的推文，时间线未建立时先重建
+func (s *fanoutTimeline) listTweets(userId int64, offset int, limit int) ([]*ms.Post, int64, error) {
+	celebrityIds, err := s.celebrityRelations(userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 有需拉取的作者时，两边各取前offset+limit条合并后再分页
+	start, size := offset, limit
+	if len(celebrityIds) > 0 {
+		start, size = 0, offset+limit
+	}
+	items, total, exist, err := s.tc.ListTimeline(userId, start, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exist {
+		if items, err = s.rebuild(userId, celebrityIds); err != nil {
+			return nil, 0, err
+		}
+		total = int64(len(items))
+		items = items[min(start, len(items)):min(start+size, len(items))]
+	}
+	if len(celebrityIds) > 0 {
+		pulled, count, err := s.pullTweets(celebrityIds, offset+limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = mergeTimelineItems(items, pulled)
+		items, total = items[min(offset, len(items)):min(offset+limit, len(items))], total+count
+	}
+	posts, err := s.visiblePosts(userId, items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// rebuild 以用户可见的非拉取作者的最近推文重建时间线
+func (s *fanoutTimeline) rebuild(userId int64, celebrityIds []int64) (items []*cs.TimelineItem, err error) {
+	friendIds, followIds, err := (&tweetSrv{db: s.db}).getUserRelation(userId)
+	if err != nil {
+		return nil, err
+	}
+	subscribedIds, err := (&dbr.Subscription{UserID: userId}).ActiveCreatorIds(s.db, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	isCelebrity := func(id int64) bool { return slices.Contains(celebrityIds, id) }
+	celebrityFriendIds := slices.DeleteFunc(slices.Clone(friendIds), func(id int64) bool { return !isCelebrity(id) })
+	friendIds = slices.DeleteFunc(friendIds, isCelebrity)
+	followIds = slices.DeleteFunc(followIds, isCelebrity)
+	conditions, args := []string{"user_id=?"}, []any{userId}
+	if len(friendIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=50 AND user_id IN(?))"), append(args, friendIds)
+	}
+	if len(celebrityFriendIds) > 0 {
+		conditions, args = append(conditions, "(visibility=50 AND user_id IN(?))"), append(args, celebrityFriendIds)
+	}
+	if len(followIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=60 AND user_id IN(?))"), append(args, followIds)
+	}
+	if len(subscribedIds) > 0 {
+		conditions, args = append(conditions, "(visibility=20 AND user_id IN(?))"), append(args, subscribedIds)
+	}
+	err = s.db.Model(&dbr.Post{}).Select("id, created_on").Where(strings.Join(conditions, " OR "), args...).
+		Order("created_on DESC, id DESC").Limit(s.maxSize).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, s.tc.ResetTimeline(userId, items)
+}
+
+// pullTweets 拉取粉丝数较多的作者的关注可见推文
+func (s *fanoutTimeline) pullTweets(authorIds []int64, limit int) (items []*cs.TimelineItem, total int64, err error) {
+	db := s.db.Model(&dbr.Post{}).Where("visibility>=60 AND user_id IN(?)", authorIds)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	err = db.Select("id, created_on").Order("created_on DESC, id DESC").Limit(limit).Find(&items).Error
+	return
+}
+
+// visiblePosts 按时间线顺序获取推文，并过滤掉关系变化后用户已不可见的推文
+func (s *fanoutTimeline) visiblePosts(userId int64, items []*cs.TimelineItem) ([]*ms.Post, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	var posts []*ms.Post
+	if err := s.db.Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	friendIds, followIds, err := (&tweetSrv{db: s.db}).getUserRelation(userId)
+	if err != nil {
+		return nil, err
+	}
+	subscribedIds, err := (&dbr.Subscription{UserID: userId}).ActiveCreatorIds(s.db, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	postMap := make(map[int64]*ms.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	res := make([]*ms.Post, 0, len(posts))
+	for _, id := range ids {
+		post, ok := postMap[id]
+		if !ok {
+			continue
+		}
+		switch {
+		case post.UserID == userId,
+			post.Visibility >= dbr.PostVisitFriend && slices.Contains(friendIds, post.UserID),
+			post.Visibility >= dbr.PostVisitFollowing && slices.Contains(followIds, post.UserID),
+			post.Visibility == dbr.PostVisitSubscribe && slices.Contains(subscribedIds, post.UserID):
+			res = append(res, post)
+		}
+	}
+	return res, nil
+}
+
+// pushTweet 推文写入作者及其受众的时间线
+func (s *fanoutTimeline) pushTweet(post *ms.Post) error {
+	celebrity, err := s.isCelebrity(post.UserID)
+	if err != nil {
+		return err
+	}
+	userIds := []int64{post.UserID}
+	if post.Visibility >= dbr.PostVisitFollowing && !celebrity {
+		ids, err := s.followerIds(post.UserID)
+		if err != nil {
+			return err
+		}
+		userIds = append(userIds, ids...)
+	}
+	// 粉丝数较多的作者的关注可见推文由好友读取时拉取
+	if post.Visibility >= dbr.PostVisitFriend && (!celebrity || post.Visibility < dbr.PostVisitFollowing) {
+		ids, err := s.friendIds(post.UserID)
+		if err != nil {
+			return err
+		}
+		userIds = append(userIds, ids...)
+	}
+	if post.Visibility == dbr.PostVisitSubscribe {
+		ids, err := (&dbr.Subscription{CreatorID: post.UserID}).ActiveSubscriberIds(s.db, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+		userIds = append(userIds, ids...)
+	}
+	slices.Sort(userIds)
+	return s.tc.PushTweets(slices.Compact(userIds), &cs.TimelineItem{
+		ID:        post.ID,
+		CreatedOn: post.CreatedOn,
+	})
+}
+
+// removeTweet 从作者及所有可能的受众的时间线中移除推文
+func (s *fanoutTimeline) removeTweet(post *ms.Post) error {
+	followerIds, err := s.followerIds(post.UserID)
+	if err != nil {
+		return err
+	}
+	friendIds, err := s.friendIds(post.UserID)
+	if err != nil {
+		return err
+	}
+	subscriberIds, err := (&dbr.Subscription{CreatorID: post.UserID}).ActiveSubscriberIds(s.db, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	userIds := append(append(append([]int64{post.UserID}, followerIds...), friendIds...), subscriberIds...)
+	slices.Sort(userIds)
+	return s.tc.RemoveTweets(slices.Compact(userIds), post.ID)
+}
+
+// backfill 关注用户后回填其最近的关注可见推文
+func (s *fanoutTimeline) backfill(userId int64, followId int64) error {
+	if celebrity, err := s.isCelebrity(followId); err != nil || celebrity {
+		return err
+	}
+	var items []*cs.TimelineItem
+	err := s.db.Model(&dbr.Post{}).Select("id, created_on").Where("visibility>=60 AND user_id=?", followId).
+		Order("created_on DESC, id DESC").Limit(s.backfillSize).Find(&items).Error
+	if err != nil {
+		return err
+	}
+	return s.tc.PushTweets([]int64{userId}, items...)
+}
+
+// unfill 取消关注后移除其关注可见的推文，仍是好友时保留
+func (s *fanoutTimeline) unfill(userId int64, followId int64) error {
+	if s.isFriend(userId, followId) {
+		return nil
+	}
+	var ids []int64
+	err := s.db.Model(&dbr.Post{}).Where("visibility>=60 AND user_id=?", followId).
+		Order("created_on DESC, id DESC").Limit(s.maxSize).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return s.tc.RemoveTweets([]int64{userId}, ids...)
+}
+
+// celebrityRelations 用户的好友及关注的用户中粉丝数较多需要拉取推文的作者
+func (s *fanoutTimeline) celebrityRelations(userId int64) (ids []int64, err error) {
+	friendIds, followIds, err := (&tweetSrv{db: s.db}).getUserRelation(userId)
+	if err != nil {
+		return nil, err
+	}
+	relationIds := append(friendIds, followIds...)
+	if len(relationIds) == 0 {
+		return nil, nil
+	}
+	err = s.db.Table(_following_).Where("follow_id IN ? AND is_del=0", relationIds).
+		Group("follow_id").Having("COUNT(*) >= ?", s.celebrityFollowers).Pluck("follow_id", &ids).Error
+	return
+}
+
+func (s *fanoutTimeline) isCelebrity(userId int64) (bool, error) {
+	var count int64
+	err := s.db.Table(_following_).Where("follow_id=? AND is_del=0", userId).Count(&count).Error
+	return count >= s.celebrityFollowers, err
+}
+
+func (s *fanoutTimeline) isFriend(userId int64, friendId int64) bool {
+	var count int64
+	s.db.Table(_contact_).Where("user_id=? AND friend_id=? AND status=2 AND is_del=0", userId, friendId).Count(&count)
+	return count > 0
+}
+
+func (s *fanoutTimeline) followerIds(userId int64) (ids []int64, err error) {
+	err = s.db.Table(_following_).Where("follow_id=? AND is_del=0", userId).Pluck("user_id", &ids).Error
+	return
+}
+
+func (s *fanoutTimeline) friendIds(userId int64) (ids []int64, err error) {
+	err = s.db.Table(_contact_).Where("user_id=? AND status=2 AND is_del=0", userId).Pluck("friend_id", &ids).Error
+	return
+}
+
+// mergeTimelineItems 合并两条时间线，按发布时间倒序，去除重复的推文
+func mergeTimelineItems(a, b []*cs.TimelineItem) []*cs.TimelineItem {
+	res := make([]*cs.TimelineItem, 0, len(a)+len(b))
+	seen := make(map[int64]struct{}, len(a)+len(b))
+	for _, item := range append(a, b...) {
+		if _, ok := seen[item.ID]; !ok {
+			seen[item.ID] = struct{}{}
+			res = append(res, item)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].CreatedOn != res[j].CreatedOn {
+			return res[i].CreatedOn > res[j].CreatedOn
+		}
+		return res[i].ID > res[j].ID
+	})
+	return res
+}
+
+func newFanoutTimeline(db *gorm.DB, tc core.TimelineCache) *fanoutTimeline {
+	s := conf.FanoutTimelineSetting
+	return &fanoutTimeline{
+		db:                 db,
+		tc:                 tc,
+		maxSize:            int(s.MaxSize),
+		backfillSize:       s.BackfillSize,
+		celebrityFollowers: s.CelebrityFollowers,
+	}
+}
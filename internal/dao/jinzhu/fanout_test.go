@@ -0,0 +1,216 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"database/sql"
+	"os"
+	"slices"
+	"strings"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/debug"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// memoryTimelineCache 内存中的时间线，用于测试
+type memoryTimelineCache struct {
+	timelines map[int64][]*cs.TimelineItem
+	resets    int
+}
+
+func (c *memoryTimelineCache) PushTweets(userIds []int64, items ...*cs.TimelineItem) error {
+	for _, userId := range userIds {
+		if timeline, exist := c.timelines[userId]; exist {
+			c.timelines[userId] = mergeTimelineItems(items, timeline)
+		}
+	}
+	return nil
+}
+
+func (c *memoryTimelineCache) RemoveTweets(userIds []int64, tweetIds ...int64) error {
+	for _, userId := range userIds {
+		if timeline, exist := c.timelines[userId]; exist {
+			c.timelines[userId] = slices.DeleteFunc(timeline, func(item *cs.TimelineItem) bool {
+				return slices.Contains(tweetIds, item.ID)
+			})
+		}
+	}
+	return nil
+}
+
+func (c *memoryTimelineCache) ResetTimeline(userId int64, items []*cs.TimelineItem) error {
+	c.resets++
+	c.timelines[userId] = slices.Clone(items)
+	return nil
+}
+
+func (c *memoryTimelineCache) ListTimeline(userId int64, offset int, limit int) ([]*cs.TimelineItem, int64, bool, error) {
+	timeline, exist := c.timelines[userId]
+	if !exist {
+		return nil, 0, false, nil
+	}
+	return timeline[min(offset, len(timeline)):min(offset+limit, len(timeline))], int64(len(timeline)), true, nil
+}
+
+func (c *memoryTimelineCache) DeleteTimeline(userIds ...int64) error {
+	for _, userId := range userIds {
+		delete(c.timelines, userId)
+	}
+	return nil
+}
+
+// newSqlite3TestDB 以完整的建表脚本初始化内存中的sqlite3数据库
+func newSqlite3TestDB() *gorm.DB {
+	driverName := "sqlite"
+	if slices.Contains(sql.Drivers(), "sqlite3") {
+		driverName = "sqlite3"
+	}
+	db, err := gorm.Open(&sqlite.Dialector{DriverName: driverName, DSN: ":memory:"}, &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix:   "p_",
+			SingularTable: true,
+		},
+	})
+	m.Expect(err).To(m.BeNil())
+	data, err := os.ReadFile("../../../scripts/paopao-sqlite3.sql")
+	m.Expect(err).To(m.BeNil())
+	for _, stmt := range strings.Split(string(data), ";\n") {
+		if strings.TrimSpace(stmt) != "" {
+			m.Expect(db.Exec(stmt).Error).To(m.BeNil())
+		}
+	}
+	return db
+}
+
+func timelineIds(items []*cs.TimelineItem) []int64 {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+var _ = g.Describe("FanoutTimeline", func() {
+	g.It("merge timelines by created time and drop duplicates", func() {
+		a := []*cs.TimelineItem{{ID: 5, CreatedOn: 500}, {ID: 3, CreatedOn: 300}, {ID: 1, CreatedOn: 100}}
+		b := []*cs.TimelineItem{{ID: 4, CreatedOn: 300}, {ID: 3, CreatedOn: 300}, {ID: 2, CreatedOn: 200}}
+		m.Expect(timelineIds(mergeTimelineItems(a, b))).To(m.Equal([]int64{5, 4, 3, 2, 1}))
+		m.Expect(mergeTimelineItems(nil, nil)).To(m.BeEmpty())
+	})
+
+	g.Context("list tweets", g.Ordered, func() {
+		const (
+			reader    int64 = 1
+			author    int64 = 2
+			celebrity int64 = 3
+			stranger  int64 = 4
+		)
+		var (
+			ftl *fanoutTimeline
+			tc  *memoryTimelineCache
+		)
+
+		g.BeforeAll(func() {
+			_following_, _contact_ = "p_following", "p_contact"
+			db := newSqlite3TestDB()
+			tc = &memoryTimelineCache{timelines: make(map[int64][]*cs.TimelineItem)}
+			ftl = &fanoutTimeline{
+				db:                 db,
+				tc:                 tc,
+				maxSize:            100,
+				backfillSize:       10,
+				celebrityFollowers: 2,
+			}
+			for _, f := range [][2]int64{{reader, author}, {reader, celebrity}, {stranger, celebrity}} {
+				m.Expect(db.Create(&dbr.Following{Model: &dbr.Model{}, UserId: f[0], FollowId: f[1]}).Error).To(m.BeNil())
+			}
+			// 普通作者与粉丝数较多的作者的推文交错发布，另有不可见及无关的推文
+			posts := []struct {
+				id, userId int64
+				visibility dbr.PostVisibleT
+			}{
+				{1, author, dbr.PostVisitPublic},
+				{2, celebrity, dbr.PostVisitPublic},
+				{3, author, dbr.PostVisitFollowing},
+				{4, celebrity, dbr.PostVisitFollowing},
+				{5, author, dbr.PostVisitPrivate},
+				{6, stranger, dbr.PostVisitPublic},
+				{7, author, dbr.PostVisitPublic},
+				{8, celebrity, dbr.PostVisitPublic},
+			}
+			for _, p := range posts {
+				post := &dbr.Post{
+					Model:      &dbr.Model{ID: p.id},
+					UserID:     p.userId,
+					Visibility: p.visibility,
+				}
+				m.Expect(db.Create(post).Error).To(m.BeNil())
+				m.Expect(db.Model(post).UpdateColumn("created_on", p.id*100).Error).To(m.BeNil())
+			}
+		})
+
+		g.It("rebuild without celebrity tweets then merge pulled tweets", func() {
+			posts, total, err := ftl.listTweets(reader, 0, 3)
+			m.Expect(err).To(m.BeNil())
+			m.Expect(tc.resets).To(m.Equal(1))
+			m.Expect(timelineIds(tc.timelines[reader])).To(m.Equal([]int64{7, 3, 1}))
+			m.Expect(total).To(m.Equal(int64(6)))
+			ids := make([]int64, 0, len(posts))
+			for _, post := range posts {
+				ids = append(ids, post.ID)
+			}
+			m.Expect(ids).To(m.Equal([]int64{8, 7, 4}))
+		})
+
+		g.It("walk the next page from the existing timeline", func() {
+			posts, total, err := ftl.listTweets(reader, 3, 3)
+			m.Expect(err).To(m.BeNil())
+			m.Expect(tc.resets).To(m.Equal(1))
+			m.Expect(total).To(m.Equal(int64(6)))
+			ids := make([]int64, 0, len(posts))
+			for _, post := range posts {
+				ids = append(ids, post.ID)
+			}
+			m.Expect(ids).To(m.Equal([]int64{3, 2, 1}))
+			posts, _, err = ftl.listTweets(reader, 6, 3)
+			m.Expect(err).To(m.BeNil())
+			m.Expect(posts).To(m.BeEmpty())
+		})
+
+		g.It("serve tweet timeline from the fanout timeline", func() {
+			ips := &simpleIndexPostsSrv{
+				ths: newTweetHelpService(ftl.db),
+				ftl: ftl,
+				db:  ftl.db,
+			}
+			box, err := ips.TweetTimeline(reader, 0, 3)
+			m.Expect(err).To(m.BeNil())
+			m.Expect(box.Total).To(m.Equal(int64(6)))
+			ids := make([]int64, 0, len(box.Tweets))
+			for _, tweet := range box.Tweets {
+				ids = append(ids, tweet.ID)
+			}
+			m.Expect(ids).To(m.Equal([]int64{8, 7, 4}))
+			m.Expect(box.Tweets[0].UserID).To(m.Equal(celebrity))
+			// 未启用写扩散时间线时不支持
+			ips = newSimpleIndexPostsService(ftl.db, ips.ths, nil).(*simpleIndexPostsSrv)
+			_, err = ips.TweetTimeline(reader, 0, 3)
+			m.Expect(err).To(m.MatchError(debug.ErrNotImplemented))
+		})
+
+		g.It("push tweets only to celebrity author's own timeline", func() {
+			m.Expect(ftl.pushTweet(&dbr.Post{Model: &dbr.Model{ID: 9, CreatedOn: 900}, UserID: celebrity, Visibility: dbr.PostVisitPublic})).To(m.BeNil())
+			m.Expect(timelineIds(tc.timelines[reader])).To(m.Equal([]int64{7, 3, 1}))
+			m.Expect(ftl.pushTweet(&dbr.Post{Model: &dbr.Model{ID: 10, CreatedOn: 1000}, UserID: author, Visibility: dbr.PostVisitPublic})).To(m.BeNil())
+			m.Expect(timelineIds(tc.timelines[reader])).To(m.Equal([]int64{10, 7, 3, 1}))
+		})
+	})
+})
@@ -6,8 +6,10 @@ package jinzhu
 
 import (
 	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -92,6 +94,59 @@ func (s *followingManageSrv) ListFollowings(userId int64, limit, offset int) (*m
 	return res, nil
 }
 
+func (s *followingManageSrv) ListFollowsByCursor(userId int64, c *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error) {
+	follows, err := s.f.ListFollowsByCursor(s.db, userId, c, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	follows, next := cursor.Next(follows, limit, (*dbr.Following).Cursor)
+	res := &ms.ContactList{}
+	for _, f := range follows {
+		res.Contacts = append(res.Contacts, ms.ContactItem{
+			UserId:    f.User.ID,
+			Username:  f.User.Username,
+			Nickname:  f.User.Nickname,
+			Avatar:    f.User.Avatar,
+			CreatedOn: f.User.CreatedOn,
+		})
+	}
+	return res, next, nil
+}
+
+func (s *followingManageSrv) ListFollowingsByCursor(userId int64, c *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error) {
+	followings, err := s.f.ListFollowingsByCursor(s.db, userId, c, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	followings, next := cursor.Next(followings, limit, (*dbr.Following).Cursor)
+	userIds := make([]int64, 0, len(followings))
+	for _, f := range followings {
+		userIds = append(userIds, f.UserId)
+	}
+	users, err := s.u.ListUserInfoById(s.db, userIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	userMap := make(map[int64]*cs.UserInfo, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+	res := &ms.ContactList{}
+	// 保持关注时间的顺序
+	for _, id := range userIds {
+		if user, ok := userMap[id]; ok {
+			res.Contacts = append(res.Contacts, ms.ContactItem{
+				UserId:    user.ID,
+				Username:  user.Username,
+				Nickname:  user.Nickname,
+				Avatar:    user.Avatar,
+				CreatedOn: user.CreatedOn,
+			})
+		}
+	}
+	return res, next, nil
+}
+
 func (s *followingManageSrv) GetFollowCount(userId int64) (int64, int64, error) {
 	return s.f.FollowCount(s.db, userId)
 }
@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/alimy/tryst/cfg"
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/internal/core"
 	"github.com/rocboss/paopao-ce/internal/dao/cache"
@@ -30,11 +31,29 @@ var (
 
 type dataSrv struct {
 	core.WalletService
+	core.WithdrawalService
+	core.LedgerService
+	core.SubscriptionService
+	core.TipService
+	core.RefundService
 	core.MessageService
 	core.TopicService
+	core.TopicManageService
 	core.TweetService
 	core.TweetManageService
 	core.TweetHelpService
+	core.TweetScheduleService
+	core.TweetDraftService
+	core.CollectionFolderService
+	core.TweetRevisionService
+	core.TweetPollService
+	core.TweetRecommendService
+	core.LinkPreviewService
+	core.MediaProcessService
+	core.UploadSessionService
+	core.StorageQuotaService
+	core.ObjectBlobService
+	core.ObjectReferenceService
 	core.TweetMetricServantA
 	core.CommentService
 	core.CommentManageService
@@ -64,25 +83,55 @@ func NewDataService() (core.DataService, core.VersionInfo) {
 	ums := newUserMetricServentA(db)
 	cms := newCommentMetricServentA(db)
 	cis := cache.NewEventCacheIndexSrv(tms)
+	var ftl *fanoutTimeline
+	if cfg.If("FanoutTimeline") {
+		ftl = newFanoutTimeline(db, cache.NewRedisTimelineCache())
+		cis = &fanoutCacheIndexSrv{CacheIndexService: cis, ftl: ftl}
+	}
 	ds := &dataSrv{
-		TweetMetricServantA:    tms,
-		CommentMetricServantA:  cms,
-		UserMetricServantA:     ums,
-		WalletService:          newWalletService(db),
-		MessageService:         newMessageService(db),
-		TopicService:           newTopicService(db),
-		TweetService:           newTweetService(db),
-		TweetManageService:     newTweetManageService(db, cis),
-		TweetHelpService:       newTweetHelpService(db),
-		CommentService:         newCommentService(db),
-		CommentManageService:   newCommentManageService(db),
-		TrendsManageServantA:   newTrendsManageServentA(db),
-		UserManageService:      newUserManageService(db, ums),
-		ContactManageService:   newContactManageService(db),
-		FollowingManageService: newFollowingManageService(db),
-		UserRelationService:    newUserRelationService(db),
-		SecurityService:        newSecurityService(db, pvs),
-		AttachmentCheckService: security.NewAttachmentCheckService(),
+		TweetMetricServantA:     tms,
+		CommentMetricServantA:   cms,
+		UserMetricServantA:      ums,
+		WalletService:           newWalletService(db),
+		WithdrawalService:       newWithdrawalService(db),
+		LedgerService:           newLedgerService(db),
+		SubscriptionService:     newSubscriptionService(db),
+		TipService:              newTipService(db),
+		RefundService:           newRefundService(db),
+		MessageService:          newMessageService(db),
+		TopicService:            newTopicService(db),
+		TopicManageService:      newTopicManageService(db),
+		TweetService:            newTweetService(db),
+		TweetManageService:      newTweetManageService(db, cis),
+		TweetHelpService:        newTweetHelpService(db),
+		TweetScheduleService:    newTweetScheduleService(db),
+		TweetDraftService:       newTweetDraftService(db),
+		CollectionFolderService: newCollectionFolderService(db),
+		TweetRevisionService:    newTweetRevisionService(db, cis),
+		TweetPollService:        newTweetPollService(db),
+		TweetRecommendService:   newTweetRecommendService(db),
+		LinkPreviewService:      newLinkPreviewService(db),
+		MediaProcessService:     newMediaProcessService(db),
+		UploadSessionService:    newUploadSessionService(db),
+		StorageQuotaService:     newStorageQuotaService(db),
+		ObjectBlobService:       newObjectBlobService(db),
+		ObjectReferenceService:  newObjectReferenceService(db),
+		CommentService:          newCommentService(db),
+		CommentManageService:    newCommentManageService(db),
+		TrendsManageServantA:    newTrendsManageServentA(db),
+		UserManageService:       newUserManageService(db, ums),
+		ContactManageService:    newContactManageService(db),
+		FollowingManageService:  newFollowingManageService(db),
+		UserRelationService:     newUserRelationService(db),
+		SecurityService:         newSecurityService(db, pvs),
+		AttachmentCheckService:  security.NewAttachmentCheckService(),
+	}
+	if ftl != nil {
+		// 关注动态改由写扩散的时间线提供
+		ds.TweetService = &fanoutTweetSrv{TweetService: ds.TweetService, ftl: ftl}
+		ds.FollowingManageService = &fanoutFollowingSrv{FollowingManageService: ds.FollowingManageService, ftl: ftl}
+		ds.ContactManageService = &fanoutContactSrv{ContactManageService: ds.ContactManageService, ftl: ftl}
+		ds.SubscriptionService = &fanoutSubscriptionSrv{SubscriptionService: ds.SubscriptionService, ftl: ftl}
 	}
 	return cache.NewCacheDataService(ds), ds
 }
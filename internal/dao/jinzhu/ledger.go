@@ -0,0 +1,339 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+const (
+	_ledgerMaxRetry = 5
+	_ledgerBackoff  = 10 * time.Millisecond
+
+	_accountOpening    = "system:opening"
+	_accountRevenue    = "system:revenue"
+	_accountWithdrawal = "system:withdrawal"
+	_accountPayout     = "system:payout"
+	_accountFee        = "system:fee"
+)
+
+var (
+	_ core.LedgerService = (*ledgerSrv)(nil)
+)
+
+var (
+	errLedgerConflict  = errors.New("ledger account version conflict")
+	errLedgerUnbalance = errors.New("ledger journal is not balanced")
+	errJournalPosted   = errors.New("ledger journal already posted")
+)
+
+// ledgerLine 凭证中的一条分录，用户账户的分录同时更新用户余额并记录账单
+type ledgerLine struct {
+	code   string
+	userId int64
+	amount int64
+	reason string
+	postId int64
+}
+
+type ledgerSrv struct {
+	db *gorm.DB
+}
+
+func newLedgerService(db *gorm.DB) core.LedgerService {
+	return &ledgerSrv{
+		db: db,
+	}
+}
+
+func userAccount(userId int64) string {
+	return "user:" + strconv.FormatInt(userId, 10)
+}
+
+func channelAccount(provider string) string {
+	return "channel:" + provider
+}
+
+// userLine 用户账户的分录
+func userLine(userId int64, amount int64, reason string, postId int64) *ledgerLine {
+	return &ledgerLine{
+		code:   userAccount(userId),
+		userId: userId,
+		amount: amount,
+		reason: reason,
+		postId: postId,
+	}
+}
+
+// systemLine 系统账户或支付渠道账户的分录
+func systemLine(code string, amount int64) *ledgerLine {
+	return &ledgerLine{
+		code:   code,
+		amount: amount,
+	}
+}
+
+// withLedger 执行记账事务，账户版本冲突时随机退避后重试；凭证已记账时视为成功
+func withLedger(db *gorm.DB, key string, fn func(tx *gorm.DB) error) (err error) {
+	for i := 0; i < _ledgerMaxRetry; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(rand.Int63n(int64(_ledgerBackoff << i))))
+		}
+		if err = db.Transaction(fn); err != errLedgerConflict {
+			break
+		}
+	}
+	switch {
+	case err == nil, err == errJournalPosted:
+		return nil
+	case err == errLedgerConflict, err == cs.ErrNoBalance:
+		return err
+	}
+	// 并发记账同一凭证时幂等键唯一索引冲突，另一方已记账
+	if posted, _ := (&dbr.LedgerJournal{IdempotencyKey: key}).Exist(db); posted {
+		return nil
+	}
+	return err
+}
+
+// journalLines 按幂等键获取已记账凭证的分录，凭证不存在时返回空
+func journalLines(tx *gorm.DB, key string) (res []*ledgerLine, err error) {
+	tnJournal := tx.NamingStrategy.TableName("LedgerJournal")
+	tnAccount := tx.NamingStrategy.TableName("LedgerAccount")
+	var rows []*struct {
+		Code   string
+		UserID int64
+		Amount int64
+	}
+	err = tx.Model(&dbr.LedgerEntry{}).Select("a.code AS code, a.user_id AS user_id, amount").
+		Joins(fmt.Sprintf("JOIN %s j ON j.id = journal_id", tnJournal)).
+		Joins(fmt.Sprintf("JOIN %s a ON a.id = account_id", tnAccount)).
+		Where("j.idempotency_key = ? AND j.is_del = 0", key).
+		Find(&rows).Error
+	for _, r := range rows {
+		res = append(res, &ledgerLine{code: r.Code, userId: r.UserID, amount: r.Amount})
+	}
+	return
+}
+
+// postJournal 在事务中按幂等键记账，已记账时返回errJournalPosted；
+// 用户账户余额不足时返回cs.ErrNoBalance
+func postJournal(tx *gorm.DB, key string, kind string, memo string, lines ...*ledgerLine) error {
+	journal := &dbr.LedgerJournal{
+		IdempotencyKey: key,
+		Kind:           kind,
+		Memo:           memo,
+	}
+	if posted, err := journal.Exist(tx); err != nil {
+		return err
+	} else if posted {
+		return errJournalPosted
+	}
+	lines = slices.DeleteFunc(lines, func(l *ledgerLine) bool { return l.amount == 0 })
+	var sum int64
+	for _, l := range lines {
+		sum += l.amount
+	}
+	if sum != 0 {
+		return errLedgerUnbalance
+	}
+	if _, err := journal.Create(tx); err != nil {
+		return err
+	}
+	// 按账户编码顺序锁定并更新，避免并发记账时死锁
+	slices.SortFunc(lines, func(a, b *ledgerLine) int { return strings.Compare(a.code, b.code) })
+	for _, l := range lines {
+		account, err := ledgerAccount(tx, l.code, l.userId)
+		if err != nil {
+			return err
+		}
+		balance := account.Balance + l.amount
+		if l.userId > 0 && l.amount < 0 && balance < 0 {
+			return cs.ErrNoBalance
+		}
+		if err = postEntry(tx, journal, account, balance); err != nil {
+			return err
+		}
+		if l.userId <= 0 {
+			continue
+		}
+		if err = tx.Model(&dbr.User{}).Where("id = ?", l.userId).Update("balance", balance).Error; err != nil {
+			return err
+		}
+		if err = tx.Create(&dbr.WalletStatement{
+			UserID:          l.userId,
+			ChangeAmount:    l.amount,
+			BalanceSnapshot: balance,
+			Reason:          l.reason,
+			PostID:          l.postId,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postEntry 更新账户余额并记录分录
+func postEntry(tx *gorm.DB, journal *dbr.LedgerJournal, account *dbr.LedgerAccount, balance int64) error {
+	amount := balance - account.Balance
+	if ok, err := account.UpdateBalance(tx, balance); err != nil {
+		return err
+	} else if !ok {
+		return errLedgerConflict
+	}
+	_, err := (&dbr.LedgerEntry{
+		JournalID:       journal.ID,
+		AccountID:       account.ID,
+		Amount:          amount,
+		BalanceSnapshot: balance,
+	}).Create(tx)
+	return err
+}
+
+// ledgerAccount 获取并锁定账户，不存在时创建；用户首次记账时以当前余额记一笔期初凭证
+func ledgerAccount(tx *gorm.DB, code string, userId int64) (*dbr.LedgerAccount, error) {
+	account, err := (&dbr.LedgerAccount{Code: code}).LockByCode(tx)
+	if err != nil || account != nil {
+		return account, err
+	}
+	if account, err = (&dbr.LedgerAccount{Code: code, UserID: userId}).Create(tx); isDuplicateKey(err) {
+		// 并发首次记账时另一方已创建账户，重试事务
+		return nil, errLedgerConflict
+	} else if err != nil {
+		return nil, err
+	}
+	if userId <= 0 {
+		return account, nil
+	}
+	var balance int64
+	if err = tx.Model(&dbr.User{}).Where("id = ?", userId).Select("balance").Scan(&balance).Error; err != nil || balance == 0 {
+		return account, err
+	}
+	journal, err := (&dbr.LedgerJournal{
+		IdempotencyKey: "opening:" + code,
+		Kind:           "opening",
+		Memo:           "期初余额",
+	}).Create(tx)
+	if err != nil {
+		return nil, err
+	}
+	opening, err := ledgerAccount(tx, _accountOpening, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = postEntry(tx, journal, opening, opening.Balance-balance); err != nil {
+		return nil, err
+	}
+	if err = postEntry(tx, journal, account, balance); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// isDuplicateKey 是否唯一索引冲突，兼容未开启gorm错误转换时各数据库驱动的错误信息
+func isDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Duplicate entry") || // mysql
+		strings.Contains(msg, "duplicate key value") || // postgres
+		strings.Contains(msg, "UNIQUE constraint failed") // sqlite3
+}
+
+// AuditLedger 检查凭证是否借贷平衡、账户余额是否等于分录合计、用户余额是否等于其账户余额
+func (s *ledgerSrv) AuditLedger() (*ms.LedgerAuditReport, error) {
+	report := &ms.LedgerAuditReport{}
+	tnAccount := s.db.NamingStrategy.TableName("LedgerAccount")
+	tnEntry := s.db.NamingStrategy.TableName("LedgerEntry")
+	if err := s.db.Model(&dbr.LedgerJournal{}).Where("is_del = 0").Count(&report.Journals).Error; err != nil {
+		return nil, err
+	}
+	err := s.db.Model(&dbr.LedgerEntry{}).Select("journal_id").Where("is_del = 0").
+		Group("journal_id").Having("SUM(amount) != 0").Pluck("journal_id", &report.UnbalancedJournals).Error
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*struct {
+		Code     string
+		UserID   int64
+		Balance  int64
+		EntrySum int64
+	}
+	err = s.db.Table(tnAccount).
+		Select(fmt.Sprintf("%s.code, %s.user_id, %s.balance, COALESCE(SUM(%s.amount), 0) AS entry_sum", tnAccount, tnAccount, tnAccount, tnEntry)).
+		Joins(fmt.Sprintf("LEFT JOIN %s ON %s.account_id=%s.id AND %s.is_del=0", tnEntry, tnEntry, tnAccount, tnEntry)).
+		Where(fmt.Sprintf("%s.is_del=0", tnAccount)).
+		Group(fmt.Sprintf("%s.id, %s.code, %s.user_id, %s.balance", tnAccount, tnAccount, tnAccount, tnAccount)).
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[int64]int64)
+	for _, a := range accounts {
+		report.Accounts++
+		report.TotalBalance += a.Balance
+		if a.Balance != a.EntrySum {
+			report.Drifts = append(report.Drifts, &ms.LedgerDrift{
+				Code:     a.Code,
+				UserID:   a.UserID,
+				Expected: a.EntrySum,
+				Actual:   a.Balance,
+				Reason:   "account balance not equal to entries",
+			})
+		}
+		if a.UserID > 0 {
+			users[a.UserID] = a.Balance
+		}
+	}
+	// 还没有账户的用户余额应为0，有余额时同样计入差异，首次记账时会补记期初凭证
+	var balances []*struct {
+		ID      int64
+		Balance int64
+	}
+	if err = s.db.Model(&dbr.User{}).Select("id, balance").Where("is_del = 0").Find(&balances).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range balances {
+		expected, exist := users[u.ID]
+		if !exist {
+			if u.Balance != 0 {
+				report.Drifts = append(report.Drifts, &ms.LedgerDrift{
+					Code:     userAccount(u.ID),
+					UserID:   u.ID,
+					Expected: 0,
+					Actual:   u.Balance,
+					Reason:   "user has balance but no ledger account",
+				})
+			}
+			continue
+		}
+		if u.Balance != expected {
+			report.Drifts = append(report.Drifts, &ms.LedgerDrift{
+				Code:     userAccount(u.ID),
+				UserID:   u.ID,
+				Expected: expected,
+				Actual:   u.Balance,
+				Reason:   "user balance not equal to account",
+			})
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var _ = g.Describe("Ledger", func() {
+	const (
+		alice int64 = 1
+		bob   int64 = 2
+	)
+	var db *gorm.DB
+
+	g.BeforeEach(func() {
+		db = newSqlite3TestDB()
+		for _, id := range []int64{alice, bob} {
+			m.Expect(db.Create(&dbr.User{Model: &dbr.Model{ID: id}, Username: userAccount(id)}).Error).To(m.BeNil())
+		}
+	})
+
+	userBalance := func(userId int64) (balance int64) {
+		m.Expect(db.Model(&dbr.User{}).Where("id = ?", userId).Select("balance").Scan(&balance).Error).To(m.BeNil())
+		return
+	}
+
+	// recharge 以key记一笔充值凭证
+	recharge := func(key string, userId int64, amount int64) error {
+		return withLedger(db, key, func(tx *gorm.DB) error {
+			return postJournal(tx, key, "recharge", "用户充值",
+				userLine(userId, amount, "用户充值", 0),
+				systemLine(channelAccount("alipay"), -amount))
+		})
+	}
+
+	g.It("post a journal key only once", func() {
+		m.Expect(recharge("recharge:alipay:1", alice, 100)).To(m.Succeed())
+		m.Expect(recharge("recharge:alipay:1", alice, 100)).To(m.Succeed())
+		m.Expect(recharge("recharge:alipay:2", alice, 50)).To(m.Succeed())
+		m.Expect(userBalance(alice)).To(m.Equal(int64(150)))
+		var statements int64
+		m.Expect(db.Model(&dbr.WalletStatement{}).Where("user_id = ?", alice).Count(&statements).Error).To(m.BeNil())
+		m.Expect(statements).To(m.Equal(int64(2)))
+	})
+
+	g.It("reject overdrafts without posting", func() {
+		m.Expect(recharge("recharge:alipay:1", alice, 100)).To(m.Succeed())
+		key := "tip:1"
+		err := withLedger(db, key, func(tx *gorm.DB) error {
+			return postJournal(tx, key, "tip", "推文充电",
+				userLine(alice, -200, "推文充电支出", 0),
+				userLine(bob, 200, "推文充电收入", 0))
+		})
+		m.Expect(err).To(m.MatchError(cs.ErrNoBalance))
+		m.Expect(userBalance(alice)).To(m.Equal(int64(100)))
+		posted, err := (&dbr.LedgerJournal{IdempotencyKey: key}).Exist(db)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(posted).To(m.BeFalse())
+	})
+
+	g.It("retry the transaction on account version conflict", func() {
+		m.Expect(recharge("recharge:alipay:1", alice, 100)).To(m.Succeed())
+		key, attempts := "tip:1", 0
+		err := withLedger(db, key, func(tx *gorm.DB) error {
+			attempts++
+			if attempts == 1 {
+				// 读取账户后其他事务先更新了余额
+				account, err := ledgerAccount(tx, userAccount(alice), alice)
+				m.Expect(err).To(m.BeNil())
+				stale := *account
+				m.Expect(account.UpdateBalance(tx, account.Balance)).To(m.BeTrue())
+				return postEntry(tx, &dbr.LedgerJournal{Model: &dbr.Model{}}, &stale, stale.Balance-30)
+			}
+			return postJournal(tx, key, "tip", "推文充电",
+				userLine(alice, -30, "推文充电支出", 0),
+				userLine(bob, 30, "推文充电收入", 0))
+		})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(attempts).To(m.Equal(2))
+		m.Expect(userBalance(alice)).To(m.Equal(int64(70)))
+		m.Expect(userBalance(bob)).To(m.Equal(int64(30)))
+
+		attempts = 0
+		err = withLedger(db, "tip:2", func(tx *gorm.DB) error {
+			attempts++
+			return errLedgerConflict
+		})
+		m.Expect(err).To(m.MatchError(errLedgerConflict))
+		m.Expect(attempts).To(m.Equal(_ledgerMaxRetry))
+	})
+
+	g.It("audit balances drifted from the ledger", func() {
+		m.Expect(recharge("recharge:alipay:1", alice, 100)).To(m.Succeed())
+		s := &ledgerSrv{db: db}
+		report, err := s.AuditLedger()
+		m.Expect(err).To(m.BeNil())
+		m.Expect(report.Clean()).To(m.BeTrue())
+		m.Expect(report.Journals).To(m.Equal(int64(1)))
+		m.Expect(report.Accounts).To(m.Equal(int64(2)))
+
+		// 绕过记账直接修改余额
+		m.Expect(db.Model(&dbr.User{}).Where("id = ?", alice).Update("balance", 120).Error).To(m.BeNil())
+		m.Expect(db.Model(&dbr.User{}).Where("id = ?", bob).Update("balance", 10).Error).To(m.BeNil())
+		m.Expect(db.Model(&dbr.LedgerAccount{}).Where("code = ?", channelAccount("alipay")).Update("balance", -90).Error).To(m.BeNil())
+		report, err = s.AuditLedger()
+		m.Expect(err).To(m.BeNil())
+		m.Expect(report.Clean()).To(m.BeFalse())
+		m.Expect(report.TotalBalance).To(m.Equal(int64(10)))
+		drifts := make(map[string][2]int64, len(report.Drifts))
+		for _, d := range report.Drifts {
+			drifts[d.Code] = [2]int64{d.Expected, d.Actual}
+		}
+		m.Expect(drifts).To(m.Equal(map[string][2]int64{
+			channelAccount("alipay"): {-100, -90},
+			userAccount(alice):       {100, 120},
+			userAccount(bob):         {0, 10},
+		}))
+	})
+})
@@ -0,0 +1,38 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.MediaProcessService = (*mediaProcessSrv)(nil)
+)
+
+type mediaProcessSrv struct {
+	db *gorm.DB
+}
+
+func newMediaProcessService(db *gorm.DB) core.MediaProcessService {
+	return &mediaProcessSrv{
+		db: db,
+	}
+}
+
+func (s *mediaProcessSrv) ListPendingMedia(staleBefore int64, limit int) ([]*ms.Attachment, error) {
+	return (&dbr.Attachment{}).ListPendingMedia(s.db, staleBefore, limit)
+}
+
+func (s *mediaProcessSrv) ClaimMedia(attachment *ms.Attachment) (bool, error) {
+	return attachment.Claim(s.db)
+}
+
+func (s *mediaProcessSrv) UpdateMedia(attachment *ms.Attachment) error {
+	return attachment.UpdateMedia(s.db)
+}
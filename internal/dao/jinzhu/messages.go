@@ -9,6 +9,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"gorm.io/gorm"
 )
 
@@ -53,6 +54,36 @@ func (s *messageSrv) ReadAllMessage(userId int64) error {
 
 func (s *messageSrv) GetMessages(userId int64, style cs.MessageStyle, limit int, offset int) (res []*ms.MessageFormated, total int64, err error) {
 	var messages []*dbr.Message
+	db := s.messagesQuery(userId, style)
+	if err = db.Count(&total).Error; err != nil || total == 0 {
+		return
+	}
+	if offset >= 0 && limit > 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	if err = db.Order("id DESC").Find(&messages).Error; err != nil {
+		return
+	}
+	for _, message := range messages {
+		res = append(res, message.Format())
+	}
+	return
+}
+
+func (s *messageSrv) GetMessagesByCursor(userId int64, style cs.MessageStyle, c *cs.Cursor, limit int) (res []*ms.MessageFormated, next *cs.Cursor, err error) {
+	var messages []*dbr.Message
+	db := s.messagesQuery(userId, style)
+	if err = dbr.KeysetPage(db, "", c, true, limit).Find(&messages).Error; err != nil {
+		return
+	}
+	messages, next = cursor.Next(messages, limit, (*dbr.Message).Cursor)
+	for _, message := range messages {
+		res = append(res, message.Format())
+	}
+	return
+}
+
+func (s *messageSrv) messagesQuery(userId int64, style cs.MessageStyle) *gorm.DB {
 	db := s.db.Table(_message_)
 	// 1动态，2评论，3回复，4私信，5好友申请，99系统通知'
 	switch style {
@@ -69,17 +100,5 @@ func (s *messageSrv) GetMessages(userId int64, style cs.MessageStyle, limit int,
 	default:
 		db = db.Where("receiver_user_id=? OR (sender_user_id=? AND type=4)", userId, userId)
 	}
-	if err = db.Count(&total).Error; err != nil || total == 0 {
-		return
-	}
-	if offset >= 0 && limit > 0 {
-		db = db.Limit(limit).Offset(offset)
-	}
-	if err = db.Order("id DESC").Find(&messages).Error; err != nil {
-		return
-	}
-	for _, message := range messages {
-		res = append(res, message.Format())
-	}
-	return
+	return db
 }
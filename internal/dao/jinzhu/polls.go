@@ -0,0 +1,146 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TweetPollService = (*tweetPollSrv)(nil)
+
+	errInvalidPollOption = errors.New("invalid poll option")
+)
+
+type tweetPollSrv struct {
+	db *gorm.DB
+}
+
+func newTweetPollService(db *gorm.DB) core.TweetPollService {
+	return &tweetPollSrv{
+		db: db,
+	}
+}
+
+func (s *tweetPollSrv) CreatePoll(poll *ms.PostPoll, options []*ms.PostPollOption) (*ms.PostPoll, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := poll.Create(tx); err != nil {
+			return err
+		}
+		for _, option := range options {
+			option.PollID = poll.ID
+			if _, err := option.Create(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+func (s *tweetPollSrv) GetPollByPostId(postId int64) (*ms.PostPoll, error) {
+	poll := &dbr.PostPoll{
+		PostID: postId,
+	}
+	return poll.Get(s.db)
+}
+
+func (s *tweetPollSrv) PollsByPostIds(ids []int64) ([]*ms.PostPollFormated, error) {
+	polls, err := (&dbr.PostPoll{}).List(s.db, &dbr.ConditionsT{
+		"post_id IN ?": ids,
+	}, 0, 0)
+	if err != nil || len(polls) == 0 {
+		return nil, err
+	}
+	pollIds := make([]int64, 0, len(polls))
+	for _, poll := range polls {
+		pollIds = append(pollIds, poll.ID)
+	}
+	options, err := (&dbr.PostPollOption{}).ListByPollIds(s.db, pollIds)
+	if err != nil {
+		return nil, err
+	}
+	optionMap := make(map[int64][]*dbr.PostPollOptionFormated, len(polls))
+	for _, option := range options {
+		optionMap[option.PollID] = append(optionMap[option.PollID], option.Format())
+	}
+	res := make([]*ms.PostPollFormated, 0, len(polls))
+	for _, poll := range polls {
+		pollFormated := poll.Format()
+		if items, exist := optionMap[poll.ID]; exist {
+			pollFormated.Options = items
+		}
+		res = append(res, pollFormated)
+	}
+	return res, nil
+}
+
+func (s *tweetPollSrv) UserPollVotes(userId int64, pollIds []int64) ([]*ms.PostPollVote, error) {
+	return (&dbr.PostPollVote{}).List(s.db, &dbr.ConditionsT{
+		"user_id = ?":  userId,
+		"poll_id IN ?": pollIds,
+	}, 0, 0)
+}
+
+func (s *tweetPollSrv) GetUserPollVote(pollId, userId int64) (*ms.PostPollVote, error) {
+	vote := &dbr.PostPollVote{
+		PollID: pollId,
+		UserID: userId,
+	}
+	return vote.Get(s.db)
+}
+
+// VotePoll 投票，每个用户在一个投票中的唯一记录由数据库唯一索引保证
+func (s *tweetPollSrv) VotePoll(vote *ms.PostPollVote, optionIds []int64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		vote.SetOptionIds(optionIds)
+		if _, err := vote.Create(tx); err != nil {
+			return err
+		}
+		count, err := (&dbr.PostPollOption{}).IncrVoteCount(tx, vote.PollID, optionIds)
+		if err != nil {
+			return err
+		}
+		if count != int64(len(optionIds)) {
+			return errInvalidPollOption
+		}
+		return tx.Model(&dbr.PostPoll{}).Where("id = ? AND is_del = ?", vote.PollID, 0).Update("vote_count", gorm.Expr("vote_count + 1")).Error
+	})
+}
+
+func (s *tweetPollSrv) ListPollVotes(pollId int64, limit, offset int) (res []*ms.PostPollVote, total int64, err error) {
+	vote := &dbr.PostPollVote{
+		PollID: pollId,
+	}
+	if total, err = vote.Count(s.db); err != nil || total == 0 {
+		return
+	}
+	res, err = vote.List(s.db, &dbr.ConditionsT{
+		"ORDER": "id DESC",
+	}, offset, limit)
+	return
+}
+
+func (s *tweetPollSrv) ListExpiredPolls(now int64, limit int) ([]*ms.PostPoll, error) {
+	return (&dbr.PostPoll{}).List(s.db, &dbr.ConditionsT{
+		"closed_on = ?":                      0,
+		"expired_on > 0 AND expired_on <= ?": now,
+		"ORDER":                              "expired_on ASC",
+	}, 0, limit)
+}
+
+func (s *tweetPollSrv) ClosePoll(poll *ms.PostPoll) error {
+	poll.ClosedOn = time.Now().Unix()
+	return poll.Close(s.db)
+}
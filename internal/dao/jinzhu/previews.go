@@ -0,0 +1,53 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.LinkPreviewService = (*linkPreviewSrv)(nil)
+)
+
+type linkPreviewSrv struct {
+	db *gorm.DB
+}
+
+func newLinkPreviewService(db *gorm.DB) core.LinkPreviewService {
+	return &linkPreviewSrv{
+		db: db,
+	}
+}
+
+func (s *linkPreviewSrv) GetLinkPreview(url string) (*ms.LinkPreview, error) {
+	preview := &dbr.LinkPreview{
+		URLHash: dbr.LinkURLHash(url),
+	}
+	return preview.Get(s.db)
+}
+
+func (s *linkPreviewSrv) LinkPreviewsByURLs(urls []string) ([]*ms.LinkPreview, error) {
+	hashes := make([]string, 0, len(urls))
+	for _, url := range urls {
+		hashes = append(hashes, dbr.LinkURLHash(url))
+	}
+	return (&dbr.LinkPreview{}).ListByHashes(s.db, hashes)
+}
+
+// SaveLinkPreview 保存链接预览，已存在时覆盖旧的预览信息
+func (s *linkPreviewSrv) SaveLinkPreview(preview *ms.LinkPreview) error {
+	preview.SetURL(preview.URL)
+	old, err := (&dbr.LinkPreview{URLHash: preview.URLHash}).Get(s.db)
+	if err == nil {
+		preview.Model = old.Model
+		return preview.Update(s.db)
+	}
+	_, err = preview.Create(s.db)
+	return err
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+const (
+	// 参与推荐的关注的用户所关注的用户的最大数量
+	_maxFriendOfFriends = 200
+)
+
+var (
+	_ core.TweetRecommendService = (*tweetRecommendSrv)(nil)
+)
+
+type tweetRecommendSrv struct {
+	*tweetSrv
+}
+
+type authorCount struct {
+	AuthorId int64
+	Count    int
+}
+
+func newTweetRecommendService(db *gorm.DB) core.TweetRecommendService {
+	return &tweetRecommendSrv{
+		tweetSrv: &tweetSrv{
+			db: db,
+		},
+	}
+}
+
+// ListRecommendCandidates 个性化推荐的候选推文，包括关注、好友及订阅的用户的推文，关注的话题、关注的用户所关注的用户、
+// 近期互动过的作者的公开推文，以及全站热门推文；均为since之后发布且不含自己的推文，每个来源最多limit条
+func (s *tweetRecommendSrv) ListRecommendCandidates(userId int64, tags []string, since int64, limit int) ([]*cs.TweetCandidate, error) {
+	friendIds, followIds, err := s.getUserRelation(userId)
+	if err != nil {
+		return nil, err
+	}
+	subscribedIds, err := (&dbr.Subscription{UserID: userId}).ActiveCreatorIds(s.db, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	fofs, err := s.friendOfFriends(userId, append(friendIds, followIds...))
+	if err != nil {
+		return nil, err
+	}
+	engagements, err := s.engagements(userId, since)
+	if err != nil {
+		return nil, err
+	}
+	postMap := make(map[int64]*dbr.Post)
+	// 关注、好友及订阅的用户的推文，可见性与关注动态一致
+	conditions, args := []string{}, []any{}
+	if len(friendIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=? AND user_id IN ?)"), append(args, dbr.PostVisitFriend, friendIds)
+	}
+	if len(followIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=? AND user_id IN ?)"), append(args, dbr.PostVisitFollowing, followIds)
+	}
+	if len(subscribedIds) > 0 {
+		conditions, args = append(conditions, "(visibility=? AND user_id IN ?)"), append(args, dbr.PostVisitSubscribe, subscribedIds)
+	}
+	if err = s.mergeRecentPosts(postMap, userId, since, limit, conditions, args); err != nil {
+		return nil, err
+	}
+	// 关注的话题及关注的用户所关注的用户、近期互动过的作者的公开推文
+	conditions, args = []string{}, []any{}
+	discoverIds := make([]int64, 0, len(fofs)+len(engagements))
+	for id := range fofs {
+		discoverIds = append(discoverIds, id)
+	}
+	for id := range engagements {
+		discoverIds = append(discoverIds, id)
+	}
+	if len(discoverIds) > 0 {
+		conditions, args = append(conditions, "user_id IN ?"), append(args, discoverIds)
+	}
+	for _, tag := range tags {
+		conditions, args = append(conditions, "tags LIKE ?"), append(args, "%"+tag+"%")
+	}
+	if len(conditions) > 0 {
+		conditions = []string{fmt.Sprintf("(visibility>=? AND (%s))", strings.Join(conditions, " OR "))}
+		args = append([]any{dbr.PostVisitPublic}, args...)
+		if err = s.mergeRecentPosts(postMap, userId, since, limit, conditions, args); err != nil {
+			return nil, err
+		}
+	}
+	// 全站热门推文
+	var hots []*dbr.Post
+	err = s.db.Table(_post_).Joins(fmt.Sprintf("JOIN %s metric ON %s.id=metric.post_id", _post_metric_, _post_)).
+		Where(fmt.Sprintf("visibility>=? AND %s.created_on>=? AND %s.user_id<>? AND %s.is_del=0 AND metric.is_del=0", _post_, _post_, _post_), dbr.PostVisitPublic, since, userId).
+		Select(_post_ + ".*").Order("metric.rank_score DESC").Limit(limit).Find(&hots).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range hots {
+		postMap[post.ID] = post
+	}
+	return s.candidatesFrom(postMap, tags, friendIds, followIds, fofs, engagements)
+}
+
+// mergeRecentPosts 合并满足任一条件的近期推文
+func (s *tweetRecommendSrv) mergeRecentPosts(postMap map[int64]*dbr.Post, userId int64, since int64, limit int, conditions []string, args []any) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var posts []*dbr.Post
+	err := s.db.Model(&dbr.Post{}).Where("created_on>=? AND user_id<>?", since, userId).
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Order("created_on DESC").Limit(limit).Find(&posts).Error
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	return nil
+}
+
+// candidatesFrom 生成候选推文的推荐特征
+func (s *tweetRecommendSrv) candidatesFrom(postMap map[int64]*dbr.Post, tags []string, friendIds, followIds []int64, fofs, engagements map[int64]int) ([]*cs.TweetCandidate, error) {
+	postIds := make([]int64, 0, len(postMap))
+	for id := range postMap {
+		postIds = append(postIds, id)
+	}
+	rankScores := make(map[int64]int64, len(postIds))
+	if len(postIds) > 0 {
+		var metrics []*dbr.PostMetric
+		if err := s.db.Table(_post_metric_).Where("post_id IN ? AND is_del=0", postIds).Select("post_id, rank_score").Find(&metrics).Error; err != nil {
+			return nil, err
+		}
+		for _, metric := range metrics {
+			rankScores[metric.PostId] = metric.RankScore
+		}
+	}
+	tagSet := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tagSet[tag] = struct{}{}
+	}
+	friendSet, followSet := make(map[int64]struct{}, len(friendIds)), make(map[int64]struct{}, len(followIds))
+	for _, id := range friendIds {
+		friendSet[id] = struct{}{}
+	}
+	for _, id := range followIds {
+		followSet[id] = struct{}{}
+	}
+	res := make([]*cs.TweetCandidate, 0, len(postMap))
+	for _, post := range postMap {
+		_, isFriend := friendSet[post.UserID]
+		_, isFollowing := followSet[post.UserID]
+		candidate := &cs.TweetCandidate{
+			PostID:         post.ID,
+			AuthorID:       post.UserID,
+			CreatedOn:      post.CreatedOn,
+			Following:      isFollowing,
+			Friend:         isFriend,
+			FriendOfFriend: fofs[post.UserID],
+			Engagement:     engagements[post.UserID],
+			RankScore:      rankScores[post.ID],
+		}
+		for _, tag := range strings.Split(post.Tags, ",") {
+			if _, ok := tagSet[tag]; ok && tag != "" {
+				candidate.TopicHits++
+			}
+		}
+		res = append(res, candidate)
+	}
+	return res, nil
+}
+
+// friendOfFriends 关注的用户及好友中同样关注某用户的人数，不含已关注的用户
+func (s *tweetRecommendSrv) friendOfFriends(userId int64, relationIds []int64) (map[int64]int, error) {
+	res := make(map[int64]int)
+	if len(relationIds) == 0 {
+		return res, nil
+	}
+	var items []*authorCount
+	err := s.db.Table(_following_).Where("user_id IN ? AND follow_id<>? AND follow_id NOT IN ? AND is_del=0", relationIds, userId, relationIds).
+		Select("follow_id AS author_id, COUNT(*) AS count").Group("follow_id").
+		Order("count DESC").Limit(_maxFriendOfFriends).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		res[item.AuthorId] = item.Count
+	}
+	return res, nil
+}
+
+// engagements 近期对各作者推文的点赞、收藏及评论次数
+func (s *tweetRecommendSrv) engagements(userId int64, since int64) (map[int64]int, error) {
+	res := make(map[int64]int)
+	for _, table := range []string{_postStar_, _postCollection_, _comment_} {
+		var items []*authorCount
+		err := s.db.Table(table+" e").Joins(fmt.Sprintf("JOIN %s p ON p.id=e.post_id", _post_)).
+			Where("e.user_id=? AND e.created_on>=? AND e.is_del=0 AND p.user_id<>?", userId, since, userId).
+			Select("p.user_id AS author_id, COUNT(*) AS count").Group("p.user_id").Scan(&items).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			res[item.AuthorId] += item.Count
+		}
+	}
+	return res, nil
+}
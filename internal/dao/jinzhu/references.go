@@ -0,0 +1,220 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"slices"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"gorm.io/gorm"
+)
+
+const (
+	_referenceBatchSize = 1000
+)
+
+var (
+	_ core.ObjectReferenceService = (*objectReferenceSrv)(nil)
+
+	// 保存在对象存储中的推文内容类型
+	_mediaContentTypes = []dbr.PostContentT{
+		dbr.ContentTypeImage,
+		dbr.ContentTypeVideo,
+		dbr.ContentTypeAudio,
+		dbr.ContentTypeAttachment,
+		dbr.ContentTypeChargeAttachment,
+	}
+)
+
+type objectReferenceSrv struct {
+	db *gorm.DB
+}
+
+// referenceRow 引用媒体内容的记录，Value为媒体地址或JSON格式的推文内容
+type referenceRow struct {
+	ID    int64
+	Value string
+}
+
+func newObjectReferenceService(db *gorm.DB) core.ObjectReferenceService {
+	return &objectReferenceSrv{
+		db: db,
+	}
+}
+
+// WalkObjectReferences 分批遍历推文、评论、头像、话题封面、链接预览、草稿、定时推文及修订历史中引用的媒体地址
+func (s *objectReferenceSrv) WalkObjectReferences(fn func(contents []string) error) error {
+	plainFn := func(rows []*referenceRow) error {
+		contents := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if row.Value != "" {
+				contents = append(contents, row.Value)
+			}
+		}
+		return fn(contents)
+	}
+	itemsFn := func(rows []*referenceRow) error {
+		var contents []string
+		for _, row := range rows {
+			var items []*dbr.PostContentFormated
+			if row.Value == "" || json.Unmarshal([]byte(row.Value), &items) != nil {
+				continue
+			}
+			for _, item := range items {
+				if slices.Contains(_mediaContentTypes, item.Type) {
+					contents = append(contents, item.Content)
+				}
+			}
+		}
+		return fn(contents)
+	}
+	walks := []struct {
+		model  any
+		column string
+		where  []any
+		fn     func([]*referenceRow) error
+	}{
+		{&dbr.PostContent{}, "content", []any{"type IN ?", _mediaContentTypes}, plainFn},
+		{&dbr.CommentContent{}, "content", []any{"type = ?", dbr.ContentTypeImage}, plainFn},
+		{&dbr.User{}, "avatar", []any{"avatar != ?", ""}, plainFn},
+		{&dbr.Tag{}, "cover", []any{"cover != ?", ""}, plainFn},
+		{&dbr.LinkPreview{}, "image", []any{"image != ?", ""}, plainFn},
+		{&dbr.PostDraft{}, "contents", nil, itemsFn},
+		{&dbr.PostSchedule{}, "contents", nil, itemsFn},
+		{&dbr.PostRevision{}, "contents", nil, itemsFn},
+	}
+	for _, w := range walks {
+		if err := s.walkColumn(w.model, w.column, w.where, w.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkRecentAttachments 分批遍历since之后创建的附件的媒体地址，包括去重后共用已有对象的附件
+func (s *objectReferenceSrv) WalkRecentAttachments(since int64, fn func(contents []string) error) error {
+	return s.walkColumn(&dbr.Attachment{}, "content", []any{"created_on >= ?", since}, func(rows []*referenceRow) error {
+		contents := make([]string, 0, len(rows))
+		for _, row := range rows {
+			contents = append(contents, row.Value)
+		}
+		return fn(contents)
+	})
+}
+
+// RewriteObjectReferences 改写引用的媒体地址，包括附件及其各版本的地址，返回更新的记录数；
+// rewrite返回原地址时表示不需要改写
+func (s *objectReferenceSrv) RewriteObjectReferences(rewrite func(content string) string) (int64, error) {
+	plainFn := func(value string) string {
+		if value == "" {
+			return value
+		}
+		return rewrite(value)
+	}
+	itemsFn := func(value string) string {
+		var items []*dbr.PostContentFormated
+		if value == "" || json.Unmarshal([]byte(value), &items) != nil {
+			return value
+		}
+		changed := false
+		for _, item := range items {
+			if !slices.Contains(_mediaContentTypes, item.Type) {
+				continue
+			}
+			if content := rewrite(item.Content); content != item.Content {
+				item.Content, changed = content, true
+			}
+		}
+		if !changed {
+			return value
+		}
+		data, _ := json.Marshal(items)
+		return string(data)
+	}
+	variantsFn := func(value string) string {
+		var variants []*dbr.ImageVariant
+		if value == "" || json.Unmarshal([]byte(value), &variants) != nil {
+			return value
+		}
+		changed := false
+		for _, variant := range variants {
+			if content := rewrite(variant.Content); content != variant.Content {
+				variant.Content, changed = content, true
+			}
+		}
+		if !changed {
+			return value
+		}
+		data, _ := json.Marshal(variants)
+		return string(data)
+	}
+	rewrites := []struct {
+		model  any
+		column string
+		where  []any
+		fn     func(string) string
+	}{
+		{&dbr.PostContent{}, "content", []any{"type IN ?", _mediaContentTypes}, plainFn},
+		{&dbr.CommentContent{}, "content", []any{"type = ?", dbr.ContentTypeImage}, plainFn},
+		{&dbr.Attachment{}, "content", nil, plainFn},
+		{&dbr.Attachment{}, "poster", []any{"poster != ?", ""}, plainFn},
+		{&dbr.Attachment{}, "playlist", []any{"playlist != ?", ""}, plainFn},
+		{&dbr.Attachment{}, "variants", []any{"variants != ?", ""}, variantsFn},
+		{&dbr.ObjectBlob{}, "content", nil, plainFn},
+		{&dbr.User{}, "avatar", []any{"avatar != ?", ""}, plainFn},
+		{&dbr.Tag{}, "cover", []any{"cover != ?", ""}, plainFn},
+		{&dbr.LinkPreview{}, "image", []any{"image != ?", ""}, plainFn},
+		{&dbr.PostDraft{}, "contents", nil, itemsFn},
+		{&dbr.PostSchedule{}, "contents", nil, itemsFn},
+		{&dbr.PostRevision{}, "contents", nil, itemsFn},
+	}
+	var updated int64
+	for _, r := range rewrites {
+		err := s.walkColumn(r.model, r.column, r.where, func(rows []*referenceRow) error {
+			for _, row := range rows {
+				value := r.fn(row.Value)
+				if value == row.Value {
+					continue
+				}
+				if err := s.db.Model(r.model).Where("id = ?", row.ID).UpdateColumn(r.column, value).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+		if err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// walkColumn 以主键为游标分批读取未删除记录的指定列
+func (s *objectReferenceSrv) walkColumn(model any, column string, where []any, fn func([]*referenceRow) error) error {
+	var lastId int64
+	for {
+		db := s.db.Model(model).Select("id, "+column+" AS value").Where("id > ? AND is_del = 0", lastId)
+		if len(where) > 0 {
+			db = db.Where(where[0], where[1:]...)
+		}
+		var rows []*referenceRow
+		if err := db.Order("id ASC").Limit(_referenceBatchSize).Scan(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < _referenceBatchSize {
+			return nil
+		}
+		lastId = rows[len(rows)-1].ID
+	}
+}
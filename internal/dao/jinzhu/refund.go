@@ -0,0 +1,123 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"fmt"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.RefundService = (*refundSrv)(nil)
+)
+
+type refundSrv struct {
+	db *gorm.DB
+}
+
+func newRefundService(db *gorm.DB) core.RefundService {
+	return &refundSrv{
+		db: db,
+	}
+}
+
+func (s *refundSrv) CreateAttachmentRefund(refund *ms.AttachmentRefund) (*ms.AttachmentRefund, error) {
+	return refund.Create(s.db)
+}
+
+func (s *refundSrv) GetAttachmentRefund(id int64) (*ms.AttachmentRefund, error) {
+	refund := &dbr.AttachmentRefund{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return refund.Get(s.db)
+}
+
+// GetBillAttachmentRefund 每条购买记录只能申请一次退款，被拒绝后通过申诉处理，不存在时返回nil
+func (s *refundSrv) GetBillAttachmentRefund(billId int64) (*ms.AttachmentRefund, error) {
+	return (&dbr.AttachmentRefund{BillID: billId}).GetByBill(s.db)
+}
+
+func (s *refundSrv) ListAttachmentRefunds(userId int64, authorId int64, status ms.RefundStatus, offset, limit int) ([]*ms.AttachmentRefund, int64, error) {
+	return (&dbr.AttachmentRefund{UserID: userId, AuthorID: authorId}).List(s.db, status, offset, limit)
+}
+
+// UpdateAttachmentRefundStatus 从from状态变更为refund的状态，不涉及退款；状态已被变更时返回false
+func (s *refundSrv) UpdateAttachmentRefundStatus(refund *ms.AttachmentRefund, from ms.RefundStatus) (bool, error) {
+	return refund.UpdateStatus(s.db, from)
+}
+
+// RefundAttachment 从from状态变更为已退款，删除购买记录并冲销购买凭证，购买者收回已付金额，
+// 作者及平台退回各自所得；作者余额不足时返回cs.ErrNoBalance，状态已被变更时返回false
+func (s *refundSrv) RefundAttachment(refund *ms.AttachmentRefund, from ms.RefundStatus) (ok bool, err error) {
+	key := fmt.Sprintf("attachment_refund:%d", refund.ID)
+	renewal := *refund
+	renewal.Status = dbr.RefundStatusRefunded
+	err = withLedger(s.db, key, func(tx *gorm.DB) (err error) {
+		if ok, err = renewal.UpdateStatus(tx, from); err != nil || !ok {
+			return err
+		}
+		bill := &dbr.PostAttachmentBill{
+			Model:  &dbr.Model{ID: refund.BillID},
+			PostID: refund.PostID,
+			UserID: refund.UserID,
+		}
+		if ok, err = bill.Refund(tx); err != nil || !ok {
+			return err
+		}
+		lines, err := attachmentRefundLines(tx, refund, bill)
+		if err != nil {
+			return err
+		}
+		return postJournal(tx, key, "attachment_refund", "附件退款", lines...)
+	})
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		*refund = renewal
+	}
+	return
+}
+
+func (s *refundSrv) ListRefundableAttachmentBills(postId int64, since int64) ([]*ms.PostAttachmentBill, error) {
+	return (&dbr.PostAttachmentBill{PostID: postId}).ListSince(s.db, since)
+}
+
+// attachmentRefundLines 按原购买凭证生成冲销分录，记账前的购买记录按当前分成比例冲销
+func attachmentRefundLines(tx *gorm.DB, refund *dbr.AttachmentRefund, bill *dbr.PostAttachmentBill) ([]*ledgerLine, error) {
+	generation, err := bill.Generation(tx)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := journalLines(tx, attachmentJournalKey(refund.PostID, refund.UserID, generation))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		income := int64(float64(refund.Amount) * conf.AppSetting.AttachmentIncomeRate)
+		return []*ledgerLine{
+			userLine(refund.UserID, refund.Amount, "附件退款", refund.PostID),
+			userLine(refund.AuthorID, -income, "附件退款扣回", refund.PostID),
+			systemLine(_accountRevenue, income-refund.Amount),
+		}, nil
+	}
+	for _, l := range lines {
+		l.amount, l.postId = -l.amount, refund.PostID
+		switch l.userId {
+		case refund.UserID:
+			l.reason = "附件退款"
+		case refund.AuthorID:
+			l.reason = "附件退款扣回"
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TweetRevisionService = (*tweetRevisionSrv)(nil)
+)
+
+type tweetRevisionSrv struct {
+	cacheIndex core.CacheIndexService
+	db         *gorm.DB
+}
+
+func newTweetRevisionService(db *gorm.DB, cacheIndex core.CacheIndexService) core.TweetRevisionService {
+	return &tweetRevisionSrv{
+		cacheIndex: cacheIndex,
+		db:         db,
+	}
+}
+
+func (s *tweetRevisionSrv) EditPost(post *ms.Post, tags []string, contents []*ms.PostContent) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 保存编辑前的推文内容作为修订历史
+		var oldContents []*dbr.PostContent
+		if err := tx.Where("post_id = ? AND is_del = ?", post.ID, 0).Order("sort ASC").Find(&oldContents).Error; err != nil {
+			return err
+		}
+		items := make([]*dbr.PostContentFormated, 0, len(oldContents))
+		for _, content := range oldContents {
+			items = append(items, content.Format())
+		}
+		revision := &dbr.PostRevision{
+			PostID: post.ID,
+			UserID: post.UserID,
+			Tags:   post.Tags,
+		}
+		count, err := revision.Count(tx)
+		if err != nil {
+			return err
+		}
+		revision.Revision = count + 1
+		if err = revision.SetContentItems(items); err != nil {
+			return err
+		}
+		if _, err = revision.Create(tx); err != nil {
+			return err
+		}
+
+		// 替换推文内容
+		if err = (&dbr.PostContent{}).DeleteByPostId(tx, post.ID); err != nil {
+			return err
+		}
+		for _, content := range contents {
+			content.PostID, content.UserID = post.ID, post.UserID
+			if _, err = content.Create(tx); err != nil {
+				return err
+			}
+		}
+		// 只更新编辑涉及的字段，避免覆盖并发更新的评论数、点赞数等
+		now := time.Now().Unix()
+		post.Tags, post.EditedOn, post.ModifiedOn = strings.Join(tags, ","), now, now
+		return tx.Model(&dbr.Post{}).Where("id = ? AND is_del = 0", post.ID).Updates(map[string]any{
+			"tags":        post.Tags,
+			"edited_on":   post.EditedOn,
+			"modified_on": post.ModifiedOn,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.cacheIndex.SendAction(core.IdxActUpdatePost, post)
+	return nil
+}
+
+func (s *tweetRevisionSrv) ListPostRevisions(postId int64, limit, offset int) (res []*ms.PostRevision, total int64, err error) {
+	revision := &dbr.PostRevision{
+		PostID: postId,
+	}
+	if total, err = revision.Count(s.db); err != nil || total == 0 {
+		return
+	}
+	res, err = revision.List(s.db, offset, limit)
+	return
+}
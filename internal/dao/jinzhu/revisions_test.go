@@ -0,0 +1,41 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+)
+
+// discardCacheIndex 忽略所有索引变更
+type discardCacheIndex struct{}
+
+func (discardCacheIndex) SendAction(core.IdxAct, *ms.Post) {}
+
+var _ = g.Describe("TweetRevision", func() {
+	g.It("edit post keeps concurrently updated counters", func() {
+		db := newSqlite3TestDB()
+		post := &dbr.Post{Model: &dbr.Model{}, UserID: 1, Tags: "old"}
+		m.Expect(db.Create(post).Error).To(m.BeNil())
+		// 编辑时持有的推文是读取后被其他请求更新前的旧数据
+		m.Expect(db.Model(&dbr.Post{}).Where("id = ?", post.ID).Updates(map[string]any{"comment_count": 3, "upvote_count": 5}).Error).To(m.BeNil())
+
+		s := newTweetRevisionService(db, discardCacheIndex{})
+		m.Expect(s.EditPost(post, []string{"new"}, []*ms.PostContent{{Content: "edited", Type: ms.ContentTypeText}})).To(m.Succeed())
+		res := &dbr.Post{}
+		m.Expect(db.First(res, post.ID).Error).To(m.BeNil())
+		m.Expect(res.Tags).To(m.Equal("new"))
+		m.Expect(res.EditedOn).NotTo(m.BeZero())
+		m.Expect(res.CommentCount).To(m.Equal(int64(3)))
+		m.Expect(res.UpvoteCount).To(m.Equal(int64(5)))
+		revisions, total, err := s.ListPostRevisions(post.ID, 10, 0)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(total).To(m.Equal(int64(1)))
+		m.Expect(revisions[0].Tags).To(m.Equal("old"))
+	})
+})
@@ -0,0 +1,72 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TweetScheduleService = (*tweetScheduleSrv)(nil)
+)
+
+type tweetScheduleSrv struct {
+	db *gorm.DB
+}
+
+func newTweetScheduleService(db *gorm.DB) core.TweetScheduleService {
+	return &tweetScheduleSrv{
+		db: db,
+	}
+}
+
+func (s *tweetScheduleSrv) CreateScheduledTweet(schedule *ms.PostSchedule) (*ms.PostSchedule, error) {
+	schedule.Status = dbr.PostScheduleStatusPending
+	return schedule.Create(s.db)
+}
+
+func (s *tweetScheduleSrv) GetScheduledTweet(id int64) (*ms.PostSchedule, error) {
+	schedule := &dbr.PostSchedule{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return schedule.Get(s.db)
+}
+
+func (s *tweetScheduleSrv) UpdateScheduledTweet(schedule *ms.PostSchedule, from ms.PostScheduleStatus) (bool, error) {
+	return schedule.Update(s.db, from)
+}
+
+func (s *tweetScheduleSrv) ListUserScheduledTweets(userId int64, limit, offset int) (res []*ms.PostSchedule, total int64, err error) {
+	schedule := &dbr.PostSchedule{
+		UserID: userId,
+	}
+	conditions := &ms.ConditionsT{
+		"status = ?": dbr.PostScheduleStatusPending,
+		"ORDER":      "publish_at ASC, id ASC",
+	}
+	if total, err = schedule.Count(s.db, conditions); err != nil || total == 0 {
+		return
+	}
+	res, err = schedule.List(s.db, conditions, offset, limit)
+	return
+}
+
+func (s *tweetScheduleSrv) ListDueScheduledTweets(now int64, limit int) ([]*ms.PostSchedule, error) {
+	var res []*ms.PostSchedule
+	err := s.db.Where("status = ? AND publish_at <= ? AND is_del = ?", dbr.PostScheduleStatusPending, now, 0).
+		Order("publish_at ASC, id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (s *tweetScheduleSrv) ClaimScheduledTweet(schedule *ms.PostSchedule) (bool, error) {
+	return schedule.Claim(s.db)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+)
+
+var _ = g.Describe("TweetSchedule", func() {
+	var ds core.TweetScheduleService
+
+	g.BeforeEach(func() {
+		ds = newTweetScheduleService(newSqlite3TestDB())
+	})
+
+	newSchedule := func() *ms.PostSchedule {
+		schedule, err := ds.CreateScheduledTweet(&ms.PostSchedule{
+			Model:     &dbr.Model{},
+			UserID:    1,
+			Contents:  "[]",
+			PublishAt: 100,
+		})
+		m.Expect(err).To(m.BeNil())
+		return schedule
+	}
+
+	g.It("user edits do not overwrite a claimed schedule", func() {
+		schedule := newSchedule()
+		// 用户读取后发布任务领取了该定时推文
+		edited, err := ds.GetScheduledTweet(schedule.ID)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(ds.ClaimScheduledTweet(schedule)).To(m.BeTrue())
+
+		edited.Status = ms.PostScheduleStatusCanceled
+		m.Expect(ds.UpdateScheduledTweet(edited, ms.PostScheduleStatusPending)).To(m.BeFalse())
+		edited.Status, edited.PublishAt = ms.PostScheduleStatusPending, 200
+		m.Expect(ds.UpdateScheduledTweet(edited, ms.PostScheduleStatusPending)).To(m.BeFalse())
+
+		schedule.Status, schedule.PostID = ms.PostScheduleStatusPublished, 9
+		m.Expect(ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPublishing)).To(m.BeTrue())
+		res, err := ds.GetScheduledTweet(schedule.ID)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Status).To(m.Equal(ms.PostScheduleStatusPublished))
+		m.Expect(res.PublishAt).To(m.Equal(int64(100)))
+		m.Expect(res.PostID).To(m.Equal(int64(9)))
+	})
+
+	g.It("a canceled schedule can not be claimed", func() {
+		schedule := newSchedule()
+		schedule.Status = ms.PostScheduleStatusCanceled
+		m.Expect(ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPending)).To(m.BeTrue())
+		m.Expect(ds.ClaimScheduledTweet(schedule)).To(m.BeFalse())
+		schedule.Status = ms.PostScheduleStatusPublished
+		m.Expect(ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPublishing)).To(m.BeFalse())
+	})
+})
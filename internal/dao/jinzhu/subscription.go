@@ -0,0 +1,179 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.SubscriptionService = (*subscriptionSrv)(nil)
+)
+
+type subscriptionSrv struct {
+	db *gorm.DB
+}
+
+func newSubscriptionService(db *gorm.DB) core.SubscriptionService {
+	return &subscriptionSrv{
+		db: db,
+	}
+}
+
+func (s *subscriptionSrv) CreateSubscriptionTier(tier *ms.SubscriptionTier) (*ms.SubscriptionTier, error) {
+	return tier.Create(s.db)
+}
+
+func (s *subscriptionSrv) UpdateSubscriptionTier(tier *ms.SubscriptionTier) error {
+	return tier.Update(s.db)
+}
+
+// DeleteSubscriptionTier 删除档位，已订阅该档位的用户在到期后不再续费
+func (s *subscriptionSrv) DeleteSubscriptionTier(tier *ms.SubscriptionTier) error {
+	return tier.Delete(s.db)
+}
+
+// GetSubscriptionTier 获取订阅档位，档位不存在时返回cs.ErrNotExist
+func (s *subscriptionSrv) GetSubscriptionTier(id int64) (*ms.SubscriptionTier, error) {
+	tier := &dbr.SubscriptionTier{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	res, err := tier.Get(s.db)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, cs.ErrNotExist
+	}
+	return res, err
+}
+
+func (s *subscriptionSrv) ListSubscriptionTiers(userId int64) ([]*ms.SubscriptionTier, error) {
+	return (&dbr.SubscriptionTier{UserID: userId}).List(s.db)
+}
+
+func (s *subscriptionSrv) CountSubscriptionTiers(userId int64) (int64, error) {
+	return (&dbr.SubscriptionTier{UserID: userId}).Count(s.db)
+}
+
+// GetSubscription 获取用户对创作者的订阅，从未订阅时返回nil
+func (s *subscriptionSrv) GetSubscription(userId int64, creatorId int64) (*ms.Subscription, error) {
+	return (&dbr.Subscription{UserID: userId, CreatorID: creatorId}).GetByUserCreator(s.db)
+}
+
+func (s *subscriptionSrv) IsSubscriber(userId int64, creatorId int64) bool {
+	subscription, err := s.GetSubscription(userId, creatorId)
+	return err == nil && subscription != nil && subscription.IsActive(time.Now().Unix())
+}
+
+// Subscribe 从钱包扣除首期费用并订阅，订阅有效期内返回cs.ErrSubscribed，余额不足时返回cs.ErrNoBalance
+func (s *subscriptionSrv) Subscribe(userId int64, tier *ms.SubscriptionTier) (*ms.Subscription, error) {
+	now := time.Now().Unix()
+	subscription, err := s.GetSubscription(userId, tier.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription != nil && subscription.IsActive(now) {
+		return nil, cs.ErrSubscribed
+	}
+	key := fmt.Sprintf("subscription:%d:%d:%d", userId, tier.UserID, now)
+	err = withLedger(s.db, key, func(tx *gorm.DB) error {
+		renewal := &dbr.Subscription{
+			UserID:    userId,
+			CreatorID: tier.UserID,
+			TierID:    tier.ID,
+			Price:     tier.Price,
+			AutoRenew: 1,
+			ExpiredOn: conf.SubscriptionSetting.NextExpiredOn(now),
+		}
+		if subscription == nil {
+			if _, err := renewal.Create(tx); err != nil {
+				return err
+			}
+		} else {
+			renewal.Model = &dbr.Model{ID: subscription.ID}
+			if ok, err := renewal.Renew(tx, subscription.ExpiredOn); err != nil {
+				return err
+			} else if !ok {
+				return errJournalPosted
+			}
+		}
+		return postSubscriptionJournal(tx, key, renewal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSubscription(userId, tier.UserID)
+}
+
+// RenewSubscription 按档位当前价格续费一期，档位已删除时关闭自动续费；余额不足时关闭自动续费并返回cs.ErrNoBalance，
+// 已被续费时返回false
+func (s *subscriptionSrv) RenewSubscription(subscription *ms.Subscription) (ok bool, err error) {
+	tier, err := s.GetSubscriptionTier(subscription.TierID)
+	if err == cs.ErrNotExist {
+		return false, s.SetSubscriptionAutoRenew(subscription, false)
+	} else if err != nil {
+		return false, err
+	}
+	key := fmt.Sprintf("subscription:%d:%d", subscription.ID, subscription.ExpiredOn)
+	renewal := *subscription
+	err = withLedger(s.db, key, func(tx *gorm.DB) error {
+		renewal.Price = tier.Price
+		renewal.ExpiredOn = conf.SubscriptionSetting.NextExpiredOn(max(subscription.ExpiredOn, time.Now().Unix()))
+		if ok, err = renewal.Renew(tx, subscription.ExpiredOn); err != nil || !ok {
+			return err
+		}
+		return postSubscriptionJournal(tx, key, &renewal)
+	})
+	switch {
+	case err == cs.ErrNoBalance:
+		if xerr := s.SetSubscriptionAutoRenew(subscription, false); xerr != nil {
+			return false, xerr
+		}
+		return false, err
+	case err != nil:
+		return false, err
+	case ok:
+		*subscription = renewal
+	}
+	return
+}
+
+func (s *subscriptionSrv) SetSubscriptionAutoRenew(subscription *ms.Subscription, autoRenew bool) error {
+	subscription.AutoRenew = 0
+	if autoRenew {
+		subscription.AutoRenew = 1
+	}
+	return subscription.UpdateAutoRenew(s.db)
+}
+
+func (s *subscriptionSrv) ListSubscribers(creatorId int64, offset, limit int) ([]*ms.Subscription, int64, error) {
+	return (&dbr.Subscription{CreatorID: creatorId}).ListSubscribers(s.db, time.Now().Unix(), offset, limit)
+}
+
+func (s *subscriptionSrv) ListUserSubscriptions(userId int64, offset, limit int) ([]*ms.Subscription, int64, error) {
+	return (&dbr.Subscription{UserID: userId}).ListByUser(s.db, offset, limit)
+}
+
+func (s *subscriptionSrv) ListRenewableSubscriptions(now int64, limit int) ([]*ms.Subscription, error) {
+	return (&dbr.Subscription{}).ListRenewable(s.db, now, limit)
+}
+
+// postSubscriptionJournal 订阅费用从订阅者转给创作者，平台按比例抽成
+func postSubscriptionJournal(tx *gorm.DB, key string, subscription *dbr.Subscription) error {
+	income := conf.SubscriptionSetting.Income(subscription.Price)
+	return postJournal(tx, key, "subscription", "创作者订阅",
+		userLine(subscription.UserID, -subscription.Price, "订阅支出", 0),
+		userLine(subscription.CreatorID, income, "订阅收入", 0),
+		systemLine(_accountRevenue, subscription.Price-income))
+}
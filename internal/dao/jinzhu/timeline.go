@@ -27,6 +27,7 @@ type shipIndexSrv struct {
 
 type simpleIndexPostsSrv struct {
 	ths core.TweetHelpService
+	ftl *fanoutTimeline
 	db  *gorm.DB
 }
 
@@ -41,7 +42,12 @@ func (s *shipIndexSrv) IndexPosts(user *ms.User, offset int, limit int) (*ms.Ind
 		friendIds, _ := s.ams.BeFriendIds(user.ID)
 		friendIds = append(friendIds, user.ID)
 		args := []any{dbr.PostVisitPublic, dbr.PostVisitPrivate, user.ID, dbr.PostVisitFriend, friendIds}
-		predicates["visibility = ? OR (visibility = ? AND user_id = ?) OR (visibility = ? AND user_id IN ?)"] = args
+		query := "visibility = ? OR (visibility = ? AND user_id = ?) OR (visibility = ? AND user_id IN ?)"
+		// 订阅的创作者及自己的订阅可见推文
+		subscribedIds, _ := s.ams.SubscribedIds(user.ID)
+		subscribedIds = append(subscribedIds, user.ID)
+		query += " OR (visibility = ? AND user_id IN ?)"
+		predicates[query] = append(args, dbr.PostVisitSubscribe, subscribedIds)
 	}
 
 	posts, err := (&dbr.Post{}).Fetch(s.db, predicates, offset, limit)
@@ -94,9 +100,87 @@ func (s *simpleIndexPostsSrv) IndexPosts(_user *ms.User, offset int, limit int)
 	}, nil
 }
 
+// TweetTimeline 基于写扩散的时间线获取用户的关注动态
 func (s *simpleIndexPostsSrv) TweetTimeline(userId int64, offset int, limit int) (*cs.TweetBox, error) {
-	// TODO
-	return nil, debug.ErrNotImplemented
+	if s.ftl == nil {
+		return nil, debug.ErrNotImplemented
+	}
+	posts, total, err := s.ftl.listTweets(userId, offset, limit)
+	if err != nil {
+		logrus.Debugf("gormSimpleIndexPostsSrv.TweetTimeline err: %v", err)
+		return nil, err
+	}
+	formatPosts, err := s.ths.MergePosts(posts)
+	if err != nil {
+		return nil, err
+	}
+	tweets := make(cs.TweetList, 0, len(formatPosts))
+	for _, post := range formatPosts {
+		tweets = append(tweets, tweetItemFrom(post))
+	}
+	return &cs.TweetBox{
+		Tweets: tweets,
+		Total:  total,
+	}, nil
+}
+
+func tweetItemFrom(p *ms.PostFormated) *cs.TweetItem {
+	item := &cs.TweetItem{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		CommentCount:    p.CommentCount,
+		CollectionCount: p.CollectionCount,
+		UpvoteCount:     p.UpvoteCount,
+		Visibility:      cs.TweetVisibleType(p.Visibility),
+		IsTop:           p.IsTop,
+		IsEssence:       p.IsEssence,
+		IsLock:          p.IsLock,
+		LatestRepliedOn: p.LatestRepliedOn,
+		CreatedOn:       p.CreatedOn,
+		ModifiedOn:      p.ModifiedOn,
+		Tags:            p.Tags,
+		AttachmentPrice: p.AttachmentPrice,
+		IPLoc:           p.IPLoc,
+	}
+	if p.User != nil {
+		item.User = &cs.UserInfo{
+			ID:       p.User.ID,
+			Nickname: p.User.Nickname,
+			Username: p.User.Username,
+			Status:   p.User.Status,
+			Avatar:   p.User.Avatar,
+			IsAdmin:  p.User.IsAdmin,
+		}
+	}
+	for _, content := range p.Contents {
+		item.Contents = append(item.Contents, &cs.TweetBlock{
+			ID:      content.ID,
+			PostID:  content.PostID,
+			Content: content.Content,
+			Type:    cs.TweetBlockType(content.Type),
+			Sort:    content.Sort,
+		})
+	}
+	if p.Poll != nil {
+		item.Poll = &cs.TweetPoll{
+			ID:        p.Poll.ID,
+			TweetID:   p.Poll.PostID,
+			Multiple:  p.Poll.Multiple,
+			Anonymous: p.Poll.Anonymous,
+			VoteCount: p.Poll.VoteCount,
+			ExpiredOn: p.Poll.ExpiredOn,
+			ClosedOn:  p.Poll.ClosedOn,
+		}
+		for _, option := range p.Poll.Options {
+			item.Poll.Options = append(item.Poll.Options, &cs.TweetPollOption{
+				ID:        option.ID,
+				Content:   option.Content,
+				Sort:      option.Sort,
+				VoteCount: option.VoteCount,
+			})
+		}
+	}
+	return item
 }
 
 func newShipIndexService(db *gorm.DB, ams core.AuthorizationManageService, ths core.TweetHelpService) core.IndexPostsService {
@@ -107,9 +191,13 @@ func newShipIndexService(db *gorm.DB, ams core.AuthorizationManageService, ths c
 	}
 }
 
-func newSimpleIndexPostsService(db *gorm.DB, ths core.TweetHelpService) core.IndexPostsService {
-	return &simpleIndexPostsSrv{
+func newSimpleIndexPostsService(db *gorm.DB, ths core.TweetHelpService, tc core.TimelineCache) core.IndexPostsService {
+	s := &simpleIndexPostsSrv{
 		ths: ths,
 		db:  db,
 	}
+	if tc != nil {
+		s.ftl = newFanoutTimeline(db, tc)
+	}
+	return s
 }
@@ -0,0 +1,73 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"fmt"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TipService = (*tipSrv)(nil)
+)
+
+type tipSrv struct {
+	db *gorm.DB
+}
+
+func newTipService(db *gorm.DB) core.TipService {
+	return &tipSrv{
+		db: db,
+	}
+}
+
+// TipPost 从用户钱包扣除充电金额转给推文作者，平台按比例抽成，余额不足时返回cs.ErrNoBalance；
+// 以客户端请求ID作为幂等键，重复请求返回已有的充电记录
+func (s *tipSrv) TipPost(user *ms.User, post *ms.Post, amount int64, message string, requestId string) (*ms.PostTip, error) {
+	key := fmt.Sprintf("tip:%d:%d:%s", post.ID, user.ID, requestId)
+	var tip *dbr.PostTip
+	err := withLedger(s.db, key, func(tx *gorm.DB) (err error) {
+		income := int64(float64(amount) * conf.AppSetting.TipIncomeRate)
+		err = postJournal(tx, key, "tip", "推文充电",
+			userLine(user.ID, -amount, "充电支出", post.ID),
+			userLine(post.UserID, income, "充电收入", post.ID),
+			systemLine(_accountRevenue, amount-income))
+		if err != nil {
+			return
+		}
+		tip, err = (&dbr.PostTip{
+			PostID:    post.ID,
+			UserID:    user.ID,
+			AuthorID:  post.UserID,
+			Amount:    amount,
+			Message:   message,
+			RequestID: requestId,
+		}).Create(tx)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		// 凭证已记账，为重复的请求
+		return (&dbr.PostTip{UserID: user.ID, PostID: post.ID, RequestID: requestId}).GetByRequest(s.db)
+	}
+	return tip, nil
+}
+
+func (s *tipSrv) ListPostTips(postId int64, offset, limit int) ([]*ms.PostTip, int64, error) {
+	return (&dbr.PostTip{PostID: postId}).ListByPost(s.db, offset, limit)
+}
+
+// IsPostCharger 用户为推文累计充电是否达到查看充电可见内容的门槛
+func (s *tipSrv) IsPostCharger(userId int64, postId int64) bool {
+	amount, err := (&dbr.PostTip{UserID: userId, PostID: postId}).SumByUserPost(s.db)
+	return err == nil && amount >= conf.AppSetting.ChargeThreshold
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.TopicManageService = (*topicManageSrv)(nil)
+)
+
+type topicManageSrv struct {
+	db *gorm.DB
+}
+
+func newTopicManageService(db *gorm.DB) core.TopicManageService {
+	return &topicManageSrv{
+		db: db,
+	}
+}
+
+// GetTopic 获取话题主页信息，别名话题返回合并后的话题
+func (s *topicManageSrv) GetTopic(topicId int64) (*cs.TopicInfo, error) {
+	tag, err := (&dbr.Tag{Model: &dbr.Model{ID: topicId}}).Get(s.db)
+	if err != nil {
+		return nil, err
+	}
+	return topicInfoFrom(canonicalTag(s.db, tag)), nil
+}
+
+// GetTopicByName 按话题名获取话题主页信息，别名话题返回合并后的话题
+func (s *topicManageSrv) GetTopicByName(name string) (*cs.TopicInfo, error) {
+	tag, err := (&dbr.Tag{Tag: name}).Get(s.db)
+	if err != nil {
+		return nil, err
+	}
+	return topicInfoFrom(canonicalTag(s.db, tag)), nil
+}
+
+// GetTopicAliases 合并到该话题的别名
+func (s *topicManageSrv) GetTopicAliases(topicId int64) (res []string, err error) {
+	err = s.db.Model(&dbr.Tag{}).Where("alias_of = ? AND is_del = 0", topicId).Order("id ASC").Pluck("tag", &res).Error
+	return
+}
+
+func (s *topicManageSrv) IsFollowingTopic(userId int64, topicId int64) bool {
+	var count int64
+	s.db.Model(&dbr.TopicUser{}).Where("user_id = ? AND topic_id = ?", userId, topicId).Count(&count)
+	return count > 0
+}
+
+func (s *topicManageSrv) UpdateTopic(topicId int64, description string, cover string) error {
+	return s.db.Model(&dbr.Tag{}).Where("id = ? AND is_del = 0", topicId).Updates(map[string]any{
+		"description": description,
+		"cover":       cover,
+	}).Error
+}
+
+func (s *topicManageSrv) ChangeTopicStatus(topicId int64, isLocked int8, isHidden int8) error {
+	return s.db.Model(&dbr.Tag{}).Where("id = ? AND is_del = 0", topicId).Updates(map[string]any{
+		"is_locked": isLocked,
+		"is_hidden": isHidden,
+	}).Error
+}
+
+// MergeTopic 将源话题合并为目标话题的别名，引用数、别名、关注者、主持人及置顶推文一并转移到目标话题
+func (s *topicManageSrv) MergeTopic(sourceId int64, targetId int64) error {
+	if sourceId == targetId {
+		return errors.New("cannot merge topic into itself")
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		source, err := (&dbr.Tag{Model: &dbr.Model{ID: sourceId}}).Get(tx)
+		if err != nil {
+			return err
+		}
+		target, err := (&dbr.Tag{Model: &dbr.Model{ID: targetId}}).Get(tx)
+		if err != nil {
+			return err
+		}
+		if target.AliasOf > 0 {
+			return errors.New("cannot merge topic into an alias")
+		}
+		if err = tx.Model(&dbr.Tag{}).Where("id = ?", targetId).
+			Update("quote_num", gorm.Expr("quote_num + ?", source.QuoteNum)).Error; err != nil {
+			return err
+		}
+		if err = tx.Model(&dbr.Tag{}).Where("id = ?", sourceId).Updates(map[string]any{
+			"alias_of":  targetId,
+			"quote_num": 0,
+		}).Error; err != nil {
+			return err
+		}
+		if err = tx.Model(&dbr.Tag{}).Where("alias_of = ?", sourceId).Update("alias_of", targetId).Error; err != nil {
+			return err
+		}
+		if err = moveTopicRows(tx, &dbr.TopicUser{}, "user_id", sourceId, targetId); err != nil {
+			return err
+		}
+		if err = moveTopicRows(tx, &dbr.TopicModerator{}, "user_id", sourceId, targetId); err != nil {
+			return err
+		}
+		if err = moveTopicRows(tx, &dbr.TopicPin{}, "post_id", sourceId, targetId); err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("tag_id = ?", sourceId).Delete(&dbr.TagTrending{}).Error
+	})
+}
+
+func (s *topicManageSrv) ListTopicModerators(topicId int64) ([]int64, error) {
+	return (&dbr.TopicModerator{TopicID: topicId}).UserIds(s.db)
+}
+
+func (s *topicManageSrv) IsTopicModerator(topicId int64, userId int64) bool {
+	return (&dbr.TopicModerator{TopicID: topicId, UserID: userId}).Exist(s.db)
+}
+
+func (s *topicManageSrv) AddTopicModerator(topicId int64, userId int64) error {
+	m := &dbr.TopicModerator{TopicID: topicId, UserID: userId}
+	if m.Exist(s.db) {
+		return nil
+	}
+	return m.Create(s.db)
+}
+
+func (s *topicManageSrv) RemoveTopicModerator(topicId int64, userId int64) error {
+	return (&dbr.TopicModerator{TopicID: topicId, UserID: userId}).Delete(s.db)
+}
+
+func (s *topicManageSrv) ListTopicPins(topicId int64) ([]int64, error) {
+	return (&dbr.TopicPin{TopicID: topicId}).PostIds(s.db)
+}
+
+// PinTopicTweet 切换推文在话题内的置顶状态，返回是否已置顶，置顶数达到maxPins时返回cs.ErrTooManyPins
+func (s *topicManageSrv) PinTopicTweet(topicId int64, postId int64, userId int64, maxPins int) (pinned bool, err error) {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		pin := &dbr.TopicPin{TopicID: topicId, PostID: postId, UserID: userId}
+		if existed, err := pin.Delete(tx); err != nil || existed {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&dbr.TopicPin{}).Where("topic_id = ? AND is_del = 0", topicId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPins) {
+			return cs.ErrTooManyPins
+		}
+		pinned = true
+		return pin.Create(tx)
+	})
+	return
+}
+
+// ListTopicTweets 引用了话题或其别名的公开推文，按发布时间倒序
+func (s *topicManageSrv) ListTopicTweets(topicId int64, limit int, offset int) (res []*ms.Post, total int64, err error) {
+	tag, err := (&dbr.Tag{Model: &dbr.Model{ID: topicId}}).Get(s.db)
+	if err != nil {
+		return
+	}
+	aliases, err := s.GetTopicAliases(tag.ID)
+	if err != nil {
+		return
+	}
+	// 推文的话题以逗号分隔存储
+	conditions, args := []string{}, []any{}
+	for _, name := range append([]string{tag.Tag}, aliases...) {
+		conditions = append(conditions, "tags = ? OR tags LIKE ? OR tags LIKE ? OR tags LIKE ?")
+		args = append(args, name, name+",%", "%,"+name, "%,"+name+",%")
+	}
+	db := s.db.Model(&dbr.Post{}).Where("visibility >= ?", cs.TweetVisitPublic).
+		Where("("+strings.Join(conditions, " OR ")+")", args...)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	err = db.Order("id DESC").Find(&res).Error
+	return
+}
+
+// moveTopicRows 将源话题的关联记录转移到目标话题，目标话题已有的记录直接删除
+func moveTopicRows(tx *gorm.DB, model any, column string, sourceId, targetId int64) error {
+	var exists []int64
+	if err := tx.Unscoped().Model(model).Where("topic_id = ?", targetId).Pluck(column, &exists).Error; err != nil {
+		return err
+	}
+	db := tx.Model(model).Where("topic_id = ?", sourceId)
+	if len(exists) > 0 {
+		db = db.Where(column+" NOT IN ?", exists)
+	}
+	if err := db.Update("topic_id", targetId).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("topic_id = ?", sourceId).Delete(model).Error
+}
+
+func topicInfoFrom(tag *dbr.Tag) *cs.TopicInfo {
+	return &cs.TopicInfo{
+		ID:          tag.ID,
+		UserID:      tag.UserID,
+		Tag:         tag.Tag,
+		QuoteNum:    tag.QuoteNum,
+		Description: tag.Description,
+		Cover:       tag.Cover,
+		IsLocked:    tag.IsLocked,
+		IsHidden:    tag.IsHidden,
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"gorm.io/gorm"
 )
 
@@ -59,7 +60,12 @@ func (s *topicSrv) UpsertTags(userId int64, tags []string) (_ cs.TagInfoList, er
 			db.Rollback()
 		}
 	}()
-	return createTags(db, userId, tags)
+	res, err := createTags(db, userId, tags)
+	if err == nil {
+		// 记录话题引用次数用于计算热议话题
+		onTagUsageEvent(s.db, res)
+	}
+	return res, err
 }
 
 func (s *topicSrv) DecrTagsById(ids []int64) (err error) {
@@ -111,6 +117,24 @@ func (s *topicSrv) GetNewestTags(userId int64, limit int, offset int) (cs.TagLis
 	return s.tagsFormatA(userId, tags)
 }
 
+// GetNewestTagsByCursor 按创建时间倒序的话题
+func (s *topicSrv) GetNewestTagsByCursor(userId int64, c *cs.Cursor, limit int) (cs.TagList, *cs.Cursor, error) {
+	var tags []*dbr.Tag
+	db := s.db.Model(&dbr.Tag{}).Where("quote_num > 0 AND alias_of = 0 AND is_hidden = 0")
+	if err := dbr.KeysetPage(db, "", c, true, limit).Find(&tags).Error; err != nil {
+		return nil, nil, err
+	}
+	tags, next := cursor.Next(tags, limit, (*dbr.Tag).Cursor)
+	res, err := s.formatTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res, err = s.tagsFormatA(userId, res); err != nil {
+		return nil, nil, err
+	}
+	return res, next, nil
+}
+
 func (s *topicSrv) GetFollowTags(userId int64, isPin bool, limit int, offset int) (cs.TagList, error) {
 	if userId < 0 {
 		return nil, nil
@@ -153,38 +177,41 @@ func (s *topicSrv) GetFollowTags(userId int64, isPin bool, limit int, offset int
 }
 
 func (s *topicSrv) listTags(conditions *ms.ConditionsT, limit int, offset int) (res cs.TagList, err error) {
+	tags, err := (&dbr.Tag{}).List(s.db, conditions, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.formatTags(tags)
+}
+
+// formatTags 附上话题创建者的用户信息
+func (s *topicSrv) formatTags(tags []*dbr.Tag) (res cs.TagList, err error) {
 	// TODO: 优化查询方式，直接返回[]*core.Tag, 目前保持先转换一下
-	var (
-		tags []*dbr.Tag
-		item *cs.TagItem
-	)
-	if tags, err = (&dbr.Tag{}).List(s.db, conditions, offset, limit); err == nil {
-		if len(tags) == 0 {
-			return
-		}
-		tagMap := make(map[int64][]*cs.TagItem, len(tags))
-		for _, tag := range tags {
-			item = &cs.TagItem{
-				ID:       tag.ID,
-				UserID:   tag.UserID,
-				Tag:      tag.Tag,
-				QuoteNum: tag.QuoteNum,
-			}
-			tagMap[item.UserID] = append(tagMap[item.UserID], item)
-			res = append(res, item)
-		}
-		ids := make([]int64, 0, len(tagMap))
-		for userId := range tagMap {
-			ids = append(ids, userId)
-		}
-		userInfos, err := (&dbr.User{}).ListUserInfoById(s.db, ids)
-		if err != nil {
-			return nil, err
+	if len(tags) == 0 {
+		return
+	}
+	tagMap := make(map[int64][]*cs.TagItem, len(tags))
+	for _, tag := range tags {
+		item := &cs.TagItem{
+			ID:       tag.ID,
+			UserID:   tag.UserID,
+			Tag:      tag.Tag,
+			QuoteNum: tag.QuoteNum,
 		}
-		for _, userInfo := range userInfos {
-			for _, item = range tagMap[userInfo.ID] {
-				item.User = userInfo
-			}
+		tagMap[item.UserID] = append(tagMap[item.UserID], item)
+		res = append(res, item)
+	}
+	ids := make([]int64, 0, len(tagMap))
+	for userId := range tagMap {
+		ids = append(ids, userId)
+	}
+	userInfos, err := (&dbr.User{}).ListUserInfoById(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, userInfo := range userInfos {
+		for _, item := range tagMap[userInfo.ID] {
+			item.User = userInfo
 		}
 	}
 	return
@@ -247,6 +274,25 @@ func (s *topicSrv) tagsFormatB(userTopicsMap map[int64]*topicInfo, tags cs.TagIn
 	return tagList, nil
 }
 
+func (s *topicSrv) TagsByNames(names []string) (res cs.TagInfoList, err error) {
+	if len(names) == 0 {
+		return
+	}
+	tags, err := (&dbr.Tag{}).TagsFrom(s.db, names)
+	if err != nil {
+		return
+	}
+	for _, tag := range tags {
+		res = append(res, &cs.TagInfo{
+			ID:       tag.ID,
+			UserID:   tag.UserID,
+			Tag:      tag.Tag,
+			QuoteNum: tag.QuoteNum,
+		})
+	}
+	return
+}
+
 func (s *topicSrv) TagsByKeyword(keyword string) (res cs.TagInfoList, err error) {
 	keyword = "%" + strings.Trim(keyword, " ") + "%"
 	tag := &dbr.Tag{}
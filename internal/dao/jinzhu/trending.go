@@ -0,0 +1,158 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alimy/tryst/event"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/internal/infra/events"
+	"github.com/rocboss/paopao-ce/pkg/trending"
+	"gorm.io/gorm"
+)
+
+type tagUsageEvent struct {
+	event.UnimplementedEvent
+	db     *gorm.DB
+	tagIds []int64
+	bucket int64
+}
+
+func (e *tagUsageEvent) Name() string {
+	return "tagUsageEvent"
+}
+
+func (e *tagUsageEvent) Action() (err error) {
+	for _, id := range e.tagIds {
+		if err = (&dbr.TagUsage{TagID: id, Bucket: e.bucket, QuoteNum: 1}).Incr(e.db); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+func onTagUsageEvent(db *gorm.DB, tags cs.TagInfoList) {
+	if len(tags) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	events.OnEvent(&tagUsageEvent{
+		db:     db,
+		tagIds: ids,
+		bucket: trending.Bucket(time.Now().Unix()),
+	})
+}
+
+// GetTrendingTags 热议话题排行，还未计算出排行时以热门话题代替
+func (s *topicSrv) GetTrendingTags(userId int64, limit int, offset int) (cs.TagList, error) {
+	trendings, err := (&dbr.TagTrending{}).List(s.db, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(trendings) == 0 {
+		if offset > 0 {
+			return nil, nil
+		}
+		return s.GetHotTags(userId, limit, offset)
+	}
+	ids := make([]int64, 0, len(trendings))
+	for _, t := range trendings {
+		ids = append(ids, t.TagID)
+	}
+	var tags []*dbr.Tag
+	if err = s.db.Where("id IN ? AND is_del = 0", ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	// 保持排行的顺序
+	tagMap := make(map[int64]*dbr.Tag, len(tags))
+	for _, tag := range tags {
+		tagMap[tag.ID] = tag
+	}
+	tags = tags[:0]
+	for _, id := range ids {
+		if tag, exist := tagMap[id]; exist {
+			tags = append(tags, tag)
+		}
+	}
+	res, err := s.formatTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	return s.tagsFormatA(userId, res)
+}
+
+// RefreshTrendingTags 按最近1小时、24小时、7天的引用次数重新计算热议话题排行，并清理过期的统计桶
+func (s *topicSrv) RefreshTrendingTags() error {
+	st := conf.TrendingTagsSetting
+	now := time.Now().Unix()
+	hourSince, daySince, weekSince := trending.Bucket(now-trending.WindowHour), trending.Bucket(now-trending.WindowDay), trending.Bucket(now-trending.WindowWeek)
+	stats, err := (&dbr.TagUsage{}).StatsSince(s.db, hourSince, daySince, weekSince)
+	if err != nil {
+		return err
+	}
+	usages := make([]*trending.Usage, 0, len(stats))
+	ids := make([]int64, 0, len(stats))
+	for _, stat := range stats {
+		usages = append(usages, &trending.Usage{
+			TagID: stat.TagID,
+			Hour:  stat.HourNum,
+			Day:   stat.DayNum,
+			Week:  stat.WeekNum,
+		})
+		ids = append(ids, stat.TagID)
+	}
+	usages = trending.Rank(usages, &trending.Options{
+		MinUsage:  st.MinUsage,
+		Smoothing: st.Smoothing,
+	})
+	// 排除已删除、不再被引用、已合并、隐藏及屏蔽的话题
+	var tags []*dbr.Tag
+	if len(ids) > 0 {
+		if err = s.db.Where("id IN ? AND is_del = 0 AND quote_num > 0 AND alias_of = 0 AND is_hidden = 0", ids).Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+	blocked := make(map[string]bool, len(st.BlockedTags))
+	for _, name := range st.BlockedTags {
+		blocked[normalizeTagName(name)] = true
+	}
+	allowed := make(map[int64]bool, len(tags))
+	for _, tag := range tags {
+		if !blocked[normalizeTagName(tag.Tag)] {
+			allowed[tag.ID] = true
+		}
+	}
+	items := make([]*dbr.TagTrending, 0, len(usages))
+	for _, u := range usages {
+		if st.MaxSize > 0 && len(items) >= st.MaxSize {
+			break
+		}
+		if !allowed[u.TagID] {
+			continue
+		}
+		items = append(items, &dbr.TagTrending{
+			TagID:   u.TagID,
+			Score:   u.Score,
+			HourNum: u.Hour,
+			DayNum:  u.Day,
+			WeekNum: u.Week,
+		})
+	}
+	if err = (&dbr.TagTrending{}).Replace(s.db, items); err != nil {
+		return err
+	}
+	return (&dbr.TagUsage{}).DeleteBefore(s.db, weekSince)
+}
+
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+}
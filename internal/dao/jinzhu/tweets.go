@@ -13,6 +13,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
 	"github.com/rocboss/paopao-ce/pkg/debug"
 	"gorm.io/gorm"
 )
@@ -236,12 +237,23 @@ func (s *tweetManageSrv) DeletePost(post *ms.Post) ([]string, error) {
 			}
 
 			// 删评论
-			if contents, err := s.deleteCommentByPostId(tx, postId); err == nil {
-				mediaContents = append(mediaContents, contents...)
-			} else {
+			commentContents, err := s.deleteCommentByPostId(tx, postId)
+			if err != nil {
 				return err
 			}
 
+			// 释放媒体内容的引用，只返回不再被引用的媒体内容，评论的媒体内容属于各评论者
+			blob := &dbr.ObjectBlob{}
+			if mediaContents, err = blob.Release(tx, post.UserID, mediaContents); err != nil {
+				return err
+			}
+			for userId, contents := range commentContents {
+				if contents, err = blob.Release(tx, userId, contents); err != nil {
+					return err
+				}
+				mediaContents = append(mediaContents, contents...)
+			}
+
 			if tags := strings.Split(post.Tags, ","); len(tags) > 0 {
 				// 删tag，宽松处理错误，有错误不会回滚
 				deleteTags(tx, tags)
@@ -259,7 +271,7 @@ func (s *tweetManageSrv) DeletePost(post *ms.Post) ([]string, error) {
 	return mediaContents, nil
 }
 
-func (s *tweetManageSrv) deleteCommentByPostId(db *gorm.DB, postId int64) ([]string, error) {
+func (s *tweetManageSrv) deleteCommentByPostId(db *gorm.DB, postId int64) (map[int64][]string, error) {
 	comment := &dbr.Comment{}
 	commentContent := &dbr.CommentContent{}
 
@@ -392,25 +404,66 @@ func (s *tweetSrv) GetPosts(conditions ms.ConditionsT, offset, limit int) ([]*ms
 	return (&dbr.Post{}).List(s.db, conditions, offset, limit)
 }
 
-func (s *tweetSrv) ListUserTweets(userId int64, style uint8, justEssence bool, limit, offset int) (res []*ms.Post, total int64, err error) {
+// ListUserTweets subscribed为访问者是否订阅了该用户，订阅者可以看到订阅可见的推文；
+// 充电可见的推文对所有访问者列出，由调用方决定是否仅展示预览
+func (s *tweetSrv) ListUserTweets(userId int64, style uint8, subscribed bool, justEssence bool, limit, offset int) (res []*ms.Post, total int64, err error) {
+	db := s.userTweetsQuery(userId, style, subscribed, justEssence)
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if offset >= 0 && limit > 0 {
+		db = db.Offset(offset).Limit(limit)
+	}
+	if err = db.Order("is_top DESC, latest_replied_on DESC").Find(&res).Error; err != nil {
+		return
+	}
+	return
+}
+
+// ListUserTweetsByCursor 按发布时间倒序的用户推文，不区分置顶
+func (s *tweetSrv) ListUserTweetsByCursor(userId int64, style uint8, subscribed bool, justEssence bool, c *cs.Cursor, limit int) (res []*ms.Post, next *cs.Cursor, err error) {
+	db := s.userTweetsQuery(userId, style, subscribed, justEssence)
+	if err = dbr.KeysetPage(db, "", c, true, limit).Find(&res).Error; err != nil {
+		return
+	}
+	res, next = cursor.Next(res, limit, (*ms.Post).Cursor)
+	return
+}
+
+func (s *tweetSrv) userTweetsQuery(userId int64, style uint8, subscribed bool, justEssence bool) *gorm.DB {
 	db := s.db.Model(&dbr.Post{}).Where("user_id = ?", userId)
+	visibility := cs.TweetVisitPublic
 	switch style {
 	case cs.StyleUserTweetsAdmin:
 		fallthrough
 	case cs.StyleUserTweetsSelf:
-		db = db.Where("visibility >= ?", cs.TweetVisitPrivate)
+		visibility = cs.TweetVisitPrivate
 	case cs.StyleUserTweetsFriend:
-		db = db.Where("visibility >= ?", cs.TweetVisitFriend)
+		visibility = cs.TweetVisitFriend
 	case cs.StyleUserTweetsFollowing:
-		db = db.Where("visibility >= ?", cs.TweetVisitFollowing)
+		visibility = cs.TweetVisitFollowing
 	case cs.StyleUserTweetsGuest:
 		fallthrough
 	default:
-		db = db.Where("visibility >= ?", cs.TweetVisitPublic)
+		// nothing
+	}
+	extras := []cs.TweetVisibleType{cs.TweetVisitCharge}
+	if subscribed {
+		extras = append(extras, cs.TweetVisitSubscribe)
+	}
+	if visibility > cs.TweetVisitCharge {
+		db = db.Where("(visibility >= ? OR visibility IN ?)", visibility, extras)
+	} else {
+		db = db.Where("visibility >= ?", visibility)
 	}
 	if justEssence {
 		db = db.Where("is_essence=1")
 	}
+	return db
+}
+
+func (s *tweetSrv) ListIndexNewestTweets(limit, offset int) (res []*ms.Post, total int64, err error) {
+	db := s.db.Table(_post_).Where("visibility >= ?", cs.TweetVisitPublic)
 	if err = db.Count(&total).Error; err != nil {
 		return
 	}
@@ -423,17 +476,13 @@ func (s *tweetSrv) ListUserTweets(userId int64, style uint8, justEssence bool, l
 	return
 }
 
-func (s *tweetSrv) ListIndexNewestTweets(limit, offset int) (res []*ms.Post, total int64, err error) {
+// ListIndexNewestTweetsByCursor 按发布时间倒序的广场推文，不区分置顶
+func (s *tweetSrv) ListIndexNewestTweetsByCursor(c *cs.Cursor, limit int) (res []*ms.Post, next *cs.Cursor, err error) {
 	db := s.db.Table(_post_).Where("visibility >= ?", cs.TweetVisitPublic)
-	if err = db.Count(&total).Error; err != nil {
-		return
-	}
-	if offset >= 0 && limit > 0 {
-		db = db.Offset(offset).Limit(limit)
-	}
-	if err = db.Order("is_top DESC, latest_replied_on DESC").Find(&res).Error; err != nil {
+	if err = dbr.KeysetPage(db, "", c, true, limit).Find(&res).Error; err != nil {
 		return
 	}
+	res, next = cursor.Next(res, limit, (*ms.Post).Cursor)
 	return
 }
 
@@ -466,22 +515,9 @@ func (s *tweetSrv) ListSyncSearchTweets(limit, offset int) (res []*ms.Post, tota
 }
 
 func (s *tweetSrv) ListFollowingTweets(userId int64, limit, offset int) (res []*ms.Post, total int64, err error) {
-	beFriendIds, beFollowIds, xerr := s.getUserRelation(userId)
-	if xerr != nil {
-		return nil, 0, xerr
-	}
-	beFriendCount, beFollowCount := len(beFriendIds), len(beFollowIds)
-	db := s.db.Model(&dbr.Post{})
-	//可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开',
-	switch {
-	case beFriendCount > 0 && beFollowCount > 0:
-		db = db.Where("user_id=? OR (visibility>=50 AND user_id IN(?)) OR (visibility>=60 AND user_id IN(?))", userId, beFriendIds, beFollowIds)
-	case beFriendCount > 0 && beFollowCount == 0:
-		db = db.Where("user_id=? OR (visibility>=50 AND user_id IN(?))", userId, beFriendIds)
-	case beFriendCount == 0 && beFollowCount > 0:
-		db = db.Where("user_id=? OR (visibility>=60 AND user_id IN(?))", userId, beFollowIds)
-	case beFriendCount == 0 && beFollowCount == 0:
-		db = db.Where("user_id = ?", userId)
+	db, err := s.followingTweetsQuery(userId)
+	if err != nil {
+		return
 	}
 	if err = db.Count(&total).Error; err != nil {
 		return
@@ -495,6 +531,43 @@ func (s *tweetSrv) ListFollowingTweets(userId int64, limit, offset int) (res []*
 	return
 }
 
+// ListFollowingTweetsByCursor 按发布时间倒序的关注动态，不区分置顶
+func (s *tweetSrv) ListFollowingTweetsByCursor(userId int64, c *cs.Cursor, limit int) (res []*ms.Post, next *cs.Cursor, err error) {
+	db, err := s.followingTweetsQuery(userId)
+	if err != nil {
+		return
+	}
+	if err = dbr.KeysetPage(db, "", c, true, limit).Find(&res).Error; err != nil {
+		return
+	}
+	res, next = cursor.Next(res, limit, (*ms.Post).Cursor)
+	return
+}
+
+func (s *tweetSrv) followingTweetsQuery(userId int64) (*gorm.DB, error) {
+	beFriendIds, beFollowIds, err := s.getUserRelation(userId)
+	if err != nil {
+		return nil, err
+	}
+	subscribedIds, err := (&dbr.Subscription{UserID: userId}).ActiveCreatorIds(s.db, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	//可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开',
+	conditions, args := []string{"user_id=?"}, []any{userId}
+	if len(beFriendIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=50 AND user_id IN(?))"), append(args, beFriendIds)
+	}
+	if len(beFollowIds) > 0 {
+		conditions, args = append(conditions, "(visibility>=60 AND user_id IN(?))"), append(args, beFollowIds)
+	}
+	// 订阅的创作者的订阅可见推文
+	if len(subscribedIds) > 0 {
+		conditions, args = append(conditions, "(visibility=20 AND user_id IN(?))"), append(args, subscribedIds)
+	}
+	return s.db.Model(&dbr.Post{}).Where(strings.Join(conditions, " OR "), args...), nil
+}
+
 func (s *tweetSrv) getUserRelation(userId int64) (beFriendIds []int64, beFollowIds []int64, err error) {
 	if err = s.db.Table(_contact_).Where("friend_id=? AND status=2 AND is_del=0", userId).Select("user_id").Find(&beFriendIds).Error; err != nil {
 		return
@@ -555,7 +628,7 @@ func (s *tweetSrv) getUserTweets(db *gorm.DB, user *cs.VistUser, limit int, offs
 	visibilities := []core.PostVisibleT{core.PostVisitPublic}
 	switch user.RelTyp {
 	case cs.RelationAdmin, cs.RelationSelf:
-		visibilities = append(visibilities, core.PostVisitPrivate, core.PostVisitFriend)
+		visibilities = append(visibilities, core.PostVisitPrivate, core.PostVisitFriend, core.PostVisitSubscribe, core.PostVisitCharge)
 	case cs.RelationFriend:
 		visibilities = append(visibilities, core.PostVisitFriend)
 	case cs.RelationGuest:
@@ -563,6 +636,9 @@ func (s *tweetSrv) getUserTweets(db *gorm.DB, user *cs.VistUser, limit int, offs
 	default:
 		// nothing
 	}
+	if user.IsSubscriber && user.RelTyp != cs.RelationAdmin && user.RelTyp != cs.RelationSelf {
+		visibilities = append(visibilities, core.PostVisitSubscribe)
+	}
 	db = db.Where("visibility IN ? AND is_del=0", visibilities)
 	err = db.Count(&total).Error
 	if err != nil {
@@ -600,21 +676,29 @@ func (s *tweetSrv) GetUserPostCollection(postID, userID int64) (*ms.PostCollecti
 	return star.Get(s.db)
 }
 
-func (s *tweetSrv) GetUserPostCollections(userID int64, offset, limit int) ([]*ms.PostCollection, error) {
+// GetUserPostCollections 用户的收藏，folderID为ms.CollectionFolderAll时返回全部收藏
+func (s *tweetSrv) GetUserPostCollections(userID int64, folderID int64, offset, limit int) ([]*ms.PostCollection, error) {
 	collection := &dbr.PostCollection{
 		UserID: userID,
 	}
-
-	return collection.List(s.db, &dbr.ConditionsT{
+	conditions := dbr.ConditionsT{
 		"ORDER": s.db.NamingStrategy.TableName("PostCollection") + ".id DESC",
-	}, offset, limit)
+	}
+	if folderID != ms.CollectionFolderAll {
+		conditions["folder_id = ?"] = folderID
+	}
+	return collection.List(s.db, &conditions, offset, limit)
 }
 
-func (s *tweetSrv) GetUserPostCollectionCount(userID int64) (int64, error) {
+func (s *tweetSrv) GetUserPostCollectionCount(userID int64, folderID int64) (int64, error) {
 	collection := &dbr.PostCollection{
 		UserID: userID,
 	}
-	return collection.Count(s.db, &dbr.ConditionsT{})
+	conditions := dbr.ConditionsT{}
+	if folderID != ms.CollectionFolderAll {
+		conditions["folder_id = ?"] = folderID
+	}
+	return collection.Count(s.db, &conditions)
 }
 
 func (s *tweetSrv) GetUserWalletBills(userID int64, offset, limit int) ([]*ms.WalletStatement, error) {
@@ -658,6 +742,13 @@ func (s *tweetSrv) GetPostContentByID(id int64) (*ms.PostContent, error) {
 	}).Get(s.db)
 }
 
+func (s *tweetSrv) GetAttachmentsByContents(contents []string) ([]*ms.Attachment, error) {
+	if len(contents) == 0 {
+		return nil, nil
+	}
+	return (&dbr.Attachment{}).ListByContents(s.db, contents)
+}
+
 func (s *tweetSrvA) TweetInfoById(id int64) (*cs.TweetInfo, error) {
 	// TODO
 	return nil, debug.ErrNotImplemented
@@ -0,0 +1,142 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.UploadSessionService = (*uploadSessionSrv)(nil)
+	_ core.StorageQuotaService  = (*storageQuotaSrv)(nil)
+	_ core.ObjectBlobService    = (*objectBlobSrv)(nil)
+)
+
+type uploadSessionSrv struct {
+	db *gorm.DB
+}
+
+type storageQuotaSrv struct {
+	db *gorm.DB
+}
+
+type objectBlobSrv struct {
+	db *gorm.DB
+}
+
+func newUploadSessionService(db *gorm.DB) core.UploadSessionService {
+	return &uploadSessionSrv{
+		db: db,
+	}
+}
+
+func newStorageQuotaService(db *gorm.DB) core.StorageQuotaService {
+	return &storageQuotaSrv{
+		db: db,
+	}
+}
+
+func newObjectBlobService(db *gorm.DB) core.ObjectBlobService {
+	return &objectBlobSrv{
+		db: db,
+	}
+}
+
+func (s *uploadSessionSrv) CreateUploadSession(session *ms.UploadSession) (*ms.UploadSession, error) {
+	return session.Create(s.db)
+}
+
+func (s *uploadSessionSrv) GetUploadSession(id int64) (*ms.UploadSession, error) {
+	session := &dbr.UploadSession{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return session.Get(s.db)
+}
+
+func (s *uploadSessionSrv) UpdateUploadReceived(session *ms.UploadSession, received int64) (bool, error) {
+	return session.UpdateReceived(s.db, received)
+}
+
+func (s *uploadSessionSrv) DeleteUploadSession(session *ms.UploadSession) error {
+	return session.Delete(s.db)
+}
+
+func (s *uploadSessionSrv) CountUserUploadSessions(userId int64) (int64, error) {
+	return (&dbr.UploadSession{UserID: userId}).CountActive(s.db, time.Now().Unix())
+}
+
+func (s *uploadSessionSrv) ListExpiredUploadSessions(limit int) ([]*ms.UploadSession, error) {
+	return (&dbr.UploadSession{}).ListExpired(s.db, time.Now().Unix(), limit)
+}
+
+func (s *storageQuotaSrv) GetUserStorageUsage(userId int64) (*ms.StorageUsage, error) {
+	return (&dbr.Attachment{}).UsageOf(s.db, userId)
+}
+
+func (s *storageQuotaSrv) GetUserStorageQuota(userId int64) (*ms.UserStorageQuota, error) {
+	return (&dbr.UserStorageQuota{UserID: userId}).Get(s.db)
+}
+
+func (s *storageQuotaSrv) SaveUserStorageQuota(quota *ms.UserStorageQuota) error {
+	return quota.Save(s.db)
+}
+
+func (s *storageQuotaSrv) DeleteUserStorageQuota(userId int64) error {
+	return (&dbr.UserStorageQuota{UserID: userId}).Delete(s.db)
+}
+
+func (s *storageQuotaSrv) DeleteAttachmentsByContents(contents []string) error {
+	if len(contents) == 0 {
+		return nil
+	}
+	// 去重的对象一并删除，不再用于之后的上传
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := (&dbr.Attachment{}).DeleteByContents(tx, contents); err != nil {
+			return err
+		}
+		return (&dbr.ObjectBlob{}).DeleteByContents(tx, contents)
+	})
+}
+
+func (s *objectBlobSrv) GetObjectBlob(sha256 string, uploadType string) (*ms.ObjectBlob, error) {
+	blob := &dbr.ObjectBlob{
+		Sha256:     sha256,
+		UploadType: uploadType,
+	}
+	return blob.Get(s.db)
+}
+
+func (s *objectBlobSrv) CreateObjectBlob(blob *ms.ObjectBlob) (*ms.ObjectBlob, error) {
+	return blob.Create(s.db)
+}
+
+func (s *objectBlobSrv) AcquireObjectBlob(blob *ms.ObjectBlob) (bool, error) {
+	return blob.Acquire(s.db)
+}
+
+func (s *objectBlobSrv) ReleaseObjectBlobs(userId int64, contents []string) (res []string, err error) {
+	if len(contents) == 0 {
+		return nil, nil
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		res, err = (&dbr.ObjectBlob{}).Release(tx, userId, contents)
+		return err
+	})
+	return
+}
+
+func (s *objectBlobSrv) ReferencedObjectBlobs(contents []string) ([]string, error) {
+	if len(contents) == 0 {
+		return nil, nil
+	}
+	return (&dbr.ObjectBlob{}).Referenced(s.db, contents)
+}
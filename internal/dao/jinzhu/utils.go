@@ -14,7 +14,8 @@ func createTags(db *gorm.DB, userId int64, tags []string) (res cs.TagInfoList, e
 	for _, name := range tags {
 		tag := &dbr.Tag{Tag: name}
 		if tag, err = tag.Get(db); err == nil {
-			// 更新
+			// 更新，别名话题计入合并后的话题
+			tag = canonicalTag(db, tag)
 			tag.QuoteNum++
 			if err = tag.Update(db); err != nil {
 				return
@@ -42,6 +43,7 @@ func decrTagsByIds(db *gorm.DB, ids []int64) (err error) {
 	for _, id := range ids {
 		tag := &dbr.Tag{Model: &dbr.Model{ID: id}}
 		if tag, err = tag.Get(db); err == nil {
+			tag = canonicalTag(db, tag)
 			tag.QuoteNum--
 			if err = tag.Update(db); err != nil {
 				return
@@ -53,12 +55,23 @@ func decrTagsByIds(db *gorm.DB, ids []int64) (err error) {
 	return nil
 }
 
+// canonicalTag 合并到其他话题的别名返回合并后的话题
+func canonicalTag(db *gorm.DB, tag *dbr.Tag) *dbr.Tag {
+	if tag.AliasOf > 0 {
+		if target, err := (&dbr.Tag{Model: &dbr.Model{ID: tag.AliasOf}}).Get(db); err == nil {
+			return target
+		}
+	}
+	return tag
+}
+
 func deleteTags(db *gorm.DB, tags []string) error {
 	allTags, err := (&dbr.Tag{}).TagsFrom(db, tags)
 	if err != nil {
 		return err
 	}
 	for _, tag := range allTags {
+		tag = canonicalTag(db, tag)
 		tag.QuoteNum--
 		if tag.QuoteNum < 0 {
 			tag.QuoteNum = 0
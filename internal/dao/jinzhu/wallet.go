@@ -5,6 +5,8 @@
 package jinzhu
 
 import (
+	"fmt"
+
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/internal/core"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
@@ -12,6 +14,10 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	_rechargeTradeSuccess = "TRADE_SUCCESS"
+)
+
 var (
 	_ core.WalletService = (*walletSrv)(nil)
 )
@@ -35,10 +41,11 @@ func (s *walletSrv) GetRechargeByID(id int64) (*ms.WalletRecharge, error) {
 
 	return recharge.Get(s.db)
 }
-func (s *walletSrv) CreateRecharge(userId, amount int64) (*ms.WalletRecharge, error) {
+func (s *walletSrv) CreateRecharge(userId, amount int64, provider string) (*ms.WalletRecharge, error) {
 	recharge := &dbr.WalletRecharge{
-		UserID: userId,
-		Amount: amount,
+		UserID:   userId,
+		Amount:   amount,
+		Provider: provider,
 	}
 
 	return recharge.Create(s.db)
@@ -61,99 +68,57 @@ func (s *walletSrv) GetUserWalletBillCount(userID int64) (int64, error) {
 	return statement.Count(s.db, &dbr.ConditionsT{})
 }
 
+// HandleRechargeSuccess 以支付渠道交易号作为幂等键入账，重复的支付回调只入账一次
 func (s *walletSrv) HandleRechargeSuccess(recharge *ms.WalletRecharge, tradeNo string) error {
-	user, _ := (&dbr.User{
-		Model: &dbr.Model{
-			ID: recharge.UserID,
-		},
-	}).Get(s.db)
-
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// 扣除金额
-		if err := tx.Model(user).Update("balance", gorm.Expr("balance + ?", recharge.Amount)).Error; err != nil {
-			// 返回任何错误都会回滚事务
-			return err
+	key := fmt.Sprintf("recharge:%s:%s", recharge.Provider, tradeNo)
+	return withLedger(s.db, key, func(tx *gorm.DB) error {
+		// 标记为已付款，已付款的充值不再入账
+		res := tx.Model(&dbr.WalletRecharge{}).
+			Where("id = ? AND trade_status != ? AND is_del = 0", recharge.ID, _rechargeTradeSuccess).
+			Updates(map[string]any{
+				"trade_no":     tradeNo,
+				"trade_status": _rechargeTradeSuccess,
+			})
+		if res.Error != nil {
+			return res.Error
+		} else if res.RowsAffected == 0 {
+			return errJournalPosted
 		}
-
-		// 新增账单
-		if err := tx.Create(&dbr.WalletStatement{
-			UserID:          user.ID,
-			ChangeAmount:    recharge.Amount,
-			BalanceSnapshot: user.Balance + recharge.Amount,
-			Reason:          "用户充值",
-		}).Error; err != nil {
-			return err
-		}
-
-		// 标记为已付款
-		if err := tx.Model(recharge).Updates(map[string]any{
-			"trade_no":     tradeNo,
-			"trade_status": "TRADE_SUCCESS",
-		}).Error; err != nil {
-			return err
-		}
-
-		// 返回 nil 提交事务
-		return nil
+		return postJournal(tx, key, "recharge", "用户充值",
+			userLine(recharge.UserID, recharge.Amount, "用户充值", 0),
+			systemLine(channelAccount(recharge.Provider), -recharge.Amount))
 	})
 }
 
+// HandlePostAttachmentBought 每个用户购买同一附件只记账一次，退款后可重新购买，余额不足时返回cs.ErrNoBalance
 func (s *walletSrv) HandlePostAttachmentBought(post *ms.Post, user *ms.User) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// 扣除金额
-		if err := tx.Model(user).Update("balance", gorm.Expr("balance - ?", post.AttachmentPrice)).Error; err != nil {
-			// 返回任何错误都会回滚事务
-			return err
-		}
-
-		// 新增账单
-		if err := tx.Create(&dbr.WalletStatement{
-			PostID:          post.ID,
-			UserID:          user.ID,
-			ChangeAmount:    -post.AttachmentPrice,
-			BalanceSnapshot: user.Balance - post.AttachmentPrice,
-			Reason:          "购买附件支出",
-		}).Error; err != nil {
+	generation, err := (&dbr.PostAttachmentBill{PostID: post.ID, UserID: user.ID}).Generation(s.db)
+	if err != nil {
+		return err
+	}
+	key := attachmentJournalKey(post.ID, user.ID, generation)
+	return withLedger(s.db, key, func(tx *gorm.DB) error {
+		income := int64(float64(post.AttachmentPrice) * conf.AppSetting.AttachmentIncomeRate)
+		err := postJournal(tx, key, "attachment", "购买附件",
+			userLine(user.ID, -post.AttachmentPrice, "购买附件支出", post.ID),
+			userLine(post.UserID, income, "出售附件收入", post.ID),
+			systemLine(_accountRevenue, post.AttachmentPrice-income))
+		if err != nil {
 			return err
 		}
-
 		// 新增附件购买记录
-		if err := tx.Create(&dbr.PostAttachmentBill{
+		return tx.Create(&dbr.PostAttachmentBill{
 			PostID:     post.ID,
 			UserID:     user.ID,
 			PaidAmount: post.AttachmentPrice,
-		}).Error; err != nil {
-			return err
-		}
-
-		// 对附件主新增账单
-		income := int64(float64(post.AttachmentPrice) * conf.AppSetting.AttachmentIncomeRate)
-		if income > 0 {
-			master := &dbr.User{
-				Model: &dbr.Model{
-					ID: post.UserID,
-				},
-			}
-			master, _ = master.Get(s.db)
-
-			if err := tx.Model(master).Update("balance", gorm.Expr("balance + ?", income)).Error; err != nil {
-				// 返回任何错误都会回滚事务
-				return err
-			}
-
-			// 新增账单
-			if err := tx.Create(&dbr.WalletStatement{
-				PostID:          post.ID,
-				UserID:          master.ID,
-				ChangeAmount:    income,
-				BalanceSnapshot: master.Balance + income,
-				Reason:          "出售附件收入",
-			}).Error; err != nil {
-				return err
-			}
-		}
-
-		// 返回 nil 提交事务
-		return nil
+		}).Error
 	})
 }
+
+// attachmentJournalKey 附件购买凭证的幂等键，generation为此前购买同一附件的次数
+func attachmentJournalKey(postId int64, userId int64, generation int64) string {
+	if generation == 0 {
+		return fmt.Sprintf("attachment:%d:%d", postId, userId)
+	}
+	return fmt.Sprintf("attachment:%d:%d:%d", postId, userId, generation)
+}
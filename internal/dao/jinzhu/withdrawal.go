@@ -0,0 +1,130 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	"fmt"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var (
+	_ core.WithdrawalService = (*withdrawalSrv)(nil)
+)
+
+type withdrawalSrv struct {
+	db *gorm.DB
+}
+
+func newWithdrawalService(db *gorm.DB) core.WithdrawalService {
+	return &withdrawalSrv{
+		db: db,
+	}
+}
+
+// CreateWithdrawal 从余额中扣除提现金额并记录账单，余额不足时返回cs.ErrNoBalance
+func (s *withdrawalSrv) CreateWithdrawal(withdrawal *ms.WalletWithdrawal) (*ms.WalletWithdrawal, error) {
+	var err error
+	// 提现记录随事务回滚，账户版本冲突时重新申请
+	for i := 0; i < _ledgerMaxRetry; i++ {
+		withdrawal.Model = &dbr.Model{}
+		if err = s.db.Transaction(func(tx *gorm.DB) error {
+			withdrawal.Status = dbr.WithdrawalStatusPending
+			if _, err := withdrawal.Create(tx); err != nil {
+				return err
+			}
+			return postJournal(tx, fmt.Sprintf("withdrawal:%d", withdrawal.ID), "withdrawal", "提现申请",
+				userLine(withdrawal.UserID, -withdrawal.Amount, "提现支出", 0),
+				systemLine(_accountWithdrawal, withdrawal.Amount))
+		}); err != errLedgerConflict {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withdrawal, nil
+}
+
+func (s *withdrawalSrv) GetWithdrawal(id int64) (*ms.WalletWithdrawal, error) {
+	withdrawal := &dbr.WalletWithdrawal{
+		Model: &dbr.Model{
+			ID: id,
+		},
+	}
+	return withdrawal.Get(s.db)
+}
+
+func (s *withdrawalSrv) CountUnpaidWithdrawals(userId int64) (int64, error) {
+	return (&dbr.WalletWithdrawal{UserID: userId}).CountUnpaid(s.db)
+}
+
+func (s *withdrawalSrv) ListWithdrawals(userId int64, status ms.WithdrawalStatus, offset, limit int) ([]*ms.WalletWithdrawal, int64, error) {
+	return (&dbr.WalletWithdrawal{UserID: userId}).List(s.db, status, offset, limit)
+}
+
+// UpdateWithdrawalStatus 从from状态变更为withdrawal的状态，拒绝、取消或打款失败时退回余额并记录账单，
+// 打款成功时从待打款账户转出；状态已被变更时返回false
+func (s *withdrawalSrv) UpdateWithdrawalStatus(withdrawal *ms.WalletWithdrawal, from ms.WithdrawalStatus) (ok bool, err error) {
+	key := fmt.Sprintf("withdrawal:%d:%d", withdrawal.ID, withdrawal.Status)
+	err = withLedger(s.db, key, func(tx *gorm.DB) (err error) {
+		if ok, err = withdrawal.UpdateStatus(tx, from); err != nil || !ok {
+			return err
+		}
+		switch {
+		case withdrawal.Refundable():
+			return postJournal(tx, key, "withdrawal_refund", "提现退回",
+				userLine(withdrawal.UserID, withdrawal.Amount, "提现退回", 0),
+				systemLine(_accountWithdrawal, -withdrawal.Amount))
+		case withdrawal.Status == dbr.WithdrawalStatusPaid:
+			return postJournal(tx, key, "withdrawal_payout", "提现打款",
+				systemLine(_accountWithdrawal, -withdrawal.Amount),
+				systemLine(_accountPayout, withdrawal.Amount-withdrawal.Fee),
+				systemLine(_accountFee, withdrawal.Fee))
+		}
+		return nil
+	})
+	return
+}
+
+// ReconcileWallets 核对每个用户的余额是否等于其账单变动金额的合计
+func (s *withdrawalSrv) ReconcileWallets() (*ms.WalletReconcileReport, error) {
+	report := &ms.WalletReconcileReport{}
+	sum := fmt.Sprintf("COALESCE(SUM(%s.change_amount), 0)", _walletStatement_)
+	type row struct {
+		UserID       int64
+		Balance      int64
+		StatementSum int64
+	}
+	var rows []*row
+	err := s.db.Table(_user_).
+		Select(fmt.Sprintf("%s.id AS user_id, %s.balance AS balance, %s AS statement_sum", _user_, _user_, sum)).
+		Joins(fmt.Sprintf("LEFT JOIN %s ON %s.user_id=%s.id AND %s.is_del=0", _walletStatement_, _walletStatement_, _user_, _walletStatement_)).
+		Where(fmt.Sprintf("%s.is_del=0", _user_)).
+		Group(fmt.Sprintf("%s.id, %s.balance", _user_, _user_)).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		report.Users++
+		report.TotalBalance += r.Balance
+		report.TotalStatement += r.StatementSum
+		if r.Balance != r.StatementSum {
+			report.Mismatches = append(report.Mismatches, &ms.WalletMismatch{
+				UserID:       r.UserID,
+				Balance:      r.Balance,
+				StatementSum: r.StatementSum,
+			})
+		}
+	}
+	if report.UnpaidWithdrawal, err = (&dbr.WalletWithdrawal{}).SumUnpaid(s.db); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
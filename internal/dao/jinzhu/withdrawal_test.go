@@ -0,0 +1,136 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jinzhu
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/dao/jinzhu/dbr"
+	"gorm.io/gorm"
+)
+
+var _ = g.Describe("Withdrawal", func() {
+	const alice int64 = 1
+	var (
+		db *gorm.DB
+		s  *withdrawalSrv
+	)
+
+	g.BeforeEach(func() {
+		_user_, _walletStatement_ = "p_user", "p_wallet_statement"
+		db = newSqlite3TestDB()
+		s = &withdrawalSrv{db: db}
+		m.Expect(db.Create(&dbr.User{Model: &dbr.Model{ID: alice}, Username: userAccount(alice)}).Error).To(m.BeNil())
+		key := "recharge:alipay:1"
+		m.Expect(withLedger(db, key, func(tx *gorm.DB) error {
+			return postJournal(tx, key, "recharge", "用户充值",
+				userLine(alice, 1000, "用户充值", 0),
+				systemLine(channelAccount("alipay"), -1000))
+		})).To(m.Succeed())
+	})
+
+	userBalance := func() (balance int64) {
+		m.Expect(db.Model(&dbr.User{}).Where("id = ?", alice).Select("balance").Scan(&balance).Error).To(m.BeNil())
+		return
+	}
+
+	accountBalance := func(code string) (balance int64) {
+		db.Model(&dbr.LedgerAccount{}).Where("code = ?", code).Select("balance").Scan(&balance)
+		return
+	}
+
+	create := func(amount int64) *ms.WalletWithdrawal {
+		w, err := s.CreateWithdrawal(&ms.WalletWithdrawal{
+			UserID:      alice,
+			Amount:      amount,
+			Fee:         10,
+			AccountType: "alipay",
+			Account:     "alice@example.com",
+			AccountName: "alice",
+		})
+		m.Expect(err).To(m.BeNil())
+		return w
+	}
+
+	transit := func(w *ms.WalletWithdrawal, from, to ms.WithdrawalStatus) bool {
+		w.Status = to
+		ok, err := s.UpdateWithdrawalStatus(w, from)
+		m.Expect(err).To(m.BeNil())
+		return ok
+	}
+
+	reconcile := func(unpaid int64) {
+		report, err := s.ReconcileWallets()
+		m.Expect(err).To(m.BeNil())
+		m.Expect(report.Mismatches).To(m.BeEmpty())
+		m.Expect(report.UnpaidWithdrawal).To(m.Equal(unpaid))
+		audit, err := (&ledgerSrv{db: db}).AuditLedger()
+		m.Expect(err).To(m.BeNil())
+		m.Expect(audit.Clean()).To(m.BeTrue())
+	}
+
+	g.It("deduct balance on apply and reject overdrafts", func() {
+		create(300)
+		m.Expect(userBalance()).To(m.Equal(int64(700)))
+		m.Expect(accountBalance(_accountWithdrawal)).To(m.Equal(int64(300)))
+		reconcile(300)
+
+		_, err := s.CreateWithdrawal(&ms.WalletWithdrawal{UserID: alice, Amount: 800})
+		m.Expect(err).To(m.MatchError(cs.ErrNoBalance))
+		count, err := s.CountUnpaidWithdrawals(alice)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(count).To(m.Equal(int64(1)))
+	})
+
+	g.It("review and pay out a withdrawal", func() {
+		w := create(300)
+		m.Expect(transit(w, ms.WithdrawalStatusPending, ms.WithdrawalStatusApproved)).To(m.BeTrue())
+		reconcile(300)
+		m.Expect(transit(w, ms.WithdrawalStatusApproved, ms.WithdrawalStatusPaid)).To(m.BeTrue())
+		m.Expect(userBalance()).To(m.Equal(int64(700)))
+		m.Expect(accountBalance(_accountWithdrawal)).To(m.Equal(int64(0)))
+		m.Expect(accountBalance(_accountPayout)).To(m.Equal(int64(290)))
+		m.Expect(accountBalance(_accountFee)).To(m.Equal(int64(10)))
+		reconcile(0)
+
+		// 已打款的提现不能再退回
+		m.Expect(transit(w, ms.WithdrawalStatusApproved, ms.WithdrawalStatusFailed)).To(m.BeFalse())
+		m.Expect(userBalance()).To(m.Equal(int64(700)))
+	})
+
+	g.DescribeTable("refund a closed withdrawal exactly once",
+		func(from ms.WithdrawalStatus, to ms.WithdrawalStatus) {
+			w := create(300)
+			if from != ms.WithdrawalStatusPending {
+				m.Expect(transit(w, ms.WithdrawalStatusPending, from)).To(m.BeTrue())
+			}
+			m.Expect(transit(w, from, to)).To(m.BeTrue())
+			m.Expect(userBalance()).To(m.Equal(int64(1000)))
+			m.Expect(accountBalance(_accountWithdrawal)).To(m.Equal(int64(0)))
+			reconcile(0)
+
+			// 重复提交的状态变更不会再次退回
+			m.Expect(transit(w, from, to)).To(m.BeFalse())
+			m.Expect(userBalance()).To(m.Equal(int64(1000)))
+			reconcile(0)
+		},
+		g.Entry("rejected on review", ms.WithdrawalStatusPending, ms.WithdrawalStatusRejected),
+		g.Entry("canceled by user", ms.WithdrawalStatusPending, ms.WithdrawalStatusCanceled),
+		g.Entry("failed on payout", ms.WithdrawalStatusApproved, ms.WithdrawalStatusFailed),
+	)
+
+	g.It("keep status when changed concurrently", func() {
+		w := create(300)
+		stale := *w
+		m.Expect(transit(w, ms.WithdrawalStatusPending, ms.WithdrawalStatusCanceled)).To(m.BeTrue())
+		m.Expect(transit(&stale, ms.WithdrawalStatusPending, ms.WithdrawalStatusApproved)).To(m.BeFalse())
+		w, err := s.GetWithdrawal(w.ID)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(w.Status).To(m.Equal(ms.WithdrawalStatusCanceled))
+		reconcile(0)
+	})
+})
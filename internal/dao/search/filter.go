@@ -35,14 +35,18 @@ func (s *tweetSearchFilter) filterResp(user *ms.User, resp *core.QueryResp) {
 			}
 		}
 	} else {
-		var cutFriend, cutPrivate bool
+		var cutFriend, cutPrivate, cutSubscribe, cutCharge bool
 		friendFilter := s.ams.BeFriendFilter(user.ID)
 		friendFilter[user.ID] = types.Empty{}
+		subscribed := s.subscribedFilter(user.ID)
 		for i := 0; i <= latestIndex; i++ {
 			item = items[i]
 			cutFriend = (item.Visibility == core.PostVisitFriend && !friendFilter.IsFriend(item.UserID))
 			cutPrivate = (item.Visibility == core.PostVisitPrivate && user.ID != item.UserID)
-			if cutFriend || cutPrivate {
+			cutSubscribe = (item.Visibility == core.PostVisitSubscribe && !subscribed.IsFriend(item.UserID))
+			// 搜索结果包含完整内容，充电可见的推文仅作者本人可搜索
+			cutCharge = (item.Visibility == core.PostVisitCharge && user.ID != item.UserID)
+			if cutFriend || cutPrivate || cutSubscribe || cutCharge {
 				items[i] = items[latestIndex]
 				items = items[:latestIndex]
 				resp.Total--
@@ -54,3 +58,14 @@ func (s *tweetSearchFilter) filterResp(user *ms.User, resp *core.QueryResp) {
 
 	resp.Items = items
 }
+
+// subscribedFilter 用户自己及其订阅的创作者
+func (s *tweetSearchFilter) subscribedFilter(userId int64) ms.FriendFilter {
+	ids, _ := s.ams.SubscribedIds(userId)
+	res := make(ms.FriendFilter, len(ids)+1)
+	res[userId] = types.Empty{}
+	for _, id := range ids {
+		res[id] = types.Empty{}
+	}
+	return res
+}
@@ -29,6 +29,8 @@ type meiliTweetSearchServant struct {
 	publicFilter  string
 	privateFilter string
 	friendFilter  string
+	subFilter     string
+	chargeFilter  string
 }
 
 type postInfo struct {
@@ -174,7 +176,7 @@ func (s *meiliTweetSearchServant) filterList(user *ms.User) string {
 		return ""
 	}
 
-	return fmt.Sprintf("%s OR %s OR (%s%d)", s.publicFilter, s.friendFilter, s.privateFilter, user.ID)
+	return fmt.Sprintf("%s OR %s OR %s OR (%s%d) OR (%s%d)", s.publicFilter, s.friendFilter, s.subFilter, s.privateFilter, user.ID, s.chargeFilter, user.ID)
 }
 
 func (s *meiliTweetSearchServant) postsFrom(resp *meilisearch.SearchResponse) (*core.QueryResp, error) {
@@ -49,6 +49,8 @@ func NewMeiliTweetSearchService(ams core.AuthorizationManageService) (core.Tweet
 		publicFilter:  fmt.Sprintf("visibility=%d", core.PostVisitPublic),
 		privateFilter: fmt.Sprintf("visibility=%d AND user_id=", core.PostVisitPrivate),
 		friendFilter:  fmt.Sprintf("visibility=%d", core.PostVisitFriend),
+		subFilter:     fmt.Sprintf("visibility=%d", core.PostVisitSubscribe),
+		chargeFilter:  fmt.Sprintf("visibility=%d AND user_id=", core.PostVisitCharge),
 	}
 	return mts, mts
 }
@@ -6,7 +6,9 @@ package storage
 
 import (
 	"io"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -129,6 +131,40 @@ func (s *aliossServant) IsObjectExist(objectKey string) (bool, error) {
 	return s.bucket.IsObjectExist(objectKey)
 }
 
+func (s *aliossServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	token := ""
+	for {
+		res, err := s.bucket.ListObjectsV2(oss.Prefix(prefix), oss.ContinuationToken(token), oss.MaxKeys(1000))
+		if err != nil {
+			return err
+		}
+		for _, obj := range res.Objects {
+			if err = fn(&core.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !res.IsTruncated {
+			return nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+func (s *aliossServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	res, err := s.bucket.DoGetObject(&oss.GetObjectRequest{ObjectKey: objectKey}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	size, _ := strconv.ParseInt(res.Response.Headers.Get(oss.HTTPHeaderContentLength), 10, 64)
+	lastModified, _ := http.ParseTime(res.Response.Headers.Get(oss.HTTPHeaderLastModified))
+	return res.Response.Body, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         size,
+		ContentType:  res.Response.Headers.Get(oss.HTTPHeaderContentType),
+		LastModified: lastModified,
+	}, nil
+}
+
 func (s *aliossServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
 	signedURL, err := s.bucket.SignURL(objectKey, oss.HTTPGet, expiredInSec)
 	if err != nil {
@@ -0,0 +1,66 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core"
+)
+
+var (
+	_ core.ObjectStorageService = (*blobGuardServant)(nil)
+)
+
+// blobGuardServant 删除对象前检查去重的引用计数，仍被其他上传引用的对象不删除
+type blobGuardServant struct {
+	core.ObjectStorageService
+
+	bs core.ObjectBlobService
+}
+
+// NewBlobGuardService 包装对象存储服务，对象只在最后一个引用释放后才会被删除
+func NewBlobGuardService(oss core.ObjectStorageService, bs core.ObjectBlobService) core.ObjectStorageService {
+	return &blobGuardServant{
+		ObjectStorageService: oss,
+		bs:                   bs,
+	}
+}
+
+func (s *blobGuardServant) DeleteObject(objectKey string) error {
+	keys, err := s.unreferenced([]string{objectKey})
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	return s.ObjectStorageService.DeleteObject(objectKey)
+}
+
+func (s *blobGuardServant) DeleteObjects(objectKeys []string) error {
+	keys, err := s.unreferenced(objectKeys)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	return s.ObjectStorageService.DeleteObjects(keys)
+}
+
+func (s *blobGuardServant) unreferenced(objectKeys []string) ([]string, error) {
+	contents := make([]string, 0, len(objectKeys))
+	for _, key := range objectKeys {
+		contents = append(contents, s.ObjectURL(key))
+	}
+	referenced, err := s.bs.ReferencedObjectBlobs(contents)
+	if err != nil || len(referenced) == 0 {
+		return objectKeys, err
+	}
+	referencedMap := make(map[string]struct{}, len(referenced))
+	for _, content := range referenced {
+		referencedMap[content] = struct{}{}
+	}
+	keys := make([]string, 0, len(objectKeys))
+	for _, key := range objectKeys {
+		if _, exist := referencedMap[s.ObjectURL(key)]; !exist {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
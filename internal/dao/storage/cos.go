@@ -117,6 +117,47 @@ func (s *cosServant) IsObjectExist(objectKey string) (bool, error) {
 	return s.client.Object.IsExist(context.Background(), objectKey)
 }
 
+func (s *cosServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	opt := &cos.BucketGetOptions{
+		Prefix:  prefix,
+		MaxKeys: 1000,
+	}
+	for {
+		res, _, err := s.client.Bucket.Get(context.Background(), opt)
+		if err != nil {
+			return err
+		}
+		for _, obj := range res.Contents {
+			lastModified, _ := time.Parse(time.RFC3339, obj.LastModified)
+			if err = fn(&core.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: lastModified}); err != nil {
+				return err
+			}
+		}
+		if !res.IsTruncated || len(res.Contents) == 0 {
+			return nil
+		}
+		// 未指定分隔符时可能不返回NextMarker，以最后一个对象作为下一页的起点
+		opt.Marker = res.NextMarker
+		if opt.Marker == "" {
+			opt.Marker = res.Contents[len(res.Contents)-1].Key
+		}
+	}
+}
+
+func (s *cosServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	res, err := s.client.Object.Get(context.Background(), objectKey, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	lastModified, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+	return res.Body, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         res.ContentLength,
+		ContentType:  res.Header.Get("Content-Type"),
+		LastModified: lastModified,
+	}, nil
+}
+
 func (s *cosServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
 	signedURL, err := s.client.Object.GetPresignedURL(context.Background(),
 		http.MethodGet, objectKey, conf.COSSetting.SecretID, conf.COSSetting.SecretKey, time.Second*time.Duration(expiredInSec), nil)
@@ -166,6 +166,44 @@ func (s *huaweiobsServant) IsObjectExist(objectKey string) (bool, error) {
 	return true, nil
 }
 
+func (s *huaweiobsServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	input := &obs.ListObjectsInput{Bucket: s.bucket}
+	input.Prefix, input.MaxKeys = prefix, 1000
+	for {
+		res, err := s.client.ListObjects(input)
+		if err != nil {
+			return err
+		}
+		for _, obj := range res.Contents {
+			if err = fn(&core.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !res.IsTruncated || len(res.Contents) == 0 {
+			return nil
+		}
+		input.Marker = res.NextMarker
+		if input.Marker == "" {
+			input.Marker = res.Contents[len(res.Contents)-1].Key
+		}
+	}
+}
+
+func (s *huaweiobsServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	input := &obs.GetObjectInput{}
+	input.Bucket, input.Key = s.bucket, objectKey
+	res, err := s.client.GetObject(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Body, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         res.ContentLength,
+		ContentType:  res.ContentType,
+		LastModified: res.LastModified,
+	}, nil
+}
+
 func (s *huaweiobsServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
 	input := &obs.CreateSignedUrlInput{
 		Method:  obs.HttpMethodGet,
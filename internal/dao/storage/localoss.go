@@ -7,6 +7,8 @@ package storage
 import (
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"os"
 	"path/filepath"
 	"strings"
@@ -168,6 +170,48 @@ func (s *localossServant) IsObjectExist(objectKey string) (bool, error) {
 	return !fi.IsDir(), nil
 }
 
+func (s *localossServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	// 从前缀所在目录开始遍历，再按完整前缀过滤
+	root := s.savePath + prefix[:strings.LastIndex(prefix, "/")+1]
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		key := filepath.ToSlash(strings.TrimPrefix(path, s.savePath))
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(&core.ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *localossServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	file, err := os.Open(s.savePath + objectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	// 本地存储不保存内容类型，按扩展名推断
+	return file, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         stat.Size(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(objectKey)),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
 func (s *localossServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
 	if expiredInSec < 0 {
 		return "", fmt.Errorf("invalid expires: %d, expires must bigger than 0", expiredInSec)
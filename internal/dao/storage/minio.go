@@ -160,6 +160,39 @@ func (s *minioServant) IsObjectExist(objectKey string) (bool, error) {
 	return true, nil
 }
 
+func (s *minioServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	// 提前退出遍历时需要取消，否则列举对象的goroutine会一直阻塞
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := fn(&core.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *minioServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	obj, err := s.client.GetObject(context.Background(), s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, nil, err
+	}
+	return obj, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+	}, nil
+}
+
 func (s *minioServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
 	// TODO: Set request parameters for content-disposition.
 	reqParams := make(url.Values)
@@ -0,0 +1,175 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/cockroachdb/errors"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/pkg/obs"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ core.ObjectStorageService = (*nativeobsServant)(nil)
+	_ core.OssCreateService     = (*nativeobsCreateServant)(nil)
+	_ core.OssCreateService     = (*nativeobsCreateTempDirServant)(nil)
+	_ core.VersionInfo          = (*nativeobsServant)(nil)
+)
+
+type nativeobsCreateServant struct {
+	storage obs.Storage
+	bucket  string
+	domain  string
+}
+
+type nativeobsCreateTempDirServant struct {
+	storage obs.Storage
+	bucket  string
+	domain  string
+	tempDir string
+}
+
+type nativeobsServant struct {
+	core.OssCreateService
+
+	storage obs.Storage
+	signer  *obs.Signer
+	bucket  string
+	domain  string
+}
+
+func (s *nativeobsCreateServant) PutObject(objectKey string, reader io.Reader, objectSize int64, contentType string, _persistance bool) (string, error) {
+	if err := putNativeObject(s.storage, s.bucket, objectKey, reader, objectSize, contentType); err != nil {
+		return "", err
+	}
+	return s.domain + objectKey, nil
+}
+
+func (s *nativeobsCreateServant) PersistObject(_objectKey string) error {
+	// empty
+	return nil
+}
+
+func (s *nativeobsCreateTempDirServant) PutObject(objectKey string, reader io.Reader, objectSize int64, contentType string, persistance bool) (string, error) {
+	objectName := objectKey
+	if !persistance {
+		objectName = s.tempDir + objectKey
+	}
+	if err := putNativeObject(s.storage, s.bucket, objectName, reader, objectSize, contentType); err != nil {
+		return "", err
+	}
+	return s.domain + objectKey, nil
+}
+
+func (s *nativeobsCreateTempDirServant) PersistObject(objectKey string) error {
+	if _, err := s.storage.StatObject(s.bucket, objectKey); err == nil {
+		logrus.Debugf("object exist so do nothing objectKey: %s", objectKey)
+		return nil
+	}
+	// 内容寻址存储，复制对象不会复制实际内容
+	tmpObjKey := s.tempDir + objectKey
+	if _, err := s.storage.CopyObject(s.bucket, tmpObjKey, objectKey); err != nil {
+		return err
+	}
+	return s.storage.DeleteObject(s.bucket, tmpObjKey)
+}
+
+func (s *nativeobsServant) DeleteObject(objectKey string) error {
+	return s.storage.DeleteObject(s.bucket, objectKey)
+}
+
+func (s *nativeobsServant) DeleteObjects(objectKeys []string) (err error) {
+	// 宽松处理删除动作，尽可能删除所有objectKey，如果出错，只返回最后一个错误
+	for _, objectKey := range objectKeys {
+		if e := s.storage.DeleteObject(s.bucket, objectKey); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+func (s *nativeobsServant) IsObjectExist(objectKey string) (bool, error) {
+	_, err := s.storage.StatObject(s.bucket, objectKey)
+	if err == obs.ErrNoSuchKey {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *nativeobsServant) ListObjects(prefix string, fn func(obj *core.ObjectInfo) error) error {
+	marker := ""
+	for {
+		res, err := s.storage.ListObjects(s.bucket, prefix, "", marker, 1000)
+		if err != nil {
+			return err
+		}
+		for _, obj := range res.Objects {
+			if err = fn(&core.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.ModTime}); err != nil {
+				return err
+			}
+		}
+		if !res.IsTruncated {
+			return nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+func (s *nativeobsServant) GetObject(objectKey string) (io.ReadCloser, *core.ObjectInfo, error) {
+	info, reader, err := s.storage.GetObject(s.bucket, objectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, &core.ObjectInfo{
+		Key:          objectKey,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		LastModified: info.ModTime,
+	}, nil
+}
+
+func (s *nativeobsServant) SignURL(objectKey string, expiredInSec int64) (string, error) {
+	if expiredInSec <= 0 {
+		return "", fmt.Errorf("invalid expires: %d, expires must bigger than 0", expiredInSec)
+	}
+	return s.signer.Presign(http.MethodGet, s.domain+objectKey, time.Duration(expiredInSec)*time.Second)
+}
+
+func (s *nativeobsServant) ObjectURL(objetKey string) string {
+	return s.domain + objetKey
+}
+
+func (s *nativeobsServant) ObjectKey(objectUrl string) string {
+	return strings.Replace(objectUrl, s.domain, "", -1)
+}
+
+func (s *nativeobsServant) Name() string {
+	return "NativeOBS"
+}
+
+func (s *nativeobsServant) Version() *semver.Version {
+	return semver.MustParse("v0.1.0")
+}
+
+func putNativeObject(storage obs.Storage, bucket string, objectKey string, reader io.Reader, objectSize int64, contentType string) error {
+	info, err := storage.PutObject(bucket, objectKey, reader, contentType)
+	if err != nil {
+		return err
+	}
+	if info.Size != objectSize {
+		storage.DeleteObject(bucket, objectKey)
+		return errors.New("put object not complete")
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/internal/core"
+	nobs "github.com/rocboss/paopao-ce/pkg/obs"
 	"github.com/sirupsen/logrus"
 	"github.com/tencentyun/cos-go-sdk-v5"
 )
@@ -181,6 +182,38 @@ func MustLocalossService() (core.ObjectStorageService, core.VersionInfo) {
 	return obj, obj
 }
 
+func MustNativeobsService() (core.ObjectStorageService, core.VersionInfo) {
+	s := conf.NativeOBSSetting
+	storage := conf.MustNativeObs()
+	domain := conf.GetOssDomain()
+	var cs core.OssCreateService
+	if cfg.If("OSS:TempDir") {
+		cs = &nativeobsCreateTempDirServant{
+			storage: storage,
+			bucket:  s.Bucket,
+			domain:  domain,
+			tempDir: conf.ObjectStorage.TempDirSlash(),
+		}
+		logrus.Debugln("use OSS:TempDir feature")
+	} else {
+		cs = &nativeobsCreateServant{
+			storage: storage,
+			bucket:  s.Bucket,
+			domain:  domain,
+		}
+		logrus.Debugln("use OSS:Direct feature")
+	}
+
+	obj := &nativeobsServant{
+		OssCreateService: cs,
+		storage:          storage,
+		signer:           nobs.NewSigner(s.AccessKey, s.SecretKey, s.Region),
+		bucket:           s.Bucket,
+		domain:           domain,
+	}
+	return obj, obj
+}
+
 func MustMinioService() (core.ObjectStorageService, core.VersionInfo) {
 	// Initialize minio client object.
 	client, err := minio.New(conf.MinIOSetting.Endpoint, &minio.Options{
@@ -0,0 +1,183 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alimy/tryst/cfg"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	_deleteBatchSize = 1000
+)
+
+var (
+	errStopCollect = errors.New("stop collect")
+)
+
+// Report 一次垃圾回收的统计结果
+type Report struct {
+	Scanned    int64 // 检查的对象数
+	Recent     int64 // 宽限期内跳过的对象数
+	Orphans    int64 // 未被引用的对象数
+	OrphanSize int64 // 未被引用的对象大小
+	Deleted    int64 // 已删除的对象数
+}
+
+// Collector 对象存储垃圾回收，删除数据库中未被引用且超过宽限期的对象
+type Collector struct {
+	ds          core.DataService
+	oss         core.ObjectStorageService
+	gracePeriod time.Duration
+	maxDelete   int
+	prefixes    []string
+	tempDir     string
+}
+
+func NewCollector(ds core.DataService, oss core.ObjectStorageService) *Collector {
+	s := conf.ObjectGCSetting
+	c := &Collector{
+		ds:          ds,
+		oss:         oss,
+		gracePeriod: s.GracePeriod,
+		maxDelete:   s.MaxDelete,
+		prefixes:    s.Prefixes,
+	}
+	if cfg.If("OSS:TempDir") {
+		c.tempDir = conf.ObjectStorage.TempDirSlash()
+	}
+	return c
+}
+
+// Collect 标记所有被引用的对象后遍历对象存储，dryRun时只报告不删除；
+// 单次删除数达到MaxDelete时停止，剩余的留到下次处理
+func (c *Collector) Collect(dryRun bool, onOrphan func(obj *core.ObjectInfo)) (*Report, error) {
+	refs, err := c.references()
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	deadline := time.Now().Add(-c.gracePeriod)
+	orphans := make([]string, 0, _deleteBatchSize)
+	flush := func() error {
+		if dryRun || len(orphans) == 0 {
+			orphans = orphans[:0]
+			return nil
+		}
+		if err := c.delete(orphans); err != nil {
+			return err
+		}
+		report.Deleted += int64(len(orphans))
+		orphans = orphans[:0]
+		return nil
+	}
+	for _, prefix := range c.scanPrefixes() {
+		err = c.oss.ListObjects(prefix, func(obj *core.ObjectInfo) error {
+			report.Scanned++
+			if obj.LastModified.After(deadline) {
+				report.Recent++
+				return nil
+			}
+			// 临时目录中的对象以持久化后的对象键判断是否被引用
+			if _, exist := refs[strings.TrimPrefix(obj.Key, c.tempDir)]; exist {
+				return nil
+			}
+			report.Orphans++
+			report.OrphanSize += obj.Size
+			if onOrphan != nil {
+				onOrphan(obj)
+			}
+			orphans = append(orphans, obj.Key)
+			if len(orphans) >= _deleteBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			if !dryRun && c.maxDelete > 0 && report.Orphans >= int64(c.maxDelete) {
+				return errStopCollect
+			}
+			return nil
+		})
+		if err == errStopCollect {
+			break
+		} else if err != nil {
+			return report, err
+		}
+	}
+	if err = flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// references 被推文、评论、头像等引用的对象键，包括引用的图片/视频对应的各版本；
+// 宽限期内新建的附件也视为引用，去重时共用的对象可能早已超过宽限期
+func (c *Collector) references() (map[string]struct{}, error) {
+	refs := make(map[string]struct{})
+	mark := func(contents []string) error {
+		if len(contents) == 0 {
+			return nil
+		}
+		for _, content := range contents {
+			refs[c.oss.ObjectKey(content)] = struct{}{}
+		}
+		attachments, err := c.ds.GetAttachmentsByContents(contents)
+		if err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			for _, variant := range attachment.ImageVariants() {
+				refs[c.oss.ObjectKey(variant.Content)] = struct{}{}
+			}
+			for _, content := range []string{attachment.Poster, attachment.Playlist} {
+				if content != "" {
+					refs[c.oss.ObjectKey(content)] = struct{}{}
+				}
+			}
+		}
+		return nil
+	}
+	if err := c.ds.WalkObjectReferences(mark); err != nil {
+		return nil, err
+	}
+	if err := c.ds.WalkRecentAttachments(time.Now().Add(-c.gracePeriod).Unix(), mark); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// scanPrefixes 需要检查的对象前缀，使用临时目录时同时检查临时目录中的对象
+func (c *Collector) scanPrefixes() []string {
+	prefixes := make([]string, 0, len(c.prefixes)*2)
+	for _, prefix := range c.prefixes {
+		prefixes = append(prefixes, prefix)
+		if c.tempDir != "" {
+			prefixes = append(prefixes, c.tempDir+prefix)
+		}
+	}
+	return prefixes
+}
+
+// delete 删除对象及其对应的附件记录，附件记录删除后释放占用的存储配额
+func (c *Collector) delete(objectKeys []string) error {
+	contents := make([]string, 0, len(objectKeys))
+	for _, key := range objectKeys {
+		contents = append(contents, c.oss.ObjectURL(strings.TrimPrefix(key, c.tempDir)))
+	}
+	if err := c.ds.DeleteAttachmentsByContents(contents); err != nil {
+		return err
+	}
+	if err := c.oss.DeleteObjects(objectKeys); err != nil {
+		return err
+	}
+	logrus.Debugf("storage gc deleted %d orphan objects", len(objectKeys))
+	return nil
+}
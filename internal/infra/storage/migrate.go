@@ -0,0 +1,167 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rocboss/paopao-ce/internal/core"
+)
+
+// MigrateReport 一次迁移的统计结果
+type MigrateReport struct {
+	Scanned   int64 // 源对象存储中的对象数
+	Skipped   int64 // 之前已迁移而跳过的对象数
+	Copied    int64 // 本次复制并校验通过的对象数
+	Failed    int64 // 复制或校验失败的对象数
+	Size      int64 // 本次复制的对象大小
+	Rewritten int64 // 改写了引用地址的记录数
+}
+
+// Migrator 在两个对象存储之间迁移对象并改写数据库中引用的地址；
+// 已迁移的对象记录在状态文件中，中断后重新执行时跳过
+type Migrator struct {
+	ds        core.DataService
+	from      core.ObjectStorageService
+	to        core.ObjectStorageService
+	statePath string
+}
+
+func NewMigrator(ds core.DataService, from core.ObjectStorageService, to core.ObjectStorageService, statePath string) *Migrator {
+	return &Migrator{
+		ds:        ds,
+		from:      from,
+		to:        to,
+		statePath: statePath,
+	}
+}
+
+// Migrate 复制前缀下的所有对象，全部成功后再改写引用地址，避免引用指向缺失的对象
+func (m *Migrator) Migrate(prefixes []string, onObject func(obj *core.ObjectInfo, err error)) (*MigrateReport, error) {
+	done, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(m.statePath), 0755); err != nil {
+		return nil, err
+	}
+	state, err := os.OpenFile(m.statePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	report := &MigrateReport{}
+	for _, prefix := range prefixes {
+		err = m.from.ListObjects(prefix, func(obj *core.ObjectInfo) error {
+			report.Scanned++
+			if _, exist := done[obj.Key]; exist {
+				report.Skipped++
+				return nil
+			}
+			checksum, err := m.copyObject(obj)
+			if onObject != nil {
+				onObject(obj, err)
+			}
+			if err != nil {
+				report.Failed++
+				return nil
+			}
+			done[obj.Key] = struct{}{}
+			report.Copied++
+			report.Size += obj.Size
+			_, err = fmt.Fprintf(state, "%s\t%s\n", obj.Key, checksum)
+			return err
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d objects migrate failed, please retry to resume", report.Failed)
+	}
+	report.Rewritten, err = m.ds.RewriteObjectReferences(m.rewriteURL)
+	return report, err
+}
+
+// copyObject 复制对象并重新读取目标对象比较SHA256，返回对象的SHA256
+func (m *Migrator) copyObject(obj *core.ObjectInfo) (string, error) {
+	reader, info, err := m.from.GetObject(obj.Key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(obj.Key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	srcHash := sha256.New()
+	if _, err = m.to.PutObject(obj.Key, io.TeeReader(reader, srcHash), obj.Size, contentType, true); err != nil {
+		return "", err
+	}
+	dstReader, _, err := m.to.GetObject(obj.Key)
+	if err != nil {
+		return "", err
+	}
+	defer dstReader.Close()
+	dstHash := sha256.New()
+	if _, err = io.Copy(dstHash, dstReader); err != nil {
+		return "", err
+	}
+	checksum := hexSum(srcHash)
+	if dstChecksum := hexSum(dstHash); checksum != dstChecksum {
+		return "", fmt.Errorf("checksum mismatch: source %s but target %s", checksum, dstChecksum)
+	}
+	return checksum, nil
+}
+
+// rewriteURL 将源对象存储的地址改写为目标对象存储的地址，其他地址保持不变
+func (m *Migrator) rewriteURL(content string) string {
+	// 已经是目标对象存储的地址，重复执行时不再改写
+	if key := m.to.ObjectKey(content); key != content && m.to.ObjectURL(key) == content {
+		return content
+	}
+	key := m.from.ObjectKey(content)
+	if key == content || m.from.ObjectURL(key) != content {
+		return content
+	}
+	return m.to.ObjectURL(key)
+}
+
+// loadState 读取状态文件中已迁移的对象
+func (m *Migrator) loadState() (map[string]struct{}, error) {
+	done := make(map[string]struct{})
+	file, err := os.Open(m.statePath)
+	if os.IsNotExist(err) {
+		return done, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key, _, found := strings.Cut(scanner.Text(), "\t"); found {
+			done[key] = struct{}{}
+		}
+	}
+	return done, scanner.Err()
+}
+
+func hexSum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -6,15 +6,24 @@
 
 package joint
 
+import (
+	"github.com/rocboss/paopao-ce/pkg/cursor"
+)
+
 type BasePageInfo struct {
-	Page     int `form:"-" binding:"-"`
-	PageSize int `form:"-" binding:"-"`
+	Page     int            `form:"-" binding:"-"`
+	PageSize int            `form:"-" binding:"-"`
+	Cursor   *cursor.Cursor `form:"-" binding:"-"`
 }
 
 func (r *BasePageInfo) SetPageInfo(page int, pageSize int) {
 	r.Page, r.PageSize = page, pageSize
 }
 
+func (r *BasePageInfo) SetCursor(cursor *cursor.Cursor) {
+	r.Cursor = cursor
+}
+
 type JsonResp struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg,omitempty"`
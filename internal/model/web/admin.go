@@ -4,6 +4,11 @@
 
 package web
 
+import (
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+)
+
 type ChangeUserStatusReq struct {
 	BaseInfo `json:"-" binding:"-"`
 	ID       int64 `json:"id" form:"id" binding:"required"`
@@ -20,3 +25,85 @@ type SiteInfoResp struct {
 	HistoryMaxOnline  int   `json:"history_max_online"`
 	ServerUpTime      int64 `json:"server_up_time"`
 }
+
+type UserStorageQuotaReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	UserID   int64 `form:"user_id" binding:"required"`
+}
+
+type UserStorageQuotaResp StorageUsageResp
+
+type ChangeUserStorageQuotaReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	UserID   int64 `json:"user_id" binding:"required"`
+	MaxSize  int64 `json:"max_size" binding:"min=0"`
+	MaxFiles int64 `json:"max_files" binding:"min=0"`
+}
+
+type ResetUserStorageQuotaReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	UserID   int64 `json:"user_id" binding:"required"`
+}
+
+type ListWithdrawalsReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	UserID   int64               `form:"user_id"`
+	Status   ms.WithdrawalStatus `form:"status,default=-1"`
+	Page     int                 `form:"-" binding:"-"`
+	PageSize int                 `form:"-" binding:"-"`
+}
+
+type ListWithdrawalsResp base.PageResp
+
+type ReviewWithdrawalReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64  `json:"id" binding:"required"`
+	Approve  bool   `json:"approve"`
+	Remark   string `json:"remark" binding:"max=255"`
+}
+
+type PayoutWithdrawalReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64  `json:"id" binding:"required"`
+	Success  bool   `json:"success"`
+	TradeNo  string `json:"trade_no" binding:"required_if=Success true,max=64"`
+	Remark   string `json:"remark" binding:"max=255"`
+}
+
+type WalletReconcileReq struct {
+	BaseInfo `form:"-" binding:"-"`
+}
+
+type WalletReconcileResp ms.WalletReconcileReport
+
+type ListAttachmentRefundsReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	UserID   int64           `form:"user_id"`
+	AuthorID int64           `form:"author_id"`
+	Status   ms.RefundStatus `form:"status,default=-1"`
+	Page     int             `form:"-" binding:"-"`
+	PageSize int             `form:"-" binding:"-"`
+}
+
+type ListAttachmentRefundsResp base.PageResp
+
+type MergeTopicReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	SourceId int64 `json:"source_id" binding:"required"`
+	TargetId int64 `json:"target_id" binding:"required,nefield=SourceId"`
+}
+
+type ChangeTopicStatusReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	TopicId  int64 `json:"topic_id" binding:"required"`
+	IsLocked int8  `json:"is_locked" binding:"oneof=0 1"`
+	IsHidden int8  `json:"is_hidden" binding:"oneof=0 1"`
+}
+
+func (r *ListWithdrawalsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
+func (r *ListAttachmentRefundsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
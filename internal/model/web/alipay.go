@@ -6,6 +6,8 @@ package web
 
 import (
 	"context"
+	"math"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rocboss/paopao-ce/internal/conf"
@@ -26,14 +28,18 @@ type UserWalletBillsReq struct {
 type UserWalletBillsResp base.PageResp
 
 type UserRechargeLinkReq struct {
-	BaseInfo `json:"-" form:"-" binding:"-"`
-	Host     string `json:"-" form:"-" binding:"-"`
-	Amount   int64  `json:"amount" form:"amount" binding:"required"`
+	BaseInfo  `json:"-" form:"-" binding:"-"`
+	Host      string `json:"-" form:"-" binding:"-"`
+	ClientIP  string `json:"-" form:"-" binding:"-"`
+	Amount    int64  `json:"amount" form:"amount" binding:"required"`
+	Provider  string `json:"provider" form:"provider"`
+	TradeType string `json:"trade_type" form:"trade_type"`
 }
 
 type UserRechargeLinkResp struct {
-	Id  int64  `json:"id"`
-	Pay string `json:"pay"`
+	Id       int64  `json:"id"`
+	Provider string `json:"provider"`
+	Pay      string `json:"pay"`
 }
 
 type UserRechargeResultReq struct {
@@ -51,6 +57,7 @@ type AlipayNotifyReq struct {
 	ID          int64
 	TradeNo     string
 	TradeStatus alipay.TradeStatus
+	Amount      int64
 }
 
 func (r *AlipayNotifyReq) Bind(c *gin.Context) error {
@@ -66,6 +73,10 @@ func (r *AlipayNotifyReq) Bind(c *gin.Context) error {
 	r.Ctx = c.Request.Context()
 	r.ID = convert.StrTo(noti.OutTradeNo).MustInt64()
 	r.TradeNo, r.TradeStatus = noti.TradeNo, noti.TradeStatus
+	// 金额单位为元，转换为分
+	if amount, err := strconv.ParseFloat(noti.TotalAmount, 64); err == nil {
+		r.Amount = int64(math.Round(amount * 100))
+	}
 
 	return nil
 }
@@ -81,7 +92,7 @@ func (r *UserWalletBillsReq) Bind(c *gin.Context) error {
 }
 
 func (r *UserRechargeLinkReq) Bind(c *gin.Context) error {
-	r.Host = c.Request.Host
+	r.Host, r.ClientIP = c.Request.Host, c.ClientIP()
 	return bindAny(c, r)
 }
 
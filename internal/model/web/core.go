@@ -7,6 +7,7 @@ package web
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/model/joint"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/pkg/convert"
@@ -20,6 +21,19 @@ type ChangeAvatarReq struct {
 	Avatar   string `json:"avatar" form:"avatar" binding:"required"`
 }
 
+type GetStorageUsageReq struct {
+	BaseInfo `form:"-" binding:"-"`
+}
+
+// StorageUsageResp 存储使用量及配额，配额为0表示不限制
+type StorageUsageResp struct {
+	UsedSize  int64 `json:"used_size"`
+	UsedFiles int64 `json:"used_files"`
+	MaxSize   int64 `json:"max_size"`
+	MaxFiles  int64 `json:"max_files"`
+	Custom    bool  `json:"custom"`
+}
+
 type SyncSearchIndexReq struct {
 	BaseInfo `json:"-" binding:"-"`
 }
@@ -69,9 +83,69 @@ type SendWhisperReq struct {
 	Content    string `json:"content" binding:"required"`
 }
 
-type GetCollectionsReq BasePageReq
+// GetCollectionsReq 获取收藏列表，FolderId为-1时返回全部收藏，0为未归入收藏夹的收藏
+type GetCollectionsReq struct {
+	BasePageReq
+	FolderId int64
+}
+
 type GetCollectionsResp base.PageResp
 
+// CollectionItem 收藏的推文及私人备注
+type CollectionItem struct {
+	*ms.PostFormated
+	FolderId int64  `json:"folder_id"`
+	Note     string `json:"note"`
+}
+
+type ListCollectionFoldersReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+}
+
+type ListCollectionFoldersResp struct {
+	List []*ms.CollectionFolderFormated `json:"list"`
+}
+
+type CreateCollectionFolderReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	Name       string `json:"name" binding:"required,max=64"`
+}
+
+type CreateCollectionFolderResp ms.CollectionFolderFormated
+
+type UpdateCollectionFolderReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64  `json:"id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=64"`
+}
+
+type DeleteCollectionFolderReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64 `json:"id" binding:"required"`
+}
+
+type ShareCollectionFolderReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64 `json:"id" binding:"required"`
+	Share      bool  `json:"share"`
+}
+
+type ShareCollectionFolderResp struct {
+	ShareKey string `json:"share_key"`
+}
+
+type MoveCollectionsReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	FolderId   int64   `json:"folder_id" binding:"min=0"`
+	TweetIds   []int64 `json:"tweet_ids" binding:"required,min=1,max=100"`
+}
+
+type CollectionNoteReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	TweetId    int64  `json:"tweet_id" binding:"required"`
+	Note       string `json:"note" binding:"max=255"`
+}
+
 type GetStarsReq BasePageReq
 type GetStarsResp base.PageResp
 
@@ -136,7 +210,11 @@ func (r *UserInfoReq) Bind(c *gin.Context) error {
 }
 
 func (r *GetCollectionsReq) Bind(c *gin.Context) error {
-	return (*BasePageReq)(r).Bind(c)
+	if err := r.BasePageReq.Bind(c); err != nil {
+		return err
+	}
+	r.FolderId = convert.StrTo(c.DefaultQuery("folder_id", "-1")).MustInt64()
+	return nil
 }
 
 func (r *GetStarsReq) Bind(c *gin.Context) error {
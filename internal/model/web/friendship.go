@@ -5,6 +5,7 @@
 package web
 
 import (
+	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 )
 
@@ -31,8 +32,9 @@ type DeleteFriendReq struct {
 
 type GetContactsReq struct {
 	BaseInfo `form:"-" binding:"-"`
-	Page     int `form:"-" binding:"-"`
-	PageSize int `form:"-" binding:"-"`
+	Page     int        `form:"-" binding:"-"`
+	PageSize int        `form:"-" binding:"-"`
+	Cursor   *cs.Cursor `form:"-" binding:"-"`
 }
 
 type GetContactsResp base.PageResp
@@ -40,3 +42,7 @@ type GetContactsResp base.PageResp
 func (r *GetContactsReq) SetPageInfo(page int, pageSize int) {
 	r.Page, r.PageSize = page, pageSize
 }
+
+func (r *GetContactsReq) SetCursor(cursor *cs.Cursor) {
+	r.Cursor = cursor
+}
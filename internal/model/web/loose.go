@@ -13,6 +13,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/model/joint"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/pkg/app"
+	"github.com/rocboss/paopao-ce/pkg/xerror"
 )
 
 const (
@@ -21,6 +22,7 @@ const (
 	TagTypeFollow    = cs.TagTypeFollow
 	TagTypePin       = cs.TagTypePin
 	TagTypeHotExtral = cs.TagTypeHotExtral
+	TagTypeTrending  = cs.TagTypeTrending
 )
 
 const (
@@ -29,10 +31,12 @@ const (
 	UserPostsStyleHighlight = "highlight"
 	UserPostsStyleMedia     = "media"
 	UserPostsStyleStar      = "star"
+	UserPostsStyleScheduled = "scheduled"
 
 	StyleTweetsNewest    = "newest"
 	StyleTweetsHots      = "hots"
 	StyleTweetsFollowing = "following"
+	StyleTweetsRecommend = "recommend"
 )
 
 type TagType = cs.TagType
@@ -45,6 +49,7 @@ type TweetCommentsReq struct {
 	Style      CommentStyleType `form:"style"`
 	Page       int              `form:"-" binding:"-"`
 	PageSize   int              `form:"-" binding:"-"`
+	Cursor     *cs.Cursor       `form:"-" binding:"-"`
 }
 
 type TweetCommentsResp struct {
@@ -59,6 +64,7 @@ type TimelineReq struct {
 	Style      string              `form:"style"`
 	Page       int                 `form:"-"  binding:"-"`
 	PageSize   int                 `form:"-"  binding:"-"`
+	Cursor     *cs.Cursor          `form:"-"  binding:"-"`
 }
 
 type TimelineResp struct {
@@ -67,10 +73,11 @@ type TimelineResp struct {
 
 type GetUserTweetsReq struct {
 	BaseInfo `form:"-" binding:"-"`
-	Username string `form:"username" binding:"required"`
-	Style    string `form:"style"`
-	Page     int    `form:"-" binding:"-"`
-	PageSize int    `form:"-" binding:"-"`
+	Username string     `form:"username" binding:"required"`
+	Style    string     `form:"style"`
+	Page     int        `form:"-" binding:"-"`
+	PageSize int        `form:"-" binding:"-"`
+	Cursor   *cs.Cursor `form:"-" binding:"-"`
 }
 
 type GetUserTweetsResp struct {
@@ -99,9 +106,10 @@ type GetUserProfileResp struct {
 
 type TopicListReq struct {
 	SimpleInfo `form:"-"  binding:"-"`
-	Type       TagType `json:"type" form:"type" binding:"required"`
-	Num        int     `json:"num" form:"num" binding:"required"`
-	ExtralNum  int     `json:"extral_num" form:"extral_num"`
+	Type       TagType    `json:"type" form:"type" binding:"required"`
+	Num        int        `json:"num" form:"num" binding:"required"`
+	ExtralNum  int        `json:"extral_num" form:"extral_num"`
+	Cursor     *cs.Cursor `json:"-" form:"-" binding:"-"`
 }
 
 // TopicListResp 主题返回值
@@ -109,6 +117,7 @@ type TopicListReq struct {
 type TopicListResp struct {
 	Topics       cs.TagList `json:"topics"`
 	ExtralTopics cs.TagList `json:"extral_topics,omitempty"`
+	NextCursor   string     `json:"next_cursor,omitempty"`
 }
 
 type TweetDetailReq struct {
@@ -118,20 +127,121 @@ type TweetDetailReq struct {
 
 type TweetDetailResp ms.PostFormated
 
+type TweetRevisionsReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	TweetId  int64 `form:"id" binding:"required"`
+	Page     int   `form:"-" binding:"-"`
+	PageSize int   `form:"-" binding:"-"`
+}
+
+type TweetRevisionsResp base.PageResp
+
+type TweetPollVotesReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	TweetId  int64 `form:"id" binding:"required"`
+	Page     int   `form:"-" binding:"-"`
+	PageSize int   `form:"-" binding:"-"`
+}
+
+type TweetPollVotesResp base.PageResp
+
+type TweetTipsReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	TweetId  int64 `form:"id" binding:"required"`
+	Page     int   `form:"-" binding:"-"`
+	PageSize int   `form:"-" binding:"-"`
+}
+
+type TweetTipsResp base.PageResp
+
+type TopicDetailReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	TopicId  int64  `form:"id"`
+	Name     string `form:"name"`
+}
+
+// TopicDetailResp 话题主页
+type TopicDetailResp struct {
+	*cs.TopicInfo
+	Creator      *ms.UserFormated   `json:"creator"`
+	Moderators   []*ms.UserFormated `json:"moderators"`
+	Aliases      []string           `json:"aliases"`
+	IsFollowing  bool               `json:"is_following"`
+	PinnedTweets []*ms.PostFormated `json:"pinned_tweets"`
+}
+
+type TopicTweetsReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	TopicId  int64 `form:"id" binding:"required"`
+	Page     int   `form:"-" binding:"-"`
+	PageSize int   `form:"-" binding:"-"`
+}
+
+type TopicTweetsResp base.PageResp
+
+type SharedCollectionsReq struct {
+	BaseInfo `form:"-"  binding:"-"`
+	ShareKey string `form:"key" binding:"required"`
+	Page     int    `form:"-" binding:"-"`
+	PageSize int    `form:"-" binding:"-"`
+}
+
+// SharedCollectionsResp 分享的收藏夹及其中的公开推文
+type SharedCollectionsResp struct {
+	Folder *ms.CollectionFolderFormated `json:"folder"`
+	User   *ms.UserFormated             `json:"user"`
+	*base.PageResp
+}
+
 func (r *GetUserTweetsReq) SetPageInfo(page int, pageSize int) {
 	r.Page, r.PageSize = page, pageSize
 }
 
+func (r *TweetRevisionsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
+func (r *TweetPollVotesReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
+func (r *TweetTipsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
+func (r *SharedCollectionsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
+func (r *TopicTweetsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
 func (r *TweetCommentsReq) SetPageInfo(page int, pageSize int) {
 	r.Page, r.PageSize = page, pageSize
 }
 
-func (r *TimelineReq) Bind(c *gin.Context) error {
+func (r *GetUserTweetsReq) SetCursor(cursor *cs.Cursor) {
+	r.Cursor = cursor
+}
+
+func (r *TweetCommentsReq) SetCursor(cursor *cs.Cursor) {
+	r.Cursor = cursor
+}
+
+func (r *TopicListReq) SetCursor(cursor *cs.Cursor) {
+	r.Cursor = cursor
+}
+
+func (r *TimelineReq) Bind(c *gin.Context) (err error) {
 	user, _ := base.UserFrom(c)
 	r.BaseInfo = BaseInfo{
 		User: user,
 	}
 	r.Page, r.PageSize = app.GetPageInfo(c)
+	if r.Cursor, err = app.GetCursor(c); err != nil {
+		return xerror.InvalidParams.WithDetails(err.Error())
+	}
 	r.Query, r.Type, r.Style = c.Query("query"), "search", c.Query("style")
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rocboss/paopao-ce/internal/core"
@@ -17,6 +18,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/model/joint"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/pkg/convert"
+	"github.com/rocboss/paopao-ce/pkg/json"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 )
 
@@ -26,9 +28,21 @@ const (
 	TweetVisitPrivate
 	TweetVisitFriend
 	TweetVisitFollowing
+	TweetVisitSubscribe
+	TweetVisitCharge
 	TweetVisitInvalid
 )
 
+const (
+	// 投票限制
+	_minPollOptions        = 2
+	_maxPollOptions        = 10
+	_maxPollOptionLength   = 64
+	_maxPollQuestionLength = 128
+	_minPollDuration       = 5 * 60
+	_maxPollDuration       = 30 * 24 * 3600
+)
+
 type TweetVisibleType cs.TweetVisibleType
 
 type TweetCommentThumbsReq struct {
@@ -50,6 +64,15 @@ type PostContentItem struct {
 	Sort    int64           `json:"sort"  binding:"required"`
 }
 
+// PollContent 投票内容块的配置，以JSON形式保存在内容块中，Duration为发布后的投票时长(秒)
+type PollContent struct {
+	Question  string   `json:"question"`
+	Options   []string `json:"options"`
+	Multiple  bool     `json:"multiple"`
+	Anonymous bool     `json:"anonymous"`
+	Duration  int64    `json:"duration"`
+}
+
 type CreateTweetReq struct {
 	BaseInfo        `json:"-" binding:"-"`
 	Contents        []*PostContentItem `json:"contents" binding:"required"`
@@ -57,10 +80,154 @@ type CreateTweetReq struct {
 	Users           []string           `json:"users" binding:"required"`
 	AttachmentPrice int64              `json:"attachment_price"`
 	Visibility      TweetVisibleType   `json:"visibility"`
+	PublishAt       int64              `json:"publish_at"`
 	ClientIP        string             `json:"-" binding:"-"`
 }
 
-type CreateTweetResp ms.PostFormated
+// CreateTweetResp 立即发布时返回推文，定时发布时返回定时推文信息
+type CreateTweetResp struct {
+	*ms.PostFormated
+	Schedule *ms.PostScheduleFormated `json:"schedule,omitempty"`
+}
+
+type UpdateScheduledTweetReq struct {
+	BaseInfo        `json:"-" binding:"-"`
+	ID              int64              `json:"id" binding:"required"`
+	Contents        []*PostContentItem `json:"contents" binding:"required"`
+	Tags            []string           `json:"tags" binding:"required"`
+	Users           []string           `json:"users" binding:"required"`
+	AttachmentPrice int64              `json:"attachment_price"`
+	Visibility      TweetVisibleType   `json:"visibility"`
+	PublishAt       int64              `json:"publish_at" binding:"required"`
+}
+
+type UpdateScheduledTweetResp ms.PostScheduleFormated
+
+type CancelScheduledTweetReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64 `json:"id" binding:"required"`
+}
+
+type CreateDraftReq struct {
+	BaseInfo        `json:"-" binding:"-"`
+	Contents        []*PostContentItem `json:"contents"`
+	Tags            []string           `json:"tags"`
+	Users           []string           `json:"users"`
+	AttachmentPrice int64              `json:"attachment_price"`
+	Visibility      TweetVisibleType   `json:"visibility"`
+}
+
+type CreateDraftResp ms.PostDraftFormated
+
+type UpdateDraftReq struct {
+	BaseInfo        `json:"-" binding:"-"`
+	ID              int64              `json:"id" binding:"required"`
+	Contents        []*PostContentItem `json:"contents"`
+	Tags            []string           `json:"tags"`
+	Users           []string           `json:"users"`
+	AttachmentPrice int64              `json:"attachment_price"`
+	Visibility      TweetVisibleType   `json:"visibility"`
+}
+
+type UpdateDraftResp ms.PostDraftFormated
+
+type DeleteDraftReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64 `json:"id" binding:"required"`
+}
+
+type ListDraftsReq BasePageReq
+type ListDraftsResp base.PageResp
+
+type PublishDraftReq struct {
+	BaseInfo  `json:"-" binding:"-"`
+	ID        int64  `json:"id" binding:"required"`
+	PublishAt int64  `json:"publish_at"`
+	ClientIP  string `json:"-" binding:"-"`
+}
+
+type EditTweetReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64              `json:"id" binding:"required"`
+	Contents []*PostContentItem `json:"contents" binding:"required"`
+	Tags     []string           `json:"tags" binding:"required"`
+	Users    []string           `json:"users" binding:"required"`
+}
+
+type EditTweetResp ms.PostFormated
+
+type VotePollReq struct {
+	BaseInfo  `json:"-" binding:"-"`
+	TweetId   int64   `json:"tweet_id" binding:"required"`
+	OptionIds []int64 `json:"option_ids" binding:"required"`
+}
+
+type VotePollResp ms.PostPollFormated
+
+type TipTweetReq struct {
+	BaseInfo  `json:"-" binding:"-"`
+	TweetId   int64  `json:"tweet_id" binding:"required"`
+	Amount    int64  `json:"amount" binding:"required,min=1"`
+	Message   string `json:"message" binding:"max=255"`
+	RequestId string `json:"request_id" binding:"required,max=64"`
+}
+
+type TipTweetResp ms.PostTipFormated
+
+type RequestAttachmentRefundReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	TweetId  int64  `json:"tweet_id" binding:"required"`
+	Reason   string `json:"reason" binding:"required,max=255"`
+}
+
+type RequestAttachmentRefundResp ms.AttachmentRefund
+
+type DisputeAttachmentRefundReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64  `json:"id" binding:"required"`
+	Reason   string `json:"reason" binding:"max=255"`
+}
+
+type ReviewAttachmentRefundReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64  `json:"id" binding:"required"`
+	Approve  bool   `json:"approve"`
+	Remark   string `json:"remark" binding:"max=255"`
+}
+
+// UserAttachmentRefundsReq Style为buyer时获取用户申请的退款，为author时获取待用户处理的退款
+type UserAttachmentRefundsReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	Style    string          `form:"style"`
+	Status   ms.RefundStatus `form:"status,default=-1"`
+	Page     int             `form:"-" binding:"-"`
+	PageSize int             `form:"-" binding:"-"`
+}
+
+type UserAttachmentRefundsResp base.PageResp
+
+type CreateWithdrawalReq struct {
+	SimpleInfo  `json:"-" binding:"-"`
+	Amount      int64  `json:"amount" binding:"required,min=1"`
+	AccountType string `json:"account_type" binding:"required"`
+	Account     string `json:"account" binding:"required,max=128"`
+	AccountName string `json:"account_name" binding:"required,max=64"`
+}
+
+type CreateWithdrawalResp ms.WalletWithdrawal
+
+type UserWithdrawalsReq struct {
+	SimpleInfo `form:"-" binding:"-"`
+	Page       int `form:"-" binding:"-"`
+	PageSize   int `form:"-" binding:"-"`
+}
+
+type UserWithdrawalsResp base.PageResp
+
+type CancelWithdrawalReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64 `json:"id" binding:"required"`
+}
 
 type DeleteTweetReq struct {
 	BaseInfo `json:"-" binding:"-"`
@@ -171,12 +338,16 @@ type UploadAttachmentReq struct {
 }
 
 type UploadAttachmentResp struct {
-	UserID    int64             `json:"user_id"`
-	FileSize  int64             `json:"file_size"`
-	ImgWidth  int               `json:"img_width"`
-	ImgHeight int               `json:"img_height"`
-	Type      ms.AttachmentType `json:"type"`
-	Content   string            `json:"content"`
+	UserID        int64              `json:"user_id"`
+	FileSize      int64              `json:"file_size"`
+	ImgWidth      int                `json:"img_width"`
+	ImgHeight     int                `json:"img_height"`
+	Type          ms.AttachmentType  `json:"type"`
+	Content       string             `json:"content"`
+	Blurhash      string             `json:"blurhash,omitempty"`
+	DominantColor string             `json:"dominant_color,omitempty"`
+	Variants      []*ms.ImageVariant `json:"variants,omitempty"`
+	Status        ms.MediaStatus     `json:"status"`
 }
 
 type DownloadAttachmentPrecheckReq struct {
@@ -215,6 +386,29 @@ type PinTopicResp struct {
 	PinStatus int8 `json:"pin_status"`
 }
 
+type UpdateTopicReq struct {
+	BaseInfo    `json:"-" binding:"-"`
+	TopicId     int64  `json:"topic_id" binding:"required"`
+	Description string `json:"description" binding:"max=255"`
+	Cover       string `json:"cover" binding:"max=255"`
+}
+
+type PinTopicTweetReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	TopicId  int64 `json:"topic_id" binding:"required"`
+	TweetId  int64 `json:"tweet_id" binding:"required"`
+}
+
+type PinTopicTweetResp struct {
+	PinStatus int8 `json:"pin_status"`
+}
+
+type TopicModeratorReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	TopicId  int64 `json:"topic_id" binding:"required"`
+	UserId   int64 `json:"user_id" binding:"required"`
+}
+
 type FollowTopicReq struct {
 	SimpleInfo `json:"-" binding:"-"`
 	TopicId    int64 `json:"topic_id" binding:"required"`
@@ -239,6 +433,47 @@ func (p *PostContentItem) Check(acs core.AttachmentCheckService) error {
 			return fmt.Errorf("链接不合法")
 		}
 	}
+	// 检查投票是否合法
+	if p.Type == ms.ContentTypePoll {
+		if _, err := p.PollContent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PollContent 解析投票内容块中的投票配置
+func (p *PostContentItem) PollContent() (*PollContent, error) {
+	poll := &PollContent{}
+	if err := json.Unmarshal([]byte(p.Content), poll); err != nil {
+		return nil, ErrInvalidPoll
+	}
+	if err := poll.Check(); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// Check 检查投票配置是否合法，并规整选项内容
+func (p *PollContent) Check() error {
+	p.Question = strings.TrimSpace(p.Question)
+	if utf8.RuneCountInString(p.Question) > _maxPollQuestionLength {
+		return ErrInvalidPoll
+	}
+	if len(p.Options) < _minPollOptions || len(p.Options) > _maxPollOptions {
+		return ErrInvalidPoll
+	}
+	if p.Duration < _minPollDuration || p.Duration > _maxPollDuration {
+		return ErrInvalidPoll
+	}
+	exists := make(map[string]struct{}, len(p.Options))
+	for i, option := range p.Options {
+		option = strings.TrimSpace(option)
+		if _, exist := exists[option]; exist || option == "" || utf8.RuneCountInString(option) > _maxPollOptionLength {
+			return ErrInvalidPoll
+		}
+		exists[option], p.Options[i] = struct{}{}, option
+	}
 	return nil
 }
 
@@ -304,6 +539,15 @@ func (r *CreateTweetReq) Bind(c *gin.Context) error {
 	return bindAny(c, r)
 }
 
+func (r *PublishDraftReq) Bind(c *gin.Context) error {
+	r.ClientIP = c.ClientIP()
+	return bindAny(c, r)
+}
+
+func (r *ListDraftsReq) Bind(c *gin.Context) error {
+	return (*BasePageReq)(r).Bind(c)
+}
+
 func (r *CreateCommentReplyReq) Bind(c *gin.Context) error {
 	r.ClientIP = c.ClientIP()
 	return bindAny(c, r)
@@ -314,7 +558,28 @@ func (r *CreateCommentReq) Bind(c *gin.Context) error {
 	return bindAny(c, r)
 }
 
+func (r *UserWithdrawalsReq) SetPageInfo(page int, pageSize int) {
+	r.Page, r.PageSize = page, pageSize
+}
+
 func (r *CreateTweetResp) Render(c *gin.Context) {
+	c.JSON(http.StatusOK, &joint.JsonResp{
+		Code: 0,
+		Msg:  "success",
+		Data: r,
+	})
+	// 定时推文尚未发布，暂不审核
+	if r.PostFormated == nil {
+		return
+	}
+	// 设置审核元信息，用于接下来的审核逻辑
+	c.Set(AuditHookCtxKey, &AuditMetaInfo{
+		Style: AuditStyleUserTweet,
+		Id:    r.ID,
+	})
+}
+
+func (r *EditTweetResp) Render(c *gin.Context) {
 	c.JSON(http.StatusOK, &joint.JsonResp{
 		Code: 0,
 		Msg:  "success",
@@ -328,7 +593,7 @@ func (r *CreateTweetResp) Render(c *gin.Context) {
 }
 
 func (t TweetVisibleType) ToVisibleValue() (res cs.TweetVisibleType) {
-	// 原来的可见性: 0公开 1私密 2好友可见 3关注可见
+	// 原来的可见性: 0公开 1私密 2好友可见 3关注可见 4订阅可见 5充电可见
 	//  现在的可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开
 	switch t {
 	case TweetVisitPublic:
@@ -339,6 +604,10 @@ func (t TweetVisibleType) ToVisibleValue() (res cs.TweetVisibleType) {
 		res = cs.TweetVisitFriend
 	case TweetVisitFollowing:
 		res = cs.TweetVisitFollowing
+	case TweetVisitSubscribe:
+		res = cs.TweetVisitSubscribe
+	case TweetVisitCharge:
+		res = cs.TweetVisitCharge
 	default:
 		// TODO: 默认私密
 		res = cs.TweetVisitPrivate
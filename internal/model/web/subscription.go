@@ -0,0 +1,70 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/model/joint"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+)
+
+type ListSubscriptionTiersReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	Username string `form:"username" binding:"required"`
+}
+
+type ListSubscriptionTiersResp struct {
+	List         []*ms.SubscriptionTier `json:"list"`
+	Subscription *ms.Subscription       `json:"subscription,omitempty"`
+}
+
+type CreateSubscriptionTierReq struct {
+	BaseInfo    `json:"-" binding:"-"`
+	Title       string `json:"title" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+	Price       int64  `json:"price" binding:"required"`
+}
+
+type CreateSubscriptionTierResp ms.SubscriptionTier
+
+type UpdateSubscriptionTierReq struct {
+	BaseInfo    `json:"-" binding:"-"`
+	ID          int64  `json:"id" binding:"required"`
+	Title       string `json:"title" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+	Price       int64  `json:"price" binding:"required"`
+}
+
+type DeleteSubscriptionTierReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	ID       int64 `json:"id" binding:"required"`
+}
+
+type SubscribeReq struct {
+	BaseInfo `json:"-" binding:"-"`
+	TierID   int64 `json:"tier_id" binding:"required"`
+}
+
+type SubscribeResp ms.Subscription
+
+type UpdateSubscriptionRenewReq struct {
+	BaseInfo  `json:"-" binding:"-"`
+	CreatorID int64 `json:"creator_id" binding:"required"`
+	AutoRenew bool  `json:"auto_renew"`
+}
+
+type UserSubscriptionsReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	joint.BasePageInfo
+}
+
+type UserSubscriptionsResp base.PageResp
+
+type ListSubscribersReq struct {
+	BaseInfo `form:"-" binding:"-"`
+	joint.BasePageInfo
+}
+
+type ListSubscribersResp base.PageResp
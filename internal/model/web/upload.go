@@ -0,0 +1,133 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/pkg/xerror"
+)
+
+const (
+	// UploadChunkContentType 断点续传分块请求的Content-Type，同tus协议
+	UploadChunkContentType = "application/offset+octet-stream"
+)
+
+type CreateUploadReq struct {
+	SimpleInfo  `json:"-" binding:"-"`
+	UploadType  string `json:"type"`
+	ContentType string `json:"content_type"`
+	FileSize    int64  `json:"file_size"`
+	FileExt     string `json:"-"`
+}
+
+type CreateUploadResp struct {
+	ID           int64 `json:"id"`
+	FileSize     int64 `json:"file_size"`
+	Offset       int64 `json:"offset"`
+	MaxChunkSize int64 `json:"max_chunk_size"`
+	ExpiredOn    int64 `json:"expired_on"`
+}
+
+type UploadChunkReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64
+	Offset     int64
+	Body       io.Reader
+}
+
+type UploadChunkResp struct {
+	Offset    int64
+	ExpiredOn int64
+}
+
+type UploadProgressReq struct {
+	SimpleInfo `form:"-" binding:"-"`
+	ID         int64 `form:"id" binding:"required"`
+}
+
+type UploadProgressResp struct {
+	FileSize  int64
+	Offset    int64
+	ExpiredOn int64
+}
+
+type CompleteUploadReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64 `json:"id" binding:"required"`
+}
+
+type CancelUploadReq struct {
+	SimpleInfo `json:"-" binding:"-"`
+	ID         int64 `json:"id" binding:"required"`
+}
+
+func (r *CreateUploadReq) Bind(c *gin.Context) error {
+	userId, exist := base.UserIdFrom(c)
+	if !exist {
+		return xerror.UnauthorizedAuthNotExist
+	}
+	if err := c.ShouldBindJSON(r); err != nil || r.FileSize <= 0 {
+		return xerror.InvalidParams
+	}
+	if err := uploadCheck(r.UploadType, r.FileSize, conf.ResumableUploadSetting.MaxSize); err != nil {
+		return err
+	}
+	fileExt, err := getFileExt(r.ContentType)
+	if err != nil {
+		return err
+	}
+	r.SimpleInfo = SimpleInfo{
+		Uid: userId,
+	}
+	r.FileExt = fileExt
+	return nil
+}
+
+// Bind 分块内容为请求体，偏移量通过Upload-Offset请求头指定
+func (r *UploadChunkReq) Bind(c *gin.Context) error {
+	userId, exist := base.UserIdFrom(c)
+	if !exist {
+		return xerror.UnauthorizedAuthNotExist
+	}
+	if c.ContentType() != UploadChunkContentType {
+		return xerror.InvalidParams.WithDetails("Content-Type必须为" + UploadChunkContentType)
+	}
+	id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return xerror.InvalidParams
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return xerror.InvalidParams.WithDetails("缺少有效的Upload-Offset")
+	}
+	r.SimpleInfo = SimpleInfo{
+		Uid: userId,
+	}
+	r.ID, r.Offset, r.Body = id, offset, c.Request.Body
+	return nil
+}
+
+// Render 同tus协议，成功时返回204及新的Upload-Offset
+func (r *UploadChunkResp) Render(c *gin.Context) {
+	c.Header("Upload-Offset", strconv.FormatInt(r.Offset, 10))
+	c.Header("Upload-Expires", time.Unix(r.ExpiredOn, 0).UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+}
+
+// Render 同tus协议，通过响应头返回上传进度
+func (r *UploadProgressResp) Render(c *gin.Context) {
+	c.Header("Upload-Length", strconv.FormatInt(r.FileSize, 10))
+	c.Header("Upload-Offset", strconv.FormatInt(r.Offset, 10))
+	c.Header("Upload-Expires", time.Unix(r.ExpiredOn, 0).UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
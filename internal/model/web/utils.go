@@ -5,19 +5,26 @@
 package web
 
 import (
+	"fmt"
+
 	"github.com/alimy/mir/v5"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 )
 
 func fileCheck(uploadType string, size int64) mir.Error {
+	return uploadCheck(uploadType, size, 100)
+}
+
+// uploadCheck 检查上传类型及文件大小，maxSize单位为MB
+func uploadCheck(uploadType string, size int64, maxSize int64) mir.Error {
 	if uploadType != "public/video" &&
 		uploadType != "public/image" &&
 		uploadType != "public/avatar" &&
 		uploadType != "attachment" {
 		return xerror.InvalidParams
 	}
-	if size > 1024*1024*100 {
-		return ErrFileInvalidSize.WithDetails("最大允许100MB")
+	if size > maxSize<<20 {
+		return ErrFileInvalidSize.WithDetails(fmt.Sprintf("最大允许%dMB", maxSize))
 	}
 	return nil
 }
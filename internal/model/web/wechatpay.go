@@ -0,0 +1,38 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/pkg/convert"
+	"github.com/sirupsen/logrus"
+)
+
+type WechatPayNotifyReq struct {
+	Ctx        context.Context
+	ID         int64
+	TradeNo    string
+	TradeState string
+	Amount     int64
+}
+
+func (r *WechatPayNotifyReq) Bind(c *gin.Context) error {
+	notify, trans, err := conf.MustWechatPayClient().ParseNotify(c.Request)
+	if err != nil {
+		logrus.Errorf("wechatPayClient.ParseNotify err: %s", err)
+		return ErrRechargeNotifyError
+	}
+	logrus.Debugf("wechat pay notify id:%s event:%s", notify.ID, notify.EventType)
+	r.Ctx = c.Request.Context()
+	r.ID = convert.StrTo(trans.OutTradeNo).MustInt64()
+	r.TradeNo, r.TradeState = trans.TransactionID, trans.TradeState
+	if trans.Amount != nil {
+		r.Amount = trans.Amount.Total
+	}
+	return nil
+}
@@ -49,6 +49,26 @@ var (
 	ErrHighlightPostFailed     = xerror.NewError(30013, "动态设为亮点失败")
 	ErrGetPostsUnknowStyle     = xerror.NewError(30014, "使用未知样式参数获取动态列表")
 	ErrGetPostsNilUser         = xerror.NewError(30015, "使用游客账户获取动态详情失败")
+	ErrInvalidPublishAt        = xerror.NewError(30016, "定时发布时间不合法")
+	ErrCreateScheduledFailed   = xerror.NewError(30017, "定时动态创建失败")
+	ErrGetScheduledFailed      = xerror.NewError(30018, "获取定时动态失败")
+	ErrUpdateScheduledFailed   = xerror.NewError(30019, "定时动态更新失败")
+	ErrCancelScheduledFailed   = xerror.NewError(30020, "定时动态取消失败")
+	ErrSaveDraftFailed         = xerror.NewError(30021, "草稿保存失败")
+	ErrGetDraftFailed          = xerror.NewError(30022, "获取草稿失败")
+	ErrGetDraftsFailed         = xerror.NewError(30023, "获取草稿列表失败")
+	ErrDeleteDraftFailed       = xerror.NewError(30024, "草稿删除失败")
+	ErrEditPostFailed          = xerror.NewError(30025, "动态编辑失败")
+	ErrEditPostExpired         = xerror.NewError(30026, "动态已超过可编辑时间")
+	ErrGetPostRevisionsFailed  = xerror.NewError(30027, "获取动态修订历史失败")
+	ErrInvalidPoll             = xerror.NewError(30028, "投票内容不合法")
+	ErrInvalidPollOptions      = xerror.NewError(30029, "投票选项不合法")
+	ErrGetPollFailed           = xerror.NewError(30030, "获取投票失败")
+	ErrVotePollFailed          = xerror.NewError(30031, "投票失败")
+	ErrPollClosed              = xerror.NewError(30032, "投票已结束")
+	ErrPollAlreadyVoted        = xerror.NewError(30033, "您已经投过票了")
+	ErrPollAnonymous           = xerror.NewError(30034, "匿名投票不公开投票人")
+	ErrScheduledNotPending     = xerror.NewError(30035, "定时动态已发布或已取消")
 
 	ErrGetCommentsFailed      = xerror.NewError(40001, "获取评论列表失败")
 	ErrCreateCommentFailed    = xerror.NewError(40002, "评论发布失败")
@@ -66,13 +86,62 @@ var (
 	ErrNoWhisperToSelf   = xerror.NewError(50004, "不允许给自己发送私信")
 	ErrTooManyWhisperNum = xerror.NewError(50005, "今日私信次数已达上限")
 
-	ErrGetCollectionsFailed = xerror.NewError(60001, "获取收藏列表失败")
-	ErrGetStarsFailed       = xerror.NewError(60002, "获取点赞列表失败")
+	ErrGetCollectionsFailed         = xerror.NewError(60001, "获取收藏列表失败")
+	ErrGetStarsFailed               = xerror.NewError(60002, "获取点赞列表失败")
+	ErrNoExistCollectionFolder      = xerror.NewError(60003, "收藏夹不存在")
+	ErrCollectionFolderExisted      = xerror.NewError(60004, "已存在同名收藏夹")
+	ErrCreateCollectionFolderFailed = xerror.NewError(60005, "创建收藏夹失败")
+	ErrUpdateCollectionFolderFailed = xerror.NewError(60006, "更新收藏夹失败")
+	ErrDeleteCollectionFolderFailed = xerror.NewError(60007, "删除收藏夹失败")
+	ErrMoveCollectionsFailed        = xerror.NewError(60008, "移动收藏失败")
+	ErrNoExistCollection            = xerror.NewError(60009, "尚未收藏该推文")
+	ErrUpdateCollectionNoteFailed   = xerror.NewError(60010, "更新收藏备注失败")
+	ErrTooManyCollectionFolders     = xerror.NewError(60011, "收藏夹数量已达上限")
 
 	ErrRechargeReqFail       = xerror.NewError(70001, "充值请求失败")
 	ErrRechargeNotifyError   = xerror.NewError(70002, "充值回调失败")
 	ErrGetRechargeFailed     = xerror.NewError(70003, "充值详情获取失败")
 	ErrUserWalletBillsFailed = xerror.NewError(70004, "用户钱包账单获取失败")
+	ErrRechargeProvider      = xerror.NewError(70005, "不支持的支付渠道")
+	ErrWithdrawalAmount      = xerror.NewError(70006, "提现金额低于最低提现金额")
+	ErrInsufficientBalance   = xerror.NewError(70007, "钱包余额不足")
+	ErrWithdrawalAccountType = xerror.NewError(70008, "不支持的收款渠道")
+	ErrTooManyWithdrawals    = xerror.NewError(70009, "未完成的提现申请过多")
+	ErrCreateWithdrawal      = xerror.NewError(70010, "提现申请失败")
+	ErrGetWithdrawalsFailed  = xerror.NewError(70011, "提现记录获取失败")
+	ErrNoExistWithdrawal     = xerror.NewError(70012, "提现申请不存在")
+	ErrWithdrawalStatus      = xerror.NewError(70013, "提现申请状态已变更")
+	ErrUpdateWithdrawal      = xerror.NewError(70014, "提现状态更新失败")
+	ErrWalletReconcileFailed = xerror.NewError(70015, "钱包对账失败")
+
+	ErrNoSubscription           = xerror.NewError(70101, "订阅后才能查看")
+	ErrSubscriptionTierPrice    = xerror.NewError(70102, "订阅价格不在允许范围内")
+	ErrTooManySubscriptionTiers = xerror.NewError(70103, "订阅档位数已达上限")
+	ErrCreateSubscriptionTier   = xerror.NewError(70104, "订阅档位创建失败")
+	ErrUpdateSubscriptionTier   = xerror.NewError(70105, "订阅档位更新失败")
+	ErrNoExistSubscriptionTier  = xerror.NewError(70106, "订阅档位不存在")
+	ErrSubscribeSelf            = xerror.NewError(70107, "不能订阅自己")
+	ErrAlreadySubscribed        = xerror.NewError(70108, "已在订阅有效期内")
+	ErrSubscribeFailed          = xerror.NewError(70109, "订阅失败")
+	ErrNoExistSubscription      = xerror.NewError(70110, "订阅不存在")
+	ErrUpdateSubscription       = xerror.NewError(70111, "订阅更新失败")
+	ErrGetSubscriptionsFailed   = xerror.NewError(70112, "获取订阅列表失败")
+
+	ErrTipAmount      = xerror.NewError(70201, "充电金额低于最低金额")
+	ErrTipSelf        = xerror.NewError(70202, "不能为自己的动态充电")
+	ErrTipTweetFailed = xerror.NewError(70203, "充电失败")
+	ErrGetTipsFailed  = xerror.NewError(70204, "获取充电记录失败")
+	ErrNoCharge       = xerror.NewError(70205, "充电后才能查看完整内容")
+
+	ErrNoExistAttachmentBill = xerror.NewError(70301, "未购买该附件")
+	ErrRefundExpired         = xerror.NewError(70302, "已超过可申请退款的期限")
+	ErrRefundExisted         = xerror.NewError(70303, "已有处理中的退款申请")
+	ErrCreateRefundFailed    = xerror.NewError(70304, "退款申请提交失败")
+	ErrNoExistRefund         = xerror.NewError(70305, "退款申请不存在")
+	ErrRefundStatus          = xerror.NewError(70306, "退款申请当前状态不允许该操作")
+	ErrRefundFailed          = xerror.NewError(70307, "退款处理失败")
+	ErrAuthorNoBalance       = xerror.NewError(70308, "作者余额不足，暂时无法退款")
+	ErrGetRefundsFailed      = xerror.NewError(70309, "获取退款申请列表失败")
 
 	ErrNoRequestingFriendToSelf   = xerror.NewError(80001, "不允许添加自己为好友")
 	ErrNotExistFriendId           = xerror.NewError(80002, "好友id不存在")
@@ -96,6 +165,16 @@ var (
 	ErrUnfollowTopicFailed    = xerror.NewError(90002, "取消关注话题失败")
 	ErrStickTopicFailed       = xerror.NewError(90003, "更行话题置顶状态失败")
 	ErrPinTopicFailed         = xerror.NewError(90005, "更行话题钉住状态失败")
+	ErrGetTopicFailed         = xerror.NewError(90006, "获取话题信息失败")
+	ErrNoExistTopic           = xerror.NewError(90007, "话题不存在")
+	ErrNoPermissionTopic      = xerror.NewError(90008, "无权管理该话题")
+	ErrTopicLocked            = xerror.NewError(90009, "话题已被锁定")
+	ErrUpdateTopicFailed      = xerror.NewError(90010, "更新话题信息失败")
+	ErrPinTopicTweetFailed    = xerror.NewError(90011, "更新话题内推文置顶状态失败")
+	ErrTooManyTopicPins       = xerror.NewError(90012, "话题置顶推文数已达上限")
+	ErrTweetNotInTopic        = xerror.NewError(90013, "推文不属于该话题")
+	ErrMergeTopicFailed       = xerror.NewError(90014, "合并话题失败")
+	ErrTopicModeratorFailed   = xerror.NewError(90015, "更新话题主持人失败")
 	ErrThumbsUpTweetComment   = xerror.NewError(90101, "评论点赞失败")
 	ErrThumbsDownTweetComment = xerror.NewError(90102, "评论点踩失败")
 	ErrThumbsUpTweetReply     = xerror.NewError(90103, "评论回复点赞失败")
@@ -105,5 +184,13 @@ var (
 	ErrFileInvalidExt   = xerror.NewError(10201, "文件类型不合法")
 	ErrFileInvalidSize  = xerror.NewError(10202, "文件大小超限")
 
+	ErrUploadSessionNotExist = xerror.NewError(10203, "上传会话不存在或已过期")
+	ErrUploadOffsetMismatch  = xerror.NewError(10204, "上传偏移量不匹配")
+	ErrUploadSessionBusy     = xerror.NewError(10205, "上传会话正在写入中")
+	ErrUploadIncomplete      = xerror.NewError(10206, "文件尚未上传完成")
+	ErrTooManyUploadSessions = xerror.NewError(10207, "进行中的上传过多")
+	ErrStorageQuotaExceeded  = xerror.NewError(10208, "存储配额不足")
+	ErrInvalidPicture        = xerror.NewError(10209, "图片无法解析")
+
 	ErrNotImplemented = xerror.NewError(10501, "功能未实现")
 )
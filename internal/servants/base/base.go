@@ -28,6 +28,9 @@ import (
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 )
 
+// _chargePreviewSize 充电可见推文预览的最大字数
+const _chargePreviewSize = 100
+
 type BaseServant struct {
 	bindAny  func(c *gin.Context, obj any) error
 	bindJson func(c *gin.Context, obj any) error
@@ -58,6 +61,10 @@ type PageInfoSetter interface {
 	SetPageInfo(page, pageSize int)
 }
 
+type CursorSetter interface {
+	SetCursor(cursor *cs.Cursor)
+}
+
 func UserFrom(c *gin.Context) (*ms.User, bool) {
 	if u, exists := c.Get("USER"); exists {
 		user, ok := u.(*ms.User)
@@ -103,6 +110,14 @@ func bindAny(c *gin.Context, obj any) error {
 		page, pageSize := app.GetPageInfo(c)
 		setter.SetPageInfo(page, pageSize)
 	}
+	// setup Cursor if needed
+	if setter, ok := obj.(CursorSetter); ok {
+		cursor, err := app.GetCursor(c)
+		if err != nil {
+			return mir.NewError(xerror.InvalidParams.StatusCode(), xerror.InvalidParams.WithDetails(err.Error()))
+		}
+		setter.SetCursor(cursor)
+	}
 	return nil
 }
 
@@ -136,6 +151,14 @@ func bindAnySentry(c *gin.Context, obj any) error {
 		page, pageSize := app.GetPageInfo(c)
 		setter.SetPageInfo(page, pageSize)
 	}
+	// setup Cursor if needed
+	if setter, ok := obj.(CursorSetter); ok {
+		cursor, err := app.GetCursor(c)
+		if err != nil {
+			return mir.NewError(xerror.InvalidParams.StatusCode(), xerror.InvalidParams.WithDetails(err.Error()))
+		}
+		setter.SetCursor(cursor)
+	}
 	return nil
 
 }
@@ -234,6 +257,22 @@ func (s *DaoServant) PrepareMessages(userId int64, messages []*ms.MessageFormate
 }
 
 func (s *DaoServant) PrepareTweet(user *ms.User, tweet *ms.PostFormated) error {
+	if tweet.Visibility == ms.PostVisitCharge && !s.isChargedTweet(user, tweet) {
+		lockChargeTweet(tweet)
+	}
+	userId := int64(-1)
+	if user != nil {
+		userId = user.ID
+	}
+	if err := s.PrepareTweetPolls(userId, []*ms.PostFormated{tweet}); err != nil {
+		return err
+	}
+	if err := s.PrepareTweetLinks([]*ms.PostFormated{tweet}); err != nil {
+		return err
+	}
+	if err := s.PrepareTweetMedia([]*ms.PostFormated{tweet}); err != nil {
+		return err
+	}
 	// guest用户
 	if user == nil {
 		return nil
@@ -256,9 +295,22 @@ func (s *DaoServant) PrepareTweets(userId int64, tweets []*ms.PostFormated) erro
 	userIdSet := make(map[int64]types.Empty, len(tweets))
 	for _, tweet := range tweets {
 		userIdSet[tweet.UserID] = types.Empty{}
+		// 列表中充电可见的推文仅对作者展示完整内容
+		if tweet.Visibility == ms.PostVisitCharge && tweet.UserID != userId {
+			lockChargeTweet(tweet)
+		}
 		// 顺便转换一下可见性的值
 		tweet.Visibility = ms.PostVisibleT(tweet.Visibility.ToOutValue())
 	}
+	if err := s.PrepareTweetPolls(userId, tweets); err != nil {
+		return err
+	}
+	if err := s.PrepareTweetLinks(tweets); err != nil {
+		return err
+	}
+	if err := s.PrepareTweetMedia(tweets); err != nil {
+		return err
+	}
 	// guest用户的userId<0
 	if userId < 0 {
 		return nil
@@ -282,6 +334,121 @@ func (s *DaoServant) PrepareTweets(userId int64, tweets []*ms.PostFormated) erro
 	return nil
 }
 
+// isChargedTweet 作者本人、管理员及累计充电达到门槛的用户可查看充电可见推文的完整内容
+func (s *DaoServant) isChargedTweet(user *ms.User, tweet *ms.PostFormated) bool {
+	if user == nil {
+		return false
+	}
+	return user.ID == tweet.UserID || user.IsAdmin || s.Ds.IsPostCharger(user.ID, tweet.ID)
+}
+
+// PrepareTweetPolls 填充推文投票的实时计数以及当前用户的投票选项
+func (s *DaoServant) PrepareTweetPolls(userId int64, tweets []*ms.PostFormated) error {
+	tweetMap := make(map[int64]*ms.PostFormated)
+	for _, tweet := range tweets {
+		// 投票计数实时获取，忽略缓存中的旧数据
+		tweet.Poll = nil
+		for _, content := range tweet.Contents {
+			if content.Type == ms.ContentTypePoll {
+				tweetMap[tweet.ID] = tweet
+				break
+			}
+		}
+	}
+	if len(tweetMap) == 0 {
+		return nil
+	}
+	postIds := make([]int64, 0, len(tweetMap))
+	for id := range tweetMap {
+		postIds = append(postIds, id)
+	}
+	polls, err := s.Ds.PollsByPostIds(postIds)
+	if err != nil {
+		return err
+	}
+	pollMap := make(map[int64]*ms.PostPollFormated, len(polls))
+	pollIds := make([]int64, 0, len(polls))
+	for _, poll := range polls {
+		if tweet, exist := tweetMap[poll.PostID]; exist {
+			tweet.Poll = poll
+		}
+		pollMap[poll.ID] = poll
+		pollIds = append(pollIds, poll.ID)
+	}
+	// guest用户的userId<0
+	if userId < 0 || len(pollIds) == 0 {
+		return nil
+	}
+	votes, err := s.Ds.UserPollVotes(userId, pollIds)
+	if err != nil {
+		return err
+	}
+	for _, vote := range votes {
+		if poll, exist := pollMap[vote.PollID]; exist {
+			poll.Voted = vote.OptionIdList()
+		}
+	}
+	return nil
+}
+
+// PrepareTweetLinks 填充推文链接内容的预览信息
+func (s *DaoServant) PrepareTweetLinks(tweets []*ms.PostFormated) error {
+	linkMap := make(map[string][]*ms.PostContentFormated)
+	for _, tweet := range tweets {
+		for _, content := range tweet.Contents {
+			if content.Type == ms.ContentTypeLink {
+				linkMap[content.Content] = append(linkMap[content.Content], content)
+			}
+		}
+	}
+	if len(linkMap) == 0 {
+		return nil
+	}
+	urls := make([]string, 0, len(linkMap))
+	for url := range linkMap {
+		urls = append(urls, url)
+	}
+	previews, err := s.Ds.LinkPreviewsByURLs(urls)
+	if err != nil {
+		return err
+	}
+	for _, preview := range previews {
+		for _, content := range linkMap[preview.URL] {
+			content.Preview = preview.Format()
+		}
+	}
+	return nil
+}
+
+// PrepareTweetMedia 填充推文图片/视频的尺寸、占位信息、转码状态及缩略图等版本
+func (s *DaoServant) PrepareTweetMedia(tweets []*ms.PostFormated) error {
+	mediaMap := make(map[string][]*ms.PostContentFormated)
+	for _, tweet := range tweets {
+		for _, content := range tweet.Contents {
+			if content.Type == ms.ContentTypeImage || content.Type == ms.ContentTypeVideo {
+				mediaMap[content.Content] = append(mediaMap[content.Content], content)
+			}
+		}
+	}
+	if len(mediaMap) == 0 {
+		return nil
+	}
+	medias := make([]string, 0, len(mediaMap))
+	for media := range mediaMap {
+		medias = append(medias, media)
+	}
+	attachments, err := s.Ds.GetAttachmentsByContents(medias)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		for _, content := range mediaMap[attachment.Content] {
+			content.Image, content.Video = attachment.ImageFormat(), attachment.VideoFormat()
+		}
+	}
+	return nil
+}
+
 func (s *DaoServant) GetTweetBy(id int64) (*ms.PostFormated, error) {
 	post, err := s.Ds.GetPostByID(id)
 	if err != nil {
@@ -416,6 +583,9 @@ func (s *DaoServant) RelationTypFrom(me *ms.User, username string) (res *cs.Vist
 	} else {
 		res.RelTyp = cs.RelationGuest
 	}
+	if !me.IsAdmin {
+		res.IsSubscriber = s.Ds.IsSubscriber(me.ID, he.ID)
+	}
 	return
 }
 
@@ -449,3 +619,25 @@ func NewDaoServant() *DaoServant {
 		Ts:          dao.TweetSearchService(),
 	}
 }
+
+// lockChargeTweet 充电可见的推文仅保留标题及首段文字的预览
+func lockChargeTweet(tweet *ms.PostFormated) {
+	contents := make([]*ms.PostContentFormated, 0, 2)
+	hasTitle, hasText := false, false
+	for _, content := range tweet.Contents {
+		switch {
+		case content.Type == ms.ContentTypeTitle && !hasTitle:
+			hasTitle = true
+			contents = append(contents, content)
+		case content.Type == ms.ContentTypeText && !hasText:
+			hasText = true
+			if text := []rune(content.Content); len(text) > _chargePreviewSize {
+				preview := *content
+				preview.Content = string(text[:_chargePreviewSize]) + "..."
+				content = &preview
+			}
+			contents = append(contents, content)
+		}
+	}
+	tweet.Contents, tweet.Poll, tweet.ChargeLocked = contents, nil, true
+}
@@ -5,9 +5,10 @@
 package base
 
 type Pager struct {
-	Page      int   `json:"page"`
-	PageSize  int   `json:"page_size"`
-	TotalRows int64 `json:"total_rows"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalRows  int64  `json:"total_rows"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type PageResp struct {
@@ -25,3 +26,14 @@ func PageRespFrom(list any, page int, pageSize int, totalRows int64) *PageResp {
 		},
 	}
 }
+
+// CursorPageRespFrom 游标分页的响应，nextCursor为空时没有下一页，不统计总数
+func CursorPageRespFrom(list any, pageSize int, nextCursor string) *PageResp {
+	return &PageResp{
+		List: list,
+		Pager: Pager{
+			PageSize:   pageSize,
+			NextCursor: nextCursor,
+		},
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	api "github.com/rocboss/paopao-ce/auto/api/s/v1"
 	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/pkg/obs"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,4 +28,9 @@ func RouteLocalOSS(e *gin.Engine) {
 // RouteLocaloss register LocalOSS route if needed
 func RouteLocaloss(e *gin.Engine) {
 	api.RegisterUserServant(e, newUserSrv())
+
+	// 其余请求交由NativeOBS的S3兼容接口处理
+	s := conf.NativeOBSSetting
+	e.NoRoute(gin.WrapH(obs.NewHandler(conf.MustNativeObs(), conf.NativeObsConfig())))
+	logrus.Infof("register NativeOBS s3 compatible route with bucket %s on save path: %s", s.Bucket, s.SavePath)
 }
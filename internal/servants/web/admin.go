@@ -5,12 +5,14 @@
 package web
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	api "github.com/rocboss/paopao-ce/auto/api/v1"
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
@@ -46,6 +48,166 @@ func (s *adminSrv) ChangeUserStatus(req *web.ChangeUserStatusReq) error {
 	return nil
 }
 
+func (s *adminSrv) UserStorageQuota(req *web.UserStorageQuotaReq) (*web.UserStorageQuotaResp, error) {
+	user, err := s.Ds.GetUserByID(req.UserID)
+	if err != nil || user.Model == nil || user.ID <= 0 {
+		return nil, web.ErrNoExistUsername
+	}
+	resp, err := storageUsageOf(s.Ds, user)
+	if err != nil {
+		logrus.Errorf("get user(%d) storage usage failed: %s", req.UserID, err)
+		return nil, xerror.ServerError
+	}
+	return (*web.UserStorageQuotaResp)(resp), nil
+}
+
+func (s *adminSrv) ChangeUserStorageQuota(req *web.ChangeUserStorageQuotaReq) error {
+	if _, err := s.Ds.GetUserByID(req.UserID); err != nil {
+		return web.ErrNoExistUsername
+	}
+	err := s.Ds.SaveUserStorageQuota(&ms.UserStorageQuota{
+		UserID:   req.UserID,
+		MaxSize:  req.MaxSize,
+		MaxFiles: req.MaxFiles,
+	})
+	if err != nil {
+		logrus.Errorf("save user(%d) storage quota failed: %s", req.UserID, err)
+		return xerror.ServerError
+	}
+	return nil
+}
+
+func (s *adminSrv) ResetUserStorageQuota(req *web.ResetUserStorageQuotaReq) error {
+	if err := s.Ds.DeleteUserStorageQuota(req.UserID); err != nil {
+		logrus.Errorf("reset user(%d) storage quota failed: %s", req.UserID, err)
+		return xerror.ServerError
+	}
+	return nil
+}
+
+func (s *adminSrv) ListWithdrawals(req *web.ListWithdrawalsReq) (*web.ListWithdrawalsResp, error) {
+	withdrawals, total, err := s.Ds.ListWithdrawals(req.UserID, req.Status, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListWithdrawals err: %s", err)
+		return nil, web.ErrGetWithdrawalsFailed
+	}
+	resp := base.PageRespFrom(withdrawals, req.Page, req.PageSize, total)
+	return (*web.ListWithdrawalsResp)(resp), nil
+}
+
+func (s *adminSrv) ReviewWithdrawal(req *web.ReviewWithdrawalReq) error {
+	withdrawal, err := s.Ds.GetWithdrawal(req.ID)
+	if err != nil {
+		return web.ErrNoExistWithdrawal
+	}
+	if withdrawal.Status != ms.WithdrawalStatusPending {
+		return web.ErrWithdrawalStatus
+	}
+	withdrawal.Status, withdrawal.Remark = ms.WithdrawalStatusRejected, req.Remark
+	brief := "你的提现申请未通过审核，提现金额已退回钱包"
+	if req.Approve {
+		withdrawal.Status = ms.WithdrawalStatusApproved
+		brief = "你的提现申请已通过审核，等待打款"
+	}
+	withdrawal.ReviewerID, withdrawal.ReviewedOn = req.User.ID, time.Now().Unix()
+	return s.updateWithdrawal(withdrawal, ms.WithdrawalStatusPending, brief)
+}
+
+func (s *adminSrv) PayoutWithdrawal(req *web.PayoutWithdrawalReq) error {
+	withdrawal, err := s.Ds.GetWithdrawal(req.ID)
+	if err != nil {
+		return web.ErrNoExistWithdrawal
+	}
+	if withdrawal.Status != ms.WithdrawalStatusApproved {
+		return web.ErrWithdrawalStatus
+	}
+	withdrawal.Status, withdrawal.Remark = ms.WithdrawalStatusFailed, req.Remark
+	brief := "你的提现打款失败，提现金额已退回钱包"
+	if req.Success {
+		withdrawal.Status, withdrawal.TradeNo, withdrawal.PaidOn = ms.WithdrawalStatusPaid, req.TradeNo, time.Now().Unix()
+		brief = "你的提现已打款，请注意查收"
+	}
+	withdrawal.ReviewerID = req.User.ID
+	return s.updateWithdrawal(withdrawal, ms.WithdrawalStatusApproved, brief)
+}
+
+func (s *adminSrv) WalletReconcile(req *web.WalletReconcileReq) (*web.WalletReconcileResp, error) {
+	report, err := s.Ds.ReconcileWallets()
+	if err != nil {
+		logrus.Errorf("Ds.ReconcileWallets err: %s", err)
+		return nil, web.ErrWalletReconcileFailed
+	}
+	return (*web.WalletReconcileResp)(report), nil
+}
+
+func (s *adminSrv) ListAttachmentRefunds(req *web.ListAttachmentRefundsReq) (*web.ListAttachmentRefundsResp, error) {
+	refunds, total, err := s.Ds.ListAttachmentRefunds(req.UserID, req.AuthorID, req.Status, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListAttachmentRefunds err: %s", err)
+		return nil, web.ErrGetRefundsFailed
+	}
+	resp := base.PageRespFrom(refunds, req.Page, req.PageSize, total)
+	return (*web.ListAttachmentRefundsResp)(resp), nil
+}
+
+// ArbitrateAttachmentRefund 管理员可直接处理待作者处理或申诉中的退款申请
+func (s *adminSrv) ArbitrateAttachmentRefund(req *web.ReviewAttachmentRefundReq) error {
+	refund, err := s.Ds.GetAttachmentRefund(req.ID)
+	if err != nil {
+		return web.ErrNoExistRefund
+	}
+	if refund.Status != ms.RefundStatusPending && refund.Status != ms.RefundStatusDisputed {
+		return web.ErrRefundStatus
+	}
+	brief := "你的附件退款申请经平台处理未通过"
+	if req.Approve {
+		brief = fmt.Sprintf("你的附件退款申请经平台处理已通过，%.2f元已退回钱包", float64(refund.Amount)/100)
+	}
+	return reviewAttachmentRefund(s.Ds, refund, req, brief)
+}
+
+// MergeTopic 将话题合并为另一话题的别名，例如大小写不同的同名话题
+func (s *adminSrv) MergeTopic(req *web.MergeTopicReq) error {
+	if _, err := s.Ds.GetTopic(req.TargetId); err != nil {
+		return web.ErrNoExistTopic
+	}
+	if err := s.Ds.MergeTopic(req.SourceId, req.TargetId); err != nil {
+		logrus.Errorf("merge topic(%d) into topic(%d) failed: %s", req.SourceId, req.TargetId, err)
+		return web.ErrMergeTopicFailed
+	}
+	return nil
+}
+
+// ChangeTopicStatus 锁定话题或在话题推荐中隐藏话题
+func (s *adminSrv) ChangeTopicStatus(req *web.ChangeTopicStatusReq) error {
+	topic, err := s.Ds.GetTopic(req.TopicId)
+	if err != nil {
+		return web.ErrNoExistTopic
+	}
+	if err = s.Ds.ChangeTopicStatus(topic.ID, req.IsLocked, req.IsHidden); err != nil {
+		logrus.Errorf("change topic(%d) status failed: %s", topic.ID, err)
+		return web.ErrUpdateTopicFailed
+	}
+	return nil
+}
+
+// updateWithdrawal 更新提现状态并通知用户
+func (s *adminSrv) updateWithdrawal(withdrawal *ms.WalletWithdrawal, from ms.WithdrawalStatus, brief string) error {
+	ok, err := s.Ds.UpdateWithdrawalStatus(withdrawal, from)
+	if err != nil {
+		logrus.Errorf("Ds.UpdateWithdrawalStatus id:%d err: %s", withdrawal.ID, err)
+		return web.ErrUpdateWithdrawal
+	} else if !ok {
+		return web.ErrWithdrawalStatus
+	}
+	onCreateMessageEvent(&ms.Message{
+		ReceiverUserID: withdrawal.UserID,
+		Type:           ms.MsgTypeSystem,
+		Brief:          brief,
+	})
+	return nil
+}
+
 func (s *adminSrv) SiteInfo(req *web.SiteInfoReq) (*web.SiteInfoResp, error) {
 	res, err := &web.SiteInfoResp{ServerUpTime: s.serverUpTime}, error(nil)
 	res.RegisterUserCount, err = s.Ds.GetRegisterUserCount()
@@ -5,8 +5,6 @@
 package web
 
 import (
-	"fmt"
-
 	"github.com/gin-gonic/gin"
 	api "github.com/rocboss/paopao-ce/auto/api/v1"
 	"github.com/rocboss/paopao-ce/internal/model/web"
@@ -30,29 +28,15 @@ type alipayPrivSrv struct {
 	api.UnimplementedAlipayPrivServant
 	*base.DaoServant
 
-	alipayClient *alipay.Client
+	paymentNames     []string
+	paymentProviders map[string]paymentProvider
 }
 
 func (s *alipayPubSrv) AlipayNotify(req *web.AlipayNotifyReq) error {
-	if req.TradeStatus == alipay.TradeStatusSuccess {
-		if err := s.Redis.SetRechargeStatus(req.Ctx, req.TradeNo); err == nil {
-			recharge, err := s.Ds.GetRechargeByID(req.ID)
-			if err != nil {
-				logrus.Errorf("GetRechargeByID id:%d err: %s", req.ID, err)
-				return web.ErrRechargeNotifyError
-			}
-			if recharge.TradeStatus != "TRADE_SUCCESS" {
-				// 标记为已付款
-				err := s.Ds.HandleRechargeSuccess(recharge, req.TradeNo)
-				defer s.Redis.DelRechargeStatus(req.Ctx, req.TradeNo)
-				if err != nil {
-					logrus.Errorf("HandleRechargeSuccess id:%d err: %s", req.ID, err)
-					return web.ErrRechargeNotifyError
-				}
-			}
-		}
+	if req.TradeStatus != alipay.TradeStatusSuccess {
+		return nil
 	}
-	return nil
+	return handleRechargeNotify(s.DaoServant, req.Ctx, _paymentAlipay, req.ID, req.TradeNo, req.Amount)
 }
 
 func (s *alipayPrivSrv) Chain() gin.HandlersChain {
@@ -75,27 +59,28 @@ func (s *alipayPrivSrv) UserWalletBills(req *web.UserWalletBillsReq) (*web.UserW
 }
 
 func (s *alipayPrivSrv) UserRechargeLink(req *web.UserRechargeLinkReq) (*web.UserRechargeLinkResp, error) {
-	recharge, err := s.Ds.CreateRecharge(req.User.ID, req.Amount)
+	// 未指定支付渠道时使用默认渠道
+	if req.Provider == "" {
+		req.Provider = s.paymentNames[0]
+	}
+	provider, exist := s.paymentProviders[req.Provider]
+	if !exist {
+		return nil, web.ErrRechargeProvider
+	}
+	recharge, err := s.Ds.CreateRecharge(req.User.ID, req.Amount, req.Provider)
 	if err != nil {
 		logrus.Errorf("Ds.CreateRecharge err: %v", err)
 		return nil, web.ErrRechargeReqFail
 	}
-	p := alipay.TradePreCreate{}
-	p.OutTradeNo = fmt.Sprintf("%d", recharge.ID)
-	p.Subject = "PaoPao用户钱包充值"
-	p.TotalAmount = fmt.Sprintf("%.2f", float64(recharge.Amount)/100.0)
-	p.NotifyURL = "https://" + req.Host + "/v1/alipay/notify"
-	rsp, err := s.alipayClient.TradePreCreate(p)
+	pay, err := provider.precreate(recharge, req)
 	if err != nil {
-		logrus.Errorf("client.TradePreCreate err: %v\n", err)
-		return nil, web.ErrRechargeReqFail
-	}
-	if rsp.Code != alipay.CodeSuccess {
+		logrus.Errorf("%s precreate recharge(%d) err: %v", req.Provider, recharge.ID, err)
 		return nil, web.ErrRechargeReqFail
 	}
 	return &web.UserRechargeLinkResp{
-		Id:  recharge.ID,
-		Pay: rsp.QRCode,
+		Id:       recharge.ID,
+		Provider: req.Provider,
+		Pay:      pay,
 	}, nil
 }
 
@@ -121,9 +106,10 @@ func newAlipayPubSrv(s *base.DaoServant) api.AlipayPub {
 	}
 }
 
-func newAlipayPrivSrv(s *base.DaoServant, client *alipay.Client) api.AlipayPriv {
+func newAlipayPrivSrv(s *base.DaoServant, names []string, providers map[string]paymentProvider) api.AlipayPriv {
 	return &alipayPrivSrv{
-		DaoServant:   s,
-		alipayClient: client,
+		DaoServant:       s,
+		paymentNames:     names,
+		paymentProviders: providers,
 	}
 }
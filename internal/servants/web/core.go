@@ -7,19 +7,24 @@ package web
 import (
 	"context"
 	"fmt"
-
+	"slices"
+	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
 	api "github.com/rocboss/paopao-ce/auto/api/v1"
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/model/joint"
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/app"
+	"github.com/rocboss/paopao-ce/pkg/utils"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 	"github.com/sirupsen/logrus"
 )
@@ -87,13 +92,25 @@ func (s *coreSrv) GetUserInfo(req *web.UserInfoReq) (*web.UserInfoResp, error) {
 
 func (s *coreSrv) GetMessages(req *web.GetMessagesReq) (res *web.GetMessagesResp, _ error) {
 	limit, offset := req.PageSize, (req.Page-1)*req.PageSize
-	// 尝试直接从缓存中获取数据
+	// 尝试直接从缓存中获取数据，游标分页不走缓存
 	key, ok := "", false
-	if res, key, ok = s.messagesFromCache(req, limit, offset); ok {
-		// logrus.Debugf("coreSrv.GetMessages from cache key:%s", key)
-		return
+	if req.Cursor == nil {
+		if res, key, ok = s.messagesFromCache(req, limit, offset); ok {
+			// logrus.Debugf("coreSrv.GetMessages from cache key:%s", key)
+			return
+		}
+	}
+	var (
+		messages  []*ms.MessageFormated
+		totalRows int64
+		next      *cs.Cursor
+		err       error
+	)
+	if req.Cursor != nil {
+		messages, next, err = s.Ds.GetMessagesByCursor(req.Uid, req.Style, req.Cursor, limit)
+	} else {
+		messages, totalRows, err = s.Ds.GetMessages(req.Uid, req.Style, limit, offset)
 	}
-	messages, totalRows, err := s.Ds.GetMessages(req.Uid, req.Style, limit, offset)
 	if err != nil {
 		logrus.Errorf("Ds.GetMessages err[1]: %s", err)
 		return nil, web.ErrGetMessagesFailed
@@ -141,6 +158,13 @@ func (s *coreSrv) GetMessages(req *web.GetMessagesReq) (res *web.GetMessagesResp
 		logrus.Errorf("get messages err[3]: %s", err)
 		return nil, web.ErrGetMessagesFailed
 	}
+	if req.Cursor != nil {
+		return &web.GetMessagesResp{
+			CachePageResp: joint.CachePageResp{
+				Data: joint.CursorPageRespFrom(messages, req.PageSize, app.EncodeCursor(next)),
+			},
+		}, nil
+	}
 	resp := joint.PageRespFrom(messages, req.Page, req.PageSize, totalRows)
 	// 缓存处理
 	base.OnCacheRespEvent(s.wc, key, resp, s.messagesExpire)
@@ -209,19 +233,26 @@ func (s *coreSrv) SendUserWhisper(req *web.SendWhisperReq) error {
 }
 
 func (s *coreSrv) GetCollections(req *web.GetCollectionsReq) (*web.GetCollectionsResp, error) {
-	collections, err := s.Ds.GetUserPostCollections(req.UserId, (req.Page-1)*req.PageSize, req.PageSize)
+	if req.FolderId > 0 {
+		if _, xerr := s.userCollectionFolder(req.UserId, req.FolderId); xerr != nil {
+			return nil, xerr
+		}
+	}
+	collections, err := s.Ds.GetUserPostCollections(req.UserId, req.FolderId, (req.Page-1)*req.PageSize, req.PageSize)
 	if err != nil {
 		logrus.Errorf("Ds.GetUserPostCollections err: %s", err)
 		return nil, web.ErrGetCollectionsFailed
 	}
-	totalRows, err := s.Ds.GetUserPostCollectionCount(req.UserId)
+	totalRows, err := s.Ds.GetUserPostCollectionCount(req.UserId, req.FolderId)
 	if err != nil {
 		logrus.Errorf("Ds.GetUserPostCollectionCount err: %s", err)
 		return nil, web.ErrGetCollectionsFailed
 	}
 	var posts []*ms.Post
+	collectionMap := make(map[int64]*ms.PostCollection, len(collections))
 	for _, collection := range collections {
 		posts = append(posts, collection.Post)
+		collectionMap[collection.PostID] = collection
 	}
 	postsFormated, err := s.Ds.MergePosts(posts)
 	if err != nil {
@@ -232,10 +263,148 @@ func (s *coreSrv) GetCollections(req *web.GetCollectionsReq) (*web.GetCollection
 		logrus.Errorf("get collections prepare tweets err: %s", err)
 		return nil, web.ErrGetCollectionsFailed
 	}
-	resp := base.PageRespFrom(postsFormated, req.Page, req.PageSize, totalRows)
+	// 附带收藏所在的收藏夹及私人备注
+	items := make([]*web.CollectionItem, 0, len(postsFormated))
+	for _, post := range postsFormated {
+		item := &web.CollectionItem{PostFormated: post}
+		if collection, ok := collectionMap[post.ID]; ok {
+			item.FolderId, item.Note = collection.FolderID, collection.Note
+		}
+		items = append(items, item)
+	}
+	resp := base.PageRespFrom(items, req.Page, req.PageSize, totalRows)
 	return (*web.GetCollectionsResp)(resp), nil
 }
 
+func (s *coreSrv) ListCollectionFolders(req *web.ListCollectionFoldersReq) (*web.ListCollectionFoldersResp, error) {
+	folders, err := s.Ds.ListCollectionFolders(req.Uid)
+	if err != nil {
+		logrus.Errorf("Ds.ListCollectionFolders err: %s", err)
+		return nil, web.ErrGetCollectionsFailed
+	}
+	return &web.ListCollectionFoldersResp{
+		List: folders,
+	}, nil
+}
+
+func (s *coreSrv) CreateCollectionFolder(req *web.CreateCollectionFolderReq) (*web.CreateCollectionFolderResp, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, xerror.InvalidParams
+	}
+	folders, err := s.Ds.ListCollectionFolders(req.Uid)
+	if err != nil {
+		logrus.Errorf("Ds.ListCollectionFolders err: %s", err)
+		return nil, web.ErrCreateCollectionFolderFailed
+	}
+	if len(folders) >= conf.AppSetting.MaxCollectionFolders {
+		return nil, web.ErrTooManyCollectionFolders
+	}
+	if slices.ContainsFunc(folders, func(f *ms.CollectionFolderFormated) bool { return f.Name == name }) {
+		return nil, web.ErrCollectionFolderExisted
+	}
+	folder, err := s.Ds.CreateCollectionFolder(&ms.CollectionFolder{
+		UserID: req.Uid,
+		Name:   name,
+	})
+	if err != nil {
+		logrus.Errorf("Ds.CreateCollectionFolder err: %s", err)
+		return nil, web.ErrCreateCollectionFolderFailed
+	}
+	return (*web.CreateCollectionFolderResp)(folder.Format()), nil
+}
+
+func (s *coreSrv) UpdateCollectionFolder(req *web.UpdateCollectionFolderReq) error {
+	folder, xerr := s.userCollectionFolder(req.Uid, req.ID)
+	if xerr != nil {
+		return xerr
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return xerror.InvalidParams
+	}
+	folders, err := s.Ds.ListCollectionFolders(req.Uid)
+	if err != nil {
+		logrus.Errorf("Ds.ListCollectionFolders err: %s", err)
+		return web.ErrUpdateCollectionFolderFailed
+	}
+	if slices.ContainsFunc(folders, func(f *ms.CollectionFolderFormated) bool { return f.Name == name && f.ID != folder.ID }) {
+		return web.ErrCollectionFolderExisted
+	}
+	folder.Name = name
+	if err = s.Ds.UpdateCollectionFolder(folder); err != nil {
+		logrus.Errorf("Ds.UpdateCollectionFolder err: %s", err)
+		return web.ErrUpdateCollectionFolderFailed
+	}
+	return nil
+}
+
+// DeleteCollectionFolder 删除收藏夹，其中的收藏不会被取消
+func (s *coreSrv) DeleteCollectionFolder(req *web.DeleteCollectionFolderReq) error {
+	folder, xerr := s.userCollectionFolder(req.Uid, req.ID)
+	if xerr != nil {
+		return xerr
+	}
+	if err := s.Ds.DeleteCollectionFolder(folder); err != nil {
+		logrus.Errorf("Ds.DeleteCollectionFolder err: %s", err)
+		return web.ErrDeleteCollectionFolderFailed
+	}
+	return nil
+}
+
+// ShareCollectionFolder 开启或关闭收藏夹的公开分享，每次开启都会生成新的分享链接
+func (s *coreSrv) ShareCollectionFolder(req *web.ShareCollectionFolderReq) (*web.ShareCollectionFolderResp, error) {
+	folder, xerr := s.userCollectionFolder(req.Uid, req.ID)
+	if xerr != nil {
+		return nil, xerr
+	}
+	folder.ShareKey = ""
+	if req.Share {
+		folder.ShareKey = utils.EncodeMD5(uuid.Must(uuid.NewV4()).String())
+	}
+	if err := s.Ds.UpdateCollectionFolder(folder); err != nil {
+		logrus.Errorf("Ds.UpdateCollectionFolder err: %s", err)
+		return nil, web.ErrUpdateCollectionFolderFailed
+	}
+	return &web.ShareCollectionFolderResp{
+		ShareKey: folder.ShareKey,
+	}, nil
+}
+
+// MoveCollections 将收藏移动到指定收藏夹，收藏夹为0时移出收藏夹
+func (s *coreSrv) MoveCollections(req *web.MoveCollectionsReq) error {
+	if req.FolderId != ms.CollectionFolderDefault {
+		if _, xerr := s.userCollectionFolder(req.Uid, req.FolderId); xerr != nil {
+			return xerr
+		}
+	}
+	if err := s.Ds.MovePostCollections(req.Uid, req.FolderId, req.TweetIds); err != nil {
+		logrus.Errorf("Ds.MovePostCollections err: %s", err)
+		return web.ErrMoveCollectionsFailed
+	}
+	return nil
+}
+
+func (s *coreSrv) UpdateCollectionNote(req *web.CollectionNoteReq) error {
+	err := s.Ds.UpdatePostCollectionNote(req.Uid, req.TweetId, strings.TrimSpace(req.Note))
+	if err == cs.ErrNotExist {
+		return web.ErrNoExistCollection
+	} else if err != nil {
+		logrus.Errorf("Ds.UpdatePostCollectionNote err: %s", err)
+		return web.ErrUpdateCollectionNoteFailed
+	}
+	return nil
+}
+
+// userCollectionFolder 获取用户自己的收藏夹
+func (s *coreSrv) userCollectionFolder(userId int64, folderId int64) (*ms.CollectionFolder, error) {
+	folder, err := s.Ds.GetCollectionFolder(folderId)
+	if err != nil || folder.UserID != userId {
+		return nil, web.ErrNoExistCollectionFolder
+	}
+	return folder, nil
+}
+
 func (s *coreSrv) UserPhoneBind(req *web.UserPhoneBindReq) error {
 	// 手机重复性检查
 	u, err := s.Ds.GetUserByPhone(req.Phone)
@@ -360,7 +529,7 @@ func (s *coreSrv) ChangeNickname(req *web.ChangeNicknameReq) error {
 func (s *coreSrv) ChangeAvatar(req *web.ChangeAvatarReq) (xerr error) {
 	defer func() {
 		if xerr != nil {
-			deleteOssObjects(s.oss, []string{req.Avatar})
+			releaseOssObjects(s.oss, req.User.ID, []string{req.Avatar})
 		}
 	}()
 
@@ -373,16 +542,33 @@ func (s *coreSrv) ChangeAvatar(req *web.ChangeAvatarReq) (xerr error) {
 		return xerror.ServerError
 	}
 	user := req.User
+	oldAvatar := user.Avatar
 	user.Avatar = req.Avatar
 	if err := s.Ds.UpdateUser(user); err != nil {
 		logrus.Errorf("Ds.UpdateUser failed: %s", err)
 		return xerror.ServerError
 	}
+	// 回收用户上传的旧头像占用的存储空间，默认头像没有对应的附件
+	if oldAvatar != "" && oldAvatar != req.Avatar {
+		attachments, err := s.Ds.GetAttachmentsByContents([]string{oldAvatar})
+		if err == nil && slices.ContainsFunc(attachments, func(a *ms.Attachment) bool { return a.UserID == user.ID }) {
+			releaseOssObjects(s.oss, user.ID, []string{oldAvatar})
+		}
+	}
 	// 缓存处理
 	onChangeUsernameEvent(user.ID, user.Username)
 	return nil
 }
 
+func (s *coreSrv) GetStorageUsage(req *web.GetStorageUsageReq) (*web.StorageUsageResp, error) {
+	resp, err := storageUsageOf(s.Ds, req.User)
+	if err != nil {
+		logrus.Errorf("get user(%d) storage usage failed: %s", req.User.ID, err)
+		return nil, xerror.ServerError
+	}
+	return resp, nil
+}
+
 func (s *coreSrv) TweetCollectionStatus(req *web.TweetCollectionStatusReq) (*web.TweetCollectionStatusResp, error) {
 	resp := &web.TweetCollectionStatusResp{
 		Status: true,
@@ -5,8 +5,10 @@
 package web
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/alimy/tryst/event"
 	"github.com/rocboss/paopao-ce/internal/conf"
@@ -17,9 +19,25 @@ import (
 	"github.com/rocboss/paopao-ce/internal/infra/events"
 	"github.com/rocboss/paopao-ce/internal/model/joint"
 	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/rocboss/paopao-ce/pkg/unfurl"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	// 允许转存的链接预览图类型，不转存svg等可能包含脚本的图片
+	_previewImageExts = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpeg",
+		"image/gif":  ".gif",
+		"image/webp": ".webp",
+	}
+)
+
+const (
+	// 链接预览缓存表中链接地址的最大长度
+	_maxPreviewLinkLength = 2048
+)
+
 const (
 	_tweetActionCreate uint8 = iota
 	_tweetActionDelete
@@ -99,6 +117,20 @@ type trendsActionEvent struct {
 	userIds []int64
 }
 
+type unfurlLinksEvent struct {
+	event.UnimplementedEvent
+	ds    core.DataService
+	oss   core.ObjectStorageService
+	uf    unfurl.Unfurler
+	links []string
+}
+
+type refundAttachmentsEvent struct {
+	event.UnimplementedEvent
+	ds   core.DataService
+	post *ms.Post
+}
+
 type changeUserEvent struct {
 	*cache.BaseCacheEvent
 	userId   int64
@@ -113,6 +145,30 @@ func onChangeUsernameEvent(id int64, name string) {
 	})
 }
 
+func onRefundAttachmentsEvent(post *ms.Post) {
+	// 推文附件免费或未开启退款
+	if post.AttachmentPrice <= 0 || conf.AppSetting.AttachmentRefundDays <= 0 {
+		return
+	}
+	events.OnEvent(&refundAttachmentsEvent{
+		ds:   _ds,
+		post: post,
+	})
+}
+
+func onUnfurlLinksEvent(links []string) {
+	// 未开启链接预览功能
+	if _uf == nil || len(links) == 0 {
+		return
+	}
+	events.OnEvent(&unfurlLinksEvent{
+		ds:    _ds,
+		oss:   _oss,
+		uf:    _uf,
+		links: links,
+	})
+}
+
 func onTrendsActionEvent(action uint8, userIds ...int64) {
 	events.OnEvent(&trendsActionEvent{
 		ac:      _ac,
@@ -345,3 +401,118 @@ func (e *changeUserEvent) Name() string {
 func (e *changeUserEvent) Action() error {
 	return e.ExpireUserData(e.userId, e.username)
 }
+
+func (e *refundAttachmentsEvent) Name() string {
+	return "refundAttachmentsEvent"
+}
+
+// Action 推文被删除时自动退还退款期限内的附件费用，作者余额不足等原因退款失败时转为申诉由管理员处理
+func (e *refundAttachmentsEvent) Action() error {
+	since := time.Now().Unix() - conf.AppSetting.AttachmentRefundDays*86400
+	bills, err := e.ds.ListRefundableAttachmentBills(e.post.ID, since)
+	if err != nil {
+		return fmt.Errorf("refundAttachmentsEvent list bills occurs error: %w", err)
+	}
+	for _, bill := range bills {
+		refund, err := e.ds.GetBillAttachmentRefund(bill.ID)
+		if err != nil {
+			logrus.Errorf("refundAttachmentsEvent get refund of bill %d occurs error: %s", bill.ID, err)
+			continue
+		}
+		if refund == nil {
+			refund, err = e.ds.CreateAttachmentRefund(&ms.AttachmentRefund{
+				BillID:   bill.ID,
+				PostID:   bill.PostID,
+				UserID:   bill.UserID,
+				AuthorID: e.post.UserID,
+				Amount:   bill.PaidAmount,
+				Reason:   "动态已删除，自动退款",
+				Status:   ms.RefundStatusPending,
+			})
+			if err != nil {
+				logrus.Errorf("refundAttachmentsEvent create refund of bill %d occurs error: %s", bill.ID, err)
+				continue
+			}
+		}
+		from := refund.Status
+		refund.ReviewedOn = time.Now().Unix()
+		if ok, err := e.ds.RefundAttachment(refund, from); err != nil {
+			logrus.Errorf("refundAttachmentsEvent refund %d occurs error: %s", refund.ID, err)
+			refund.Status, refund.Remark = ms.RefundStatusDisputed, "动态已删除，自动退款失败"
+			if _, err = e.ds.UpdateAttachmentRefundStatus(refund, from); err != nil {
+				logrus.Errorf("refundAttachmentsEvent dispute refund %d occurs error: %s", refund.ID, err)
+			}
+			continue
+		} else if !ok {
+			continue
+		}
+		onCreateMessageEvent(&ms.Message{
+			ReceiverUserID: refund.UserID,
+			Type:           ms.MsgTypeSystem,
+			Brief:          fmt.Sprintf("你购买附件的动态已被删除，%.2f元已退回钱包", float64(refund.Amount)/100),
+		})
+	}
+	return nil
+}
+
+func (e *unfurlLinksEvent) Name() string {
+	return "unfurlLinksEvent"
+}
+
+func (e *unfurlLinksEvent) Action() error {
+	now := time.Now().Unix()
+	for _, link := range e.links {
+		// 缓存未过期的链接不再重复抓取
+		if preview, err := e.ds.GetLinkPreview(link); err == nil && now-preview.FetchedOn < conf.LinkPreviewSetting.CacheExpire {
+			continue
+		}
+		e.uf.Unfurl(link, e.onPreview(link))
+	}
+	return nil
+}
+
+func (e *unfurlLinksEvent) onPreview(link string) unfurl.PreviewFn {
+	return func(p *unfurl.Preview, err error) {
+		preview := &ms.LinkPreview{
+			FetchedOn: time.Now().Unix(),
+		}
+		preview.SetURL(link)
+		if err != nil {
+			logrus.Debugf("unfurlLinksEvent unfurl %s occurs error: %s", link, err)
+			preview.Status = ms.LinkPreviewStatusFailed
+			e.savePreview(preview)
+			return
+		}
+		preview.Title, preview.Description, preview.SiteName = p.Title, p.Description, p.SiteName
+		if p.Image == "" {
+			e.savePreview(preview)
+			return
+		}
+		// 预览图转存到OSS，避免直接引用第三方资源
+		e.uf.FetchImage(p.Image, func(img *unfurl.Image, err error) {
+			if err == nil {
+				preview.Image, err = e.rehostImage(preview, img)
+			}
+			if err != nil {
+				logrus.Debugf("unfurlLinksEvent fetch image %s occurs error: %s", p.Image, err)
+			}
+			e.savePreview(preview)
+		})
+	}
+}
+
+func (e *unfurlLinksEvent) rehostImage(preview *ms.LinkPreview, img *unfurl.Image) (string, error) {
+	ext, ok := _previewImageExts[img.ContentType]
+	if !ok {
+		return "", unfurl.ErrUnsupportedContent
+	}
+	hash := preview.URLHash
+	objectKey := "public/link/" + generatePath(hash[:8]) + "/" + hash[8:] + ext
+	return e.oss.PutObject(objectKey, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType, true)
+}
+
+func (e *unfurlLinksEvent) savePreview(preview *ms.LinkPreview) {
+	if err := e.ds.SaveLinkPreview(preview); err != nil {
+		logrus.Warnf("unfurlLinksEvent save preview %s occurs error: %s", preview.URL, err)
+	}
+}
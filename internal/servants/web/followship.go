@@ -7,10 +7,13 @@ package web
 import (
 	"github.com/gin-gonic/gin"
 	api "github.com/rocboss/paopao-ce/auto/api/v1"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/dao/cache"
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/app"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 	"github.com/sirupsen/logrus"
 )
@@ -34,7 +37,15 @@ func (s *followshipSrv) ListFollowings(r *web.ListFollowingsReq) (*web.ListFollo
 		logrus.Errorf("Ds.GetUserByUsername err: %s", err)
 		return nil, web.ErrNoExistUsername
 	}
-	res, err := s.Ds.ListFollowings(he.ID, r.PageSize, (r.Page-1)*r.PageSize)
+	var (
+		res  *ms.ContactList
+		next *cs.Cursor
+	)
+	if r.Cursor != nil {
+		res, next, err = s.Ds.ListFollowingsByCursor(he.ID, r.Cursor, r.PageSize)
+	} else {
+		res, err = s.Ds.ListFollowings(he.ID, r.PageSize, (r.Page-1)*r.PageSize)
+	}
 	if err != nil {
 		logrus.Errorf("Ds.ListFollowings err: %s", err)
 		return nil, web.ErrListFollowingsFailed
@@ -44,6 +55,9 @@ func (s *followshipSrv) ListFollowings(r *web.ListFollowingsReq) (*web.ListFollo
 			res.Contacts[i].IsFollowing = s.Ds.IsFollow(r.User.ID, contact.UserId)
 		}
 	}
+	if r.Cursor != nil {
+		return (*web.ListFollowingsResp)(base.CursorPageRespFrom(res.Contacts, r.PageSize, app.EncodeCursor(next))), nil
+	}
 	resp := base.PageRespFrom(res.Contacts, r.Page, r.PageSize, res.Total)
 	return (*web.ListFollowingsResp)(resp), nil
 }
@@ -54,7 +68,15 @@ func (s *followshipSrv) ListFollows(r *web.ListFollowsReq) (*web.ListFollowsResp
 		logrus.Errorf("Ds.GetUserByUsername err: %s", err)
 		return nil, web.ErrNoExistUsername
 	}
-	res, err := s.Ds.ListFollows(he.ID, r.PageSize, (r.Page-1)*r.PageSize)
+	var (
+		res  *ms.ContactList
+		next *cs.Cursor
+	)
+	if r.Cursor != nil {
+		res, next, err = s.Ds.ListFollowsByCursor(he.ID, r.Cursor, r.PageSize)
+	} else {
+		res, err = s.Ds.ListFollows(he.ID, r.PageSize, (r.Page-1)*r.PageSize)
+	}
 	if err != nil {
 		logrus.Errorf("Ds.ListFollows err: %s", err)
 		return nil, web.ErrListFollowsFailed
@@ -70,6 +92,9 @@ func (s *followshipSrv) ListFollows(r *web.ListFollowsReq) (*web.ListFollowsResp
 			}
 		}
 	}
+	if r.Cursor != nil {
+		return (*web.ListFollowsResp)(base.CursorPageRespFrom(res.Contacts, r.PageSize, app.EncodeCursor(next))), nil
+	}
 	resp := base.PageRespFrom(res.Contacts, r.Page, r.PageSize, res.Total)
 	return (*web.ListFollowsResp)(resp), nil
 }
@@ -0,0 +1,143 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	api "github.com/rocboss/paopao-ce/auto/api/v1"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
+)
+
+// followingsDs 按(created_on, id)倒序的键集语义模拟关注列表
+type followingsDs struct {
+	core.DataService
+	contacts []ms.ContactItem
+}
+
+func (s *followingsDs) GetUserByUsername(username string) (*ms.User, error) {
+	return &ms.User{Model: &ms.Model{ID: 1}, Username: username}, nil
+}
+
+func (s *followingsDs) ListFollowingsByCursor(_ int64, c *cs.Cursor, limit int) (*ms.ContactList, *cs.Cursor, error) {
+	items := make([]ms.ContactItem, 0, limit+1)
+	for _, item := range s.contacts {
+		if len(items) > limit {
+			break
+		}
+		if !c.IsStart() && (item.CreatedOn > c.CreatedOn || item.CreatedOn == c.CreatedOn && item.UserId >= c.ID) {
+			continue
+		}
+		items = append(items, item)
+	}
+	items, next := cursor.Next(items, limit, func(item ms.ContactItem) *cs.Cursor {
+		return &cs.Cursor{CreatedOn: item.CreatedOn, ID: item.UserId}
+	})
+	return &ms.ContactList{Contacts: items}, next, nil
+}
+
+// followshipWithoutChain 跳过需要数据库的JwtLoose中间件
+type followshipWithoutChain struct {
+	api.Followship
+}
+
+func (followshipWithoutChain) Chain() gin.HandlersChain {
+	return nil
+}
+
+type followingsResp struct {
+	Code int `json:"code"`
+	Data struct {
+		List  []ms.ContactItem `json:"list"`
+		Pager base.Pager       `json:"pager"`
+	} `json:"data"`
+}
+
+var _ = g.Describe("Followship", g.Ordered, func() {
+	var e *gin.Engine
+
+	g.BeforeAll(func() {
+		dir, err := os.Getwd()
+		m.Expect(err).To(m.Succeed())
+		tmp := g.GinkgoT().TempDir()
+		m.Expect(os.WriteFile(filepath.Join(tmp, "config.yaml"), nil, 0644)).To(m.Succeed())
+		m.Expect(os.Chdir(tmp)).To(m.Succeed())
+		conf.Initial(nil, false)
+		m.Expect(os.Chdir(dir)).To(m.Succeed())
+
+		ds := &followingsDs{}
+		// 同一时间关注的用户按id倒序
+		for _, it := range []struct{ createdOn, userId int64 }{
+			{500, 5}, {400, 4}, {400, 3}, {300, 2}, {100, 1},
+		} {
+			ds.contacts = append(ds.contacts, ms.ContactItem{UserId: it.userId, CreatedOn: it.createdOn})
+		}
+		gin.SetMode(gin.TestMode)
+		e = gin.New()
+		api.RegisterFollowshipServant(e, followshipWithoutChain{
+			Followship: newFollowshipSrv(&base.DaoServant{
+				BaseServant: base.NewBaseServant(),
+				Ds:          ds,
+			}),
+		})
+	})
+
+	listFollowings := func(query url.Values) *followingsResp {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/user/followings?"+query.Encode(), nil)
+		e.ServeHTTP(w, req)
+		m.Expect(w.Code).To(m.Equal(http.StatusOK))
+		resp := &followingsResp{}
+		m.Expect(json.Unmarshal(w.Body.Bytes(), resp)).To(m.Succeed())
+		m.Expect(resp.Code).To(m.BeZero())
+		return resp
+	}
+
+	g.It("walk pages with paging=cursor", func() {
+		var (
+			userIds []int64
+			pages   int
+		)
+		query := url.Values{
+			"username":  {"alice"},
+			"paging":    {"cursor"},
+			"page_size": {"2"},
+		}
+		for {
+			resp := listFollowings(query)
+			pages++
+			for _, item := range resp.Data.List {
+				userIds = append(userIds, item.UserId)
+			}
+			if resp.Data.Pager.NextCursor == "" {
+				break
+			}
+			m.Expect(resp.Data.List).To(m.HaveLen(2))
+			query.Set("cursor", resp.Data.Pager.NextCursor)
+		}
+		m.Expect(pages).To(m.Equal(3))
+		m.Expect(userIds).To(m.Equal([]int64{5, 4, 3, 2, 1}))
+	})
+
+	g.It("reject a forged cursor", func() {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/user/followings?username=alice&cursor=forged", nil)
+		e.ServeHTTP(w, req)
+		m.Expect(w.Code).To(m.Equal(http.StatusBadRequest))
+	})
+})
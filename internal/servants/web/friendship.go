@@ -10,6 +10,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/app"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 	"github.com/sirupsen/logrus"
 )
@@ -31,6 +32,15 @@ func (s *friendshipSrv) GetContacts(req *web.GetContactsReq) (*web.GetContactsRe
 	if req.User == nil {
 		return nil, xerror.ServerError
 	}
+	if req.Cursor != nil {
+		res, next, err := s.Ds.GetContactsByCursor(req.User.ID, req.Cursor, req.PageSize)
+		if err != nil {
+			logrus.Errorf("service.GetContactsByCursor err: %s", err)
+			return nil, web.ErrGetContactsFailed
+		}
+		resp := base.CursorPageRespFrom(res.Contacts, req.PageSize, app.EncodeCursor(next))
+		return (*web.GetContactsResp)(resp), nil
+	}
 	res, err := s.Ds.GetContacts(req.User.ID, (req.Page-1)*req.PageSize, req.PageSize)
 	if err != nil {
 		logrus.Errorf("service.GetContacts err: %s", err)
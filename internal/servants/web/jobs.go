@@ -5,10 +5,17 @@
 package web
 
 import (
+	"sync"
+	"time"
+
 	"github.com/alimy/tryst/cfg"
 	"github.com/robfig/cron/v3"
 	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/infra/events"
+	"github.com/rocboss/paopao-ce/internal/infra/storage"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/sirupsen/logrus"
 )
 
@@ -30,10 +37,273 @@ func onMaxOnlineJob() {
 	})
 }
 
-func scheduleJobs() {
+func onPublishScheduledTweetJob(ds *base.DaoServant) {
+	spec := conf.JobManagerSetting.PublishScheduledTweetInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	srv := &privSrv{
+		DaoServant: ds,
+		oss:        _oss,
+	}
+	var running sync.Mutex
+	events.OnTask(schedule, func() {
+		// 上一次任务还未完成时跳过本次任务
+		if !running.TryLock() {
+			return
+		}
+		defer running.Unlock()
+		schedules, err := ds.Ds.ListDueScheduledTweets(time.Now().Unix(), 100)
+		if err != nil {
+			logrus.Warnf("onPublishScheduledTweetJob[1] occurs error: %s", err)
+			return
+		}
+		for _, schedule := range schedules {
+			// 先领取再发布，已被其他实例领取或已被用户取消的跳过
+			if ok, err := ds.Ds.ClaimScheduledTweet(schedule); err != nil {
+				logrus.Warnf("onPublishScheduledTweetJob[2] claim schedule tweet %d occurs error: %s", schedule.ID, err)
+				continue
+			} else if !ok {
+				continue
+			}
+			if err = srv.publishScheduledTweet(schedule); err != nil {
+				logrus.Warnf("onPublishScheduledTweetJob[3] publish schedule tweet %d occurs error: %s", schedule.ID, err)
+				schedule.Status = ms.PostScheduleStatusFailed
+				if _, err = ds.Ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPublishing); err != nil {
+					logrus.Warnf("onPublishScheduledTweetJob[4] occurs error: %s", err)
+				}
+			}
+		}
+	})
+}
+
+func onClosePollJob(ds *base.DaoServant) {
+	spec := conf.JobManagerSetting.ClosePollInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	events.OnTask(schedule, func() {
+		polls, err := ds.Ds.ListExpiredPolls(time.Now().Unix(), 100)
+		if err != nil {
+			logrus.Warnf("onClosePollJob[1] occurs error: %s", err)
+			return
+		}
+		for _, poll := range polls {
+			if err = ds.Ds.ClosePoll(poll); err != nil {
+				logrus.Warnf("onClosePollJob[2] close poll %d occurs error: %s", poll.ID, err)
+				continue
+			}
+			// 推文已删除时不再通知发起人
+			if _, err = ds.Ds.GetPostByID(poll.PostID); err != nil {
+				continue
+			}
+			onCreateMessageEvent(&ms.Message{
+				ReceiverUserID: poll.UserID,
+				Type:           ms.MsgTypeSystem,
+				Brief:          "你发起的投票已结束",
+				PostID:         poll.PostID,
+			})
+		}
+	})
+}
+
+func onProcessMediaJob(ds *base.DaoServant) {
+	// 未开启视频转码功能
+	if _videoProcessor == nil {
+		return
+	}
+	spec := conf.JobManagerSetting.ProcessMediaInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	s := conf.VideoTranscodeSetting
+	var running sync.Mutex
+	events.OnTask(schedule, func() {
+		// 上一次任务还未完成时跳过本次任务
+		if !running.TryLock() {
+			return
+		}
+		defer running.Unlock()
+		// 处理中但超时未完成的视频(比如服务重启)会重新处理
+		staleBefore := time.Now().Add(-s.Timeout - time.Minute).Unix()
+		attachments, err := ds.Ds.ListPendingMedia(staleBefore, s.MaxBatch)
+		if err != nil {
+			logrus.Warnf("onProcessMediaJob[1] occurs error: %s", err)
+			return
+		}
+		for _, attachment := range attachments {
+			if ok, err := ds.Ds.ClaimMedia(attachment); err != nil || !ok {
+				continue
+			}
+			if err = _videoProcessor.process(attachment); err != nil {
+				logrus.Warnf("onProcessMediaJob[2] process video attachment %d occurs error: %s", attachment.ID, err)
+			}
+		}
+	})
+}
+
+func onCleanUploadSessionJob(ds *base.DaoServant) {
+	// 未开启断点续传上传功能
+	if _uploader == nil {
+		return
+	}
+	spec := conf.JobManagerSetting.CleanUploadSessionInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	events.OnTask(schedule, func() {
+		sessions, err := ds.Ds.ListExpiredUploadSessions(100)
+		if err != nil {
+			logrus.Warnf("onCleanUploadSessionJob[1] occurs error: %s", err)
+			return
+		}
+		for _, session := range sessions {
+			if !_uploader.acquire(session.ID) {
+				continue
+			}
+			if err = _uploader.remove(session); err != nil {
+				logrus.Warnf("onCleanUploadSessionJob[2] remove upload session %d occurs error: %s", session.ID, err)
+			}
+			_uploader.release(session.ID)
+		}
+	})
+}
+
+func onCollectOrphanObjectsJob(ds *base.DaoServant) {
+	// 未开启对象存储垃圾回收功能
+	if !cfg.If("ObjectGC") {
+		return
+	}
+	spec := conf.JobManagerSetting.CollectOrphanObjectsInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	collector := storage.NewCollector(ds.Ds, _oss)
+	var running sync.Mutex
+	events.OnTask(schedule, func() {
+		// 上一次任务还未完成时跳过本次任务
+		if !running.TryLock() {
+			return
+		}
+		defer running.Unlock()
+		report, err := collector.Collect(false, nil)
+		if err != nil {
+			logrus.Warnf("onCollectOrphanObjectsJob occurs error: %s", err)
+		}
+		if report != nil {
+			logrus.Infof("onCollectOrphanObjectsJob scanned %d objects, deleted %d/%d orphan objects", report.Scanned, report.Deleted, report.Orphans)
+		}
+	})
+}
+
+func onReconcileWalletJob(ds *base.DaoServant) {
+	// 未开启钱包功能
+	if !cfg.Any("Alipay", "WechatPay") {
+		return
+	}
+	spec := conf.JobManagerSetting.ReconcileWalletInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	events.OnTask(schedule, func() {
+		report, err := ds.Ds.ReconcileWallets()
+		if err != nil {
+			logrus.Warnf("onReconcileWalletJob occurs error: %s", err)
+			return
+		}
+		for _, m := range report.Mismatches {
+			logrus.Warnf("onReconcileWalletJob user(%d) balance %d not equal to statements %d", m.UserID, m.Balance, m.StatementSum)
+		}
+		logrus.Infof("onReconcileWalletJob checked %d users, total balance %d, total statements %d, unpaid withdrawal %d, %d mismatches",
+			report.Users, report.TotalBalance, report.TotalStatement, report.UnpaidWithdrawal, len(report.Mismatches))
+	})
+}
+
+func onRenewSubscriptionJob(ds *base.DaoServant) {
+	// 未开启创作者订阅功能
+	if !cfg.If("Subscription") {
+		return
+	}
+	spec := conf.JobManagerSetting.RenewSubscriptionInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	var running sync.Mutex
+	events.OnTask(schedule, func() {
+		// 上一次任务还未完成时跳过本次任务
+		if !running.TryLock() {
+			return
+		}
+		defer running.Unlock()
+		for {
+			subscriptions, err := ds.Ds.ListRenewableSubscriptions(time.Now().Unix(), 100)
+			if err != nil {
+				logrus.Warnf("onRenewSubscriptionJob[1] occurs error: %s", err)
+				return
+			}
+			renewed := 0
+			for _, subscription := range subscriptions {
+				ok, err := ds.Ds.RenewSubscription(subscription)
+				switch {
+				case err == cs.ErrNoBalance:
+					onCreateMessageEvent(&ms.Message{
+						SenderUserID:   subscription.CreatorID,
+						ReceiverUserID: subscription.UserID,
+						Type:           ms.MsgTypeSystem,
+						Brief:          "钱包余额不足，订阅自动续费失败，已关闭自动续费",
+					})
+				case err != nil:
+					logrus.Warnf("onRenewSubscriptionJob[2] renew subscription %d occurs error: %s", subscription.ID, err)
+				case ok:
+					renewed++
+				}
+			}
+			// 本批次没有成功续费的订阅时结束，避免反复处理失败的订阅
+			if len(subscriptions) < 100 || renewed == 0 {
+				return
+			}
+		}
+	})
+}
+
+func onRefreshTrendingTagsJob(ds *base.DaoServant) {
+	spec := conf.JobManagerSetting.RefreshTrendingTagsInterval
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		panic(err)
+	}
+	var running sync.Mutex
+	events.OnTask(schedule, func() {
+		// 上一次任务还未完成时跳过本次任务
+		if !running.TryLock() {
+			return
+		}
+		defer running.Unlock()
+		if err := ds.Ds.RefreshTrendingTags(); err != nil {
+			logrus.Warnf("onRefreshTrendingTagsJob occurs error: %s", err)
+		}
+	})
+}
+
+func scheduleJobs(ds *base.DaoServant) {
 	cfg.Not("DisableJobManager", func() {
 		lazyInitial()
 		onMaxOnlineJob()
+		onPublishScheduledTweetJob(ds)
+		onClosePollJob(ds)
+		onProcessMediaJob(ds)
+		onCleanUploadSessionJob(ds)
+		onCollectOrphanObjectsJob(ds)
+		onReconcileWalletJob(ds)
+		onRenewSubscriptionJob(ds)
+		onRefreshTrendingTagsJob(ds)
 		logrus.Debug("schedule inner jobs complete")
 	})
 }
@@ -6,7 +6,9 @@ package web
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/alimy/tryst/cfg"
 	"github.com/gin-gonic/gin"
 	api "github.com/rocboss/paopao-ce/auto/api/v1"
 	"github.com/rocboss/paopao-ce/internal/conf"
@@ -18,6 +20,9 @@ import (
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/app"
+	"github.com/rocboss/paopao-ce/pkg/json"
+	"github.com/rocboss/paopao-ce/pkg/recommend"
 	"github.com/sirupsen/logrus"
 )
 
@@ -49,8 +54,14 @@ type looseSrv struct {
 	prefixIdxTweetsHots string
 	// prefixIdxTweetsFollowing 是首页关注动态缓存键的前缀
 	prefixIdxTweetsFollowing string
+	// prefixIdxTweetsRecommend 是首页推荐动态缓存键的前缀
+	prefixIdxTweetsRecommend string
+	// prefixRecommendTweets 是用户推荐动态排序结果缓存键的前缀
+	prefixRecommendTweets string
 	// prefixTweetComment 是动态评论缓存键的前缀
 	prefixTweetComment string
+	// fanoutTimeline 表示关注动态由写扩散的时间线提供，此时不再按页缓存关注动态
+	fanoutTimeline bool
 }
 
 // Chain 返回应用到此服务所有路由的中间件链
@@ -68,6 +79,10 @@ func (s *looseSrv) Timeline(req *web.TimelineReq) (*web.TimelineResp, error) {
 	// 根据请求参数判断是获取首页动态还是执行搜索
 	// 如果没有查询关键词(Query)但类型(Type)是"search"，则视为获取首页动态
 	if req.Query == "" && req.Type == "search" {
+		// 携带游标时使用游标分页，热门与推荐动态不支持游标，仍按页码分页
+		if req.Cursor != nil && (req.Style == web.StyleTweetsNewest || req.Style == web.StyleTweetsFollowing) {
+			return s.getIndexTweetsByCursor(req)
+		}
 		return s.getIndexTweets(req, limit, offset)
 	}
 
@@ -140,6 +155,13 @@ func (s *looseSrv) getIndexTweets(req *web.TimelineReq, limit int, offset int) (
 		posts, total, xerr = s.Ds.ListIndexNewestTweets(limit, offset)
 	case web.StyleTweetsHots: // 获取全站热门动态
 		posts, total, xerr = s.Ds.ListIndexHotsTweets(limit, offset)
+	case web.StyleTweetsRecommend: // 获取个性化推荐动态
+		if req.User != nil {
+			posts, total, xerr = s.listRecommendTweets(req.User.ID, limit, offset)
+		} else {
+			// 游客没有个性化特征，降级为获取热门动态
+			posts, total, xerr = s.Ds.ListIndexHotsTweets(limit, offset)
+		}
 	default: // 未知的样式
 		return nil, web.ErrGetPostsUnknowStyle
 	}
@@ -171,7 +193,9 @@ func (s *looseSrv) getIndexTweets(req *web.TimelineReq, limit int, offset int) (
 	// 构建分页响应
 	resp := joint.PageRespFrom(postsFormated, req.Page, req.PageSize, total)
 	// 将从数据库获取的结果存入缓存
-	base.OnCacheRespEvent(s.ac, key, resp, s.idxTweetsExpire)
+	if key != "" {
+		base.OnCacheRespEvent(s.ac, key, resp, s.idxTweetsExpire)
+	}
 	// 封装最终的API响应
 	return &web.TimelineResp{
 		CachePageResp: joint.CachePageResp{
@@ -180,6 +204,43 @@ func (s *looseSrv) getIndexTweets(req *web.TimelineReq, limit int, offset int) (
 	}, nil
 }
 
+// getIndexTweetsByCursor 游标分页获取首页动态，游标位置各异故不使用缓存
+func (s *looseSrv) getIndexTweetsByCursor(req *web.TimelineReq) (*web.TimelineResp, error) {
+	var (
+		posts []*ms.Post
+		next  *cs.Cursor
+		err   error
+	)
+	if req.Style == web.StyleTweetsFollowing && req.User != nil {
+		posts, next, err = s.Ds.ListFollowingTweetsByCursor(req.User.ID, req.Cursor, req.PageSize)
+	} else {
+		posts, next, err = s.Ds.ListIndexNewestTweetsByCursor(req.Cursor, req.PageSize)
+	}
+	if err != nil {
+		logrus.Errorf("getIndexTweetsByCursor occurs error[1]: %s", err)
+		return nil, web.ErrGetPostFailed
+	}
+	postsFormated, err := s.Ds.MergePosts(posts)
+	if err != nil {
+		logrus.Errorf("getIndexTweetsByCursor in merge posts occurs error: %s", err)
+		return nil, web.ErrGetPostFailed
+	}
+	userId := int64(-1)
+	if req.User != nil {
+		userId = req.User.ID
+	}
+	if err := s.PrepareTweets(userId, postsFormated); err != nil {
+		logrus.Errorf("getIndexTweetsByCursor occurs error[2]: %s", err)
+		return nil, web.ErrGetPostsFailed
+	}
+	resp := joint.CursorPageRespFrom(postsFormated, req.PageSize, app.EncodeCursor(next))
+	return &web.TimelineResp{
+		CachePageResp: joint.CachePageResp{
+			Data: resp,
+		},
+	}, nil
+}
+
 // indexTweetsFromCache 尝试从缓存中获取首页动态
 func (s *looseSrv) indexTweetsFromCache(req *web.TimelineReq, limit int, offset int) (res *web.TimelineResp, key string, ok bool) {
 	// 如果是游客，用户名为"_"，否则为登录用户名
@@ -192,11 +253,17 @@ func (s *looseSrv) indexTweetsFromCache(req *web.TimelineReq, limit int, offset
 	// 注意：关注页的缓存键包含了用户名，因为每个用户关注的人不同
 	switch req.Style {
 	case web.StyleTweetsFollowing:
+		if s.fanoutTimeline && req.User != nil {
+			// 写扩散的时间线本身即是缓存，不使用按页缓存
+			return
+		}
 		key = fmt.Sprintf("%s%s:%d:%d", s.prefixIdxTweetsFollowing, username, offset, limit)
 	case web.StyleTweetsNewest:
 		key = fmt.Sprintf("%s%s:%d:%d", s.prefixIdxTweetsNewest, username, offset, limit)
 	case web.StyleTweetsHots:
 		key = fmt.Sprintf("%s%s:%d:%d", s.prefixIdxTweetsHots, username, offset, limit)
+	case web.StyleTweetsRecommend:
+		key = fmt.Sprintf("%s%s:%d:%d", s.prefixIdxTweetsRecommend, username, offset, limit)
 	default:
 		// 未知样式，直接返回，不使用缓存
 		return
@@ -215,6 +282,70 @@ func (s *looseSrv) indexTweetsFromCache(req *web.TimelineReq, limit int, offset
 	return
 }
 
+// listRecommendTweets 获取个性化推荐动态，排序结果按用户缓存，翻页时顺序保持不变
+func (s *looseSrv) listRecommendTweets(userId int64, limit int, offset int) ([]*ms.Post, int64, error) {
+	ids, err := s.recommendTweetIds(userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(ids))
+	if offset >= len(ids) {
+		return nil, total, nil
+	}
+	ids = ids[offset:min(offset+limit, len(ids))]
+	posts, err := s.Ds.GetPosts(ms.ConditionsT{"id IN ?": ids}, 0, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 按推荐顺序排列，缓存期间被删除的推文直接跳过
+	postMap := make(map[int64]*ms.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	res := make([]*ms.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := postMap[id]; ok {
+			res = append(res, post)
+		}
+	}
+	return res, total, nil
+}
+
+// recommendTweetIds 用户推荐动态的排序结果，优先从缓存中获取
+func (s *looseSrv) recommendTweetIds(userId int64) (ids []int64, err error) {
+	key := fmt.Sprintf("%s%d", s.prefixRecommendTweets, userId)
+	if data, xerr := s.ac.Get(key); xerr == nil && json.Unmarshal(data, &ids) == nil {
+		return ids, nil
+	}
+	rs := conf.RecommendSetting
+	tags, err := s.Ds.GetFollowTags(userId, false, rs.MaxTopics, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != nil {
+			names = append(names, tag.Tag)
+		}
+	}
+	now := time.Now().Unix()
+	candidates, err := s.Ds.ListRecommendCandidates(userId, names, now-rs.CandidateDays*86400, rs.MaxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	ranked := recommend.Rank(candidates, rs.Weights(), now)
+	ids = make([]int64, 0, len(ranked))
+	for _, candidate := range ranked {
+		ids = append(ids, candidate.PostID)
+	}
+	if data, xerr := json.Marshal(ids); xerr == nil {
+		if xerr = s.ac.Set(key, data, rs.Expire); xerr != nil {
+			logrus.Warnf("recommendTweetIds cache ranked tweets occurs error: %s", xerr)
+		}
+	}
+	return ids, nil
+}
+
 // tweetCommentsFromCache 尝试从缓存中获取动态的评论列表
 func (s *looseSrv) tweetCommentsFromCache(req *web.TweetCommentsReq, limit int, offset int) (res *web.TweetCommentsResp, key string, ok bool) {
 	// 根据动态ID、评论样式和分页信息构建唯一的缓存键
@@ -240,6 +371,16 @@ func (s *looseSrv) GetUserTweets(req *web.GetUserTweetsReq) (res *web.GetUserTwe
 		return nil, err
 	}
 
+	// 定时发布的动态仅作者本人可见，且不走缓存
+	if req.Style == web.UserPostsStyleScheduled {
+		return s.getUserScheduledTweets(req, user)
+	}
+
+	// 游标分页仅支持发布与精华动态，同样不走缓存
+	if req.Cursor != nil && req.Style != web.UserPostsStyleComment && req.Style != web.UserPostsStyleMedia && req.Style != web.UserPostsStyleStar {
+		return s.getUserPostTweets(req, user, req.Style == web.UserPostsStyleHighlight)
+	}
+
 	// 尝试从缓存中获取数据
 	key, ok := "", false
 	if res, key, ok = s.userTweetsFromCache(req, user); ok {
@@ -275,8 +416,8 @@ func (s *looseSrv) userTweetsFromCache(req *web.GetUserTweetsReq, user *cs.VistU
 	// 根据不同样式构建缓存键
 	switch req.Style {
 	case web.UserPostsStylePost, web.UserPostsStyleHighlight, web.UserPostsStyleMedia:
-		// 这几种样式下，内容的可见性取决于访问者与作者的关系，所以缓存键包含关系类型(RelTyp)
-		key = fmt.Sprintf("%s%d:%s:%s:%d:%d", s.prefixUserTweets, user.UserId, req.Style, user.RelTyp, req.Page, req.PageSize)
+		// 这几种样式下，内容的可见性取决于访问者与作者的关系，所以缓存键包含关系类型(RelTyp)及是否为订阅者
+		key = fmt.Sprintf("%s%d:%s:%s:%t:%d:%d", s.prefixUserTweets, user.UserId, req.Style, user.RelTyp, user.IsSubscriber, req.Page, req.PageSize)
 	default:
 		// 其他样式下，内容的可见性取决于访问者本身（比如"我"评论过的），所以缓存键包含访问者用户名
 		meName := "_"
@@ -297,6 +438,31 @@ func (s *looseSrv) userTweetsFromCache(req *web.GetUserTweetsReq, user *cs.VistU
 	return
 }
 
+// getUserScheduledTweets 获取用户待发布的定时动态列表
+func (s *looseSrv) getUserScheduledTweets(req *web.GetUserTweetsReq, user *cs.VistUser) (*web.GetUserTweetsResp, error) {
+	if user.RelTyp != cs.RelationSelf {
+		return nil, web.ErrNoPermission
+	}
+	schedules, total, err := s.Ds.ListUserScheduledTweets(user.UserId, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("getUserScheduledTweets err: %s", err)
+		return nil, web.ErrGetPostsFailed
+	}
+	userFormated := req.User.Format()
+	items := make([]*ms.PostScheduleFormated, 0, len(schedules))
+	for _, schedule := range schedules {
+		item := schedule.Format()
+		item.User = userFormated
+		items = append(items, item)
+	}
+	resp := joint.PageRespFrom(items, req.Page, req.PageSize, total)
+	return &web.GetUserTweetsResp{
+		CachePageResp: joint.CachePageResp{
+			Data: resp,
+		},
+	}, nil
+}
+
 // getUserStarTweets 获取用户点赞的动态列表
 func (s *looseSrv) getUserStarTweets(req *web.GetUserTweetsReq, user *cs.VistUser) (*web.GetUserTweetsResp, error) {
 	// 从数据库查询用户点赞的动态
@@ -410,7 +576,17 @@ func (s *looseSrv) getUserPostTweets(req *web.GetUserTweetsReq, user *cs.VistUse
 	}
 
 	// 调用DAO层获取用户动态列表
-	posts, total, err := s.Ds.ListUserTweets(user.UserId, style, isHighlight, req.PageSize, (req.Page-1)*req.PageSize)
+	var (
+		posts []*ms.Post
+		total int64
+		next  *cs.Cursor
+		err   error
+	)
+	if req.Cursor != nil {
+		posts, next, err = s.Ds.ListUserTweetsByCursor(user.UserId, style, user.IsSubscriber, isHighlight, req.Cursor, req.PageSize)
+	} else {
+		posts, total, err = s.Ds.ListUserTweets(user.UserId, style, user.IsSubscriber, isHighlight, req.PageSize, (req.Page-1)*req.PageSize)
+	}
 	if err != nil {
 		logrus.Errorf("s.GetTweetList error[1]: %s", err)
 		return nil, web.ErrGetPostsFailed
@@ -436,6 +612,9 @@ func (s *looseSrv) getUserPostTweets(req *web.GetUserTweetsReq, user *cs.VistUse
 
 	// 构建分页响应
 	resp := joint.PageRespFrom(postsFormated, req.Page, req.PageSize, total)
+	if req.Cursor != nil {
+		resp = joint.CursorPageRespFrom(postsFormated, req.PageSize, app.EncodeCursor(next))
+	}
 	return &web.GetUserTweetsResp{
 		CachePageResp: joint.CachePageResp{
 			Data: resp,
@@ -492,6 +671,7 @@ func (s *looseSrv) GetUserProfile(req *web.GetUserProfileReq) (*web.GetUserProfi
 func (s *looseSrv) TopicList(req *web.TopicListReq) (*web.TopicListResp, error) {
 	var (
 		tags, extralTags cs.TagList
+		next             *cs.Cursor
 		err              error
 	)
 	num := req.Num
@@ -499,12 +679,18 @@ func (s *looseSrv) TopicList(req *web.TopicListReq) (*web.TopicListResp, error)
 	switch req.Type {
 	case web.TagTypeHot: // 热门话题
 		tags, err = s.Ds.GetHotTags(req.Uid, num, 0)
-	case web.TagTypeNew: // 最新话题
-		tags, err = s.Ds.GetNewestTags(req.Uid, num, 0)
+	case web.TagTypeNew: // 最新话题，携带游标时使用游标分页
+		if req.Cursor != nil {
+			tags, next, err = s.Ds.GetNewestTagsByCursor(req.Uid, req.Cursor, num)
+		} else {
+			tags, err = s.Ds.GetNewestTags(req.Uid, num, 0)
+		}
 	case web.TagTypeFollow: // 我关注的话题
 		tags, err = s.Ds.GetFollowTags(req.Uid, false, num, 0)
 	case web.TagTypePin: // 我置顶的话题
 		tags, err = s.Ds.GetFollowTags(req.Uid, true, num, 0)
+	case web.TagTypeTrending: // 热议话题
+		tags, err = s.Ds.GetTrendingTags(req.Uid, num, 0)
 	case web.TagTypeHotExtral: // 获取热门话题，并额外获取我关注的话题
 		extralNum := req.ExtralNum
 		if extralNum <= 0 {
@@ -526,6 +712,7 @@ func (s *looseSrv) TopicList(req *web.TopicListReq) (*web.TopicListResp, error)
 	return &web.TopicListResp{
 		Topics:       tags,
 		ExtralTopics: extralTags,
+		NextCursor:   app.EncodeCursor(next),
 	}, nil
 }
 
@@ -533,15 +720,38 @@ func (s *looseSrv) TopicList(req *web.TopicListReq) (*web.TopicListResp, error)
 func (s *looseSrv) TweetComments(req *web.TweetCommentsReq) (res *web.TweetCommentsResp, err error) {
 	limit, offset := req.PageSize, (req.Page-1)*req.PageSize
 
+	// 订阅可见的动态仅订阅者可查看评论
+	post, xerr := s.Ds.GetPostByID(req.TweetId)
+	if xerr != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	if xerr = checkPostSubscribePermission(req.Uid, post, s.Ds); xerr != nil {
+		return nil, xerr
+	}
+
+	// 热门评论不支持游标，仍按页码分页；游标分页不走缓存
+	useCursor := req.Cursor != nil && req.Style.ToInnerValue() != cs.StyleCommentHots
+
 	// 尝试从缓存获取
 	key, ok := "", false
-	if res, key, ok = s.tweetCommentsFromCache(req, limit, offset); ok {
-		logrus.Debugf("looseSrv.TweetComments from cache key:%s", key)
-		return
+	if !useCursor {
+		if res, key, ok = s.tweetCommentsFromCache(req, limit, offset); ok {
+			logrus.Debugf("looseSrv.TweetComments from cache key:%s", key)
+			return
+		}
 	}
 
 	// 缓存未命中，从数据库查询主评论
-	comments, totalRows, xerr := s.Ds.GetComments(req.TweetId, req.Style.ToInnerValue(), limit, offset)
+	var (
+		comments  []*ms.Comment
+		totalRows int64
+		next      *cs.Cursor
+	)
+	if useCursor {
+		comments, next, xerr = s.Ds.GetCommentsByCursor(req.TweetId, req.Style.ToInnerValue(), req.Cursor, limit)
+	} else {
+		comments, totalRows, xerr = s.Ds.GetComments(req.TweetId, req.Style.ToInnerValue(), limit, offset)
+	}
 	if xerr != nil {
 		logrus.Errorf("looseSrv.TweetComments occurs error[1]: %s", xerr)
 		return nil, web.ErrGetCommentsFailed
@@ -628,6 +838,13 @@ func (s *looseSrv) TweetComments(req *web.TweetCommentsReq) (res *web.TweetComme
 		commentsFormated = append(commentsFormated, commentFormated)
 	}
 	// 构建分页响应
+	if useCursor {
+		return &web.TweetCommentsResp{
+			CachePageResp: joint.CachePageResp{
+				Data: joint.CursorPageRespFrom(commentsFormated, req.PageSize, app.EncodeCursor(next)),
+			},
+		}, nil
+	}
 	resp := joint.PageRespFrom(commentsFormated, req.Page, req.PageSize, totalRows)
 	// 将结果写入缓存
 	base.OnCacheRespEvent(s.ac, key, resp, s.tweetCommentsExpire)
@@ -687,6 +904,15 @@ func (s *looseSrv) TweetDetail(req *web.TweetDetailReq) (*web.TweetDetailResp, e
 	case post.Visibility == core.PostVisitFollowing && postFormated.User.IsFollowing:
 		// 关注者可见动态，且当前用户已关注，可以查看
 		break
+	case post.Visibility == core.PostVisitSubscribe && req.User != nil && s.Ds.IsSubscriber(req.User.ID, post.UserID):
+		// 订阅可见动态，且当前用户在订阅有效期内，可以查看
+		break
+	case post.Visibility == core.PostVisitSubscribe:
+		// 订阅可见动态，当前用户未订阅
+		return nil, web.ErrNoSubscription
+	case post.Visibility == core.PostVisitCharge:
+		// 充电可见动态，未达到充电门槛的用户仅能查看预览
+		break
 	default:
 		// 其他情况，无权限
 		return nil, web.ErrNoPermission
@@ -694,6 +920,274 @@ func (s *looseSrv) TweetDetail(req *web.TweetDetailReq) (*web.TweetDetailResp, e
 	return (*web.TweetDetailResp)(postFormated), nil
 }
 
+// TweetRevisions 获取动态的修订历史
+func (s *looseSrv) TweetRevisions(req *web.TweetRevisionsReq) (*web.TweetRevisionsResp, error) {
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	// 修订历史与动态本身的可见性一致
+	if err = checkPostViewPermission(req.User, post, s.Ds); err != nil {
+		return nil, err
+	}
+	revisions, total, err := s.Ds.ListPostRevisions(post.ID, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListPostRevisions err: %s", err)
+		return nil, web.ErrGetPostRevisionsFailed
+	}
+	items := make([]*ms.PostRevisionFormated, 0, len(revisions))
+	for _, revision := range revisions {
+		items = append(items, revision.Format())
+	}
+	resp := base.PageRespFrom(items, req.Page, req.PageSize, total)
+	return (*web.TweetRevisionsResp)(resp), nil
+}
+
+func (s *looseSrv) TweetPollVotes(req *web.TweetPollVotesReq) (*web.TweetPollVotesResp, error) {
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	if err = checkPostViewPermission(req.User, post, s.Ds); err != nil {
+		return nil, err
+	}
+	poll, err := s.Ds.GetPollByPostId(post.ID)
+	if err != nil {
+		logrus.Errorf("Ds.GetPollByPostId err: %s", err)
+		return nil, web.ErrGetPollFailed
+	}
+	// 匿名投票不公开投票人
+	if poll.Anonymous > 0 {
+		return nil, web.ErrPollAnonymous
+	}
+	votes, total, err := s.Ds.ListPollVotes(poll.ID, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListPollVotes err: %s", err)
+		return nil, web.ErrGetPollFailed
+	}
+	userIds := make([]int64, 0, len(votes))
+	for _, vote := range votes {
+		userIds = append(userIds, vote.UserID)
+	}
+	users, err := s.Ds.GetUsersByIDs(userIds)
+	if err != nil {
+		logrus.Errorf("Ds.GetUsersByIDs err: %s", err)
+		return nil, web.ErrGetPollFailed
+	}
+	userMap := make(map[int64]*ms.UserFormated, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user.Format()
+	}
+	items := make([]*ms.PostPollVoteFormated, 0, len(votes))
+	for _, vote := range votes {
+		item := vote.Format()
+		item.User = userMap[vote.UserID]
+		items = append(items, item)
+	}
+	resp := base.PageRespFrom(items, req.Page, req.PageSize, total)
+	return (*web.TweetPollVotesResp)(resp), nil
+}
+
+// TweetTips 获取为动态充电的用户及其留言
+func (s *looseSrv) TweetTips(req *web.TweetTipsReq) (*web.TweetTipsResp, error) {
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	// 充电可见的动态对所有用户展示预览及充电记录
+	if post.Visibility != core.PostVisitCharge {
+		if err = checkPostViewPermission(req.User, post, s.Ds); err != nil {
+			return nil, err
+		}
+	}
+	tips, total, err := s.Ds.ListPostTips(post.ID, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListPostTips err: %s", err)
+		return nil, web.ErrGetTipsFailed
+	}
+	userIds := make([]int64, 0, len(tips))
+	for _, tip := range tips {
+		userIds = append(userIds, tip.UserID)
+	}
+	users, err := s.Ds.GetUsersByIDs(userIds)
+	if err != nil {
+		logrus.Errorf("Ds.GetUsersByIDs err: %s", err)
+		return nil, web.ErrGetTipsFailed
+	}
+	userMap := make(map[int64]*ms.UserFormated, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user.Format()
+	}
+	items := make([]*ms.PostTipFormated, 0, len(tips))
+	for _, tip := range tips {
+		item := tip.Format()
+		item.User = userMap[tip.UserID]
+		items = append(items, item)
+	}
+	resp := base.PageRespFrom(items, req.Page, req.PageSize, total)
+	return (*web.TweetTipsResp)(resp), nil
+}
+
+// TopicDetail 获取话题主页，别名话题返回合并后的话题
+func (s *looseSrv) TopicDetail(req *web.TopicDetailReq) (*web.TopicDetailResp, error) {
+	var (
+		topic *cs.TopicInfo
+		err   error
+	)
+	switch {
+	case req.TopicId > 0:
+		topic, err = s.Ds.GetTopic(req.TopicId)
+	case req.Name != "":
+		topic, err = s.Ds.GetTopicByName(req.Name)
+	default:
+		return nil, web.ErrNoExistTopic
+	}
+	if err != nil {
+		return nil, web.ErrNoExistTopic
+	}
+	resp := &web.TopicDetailResp{
+		TopicInfo:    topic,
+		Moderators:   []*ms.UserFormated{},
+		PinnedTweets: []*ms.PostFormated{},
+	}
+	if resp.Aliases, err = s.Ds.GetTopicAliases(topic.ID); err != nil {
+		logrus.Errorf("Ds.GetTopicAliases err: %s", err)
+		return nil, web.ErrGetTopicFailed
+	}
+	moderatorIds, err := s.Ds.ListTopicModerators(topic.ID)
+	if err != nil {
+		logrus.Errorf("Ds.ListTopicModerators err: %s", err)
+		return nil, web.ErrGetTopicFailed
+	}
+	users, err := s.Ds.GetUsersByIDs(append([]int64{topic.UserID}, moderatorIds...))
+	if err != nil {
+		logrus.Errorf("Ds.GetUsersByIDs err: %s", err)
+		return nil, web.ErrGetTopicFailed
+	}
+	userMap := make(map[int64]*ms.UserFormated, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user.Format()
+	}
+	resp.Creator = userMap[topic.UserID]
+	for _, id := range moderatorIds {
+		if user, ok := userMap[id]; ok {
+			resp.Moderators = append(resp.Moderators, user)
+		}
+	}
+	userId := int64(-1)
+	if req.User != nil {
+		userId = req.User.ID
+		resp.IsFollowing = s.Ds.IsFollowingTopic(userId, topic.ID)
+	}
+	if resp.PinnedTweets, err = s.topicPinnedTweets(userId, topic.ID); err != nil {
+		logrus.Errorf("s.topicPinnedTweets err: %s", err)
+		return nil, web.ErrGetTopicFailed
+	}
+	return resp, nil
+}
+
+// topicPinnedTweets 话题内置顶的推文，按置顶顺序排列，仅展示公开推文
+func (s *looseSrv) topicPinnedTweets(userId int64, topicId int64) ([]*ms.PostFormated, error) {
+	ids, err := s.Ds.ListTopicPins(topicId)
+	if err != nil || len(ids) == 0 {
+		return []*ms.PostFormated{}, err
+	}
+	posts, err := s.Ds.GetPosts(ms.ConditionsT{
+		"id IN ?":        ids,
+		"visibility = ?": core.PostVisitPublic,
+	}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	postMap := make(map[int64]*ms.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	tweets := make([]*ms.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := postMap[id]; ok {
+			tweets = append(tweets, post)
+		}
+	}
+	postsFormated, err := s.Ds.MergePosts(tweets)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.PrepareTweets(userId, postsFormated); err != nil {
+		return nil, err
+	}
+	return postsFormated, nil
+}
+
+// TopicTweets 获取话题及其别名下的公开推文
+func (s *looseSrv) TopicTweets(req *web.TopicTweetsReq) (*web.TopicTweetsResp, error) {
+	topic, err := s.Ds.GetTopic(req.TopicId)
+	if err != nil {
+		return nil, web.ErrNoExistTopic
+	}
+	tweets, total, err := s.Ds.ListTopicTweets(topic.ID, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListTopicTweets err: %s", err)
+		return nil, web.ErrGetPostsFailed
+	}
+	postsFormated, err := s.Ds.MergePosts(tweets)
+	if err != nil {
+		logrus.Errorf("Ds.MergePosts err: %s", err)
+		return nil, web.ErrGetPostsFailed
+	}
+	userId := int64(-1)
+	if req.User != nil {
+		userId = req.User.ID
+	}
+	if err = s.PrepareTweets(userId, postsFormated); err != nil {
+		logrus.Errorf("s.PrepareTweets err: %s", err)
+		return nil, web.ErrGetPostsFailed
+	}
+	resp := base.PageRespFrom(postsFormated, req.Page, req.PageSize, total)
+	return (*web.TopicTweetsResp)(resp), nil
+}
+
+// SharedCollections 通过分享链接查看收藏夹中的公开推文，不包含收藏者的私人备注
+func (s *looseSrv) SharedCollections(req *web.SharedCollectionsReq) (*web.SharedCollectionsResp, error) {
+	folder, err := s.Ds.GetCollectionFolderByShareKey(req.ShareKey)
+	if err != nil {
+		return nil, web.ErrNoExistCollectionFolder
+	}
+	user, err := s.Ds.GetUserByID(folder.UserID)
+	if err != nil {
+		return nil, web.ErrNoExistCollectionFolder
+	}
+	collections, total, err := s.Ds.ListSharedPostCollections(folder, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListSharedPostCollections err: %s", err)
+		return nil, web.ErrGetCollectionsFailed
+	}
+	posts := make([]*ms.Post, 0, len(collections))
+	for _, collection := range collections {
+		posts = append(posts, collection.Post)
+	}
+	postsFormated, err := s.Ds.MergePosts(posts)
+	if err != nil {
+		logrus.Errorf("Ds.MergePosts err: %s", err)
+		return nil, web.ErrGetCollectionsFailed
+	}
+	userId := int64(-1)
+	if req.User != nil {
+		userId = req.User.ID
+	}
+	if err = s.PrepareTweets(userId, postsFormated); err != nil {
+		logrus.Errorf("s.PrepareTweets err: %s", err)
+		return nil, web.ErrGetCollectionsFailed
+	}
+	folderFormated := folder.Format()
+	folderFormated.ItemCount = total
+	return &web.SharedCollectionsResp{
+		Folder:   folderFormated,
+		User:     user.Format(),
+		PageResp: base.PageRespFrom(postsFormated, req.Page, req.PageSize, total),
+	}, nil
+}
+
 // newLooseSrv 创建一个新的 looseSrv 实例
 func newLooseSrv(s *base.DaoServant, ac core.AppCache) api.Loose {
 	cs := conf.CacheSetting
@@ -707,6 +1201,9 @@ func newLooseSrv(s *base.DaoServant, ac core.AppCache) api.Loose {
 		prefixIdxTweetsNewest:    conf.PrefixIdxTweetsNewest,
 		prefixIdxTweetsHots:      conf.PrefixIdxTweetsHots,
 		prefixIdxTweetsFollowing: conf.PrefixIdxTweetsFollowing,
+		prefixIdxTweetsRecommend: conf.PrefixIdxTweetsRecommend,
+		prefixRecommendTweets:    conf.PrefixRecommendTweets,
 		prefixTweetComment:       conf.PrefixTweetComment,
+		fanoutTimeline:           cfg.If("FanoutTimeline"),
 	}
 }
@@ -0,0 +1,181 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/pkg/media"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// 转码输出文件的内容类型
+	_mediaOutputTypes = map[string]string{
+		".jpg":  "image/jpeg",
+		".m3u8": "application/vnd.apple.mpegurl",
+		".ts":   "video/mp2t",
+	}
+)
+
+type videoProcessor struct {
+	ds          core.DataService
+	oss         core.ObjectStorageService
+	transcoder  media.Transcoder
+	renditions  []*media.Rendition
+	workDir     string
+	segmentTime int
+	timeout     time.Duration
+}
+
+func newVideoProcessor(ds core.DataService, oss core.ObjectStorageService, transcoder media.Transcoder) *videoProcessor {
+	s := conf.VideoTranscodeSetting
+	return &videoProcessor{
+		ds:          ds,
+		oss:         oss,
+		transcoder:  transcoder,
+		renditions:  conf.VideoRenditions(),
+		workDir:     s.WorkDir,
+		segmentTime: s.SegmentTime,
+		timeout:     s.Timeout,
+	}
+}
+
+// spoolPath 待转码视频在本地工作目录中的路径
+func (p *videoProcessor) spoolPath(objectKey string) string {
+	return filepath.Join(p.workDir, "spool", filepath.FromSlash(objectKey))
+}
+
+// spool 保存上传的视频到本地工作目录等待后台转码
+func (p *videoProcessor) spool(objectKey string, reader io.Reader) error {
+	spoolPath := p.spoolPath(objectKey)
+	if err := os.MkdirAll(filepath.Dir(spoolPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(spoolPath)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(file, reader); err != nil {
+		file.Close()
+		os.Remove(spoolPath)
+		return err
+	}
+	return file.Close()
+}
+
+// process 转码视频并更新附件，失败时标记为处理失败，客户端回退播放原视频
+func (p *videoProcessor) process(attachment *ms.Attachment) (err error) {
+	objectKey := p.oss.ObjectKey(attachment.Content)
+	spoolPath := p.spoolPath(objectKey)
+	defer func() {
+		if err != nil {
+			attachment.Status = ms.MediaStatusFailed
+			if e := p.ds.UpdateMedia(attachment); e != nil {
+				logrus.Errorf("videoProcessor.process mark attachment %d failed occurs error: %s", attachment.ID, e)
+			}
+		}
+		os.Remove(spoolPath)
+	}()
+	if err = os.MkdirAll(p.workDir, 0755); err != nil {
+		return err
+	}
+	outputDir, err := os.MkdirTemp(p.workDir, "hls-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(outputDir)
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	info, err := p.transcoder.Probe(ctx, spoolPath)
+	if err != nil {
+		return err
+	}
+	renditions := media.SelectRenditions(info, p.renditions)
+	if len(renditions) == 0 {
+		return fmt.Errorf("no rendition for video %dx%d", info.Width, info.Height)
+	}
+	if err = p.transcoder.Poster(ctx, spoolPath, media.PosterTime(info.Duration), filepath.Join(outputDir, media.PosterName)); err != nil {
+		return err
+	}
+	for _, r := range renditions {
+		if err = p.transcoder.HLS(ctx, spoolPath, outputDir, r, p.segmentTime); err != nil {
+			return err
+		}
+	}
+	if err = os.WriteFile(filepath.Join(outputDir, media.MasterPlaylist), []byte(media.BuildMasterPlaylist(renditions)), 0644); err != nil {
+		return err
+	}
+
+	// 转码输出保存在原视频同名目录下，如 xxx.mp4 的主播放列表为 xxx/master.m3u8
+	baseKey := strings.TrimSuffix(objectKey, path.Ext(objectKey)) + "/"
+	sizes := make(map[string]*media.Rendition, len(renditions))
+	for _, r := range renditions {
+		sizes[r.Name] = r
+	}
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return err
+	}
+	variants := make([]*ms.ImageVariant, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		contentType, exist := _mediaOutputTypes[path.Ext(name)]
+		if !exist || entry.IsDir() {
+			continue
+		}
+		objectUrl, err := p.putFile(baseKey+name, filepath.Join(outputDir, name), contentType)
+		if err != nil {
+			// 已上传的文件随附件的版本列表一起删除
+			attachment.SetVariants(variants)
+			return err
+		}
+		variant := &ms.ImageVariant{
+			Name:        name,
+			ContentType: contentType,
+			Content:     objectUrl,
+		}
+		if r, exist := sizes[strings.TrimSuffix(name, path.Ext(name))]; exist {
+			variant.Width, variant.Height = r.Width, r.Height
+		}
+		switch name {
+		case media.PosterName:
+			attachment.Poster = objectUrl
+		case media.MasterPlaylist:
+			attachment.Playlist = objectUrl
+		}
+		variants = append(variants, variant)
+	}
+	attachment.SetVariants(variants)
+	attachment.ImgWidth, attachment.ImgHeight = info.Width, info.Height
+	attachment.Duration = info.Duration.Milliseconds()
+	attachment.Status = ms.MediaStatusReady
+	return p.ds.UpdateMedia(attachment)
+}
+
+// putFile 保存转码输出文件，转码在后台完成时推文可能已经发布，所以直接持久化保存
+func (p *videoProcessor) putFile(objectKey string, filePath string, contentType string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	return p.oss.PutObject(objectKey, file, stat.Size(), contentType, true)
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/alimy/tryst/cfg"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/pkg/wxpay"
+	"github.com/sirupsen/logrus"
+	"github.com/smartwalle/alipay/v3"
+)
+
+const (
+	_paymentAlipay    = "alipay"
+	_paymentWechatPay = "wechatpay"
+
+	_tradeTypeNative = "native"
+	_tradeTypeH5     = "h5"
+
+	_rechargeSubject      = "PaoPao用户钱包充值"
+	_rechargeTradeSuccess = "TRADE_SUCCESS"
+)
+
+var (
+	_ paymentProvider = (*alipayProvider)(nil)
+	_ paymentProvider = (*wechatPayProvider)(nil)
+)
+
+// paymentProvider 钱包充值的支付渠道
+type paymentProvider interface {
+	// precreate 为充值订单创建支付订单，返回支付二维码内容或跳转链接
+	precreate(recharge *ms.WalletRecharge, req *web.UserRechargeLinkReq) (string, error)
+}
+
+type alipayProvider struct {
+	client *alipay.Client
+}
+
+type wechatPayProvider struct {
+	client    *wxpay.Client
+	notifyURL string
+}
+
+func (p *alipayProvider) precreate(recharge *ms.WalletRecharge, req *web.UserRechargeLinkReq) (string, error) {
+	trade := alipay.TradePreCreate{}
+	trade.OutTradeNo = strconv.FormatInt(recharge.ID, 10)
+	trade.Subject = _rechargeSubject
+	trade.TotalAmount = fmt.Sprintf("%.2f", float64(recharge.Amount)/100.0)
+	trade.NotifyURL = "https://" + req.Host + "/v1/alipay/notify"
+	rsp, err := p.client.TradePreCreate(trade)
+	if err != nil {
+		return "", err
+	}
+	if rsp.Code != alipay.CodeSuccess {
+		return "", fmt.Errorf("alipay trade precreate failed: %s %s", rsp.Code, rsp.Msg)
+	}
+	return rsp.QRCode, nil
+}
+
+// precreate 默认创建Native支付订单返回二维码内容，手机浏览器中使用H5支付返回跳转链接
+func (p *wechatPayProvider) precreate(recharge *ms.WalletRecharge, req *web.UserRechargeLinkReq) (string, error) {
+	notifyURL := p.notifyURL
+	if notifyURL == "" {
+		notifyURL = "https://" + req.Host + "/v1/wechatpay/notify"
+	}
+	order := &wxpay.Order{
+		Description: _rechargeSubject,
+		OutTradeNo:  strconv.FormatInt(recharge.ID, 10),
+		NotifyURL:   notifyURL,
+		Amount: &wxpay.Amount{
+			Total: recharge.Amount,
+		},
+	}
+	if req.TradeType == _tradeTypeH5 {
+		order.SceneInfo = &wxpay.SceneInfo{
+			PayerClientIP: req.ClientIP,
+		}
+		return p.client.H5Prepay(order)
+	}
+	return p.client.NativePrepay(order)
+}
+
+// newPaymentProviders 按配置开启的支付渠道，支付宝在前作为默认渠道
+func newPaymentProviders() (names []string, providers map[string]paymentProvider) {
+	providers = make(map[string]paymentProvider)
+	if cfg.If("Alipay") {
+		names = append(names, _paymentAlipay)
+		providers[_paymentAlipay] = &alipayProvider{
+			client: conf.MustAlipayClient(),
+		}
+	}
+	if cfg.If("WechatPay") {
+		names = append(names, _paymentWechatPay)
+		providers[_paymentWechatPay] = &wechatPayProvider{
+			client:    conf.MustWechatPayClient(),
+			notifyURL: conf.WechatPaySetting.NotifyURL,
+		}
+	}
+	return
+}
+
+// handleRechargeNotify 处理支付渠道的支付成功回调，校验支付渠道及金额后入账
+func handleRechargeNotify(ds *base.DaoServant, ctx context.Context, provider string, id int64, tradeNo string, amount int64) error {
+	if err := ds.Redis.SetRechargeStatus(ctx, tradeNo); err != nil {
+		// 相同的回调正在处理中
+		return nil
+	}
+	defer ds.Redis.DelRechargeStatus(ctx, tradeNo)
+	recharge, err := ds.Ds.GetRechargeByID(id)
+	if err != nil {
+		logrus.Errorf("GetRechargeByID id:%d err: %s", id, err)
+		return web.ErrRechargeNotifyError
+	}
+	if recharge.TradeStatus == _rechargeTradeSuccess {
+		return nil
+	}
+	if recharge.Provider != provider || recharge.Amount != amount {
+		logrus.Errorf("recharge notify mismatch id:%d provider:%s/%s amount:%d/%d", id, recharge.Provider, provider, recharge.Amount, amount)
+		return web.ErrRechargeNotifyError
+	}
+	// 标记为已付款
+	if err = ds.Ds.HandleRechargeSuccess(recharge, tradeNo); err != nil {
+		logrus.Errorf("HandleRechargeSuccess id:%d err: %s", id, err)
+		return web.ErrRechargeNotifyError
+	}
+	return nil
+}
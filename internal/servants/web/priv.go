@@ -5,8 +5,13 @@
 package web
 
 import (
-	"image"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,6 +27,7 @@ import (
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
 	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/picture"
 	"github.com/rocboss/paopao-ce/pkg/utils"
 	"github.com/rocboss/paopao-ce/pkg/xerror"
 	"github.com/sirupsen/logrus"
@@ -57,6 +63,20 @@ func (s *privChain) ChainCreateTweet() (res gin.HandlersChain) {
 	return
 }
 
+func (s *privChain) ChainEditTweet() (res gin.HandlersChain) {
+	if cfg.If("UseAuditHook") {
+		res = gin.HandlersChain{chain.AuditHook()}
+	}
+	return
+}
+
+func (s *privChain) ChainPublishDraft() (res gin.HandlersChain) {
+	if cfg.If("UseAuditHook") {
+		res = gin.HandlersChain{chain.AuditHook()}
+	}
+	return
+}
+
 func (s *privSrv) Chain() gin.HandlersChain {
 	return gin.HandlersChain{chain.JWT(), chain.Priv()}
 }
@@ -135,58 +155,441 @@ func (s *privSrv) PinTopic(req *web.PinTopicReq) (*web.PinTopicResp, error) {
 	}, nil
 }
 
+// UpdateTopic 更新话题描述及封面，话题创建者、主持人及管理员可操作
+func (s *privSrv) UpdateTopic(req *web.UpdateTopicReq) error {
+	topic, xerr := s.manageableTopic(req.User, req.TopicId, true)
+	if xerr != nil {
+		return xerr
+	}
+	if req.Cover != "" && req.Cover != topic.Cover {
+		if err := s.Ds.CheckAttachment(req.Cover); err != nil {
+			logrus.Errorf("Ds.CheckAttachment failed: %s", err)
+			return xerror.InvalidParams
+		}
+		if err := s.oss.PersistObject(s.oss.ObjectKey(req.Cover)); err != nil {
+			logrus.Errorf("Ds.UpdateTopic persist object failed: %s", err)
+			return xerror.ServerError
+		}
+	}
+	if err := s.Ds.UpdateTopic(topic.ID, req.Description, req.Cover); err != nil {
+		logrus.Errorf("user(%d) update topic(%d) failed: %s", req.User.ID, topic.ID, err)
+		return web.ErrUpdateTopicFailed
+	}
+	return nil
+}
+
+// PinTopicTweet 切换推文在话题内的置顶状态，推文需引用了该话题或其别名
+func (s *privSrv) PinTopicTweet(req *web.PinTopicTweetReq) (*web.PinTopicTweetResp, error) {
+	topic, xerr := s.manageableTopic(req.User, req.TopicId, true)
+	if xerr != nil {
+		return nil, xerr
+	}
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	aliases, err := s.Ds.GetTopicAliases(topic.ID)
+	if err != nil {
+		logrus.Errorf("Ds.GetTopicAliases err: %s", err)
+		return nil, web.ErrPinTopicTweetFailed
+	}
+	if !slices.ContainsFunc(strings.Split(post.Tags, ","), func(tag string) bool {
+		return tag == topic.Tag || slices.Contains(aliases, tag)
+	}) {
+		return nil, web.ErrTweetNotInTopic
+	}
+	pinned, err := s.Ds.PinTopicTweet(topic.ID, post.ID, req.User.ID, conf.AppSetting.MaxTopicPins)
+	if errors.Is(err, cs.ErrTooManyPins) {
+		return nil, web.ErrTooManyTopicPins
+	} else if err != nil {
+		logrus.Errorf("user(%d) pin tweet(%d) in topic(%d) failed: %s", req.User.ID, post.ID, topic.ID, err)
+		return nil, web.ErrPinTopicTweetFailed
+	}
+	resp := &web.PinTopicTweetResp{}
+	if pinned {
+		resp.PinStatus = 1
+	}
+	return resp, nil
+}
+
+// AddTopicModerator 添加话题主持人，话题创建者及管理员可操作
+func (s *privSrv) AddTopicModerator(req *web.TopicModeratorReq) error {
+	topic, xerr := s.manageableTopic(req.User, req.TopicId, false)
+	if xerr != nil {
+		return xerr
+	}
+	if _, err := s.Ds.GetUserByID(req.UserId); err != nil {
+		return web.ErrNoExistUsername
+	}
+	if err := s.Ds.AddTopicModerator(topic.ID, req.UserId); err != nil {
+		logrus.Errorf("add moderator(%d) to topic(%d) failed: %s", req.UserId, topic.ID, err)
+		return web.ErrTopicModeratorFailed
+	}
+	return nil
+}
+
+// RemoveTopicModerator 移除话题主持人，话题创建者及管理员可操作
+func (s *privSrv) RemoveTopicModerator(req *web.TopicModeratorReq) error {
+	topic, xerr := s.manageableTopic(req.User, req.TopicId, false)
+	if xerr != nil {
+		return xerr
+	}
+	if err := s.Ds.RemoveTopicModerator(topic.ID, req.UserId); err != nil {
+		logrus.Errorf("remove moderator(%d) from topic(%d) failed: %s", req.UserId, topic.ID, err)
+		return web.ErrTopicModeratorFailed
+	}
+	return nil
+}
+
+// manageableTopic 获取用户可管理的话题，锁定的话题仅管理员可管理
+func (s *privSrv) manageableTopic(user *ms.User, topicId int64, allowModerator bool) (*cs.TopicInfo, error) {
+	topic, err := s.Ds.GetTopic(topicId)
+	if err != nil {
+		return nil, web.ErrNoExistTopic
+	}
+	switch {
+	case user.IsAdmin:
+		return topic, nil
+	case topic.IsLocked == 1:
+		return nil, web.ErrTopicLocked
+	case topic.UserID == user.ID:
+		return topic, nil
+	case allowModerator && s.Ds.IsTopicModerator(topic.ID, user.ID):
+		return topic, nil
+	}
+	return nil, web.ErrNoPermissionTopic
+}
+
 func (s *privSrv) UploadAttachment(req *web.UploadAttachmentReq) (*web.UploadAttachmentResp, error) {
 	defer req.File.Close()
 
+	if err := checkStorageQuota(s.Ds, req.Uid, req.FileSize); err != nil {
+		return nil, err
+	}
+	// 相同内容已上传过时直接共用已有的对象
+	var checksum string
+	if _enableUploadDedup {
+		checksum = s.uploadChecksum(req)
+		if attachment := s.dedupAttachment(checksum, req); attachment != nil {
+			return uploadAttachmentResp(attachment), nil
+		}
+	}
 	// 生成随机路径
 	randomPath := uuid.Must(uuid.NewV4()).String()
 	ossSavePath := req.UploadType + "/" + generatePath(randomPath[:8]) + "/" + randomPath[9:] + req.FileExt
-	// NOTE: 注意这里将req.File Wrap到一个io.Reader的实例对象中是为了避免下游接口去主动调Close，req.File本身是实现了
-	// io.Closer接口的，有的下游接口会断言传参是否实现了io.Closer接口，如果实现了会主动去调，我们这里因为下文中可能还要继续
-	// 使用req.File所以应避免下游Close，否则会出现潜在的bug，比如这里的场景就是传一个超大的图片(>10MB)可能就会触发bug了。
-	data := io.NopCloser(req.File)
-	objectUrl, err := s.oss.PutObject(ossSavePath, data, req.FileSize, req.ContentType, false)
-	if err != nil {
-		logrus.Errorf("oss.putObject err: %s", err)
-		return nil, web.ErrFileUploadFailed
-	}
-
 	// 构造附件Model
 	attachment := &ms.Attachment{
 		UserID:   req.Uid,
 		FileSize: req.FileSize,
-		Content:  objectUrl,
 		Type:     _uploadAttachmentTypeMap[req.UploadType],
 	}
-	if attachment.Type == ms.AttachmentTypeImage {
-		var src image.Image
-		src, err = imaging.Decode(req.File)
-		if err == nil {
-			attachment.ImgWidth, attachment.ImgHeight = getImageSize(src.Bounds())
-		}
+	var err error
+	if attachment.Type == ms.AttachmentTypeImage && _pictureOptions != nil {
+		err = s.putPicture(attachment, ossSavePath, req)
+	} else {
+		err = s.putAttachment(attachment, ossSavePath, req)
+	}
+	if errors.Is(err, picture.ErrTooLarge) {
+		return nil, web.ErrFileInvalidSize
+	} else if errors.Is(err, web.ErrInvalidPicture) {
+		return nil, web.ErrInvalidPicture
+	} else if err != nil {
+		logrus.Errorf("oss.putObject err: %s", err)
+		return nil, web.ErrFileUploadFailed
+	}
+	if attachment.Type == ms.AttachmentTypeVideo && _videoProcessor != nil {
+		s.spoolVideo(attachment, ossSavePath, req)
 	}
 	attachment.ID, err = s.Ds.CreateAttachment(attachment)
 	if err != nil {
 		logrus.Errorf("Ds.CreateAttachment err: %s", err)
 		return nil, web.ErrFileUploadFailed
 	}
+	if checksum != "" {
+		blob := &ms.ObjectBlob{
+			Sha256:     checksum,
+			UploadType: req.UploadType,
+			Content:    attachment.Content,
+			FileSize:   attachment.FileSize,
+			RefCount:   1,
+		}
+		if _, err = s.Ds.CreateObjectBlob(blob); err != nil {
+			logrus.Errorf("Ds.CreateObjectBlob err: %s", err)
+		}
+	}
+	return uploadAttachmentResp(attachment), nil
+}
+
+// uploadChecksum 计算上传内容的SHA256，失败时返回空字符串即不去重
+func (s *privSrv) uploadChecksum(req *web.UploadAttachmentReq) string {
+	h := sha256.New()
+	_, copyErr := io.Copy(h, req.File)
+	if _, err := req.File.Seek(0, io.SeekStart); err != nil {
+		logrus.Errorf("upload checksum seek file failed: %s", err)
+		return ""
+	}
+	if copyErr != nil {
+		logrus.Errorf("upload checksum read file failed: %s", copyErr)
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dedupAttachment 已有相同内容且处理完成的对象时，增加引用计数并为用户创建一份附件记录，否则返回nil
+func (s *privSrv) dedupAttachment(checksum string, req *web.UploadAttachmentReq) *ms.Attachment {
+	if checksum == "" {
+		return nil
+	}
+	blob, err := s.Ds.GetObjectBlob(checksum, req.UploadType)
+	if err != nil {
+		return nil
+	}
+	attachments, err := s.Ds.GetAttachmentsByContents([]string{blob.Content})
+	if err != nil || len(attachments) == 0 || attachments[0].Status != ms.MediaStatusReady {
+		return nil
+	}
+	// 共用的对象可能仍在临时目录中尚未被引用
+	if err = s.oss.PersistObject(s.oss.ObjectKey(blob.Content)); err != nil {
+		logrus.Errorf("dedup attachment persist object %s failed: %s", blob.Content, err)
+		return nil
+	}
+	if ok, err := s.Ds.AcquireObjectBlob(blob); err != nil || !ok {
+		return nil
+	}
+	src := attachments[0]
+	attachment := &ms.Attachment{
+		UserID:        req.Uid,
+		FileSize:      src.FileSize,
+		ImgWidth:      src.ImgWidth,
+		ImgHeight:     src.ImgHeight,
+		Type:          src.Type,
+		Content:       src.Content,
+		Blurhash:      src.Blurhash,
+		DominantColor: src.DominantColor,
+		Variants:      src.Variants,
+		Duration:      src.Duration,
+		Poster:        src.Poster,
+		Playlist:      src.Playlist,
+		Status:        src.Status,
+	}
+	if attachment.ID, err = s.Ds.CreateAttachment(attachment); err != nil {
+		logrus.Errorf("dedup attachment Ds.CreateAttachment err: %s", err)
+		if _, err = s.Ds.ReleaseObjectBlobs(req.Uid, []string{blob.Content}); err != nil {
+			logrus.Errorf("dedup attachment release object blob failed: %s", err)
+		}
+		return nil
+	}
+	return attachment
+}
 
+func uploadAttachmentResp(attachment *ms.Attachment) *web.UploadAttachmentResp {
 	return &web.UploadAttachmentResp{
-		UserID:    req.Uid,
-		FileSize:  req.FileSize,
-		ImgWidth:  attachment.ImgWidth,
-		ImgHeight: attachment.ImgHeight,
-		Type:      attachment.Type,
-		Content:   attachment.Content,
+		UserID:        attachment.UserID,
+		FileSize:      attachment.FileSize,
+		ImgWidth:      attachment.ImgWidth,
+		ImgHeight:     attachment.ImgHeight,
+		Type:          attachment.Type,
+		Content:       attachment.Content,
+		Blurhash:      attachment.Blurhash,
+		DominantColor: attachment.DominantColor,
+		Variants:      attachment.ImageVariants(),
+		Status:        attachment.Status,
+	}
+}
+
+// spoolVideo 保存视频到本地等待后台转码，失败时宽松处理为不转码的原视频
+func (s *privSrv) spoolVideo(attachment *ms.Attachment, ossSavePath string, req *web.UploadAttachmentReq) {
+	if _, err := req.File.Seek(0, io.SeekStart); err != nil {
+		logrus.Errorf("spool video seek upload file failed: %s", err)
+		return
+	}
+	if err := _videoProcessor.spool(ossSavePath, req.File); err != nil {
+		logrus.Errorf("spool video %s failed: %s", ossSavePath, err)
+		return
+	}
+	attachment.Status = ms.MediaStatusPending
+}
+
+// putAttachment 原样保存附件，图片只读取宽高
+func (s *privSrv) putAttachment(attachment *ms.Attachment, ossSavePath string, req *web.UploadAttachmentReq) (err error) {
+	// NOTE: 注意这里将req.File Wrap到一个io.Reader的实例对象中是为了避免下游接口去主动调Close，req.File本身是实现了
+	// io.Closer接口的，有的下游接口会断言传参是否实现了io.Closer接口，如果实现了会主动去调，我们这里因为下文中可能还要继续
+	// 使用req.File所以应避免下游Close，否则会出现潜在的bug，比如这里的场景就是传一个超大的图片(>10MB)可能就会触发bug了。
+	data := io.NopCloser(req.File)
+	if attachment.Content, err = s.oss.PutObject(ossSavePath, data, req.FileSize, req.ContentType, false); err != nil {
+		return
+	}
+	if attachment.Type == ms.AttachmentTypeImage {
+		if src, err := imaging.Decode(req.File); err == nil {
+			attachment.ImgWidth, attachment.ImgHeight = getImageSize(src.Bounds())
+		}
+	}
+	return nil
+}
+
+// putPicture 去除图片EXIF信息并修正方向后保存，同时保存缩略图/WebP等版本；
+// 像素超限或无法处理的图片拒绝保存，避免原样保存含有EXIF/GPS信息的图片
+func (s *privSrv) putPicture(attachment *ms.Attachment, ossSavePath string, req *web.UploadAttachmentReq) error {
+	data, err := io.ReadAll(req.File)
+	if err != nil {
+		return err
+	}
+	res, err := picture.Process(data, req.ContentType, _pictureOptions)
+	if errors.Is(err, picture.ErrTooLarge) {
+		return err
+	} else if err != nil {
+		logrus.Warnf("process picture failed so reject it: %s", err)
+		return web.ErrInvalidPicture
+	}
+	attachment.FileSize = int64(len(res.Data))
+	if attachment.Content, err = s.oss.PutObject(ossSavePath, bytes.NewReader(res.Data), attachment.FileSize, res.ContentType, false); err != nil {
+		return err
+	}
+	attachment.ImgWidth, attachment.ImgHeight = res.Width, res.Height
+	attachment.Blurhash, attachment.DominantColor = res.Blurhash, res.DominantColor
+	// 各版本保存在原图旁边，如 xxx.jpeg 的320宽缩略图为 xxx_w320.jpg
+	basePath := strings.TrimSuffix(ossSavePath, req.FileExt)
+	variants := make([]*ms.ImageVariant, 0, len(res.Variants))
+	for _, v := range res.Variants {
+		objectUrl, err := s.oss.PutObject(basePath+"_"+v.Name+v.Ext, bytes.NewReader(v.Data), int64(len(v.Data)), v.ContentType, false)
+		if err != nil {
+			// 宽松处理，缺少的版本前端会回退使用原图
+			logrus.Errorf("put picture variant %s failed: %s", v.Name, err)
+			continue
+		}
+		variants = append(variants, &ms.ImageVariant{
+			Name:        v.Name,
+			Width:       v.Width,
+			Height:      v.Height,
+			ContentType: v.ContentType,
+			Content:     objectUrl,
+		})
+	}
+	attachment.SetVariants(variants)
+	return nil
+}
+
+func (s *privSrv) CreateUpload(req *web.CreateUploadReq) (*web.CreateUploadResp, error) {
+	if _uploader == nil {
+		return nil, web.ErrNotImplemented
+	}
+	// 提前检查配额，避免上传完成后才发现超出
+	if err := checkStorageQuota(s.Ds, req.Uid, req.FileSize); err != nil {
+		return nil, err
+	}
+	session, err := _uploader.create(req)
+	if err != nil {
+		return nil, err
+	}
+	return &web.CreateUploadResp{
+		ID:           session.ID,
+		FileSize:     session.FileSize,
+		Offset:       session.ReceivedSize,
+		MaxChunkSize: _uploader.maxChunkSize,
+		ExpiredOn:    session.ExpiredOn,
+	}, nil
+}
+
+func (s *privSrv) UploadChunk(req *web.UploadChunkReq) (*web.UploadChunkResp, error) {
+	if _uploader == nil {
+		return nil, web.ErrNotImplemented
+	}
+	if !_uploader.acquire(req.ID) {
+		return nil, web.ErrUploadSessionBusy
+	}
+	defer _uploader.release(req.ID)
+	session, err := _uploader.session(req.Uid, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = _uploader.write(session, req.Offset, req.Body); err != nil {
+		return nil, err
+	}
+	return &web.UploadChunkResp{
+		Offset:    session.ReceivedSize,
+		ExpiredOn: session.ExpiredOn,
 	}, nil
 }
 
+func (s *privSrv) UploadProgress(req *web.UploadProgressReq) (*web.UploadProgressResp, error) {
+	if _uploader == nil {
+		return nil, web.ErrNotImplemented
+	}
+	session, err := _uploader.session(req.Uid, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &web.UploadProgressResp{
+		FileSize:  session.FileSize,
+		Offset:    session.ReceivedSize,
+		ExpiredOn: session.ExpiredOn,
+	}, nil
+}
+
+// CompleteUpload 将接收完整的文件按普通上传的流程保存为附件，S3/MinIO等后端对大文件会自动使用分片上传
+func (s *privSrv) CompleteUpload(req *web.CompleteUploadReq) (*web.UploadAttachmentResp, error) {
+	if _uploader == nil {
+		return nil, web.ErrNotImplemented
+	}
+	if !_uploader.acquire(req.ID) {
+		return nil, web.ErrUploadSessionBusy
+	}
+	defer _uploader.release(req.ID)
+	session, err := _uploader.session(req.Uid, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := _uploader.open(session)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.UploadAttachment(&web.UploadAttachmentReq{
+		SimpleInfo: web.SimpleInfo{
+			Uid: req.Uid,
+		},
+		UploadType:  session.UploadType,
+		ContentType: session.ContentType,
+		File:        file,
+		FileSize:    session.FileSize,
+		FileExt:     session.FileExt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = _uploader.remove(session); err != nil {
+		logrus.Warnf("remove completed upload session %d failed: %s", session.ID, err)
+	}
+	return resp, nil
+}
+
+func (s *privSrv) CancelUpload(req *web.CancelUploadReq) error {
+	if _uploader == nil {
+		return web.ErrNotImplemented
+	}
+	if !_uploader.acquire(req.ID) {
+		return web.ErrUploadSessionBusy
+	}
+	defer _uploader.release(req.ID)
+	session, err := _uploader.session(req.Uid, req.ID)
+	if err != nil {
+		return err
+	}
+	if err = _uploader.remove(session); err != nil {
+		logrus.Errorf("remove upload session %d failed: %s", session.ID, err)
+		return web.ErrFileUploadFailed
+	}
+	return nil
+}
+
 func (s *privSrv) DownloadAttachmentPrecheck(req *web.DownloadAttachmentPrecheckReq) (*web.DownloadAttachmentPrecheckResp, error) {
 	content, err := s.Ds.GetPostContentByID(req.ContentID)
 	if err != nil {
 		logrus.Errorf("Ds.GetPostContentByID err: %s", err)
 		return nil, web.ErrInvalidDownloadReq
 	}
+	if err = s.checkContentChargePermission(req.User, content); err != nil {
+		return nil, err
+	}
 	resp := &web.DownloadAttachmentPrecheckResp{Paid: true}
 	if content.Type == ms.ContentTypeChargeAttachment {
 		tweet, err := s.GetTweetBy(content.PostID)
@@ -210,6 +613,9 @@ func (s *privSrv) DownloadAttachment(req *web.DownloadAttachmentReq) (*web.Downl
 		logrus.Errorf("s.GetPostContentByID err: %v", err)
 		return nil, web.ErrInvalidDownloadReq
 	}
+	if err = s.checkContentChargePermission(req.User, content); err != nil {
+		return nil, err
+	}
 	// 收费附件
 	if content.Type == ms.ContentTypeChargeAttachment {
 		post, err := s.GetTweetBy(content.PostID)
@@ -248,11 +654,491 @@ func (s *privSrv) DownloadAttachment(req *web.DownloadAttachmentReq) (*web.Downl
 	}, nil
 }
 
-func (s *privSrv) CreateTweet(req *web.CreateTweetReq) (_ *web.CreateTweetResp, xerr error) {
+func (s *privSrv) RequestAttachmentRefund(req *web.RequestAttachmentRefundReq) (*web.RequestAttachmentRefundResp, error) {
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	bill, err := s.Ds.GetPostAttatchmentBill(post.ID, req.User.ID)
+	if err != nil {
+		return nil, web.ErrNoExistAttachmentBill
+	}
+	if time.Now().Unix()-bill.CreatedOn > conf.AppSetting.AttachmentRefundDays*86400 {
+		return nil, web.ErrRefundExpired
+	}
+	if refund, err := s.Ds.GetBillAttachmentRefund(bill.ID); err != nil {
+		logrus.Errorf("Ds.GetBillAttachmentRefund err: %s", err)
+		return nil, web.ErrCreateRefundFailed
+	} else if refund != nil {
+		return nil, web.ErrRefundExisted
+	}
+	refund, err := s.Ds.CreateAttachmentRefund(&ms.AttachmentRefund{
+		BillID:   bill.ID,
+		PostID:   post.ID,
+		UserID:   req.User.ID,
+		AuthorID: post.UserID,
+		Amount:   bill.PaidAmount,
+		Reason:   req.Reason,
+		Status:   ms.RefundStatusPending,
+	})
+	if err != nil {
+		logrus.Errorf("Ds.CreateAttachmentRefund err: %s", err)
+		return nil, web.ErrCreateRefundFailed
+	}
+	// 通知作者处理
+	onCreateMessageEvent(&ms.Message{
+		SenderUserID:   req.User.ID,
+		ReceiverUserID: post.UserID,
+		Type:           ms.MsgTypeSystem,
+		Brief:          fmt.Sprintf("申请退还附件费用%.2f元", float64(refund.Amount)/100),
+		Content:        refund.Reason,
+		PostID:         post.ID,
+	})
+	return (*web.RequestAttachmentRefundResp)(refund), nil
+}
+
+func (s *privSrv) DisputeAttachmentRefund(req *web.DisputeAttachmentRefundReq) error {
+	refund, err := s.Ds.GetAttachmentRefund(req.ID)
+	if err != nil || refund.UserID != req.User.ID {
+		return web.ErrNoExistRefund
+	}
+	// 仅作者拒绝的退款可以申诉，管理员的处理结果为最终结果
+	if refund.Status != ms.RefundStatusRejected || refund.ReviewerID != refund.AuthorID {
+		return web.ErrRefundStatus
+	}
+	refund.Status = ms.RefundStatusDisputed
+	if req.Reason != "" {
+		refund.Reason = req.Reason
+	}
+	if ok, err := s.Ds.UpdateAttachmentRefundStatus(refund, ms.RefundStatusRejected); err != nil {
+		logrus.Errorf("Ds.UpdateAttachmentRefundStatus id:%d err: %s", refund.ID, err)
+		return web.ErrRefundFailed
+	} else if !ok {
+		return web.ErrRefundStatus
+	}
+	return nil
+}
+
+func (s *privSrv) ReviewAttachmentRefund(req *web.ReviewAttachmentRefundReq) error {
+	refund, err := s.Ds.GetAttachmentRefund(req.ID)
+	if err != nil || refund.AuthorID != req.User.ID {
+		return web.ErrNoExistRefund
+	}
+	if refund.Status != ms.RefundStatusPending {
+		return web.ErrRefundStatus
+	}
+	brief := "你的附件退款申请被作者拒绝，如有异议可以申诉"
+	if req.Approve {
+		brief = fmt.Sprintf("你的附件退款申请已通过，%.2f元已退回钱包", float64(refund.Amount)/100)
+	}
+	return reviewAttachmentRefund(s.Ds, refund, req, brief)
+}
+
+func (s *privSrv) UserAttachmentRefunds(req *web.UserAttachmentRefundsReq) (*web.UserAttachmentRefundsResp, error) {
+	userId, authorId := req.User.ID, int64(0)
+	if req.Style == "author" {
+		userId, authorId = 0, req.User.ID
+	}
+	refunds, total, err := s.Ds.ListAttachmentRefunds(userId, authorId, req.Status, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListAttachmentRefunds err: %s", err)
+		return nil, web.ErrGetRefundsFailed
+	}
+	resp := base.PageRespFrom(refunds, req.Page, req.PageSize, total)
+	return (*web.UserAttachmentRefundsResp)(resp), nil
+}
+
+func (s *privSrv) CreateWithdrawal(req *web.CreateWithdrawalReq) (*web.CreateWithdrawalResp, error) {
+	setting := conf.WithdrawalSetting
+	fee := setting.Fee(req.Amount)
+	if req.Amount < setting.MinAmount || req.Amount <= fee {
+		return nil, web.ErrWithdrawalAmount
+	}
+	if !slices.Contains(setting.AccountTypes, req.AccountType) {
+		return nil, web.ErrWithdrawalAccountType
+	}
+	if setting.MaxPending > 0 {
+		if count, err := s.Ds.CountUnpaidWithdrawals(req.Uid); err != nil {
+			logrus.Errorf("Ds.CountUnpaidWithdrawals err: %s", err)
+			return nil, web.ErrCreateWithdrawal
+		} else if count >= setting.MaxPending {
+			return nil, web.ErrTooManyWithdrawals
+		}
+	}
+	withdrawal, err := s.Ds.CreateWithdrawal(&ms.WalletWithdrawal{
+		UserID:      req.Uid,
+		Amount:      req.Amount,
+		Fee:         fee,
+		AccountType: req.AccountType,
+		Account:     req.Account,
+		AccountName: req.AccountName,
+	})
+	if err == cs.ErrNoBalance {
+		return nil, web.ErrInsufficientBalance
+	} else if err != nil {
+		logrus.Errorf("Ds.CreateWithdrawal err: %s", err)
+		return nil, web.ErrCreateWithdrawal
+	}
+	return (*web.CreateWithdrawalResp)(withdrawal), nil
+}
+
+func (s *privSrv) UserWithdrawals(req *web.UserWithdrawalsReq) (*web.UserWithdrawalsResp, error) {
+	withdrawals, total, err := s.Ds.ListWithdrawals(req.Uid, -1, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListWithdrawals err: %s", err)
+		return nil, web.ErrGetWithdrawalsFailed
+	}
+	resp := base.PageRespFrom(withdrawals, req.Page, req.PageSize, total)
+	return (*web.UserWithdrawalsResp)(resp), nil
+}
+
+func (s *privSrv) CancelWithdrawal(req *web.CancelWithdrawalReq) error {
+	withdrawal, err := s.Ds.GetWithdrawal(req.ID)
+	if err != nil || withdrawal.UserID != req.Uid {
+		return web.ErrNoExistWithdrawal
+	}
+	if withdrawal.Status != ms.WithdrawalStatusPending {
+		return web.ErrWithdrawalStatus
+	}
+	withdrawal.Status = ms.WithdrawalStatusCanceled
+	ok, err := s.Ds.UpdateWithdrawalStatus(withdrawal, ms.WithdrawalStatusPending)
+	if err != nil {
+		logrus.Errorf("Ds.UpdateWithdrawalStatus err: %s", err)
+		return web.ErrUpdateWithdrawal
+	} else if !ok {
+		return web.ErrWithdrawalStatus
+	}
+	return nil
+}
+
+func (s *privSrv) CreateTweet(req *web.CreateTweetReq) (*web.CreateTweetResp, error) {
+	return s.createTweet(req, false)
+}
+
+func (s *privSrv) UpdateScheduledTweet(req *web.UpdateScheduledTweetReq) (*web.UpdateScheduledTweetResp, error) {
+	if req.PublishAt <= time.Now().Unix() {
+		return nil, web.ErrInvalidPublishAt
+	}
+	schedule, err := s.pendingScheduledTweet(req.User, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	oldContents := mediaContentsFrom(schedule.ContentItems())
+	contents, err := persistMediaContents(s.oss, req.Contents)
+	if err != nil {
+		return nil, web.ErrUpdateScheduledFailed
+	}
+	if err = s.fillScheduledTweet(schedule, req.Contents, req.Tags, req.Users, req.AttachmentPrice, req.Visibility); err != nil {
+		return nil, web.ErrUpdateScheduledFailed
+	}
+	schedule.PublishAt = req.PublishAt
+	ok, err := s.Ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPending)
+	if err != nil {
+		logrus.Errorf("Ds.UpdateScheduledTweet err: %s", err)
+		return nil, web.ErrUpdateScheduledFailed
+	} else if !ok {
+		// 已被发布任务领取或已取消，仅删除本次新持久化的媒体内容
+		releaseOssObjects(s.oss, req.User.ID, excludeStrings(contents, oldContents))
+		return nil, web.ErrScheduledNotPending
+	}
+	// 删除修改后不再使用的媒体内容
+	releaseOssObjects(s.oss, req.User.ID, excludeStrings(oldContents, contents))
+	res := schedule.Format()
+	res.User = req.User.Format()
+	return (*web.UpdateScheduledTweetResp)(res), nil
+}
+
+func (s *privSrv) CancelScheduledTweet(req *web.CancelScheduledTweetReq) error {
+	schedule, err := s.pendingScheduledTweet(req.User, req.ID)
+	if err != nil {
+		return err
+	}
+	schedule.Status = ms.PostScheduleStatusCanceled
+	ok, err := s.Ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPending)
+	if err != nil {
+		logrus.Errorf("Ds.UpdateScheduledTweet err: %s", err)
+		return web.ErrCancelScheduledFailed
+	} else if !ok {
+		// 已被发布任务领取时媒体内容归属于发布的推文，不能删除
+		return web.ErrScheduledNotPending
+	}
+	releaseOssObjects(s.oss, schedule.UserID, mediaContentsFrom(schedule.ContentItems()))
+	return nil
+}
+
+func (s *privSrv) CreateDraft(req *web.CreateDraftReq) (*web.CreateDraftResp, error) {
+	// 持久化草稿引用的媒体内容，避免被当作临时对象过期清理
+	if _, err := persistMediaContents(s.oss, req.Contents); err != nil {
+		return nil, web.ErrSaveDraftFailed
+	}
+	draft := &ms.PostDraft{
+		UserID: req.User.ID,
+	}
+	if err := s.fillDraft(draft, req.Contents, req.Tags, req.Users, req.AttachmentPrice, req.Visibility); err != nil {
+		return nil, web.ErrSaveDraftFailed
+	}
+	draft, err := s.Ds.CreateDraft(draft)
+	if err != nil {
+		logrus.Errorf("Ds.CreateDraft err: %s", err)
+		return nil, web.ErrSaveDraftFailed
+	}
+	return (*web.CreateDraftResp)(draft.Format()), nil
+}
+
+func (s *privSrv) UpdateDraft(req *web.UpdateDraftReq) (*web.UpdateDraftResp, error) {
+	draft, err := s.userDraft(req.User, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	oldContents := mediaContentsFrom(draft.ContentItems())
+	contents, err := persistMediaContents(s.oss, req.Contents)
+	if err != nil {
+		return nil, web.ErrSaveDraftFailed
+	}
+	if err = s.fillDraft(draft, req.Contents, req.Tags, req.Users, req.AttachmentPrice, req.Visibility); err != nil {
+		return nil, web.ErrSaveDraftFailed
+	}
+	if err = s.Ds.UpdateDraft(draft); err != nil {
+		logrus.Errorf("Ds.UpdateDraft err: %s", err)
+		return nil, web.ErrSaveDraftFailed
+	}
+	// 删除草稿中不再引用的媒体内容
+	releaseOssObjects(s.oss, draft.UserID, excludeStrings(oldContents, contents))
+	return (*web.UpdateDraftResp)(draft.Format()), nil
+}
+
+func (s *privSrv) DeleteDraft(req *web.DeleteDraftReq) error {
+	draft, err := s.userDraft(req.User, req.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.Ds.DeleteDraft(draft); err != nil {
+		logrus.Errorf("Ds.DeleteDraft err: %s", err)
+		return web.ErrDeleteDraftFailed
+	}
+	releaseOssObjects(s.oss, draft.UserID, mediaContentsFrom(draft.ContentItems()))
+	return nil
+}
+
+func (s *privSrv) ListDrafts(req *web.ListDraftsReq) (*web.ListDraftsResp, error) {
+	drafts, total, err := s.Ds.ListUserDrafts(req.UserId, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListUserDrafts err: %s", err)
+		return nil, web.ErrGetDraftsFailed
+	}
+	items := make([]*ms.PostDraftFormated, 0, len(drafts))
+	for _, draft := range drafts {
+		items = append(items, draft.Format())
+	}
+	resp := base.PageRespFrom(items, req.Page, req.PageSize, total)
+	return (*web.ListDraftsResp)(resp), nil
+}
+
+func (s *privSrv) PublishDraft(req *web.PublishDraftReq) (*web.CreateTweetResp, error) {
+	draft, err := s.userDraft(req.User, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.createTweet(&web.CreateTweetReq{
+		BaseInfo:        req.BaseInfo,
+		Contents:        contentItemsFrom(draft.ContentItems()),
+		Tags:            draft.TagList(),
+		Users:           draft.UserList(),
+		AttachmentPrice: draft.AttachmentPrice,
+		Visibility:      web.TweetVisibleType(draft.Visibility.ToOutValue()),
+		PublishAt:       req.PublishAt,
+		ClientIP:        req.ClientIP,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	// 草稿已发布，媒体内容转由推文引用，仅删除草稿记录
+	if err = s.Ds.DeleteDraft(draft); err != nil {
+		logrus.Errorf("Ds.DeleteDraft err: %s", err)
+	}
+	return resp, nil
+}
+
+// userDraft 获取当前用户的草稿
+func (s *privSrv) userDraft(user *ms.User, id int64) (*ms.PostDraft, error) {
+	draft, err := s.Ds.GetDraft(id)
+	if err != nil {
+		logrus.Errorf("Ds.GetDraft err: %s", err)
+		return nil, web.ErrGetDraftFailed
+	}
+	if user == nil || draft.UserID != user.ID {
+		return nil, web.ErrNoPermission
+	}
+	return draft, nil
+}
+
+// publishScheduledTweet 发布到期的定时推文
+func (s *privSrv) publishScheduledTweet(schedule *ms.PostSchedule) error {
+	user, err := s.Ds.GetUserByID(schedule.UserID)
+	if err != nil {
+		return err
+	}
+	post, err := s.publishTweet(&web.CreateTweetReq{
+		BaseInfo: web.BaseInfo{
+			User: user,
+		},
+		Contents:        contentItemsFrom(schedule.ContentItems()),
+		Tags:            schedule.TagList(),
+		Users:           schedule.UserList(),
+		AttachmentPrice: schedule.AttachmentPrice,
+		Visibility:      web.TweetVisibleType(schedule.Visibility.ToOutValue()),
+		ClientIP:        schedule.IP,
+	}, false)
+	if err != nil {
+		return err
+	}
+	schedule.Status, schedule.PostID = ms.PostScheduleStatusPublished, post.ID
+	if ok, err := s.Ds.UpdateScheduledTweet(schedule, ms.PostScheduleStatusPublishing); err != nil {
+		return err
+	} else if !ok {
+		logrus.Warnf("scheduled tweet %d published as post %d but no longer publishing", schedule.ID, post.ID)
+	}
+	return nil
+}
+
+// createTweet 发布推文或创建定时推文，keepMedia表示发布失败时保留已持久化的媒体内容
+func (s *privSrv) createTweet(req *web.CreateTweetReq, keepMedia bool) (*web.CreateTweetResp, error) {
+	// 定时发布的推文先隐藏存储，到期后由任务发布
+	if req.PublishAt > time.Now().Unix() {
+		schedule, err := s.createScheduledTweet(req)
+		if err != nil {
+			return nil, err
+		}
+		return &web.CreateTweetResp{
+			Schedule: schedule,
+		}, nil
+	}
+	post, err := s.publishTweet(req, keepMedia)
+	if err != nil {
+		return nil, err
+	}
+	return &web.CreateTweetResp{
+		PostFormated: post,
+	}, nil
+}
+
+func (s *privSrv) createScheduledTweet(req *web.CreateTweetReq) (*ms.PostScheduleFormated, error) {
+	// 提前持久化媒体内容，避免在发布前被当作临时对象清理
+	if _, err := persistMediaContents(s.oss, req.Contents); err != nil {
+		return nil, web.ErrCreateScheduledFailed
+	}
+	schedule := &ms.PostSchedule{
+		UserID:    req.User.ID,
+		IP:        req.ClientIP,
+		IPLoc:     utils.GetIPLoc(req.ClientIP),
+		PublishAt: req.PublishAt,
+	}
+	if err := s.fillScheduledTweet(schedule, req.Contents, req.Tags, req.Users, req.AttachmentPrice, req.Visibility); err != nil {
+		return nil, web.ErrCreateScheduledFailed
+	}
+	schedule, err := s.Ds.CreateScheduledTweet(schedule)
+	if err != nil {
+		logrus.Errorf("Ds.CreateScheduledTweet err: %s", err)
+		return nil, web.ErrCreateScheduledFailed
+	}
+	res := schedule.Format()
+	res.User = req.User.Format()
+	return res, nil
+}
+
+func (s *privSrv) fillScheduledTweet(schedule *ms.PostSchedule, contents []*web.PostContentItem, tags []string, users []string, price int64, visibility web.TweetVisibleType) error {
+	if err := schedule.SetContentItems(s.checkedContentItems(contents)); err != nil {
+		logrus.Errorf("schedule.SetContentItems err: %s", err)
+		return err
+	}
+	schedule.Tags = strings.Join(tagsFrom(tags), ",")
+	schedule.Users = strings.Join(tagsFrom(users), ",")
+	schedule.AttachmentPrice = price
+	schedule.Visibility = ms.PostVisibleT(visibility.ToVisibleValue())
+	return nil
+}
+
+func (s *privSrv) fillDraft(draft *ms.PostDraft, contents []*web.PostContentItem, tags []string, users []string, price int64, visibility web.TweetVisibleType) error {
+	if err := draft.SetContentItems(s.checkedContentItems(contents)); err != nil {
+		logrus.Errorf("draft.SetContentItems err: %s", err)
+		return err
+	}
+	draft.Tags = strings.Join(tagsFrom(tags), ",")
+	draft.Users = strings.Join(tagsFrom(users), ",")
+	draft.AttachmentPrice = price
+	draft.Visibility = ms.PostVisibleT(visibility.ToVisibleValue())
+	return nil
+}
+
+// checkedContentItems 过滤掉属性非法的推文内容
+func (s *privSrv) checkedContentItems(contents []*web.PostContentItem) []*ms.PostContentFormated {
+	items := make([]*ms.PostContentFormated, 0, len(contents))
+	for _, item := range contents {
+		if err := item.Check(s.Ds); err != nil {
+			// 属性非法
+			logrus.Infof("contents check err: %s", err)
+			continue
+		}
+		items = append(items, &ms.PostContentFormated{
+			Content: item.Content,
+			Type:    item.Type,
+			Sort:    item.Sort,
+		})
+	}
+	return items
+}
+
+// createPoll 根据投票内容块创建推文投票，返回内容块中保存的投票问题
+func (s *privSrv) createPoll(post *ms.Post, item *web.PostContentItem) (string, error) {
+	content, err := item.PollContent()
+	if err != nil {
+		return "", err
+	}
+	poll := &ms.PostPoll{
+		PostID:    post.ID,
+		UserID:    post.UserID,
+		ExpiredOn: time.Now().Unix() + content.Duration,
+	}
+	if content.Multiple {
+		poll.Multiple = 1
+	}
+	if content.Anonymous {
+		poll.Anonymous = 1
+	}
+	options := make([]*ms.PostPollOption, 0, len(content.Options))
+	for i, option := range content.Options {
+		options = append(options, &ms.PostPollOption{
+			Content: option,
+			Sort:    int64(i),
+		})
+	}
+	if _, err = s.Ds.CreatePoll(poll, options); err != nil {
+		return "", err
+	}
+	return content.Question, nil
+}
+
+// pendingScheduledTweet 获取当前用户待发布的定时推文
+func (s *privSrv) pendingScheduledTweet(user *ms.User, id int64) (*ms.PostSchedule, error) {
+	schedule, err := s.Ds.GetScheduledTweet(id)
+	if err != nil {
+		logrus.Errorf("Ds.GetScheduledTweet err: %s", err)
+		return nil, web.ErrGetScheduledFailed
+	}
+	if err = checkPermision(user, schedule.UserID); err != nil {
+		return nil, err
+	}
+	if schedule.Status != ms.PostScheduleStatusPending {
+		return nil, web.ErrGetScheduledFailed
+	}
+	return schedule, nil
+}
+
+func (s *privSrv) publishTweet(req *web.CreateTweetReq, keepMedia bool) (_ *ms.PostFormated, xerr error) {
 	var mediaContents []string
 	defer func() {
-		if xerr != nil {
-			deleteOssObjects(s.oss, mediaContents)
+		if xerr != nil && !keepMedia {
+			releaseOssObjects(s.oss, req.User.ID, mediaContents)
 		}
 	}()
 
@@ -277,6 +1163,7 @@ func (s *privSrv) CreateTweet(req *web.CreateTweetReq) (_ *web.CreateTweetResp,
 	}
 
 	// 创建推文内容
+	hasPoll := false
 	for _, item := range req.Contents {
 		if err := item.Check(s.Ds); err != nil {
 			// 属性非法
@@ -293,6 +1180,17 @@ func (s *privSrv) CreateTweet(req *web.CreateTweetReq) (_ *web.CreateTweetResp,
 			Type:    item.Type,
 			Sort:    item.Sort,
 		}
+		if item.Type == ms.ContentTypePoll {
+			// 每条推文最多一个投票
+			if hasPoll {
+				continue
+			}
+			if postContent.Content, err = s.createPoll(post, item); err != nil {
+				logrus.Errorf("createPoll err: %s", err)
+				return nil, web.ErrCreatePostFailed
+			}
+			hasPoll = true
+		}
 		if _, err = s.Ds.CreatePostContent(postContent); err != nil {
 			logrus.Infof("Ds.CreatePostContent err: %s", err)
 			return nil, web.ErrCreateCommentFailed
@@ -328,11 +1226,227 @@ func (s *privSrv) CreateTweet(req *web.CreateTweetReq) (_ *web.CreateTweetResp,
 		logrus.Infof("Ds.RevampPosts err: %s", err)
 		return nil, web.ErrCreatePostFailed
 	}
+	if err = s.PrepareTweetPolls(req.User.ID, formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetPolls err: %s", err)
+	}
+	if err = s.PrepareTweetLinks(formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetLinks err: %s", err)
+	}
+	if err = s.PrepareTweetMedia(formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetMedia err: %s", err)
+	}
+	onUnfurlLinksEvent(linksFrom(req.Contents))
 	// 缓存处理
 	// TODO: 缓存逻辑合并处理
 	onTrendsActionEvent(_trendsActionCreateTweet, req.User.ID)
 	onTweetActionEvent(_tweetActionCreate, req.User.ID, req.User.Username)
-	return (*web.CreateTweetResp)(formatedPosts[0]), nil
+	return formatedPosts[0], nil
+}
+
+func (s *privSrv) EditTweet(req *web.EditTweetReq) (_ *web.EditTweetResp, xerr error) {
+	post, err := s.Ds.GetPostByID(req.ID)
+	if err != nil {
+		logrus.Errorf("Ds.GetPostByID err: %s", err)
+		return nil, web.ErrGetPostFailed
+	}
+	if req.User == nil || post.UserID != req.User.ID {
+		return nil, web.ErrNoPermission
+	}
+	if window := conf.AppSetting.TweetEditWindow; window <= 0 || time.Now().Unix()-post.CreatedOn > window {
+		return nil, web.ErrEditPostExpired
+	}
+	oldContents, err := s.Ds.GetPostContentsByIDs([]int64{post.ID})
+	if err != nil {
+		logrus.Errorf("Ds.GetPostContentsByIDs err: %s", err)
+		return nil, web.ErrEditPostFailed
+	}
+	oldText, oldItems := "", make([]*ms.PostContentFormated, 0, len(oldContents))
+	for _, content := range oldContents {
+		oldItems = append(oldItems, content.Format())
+		if content.Type == ms.ContentTypeText || content.Type == ms.ContentTypeTitle {
+			oldText += content.Content + "\n"
+		}
+	}
+	var mediaContents []string
+	oldMedia := mediaContentsFrom(oldItems)
+	defer func() {
+		// 编辑失败时仅清理新引入的媒体内容，旧内容仍被推文或修订历史引用
+		if xerr != nil {
+			releaseOssObjects(s.oss, req.User.ID, excludeStrings(mediaContents, oldMedia))
+		}
+	}()
+	if mediaContents, err = persistMediaContents(s.oss, req.Contents); err != nil {
+		return nil, web.ErrEditPostFailed
+	}
+	contents := make([]*ms.PostContent, 0, len(req.Contents))
+	for _, item := range req.Contents {
+		// 投票发布后不可编辑，沿用原有的投票内容块
+		if item.Type == ms.ContentTypePoll {
+			continue
+		}
+		if err := item.Check(s.Ds); err != nil {
+			// 属性非法
+			logrus.Infof("contents check err: %s", err)
+			continue
+		}
+		if item.Type == ms.ContentTypeAttachment && post.AttachmentPrice > 0 {
+			item.Type = ms.ContentTypeChargeAttachment
+		}
+		contents = append(contents, &ms.PostContent{
+			Content: item.Content,
+			Type:    item.Type,
+			Sort:    item.Sort,
+		})
+	}
+	for _, content := range oldContents {
+		if content.Type == ms.ContentTypePoll {
+			contents = append(contents, &ms.PostContent{
+				Content: content.Content,
+				Type:    content.Type,
+				Sort:    content.Sort,
+			})
+		}
+	}
+	oldTags := strings.Split(post.Tags, ",")
+	tags := tagsFrom(req.Tags)
+	if err = s.Ds.EditPost(post, tags, contents); err != nil {
+		logrus.Errorf("Ds.EditPost err: %s", err)
+		return nil, web.ErrEditPostFailed
+	}
+
+	// 私密推文不处理标签与用户提醒
+	if post.Visibility != core.PostVisitPrivate {
+		// 新增的标签引用计数加一，移除的标签引用计数减一
+		s.Ds.UpsertTags(req.User.ID, excludeStrings(tags, oldTags))
+		if removed := excludeStrings(tagsFrom(oldTags), tags); len(removed) > 0 {
+			if tagInfos, err := s.Ds.TagsByNames(removed); err == nil {
+				ids := make([]int64, 0, len(tagInfos))
+				for _, tag := range tagInfos {
+					ids = append(ids, tag.ID)
+				}
+				s.Ds.DecrTagsById(ids)
+			}
+		}
+		// 仅提醒编辑后新@的用户
+		oldMentions := mentionsFrom(oldText)
+		for _, u := range req.Users {
+			if _, exist := oldMentions[u]; exist {
+				continue
+			}
+			user, err := s.Ds.GetUserByUsername(u)
+			if err != nil || user.ID == req.User.ID {
+				continue
+			}
+			onCreateMessageEvent(&ms.Message{
+				SenderUserID:   req.User.ID,
+				ReceiverUserID: user.ID,
+				Type:           ms.MsgTypePost,
+				Brief:          "在编辑后的泡泡动态中@了你",
+				PostID:         post.ID,
+			})
+		}
+	}
+	// 重建索引
+	s.PushPostToSearch(post)
+	formatedPosts, err := s.Ds.RevampPosts([]*ms.PostFormated{post.Format()})
+	if err != nil {
+		logrus.Infof("Ds.RevampPosts err: %s", err)
+		return nil, web.ErrEditPostFailed
+	}
+	if err = s.PrepareTweetPolls(req.User.ID, formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetPolls err: %s", err)
+	}
+	if err = s.PrepareTweetLinks(formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetLinks err: %s", err)
+	}
+	if err = s.PrepareTweetMedia(formatedPosts); err != nil {
+		logrus.Infof("PrepareTweetMedia err: %s", err)
+	}
+	onUnfurlLinksEvent(linksFrom(req.Contents))
+	return (*web.EditTweetResp)(formatedPosts[0]), nil
+}
+
+func (s *privSrv) TipTweet(req *web.TipTweetReq) (*web.TipTweetResp, error) {
+	if req.Amount < conf.AppSetting.MinTipAmount {
+		return nil, web.ErrTipAmount
+	}
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	if post.UserID == req.User.ID {
+		return nil, web.ErrTipSelf
+	}
+	// 充电可见的动态未充电时也可以充电
+	if post.Visibility != core.PostVisitCharge {
+		if err = checkPostViewPermission(req.User, post, s.Ds); err != nil {
+			return nil, err
+		}
+	}
+	tip, err := s.Ds.TipPost(req.User, post, req.Amount, req.Message, req.RequestId)
+	if err == cs.ErrNoBalance {
+		return nil, web.ErrInsufficientBalance
+	} else if err != nil {
+		logrus.Errorf("Ds.TipPost err: %s", err)
+		return nil, web.ErrTipTweetFailed
+	}
+	// 通知动态作者
+	onCreateMessageEvent(&ms.Message{
+		SenderUserID:   req.User.ID,
+		ReceiverUserID: post.UserID,
+		Type:           ms.MsgTypeSystem,
+		Brief:          fmt.Sprintf("为你的动态充电%.2f元", float64(tip.Amount)/100),
+		Content:        tip.Message,
+		PostID:         post.ID,
+	})
+	item := tip.Format()
+	item.User = req.User.Format()
+	return (*web.TipTweetResp)(item), nil
+}
+
+func (s *privSrv) VotePoll(req *web.VotePollReq) (*web.VotePollResp, error) {
+	post, err := s.Ds.GetPostByID(req.TweetId)
+	if err != nil {
+		return nil, web.ErrGetPostFailed
+	}
+	if err = checkPostViewPermission(req.User, post, s.Ds); err != nil {
+		return nil, err
+	}
+	poll, err := s.Ds.GetPollByPostId(post.ID)
+	if err != nil {
+		logrus.Errorf("Ds.GetPollByPostId err: %s", err)
+		return nil, web.ErrGetPollFailed
+	}
+	if poll.IsClosed(time.Now().Unix()) {
+		return nil, web.ErrPollClosed
+	}
+	optionIds, exists := make([]int64, 0, len(req.OptionIds)), make(map[int64]struct{}, len(req.OptionIds))
+	for _, id := range req.OptionIds {
+		if _, exist := exists[id]; !exist {
+			exists[id] = struct{}{}
+			optionIds = append(optionIds, id)
+		}
+	}
+	if len(optionIds) == 0 || (poll.Multiple == 0 && len(optionIds) > 1) {
+		return nil, web.ErrInvalidPollOptions
+	}
+	if _, err = s.Ds.GetUserPollVote(poll.ID, req.User.ID); err == nil {
+		return nil, web.ErrPollAlreadyVoted
+	}
+	vote := &ms.PostPollVote{
+		PollID: poll.ID,
+		UserID: req.User.ID,
+	}
+	if err = s.Ds.VotePoll(vote, optionIds); err != nil {
+		logrus.Errorf("Ds.VotePoll err: %s", err)
+		return nil, web.ErrVotePollFailed
+	}
+	polls, err := s.Ds.PollsByPostIds([]int64{post.ID})
+	if err != nil || len(polls) == 0 {
+		return nil, web.ErrGetPollFailed
+	}
+	polls[0].Voted = optionIds
+	return (*web.VotePollResp)(polls[0]), nil
 }
 
 func (s *privSrv) DeleteTweet(req *web.DeleteTweetReq) error {
@@ -352,7 +1466,7 @@ func (s *privSrv) DeleteTweet(req *web.DeleteTweetReq) error {
 		logrus.Errorf("Ds.DeletePost delete post failed: %s", err)
 		return web.ErrDeletePostFailed
 	}
-	// 删除推文的媒体内容
+	// 删除推文不再被引用的媒体内容，引用计数已在删除推文时释放
 	deleteOssObjects(s.oss, mediaContents)
 	// 删除索引
 	s.DeleteSearchPost(post)
@@ -364,6 +1478,8 @@ func (s *privSrv) DeleteTweet(req *web.DeleteTweetReq) error {
 	// TODO: 缓存逻辑合并处理
 	onTrendsActionEvent(_trendsActionDeleteTweet, req.User.ID)
 	onTweetActionEvent(_tweetActionDelete, req.User.ID, req.User.Username)
+	// 退还退款期限内的附件费用
+	onRefundAttachmentsEvent(post)
 	return nil
 }
 
@@ -400,6 +1516,9 @@ func (s *privSrv) CreateCommentReply(req *web.CreateCommentReplyReq) (_ *web.Cre
 	if post, comment, atUserID, err = s.createPostPreHandler(req.CommentID, req.Uid, req.AtUserID); err != nil {
 		return nil, web.ErrCreateReplyFailed
 	}
+	if err = checkPostSubscribePermission(req.Uid, post, s.Ds); err != nil {
+		return nil, err
+	}
 
 	// 创建评论
 	reply := &ms.CommentReply{
@@ -521,7 +1640,7 @@ func (s *privSrv) CreateComment(req *web.CreateCommentReq) (_ *web.CreateComment
 	)
 	defer func() {
 		if xerr != nil {
-			deleteOssObjects(s.oss, mediaContents)
+			releaseOssObjects(s.oss, req.Uid, mediaContents)
 		}
 	}()
 
@@ -535,6 +1654,9 @@ func (s *privSrv) CreateComment(req *web.CreateCommentReq) (_ *web.CreateComment
 		logrus.Errorf("Ds.GetPostByID err:%s", err)
 		return nil, xerror.ServerError
 	}
+	if err = checkPostSubscribePermission(req.Uid, post, s.Ds); err != nil {
+		return nil, err
+	}
 	if post.CommentCount >= conf.AppSetting.MaxCommentCount {
 		return nil, web.ErrMaxCommentCount
 	}
@@ -891,13 +2013,25 @@ func (s *privSrv) buyPostAttachment(post *ms.Post, user *ms.User) error {
 		return web.ErrInsuffientDownloadMoney
 	}
 	// 执行购买
-	if err := s.Ds.HandlePostAttachmentBought(post, user); err != nil {
+	if err := s.Ds.HandlePostAttachmentBought(post, user); err == cs.ErrNoBalance {
+		return web.ErrInsuffientDownloadMoney
+	} else if err != nil {
 		logrus.Errorf("Ds.HandlePostAttachmentBought err: %s", err)
 		return xerror.ServerError
 	}
 	return nil
 }
 
+// checkContentChargePermission 充电可见动态的附件仅对达到充电门槛的用户开放下载
+func (s *privSrv) checkContentChargePermission(user *ms.User, content *ms.PostContent) error {
+	post, err := s.Ds.GetPostByID(content.PostID)
+	if err != nil {
+		logrus.Errorf("Ds.GetPostByID err: %s", err)
+		return web.ErrInvalidDownloadReq
+	}
+	return checkPostChargePermission(user, post, s.Ds)
+}
+
 func newPrivSrv(s *base.DaoServant, oss core.ObjectStorageService) api.Priv {
 	return &privSrv{
 		DaoServant: s,
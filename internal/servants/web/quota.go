@@ -0,0 +1,57 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"strconv"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/rocboss/paopao-ce/pkg/xerror"
+	"github.com/sirupsen/logrus"
+)
+
+// storageUsageOf 获取用户的存储使用量及配额，管理员单独设置的配额优先于按角色配置的配额
+func storageUsageOf(ds core.DataService, user *ms.User) (*web.StorageUsageResp, error) {
+	usage, err := ds.GetUserStorageUsage(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &web.StorageUsageResp{
+		UsedSize:  usage.UsedSize,
+		UsedFiles: usage.UsedFiles,
+	}
+	if quota, err := ds.GetUserStorageQuota(user.ID); err == nil {
+		resp.MaxSize, resp.MaxFiles, resp.Custom = quota.MaxSize, quota.MaxFiles, true
+	} else {
+		resp.MaxSize, resp.MaxFiles = conf.StorageQuotaSetting.RoleQuota(user.IsAdmin)
+	}
+	return resp, nil
+}
+
+// checkStorageQuota 检查用户新增一个size大小的文件后是否超出存储配额
+func checkStorageQuota(ds core.DataService, userId int64, size int64) error {
+	if !_enableStorageQuota {
+		return nil
+	}
+	user, err := ds.GetUserByID(userId)
+	if err != nil {
+		return xerror.UnauthorizedAuthNotExist
+	}
+	usage, err := storageUsageOf(ds, user)
+	if err != nil {
+		logrus.Errorf("get user(%d) storage usage failed: %s", userId, err)
+		return web.ErrFileUploadFailed
+	}
+	if usage.MaxSize > 0 && usage.UsedSize+size > usage.MaxSize {
+		return web.ErrStorageQuotaExceeded.WithDetails("剩余空间" + strconv.FormatInt(max(usage.MaxSize-usage.UsedSize, 0)>>20, 10) + "MB")
+	}
+	if usage.MaxFiles > 0 && usage.UsedFiles >= usage.MaxFiles {
+		return web.ErrStorageQuotaExceeded.WithDetails("最多允许" + strconv.FormatInt(usage.MaxFiles, 10) + "个文件")
+	}
+	return nil
+}
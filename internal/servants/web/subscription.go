@@ -0,0 +1,252 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/rocboss/paopao-ce/auto/api/v1"
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/internal/servants/chain"
+	"github.com/rocboss/paopao-ce/pkg/xerror"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ api.Subscription = (*subscriptionSrv)(nil)
+)
+
+type subscriptionSrv struct {
+	api.UnimplementedSubscriptionServant
+	*base.DaoServant
+}
+
+func (s *subscriptionSrv) Chain() gin.HandlersChain {
+	return gin.HandlersChain{chain.JwtLoose()}
+}
+
+func (s *subscriptionSrv) ListSubscriptionTiers(r *web.ListSubscriptionTiersReq) (*web.ListSubscriptionTiersResp, error) {
+	he, err := s.Ds.GetUserByUsername(r.Username)
+	if err != nil {
+		logrus.Errorf("Ds.GetUserByUsername err: %s", err)
+		return nil, web.ErrNoExistUsername
+	}
+	tiers, err := s.Ds.ListSubscriptionTiers(he.ID)
+	if err != nil {
+		logrus.Errorf("Ds.ListSubscriptionTiers err: %s", err)
+		return nil, web.ErrGetSubscriptionsFailed
+	}
+	resp := &web.ListSubscriptionTiersResp{
+		List: tiers,
+	}
+	if r.User != nil && r.User.ID != he.ID {
+		if resp.Subscription, err = s.Ds.GetSubscription(r.User.ID, he.ID); err != nil {
+			logrus.Errorf("Ds.GetSubscription err: %s", err)
+			return nil, web.ErrGetSubscriptionsFailed
+		}
+	}
+	return resp, nil
+}
+
+func (s *subscriptionSrv) CreateSubscriptionTier(r *web.CreateSubscriptionTierReq) (*web.CreateSubscriptionTierResp, error) {
+	if r.User == nil {
+		return nil, xerror.UnauthorizedTokenError
+	}
+	if err := checkSubscriptionPrice(r.Price); err != nil {
+		return nil, err
+	}
+	if count, err := s.Ds.CountSubscriptionTiers(r.User.ID); err != nil {
+		logrus.Errorf("Ds.CountSubscriptionTiers err: %s", err)
+		return nil, web.ErrCreateSubscriptionTier
+	} else if count >= conf.SubscriptionSetting.MaxTiers {
+		return nil, web.ErrTooManySubscriptionTiers
+	}
+	tier, err := s.Ds.CreateSubscriptionTier(&ms.SubscriptionTier{
+		UserID:      r.User.ID,
+		Title:       r.Title,
+		Description: r.Description,
+		Price:       r.Price,
+	})
+	if err != nil {
+		logrus.Errorf("Ds.CreateSubscriptionTier err: %s", err)
+		return nil, web.ErrCreateSubscriptionTier
+	}
+	return (*web.CreateSubscriptionTierResp)(tier), nil
+}
+
+func (s *subscriptionSrv) UpdateSubscriptionTier(r *web.UpdateSubscriptionTierReq) error {
+	if r.User == nil {
+		return xerror.UnauthorizedTokenError
+	}
+	if err := checkSubscriptionPrice(r.Price); err != nil {
+		return err
+	}
+	tier, err := s.ownSubscriptionTier(r.User.ID, r.ID)
+	if err != nil {
+		return err
+	}
+	tier.Title, tier.Description, tier.Price = r.Title, r.Description, r.Price
+	if err = s.Ds.UpdateSubscriptionTier(tier); err != nil {
+		logrus.Errorf("Ds.UpdateSubscriptionTier err: %s", err)
+		return web.ErrUpdateSubscriptionTier
+	}
+	return nil
+}
+
+func (s *subscriptionSrv) DeleteSubscriptionTier(r *web.DeleteSubscriptionTierReq) error {
+	if r.User == nil {
+		return xerror.UnauthorizedTokenError
+	}
+	tier, err := s.ownSubscriptionTier(r.User.ID, r.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.Ds.DeleteSubscriptionTier(tier); err != nil {
+		logrus.Errorf("Ds.DeleteSubscriptionTier err: %s", err)
+		return web.ErrUpdateSubscriptionTier
+	}
+	return nil
+}
+
+func (s *subscriptionSrv) Subscribe(r *web.SubscribeReq) (*web.SubscribeResp, error) {
+	if r.User == nil {
+		return nil, xerror.UnauthorizedTokenError
+	}
+	tier, err := s.Ds.GetSubscriptionTier(r.TierID)
+	if err == cs.ErrNotExist {
+		return nil, web.ErrNoExistSubscriptionTier
+	} else if err != nil {
+		logrus.Errorf("Ds.GetSubscriptionTier err: %s", err)
+		return nil, web.ErrSubscribeFailed
+	}
+	if tier.UserID == r.User.ID {
+		return nil, web.ErrSubscribeSelf
+	}
+	subscription, err := s.Ds.Subscribe(r.User.ID, tier)
+	switch {
+	case err == cs.ErrSubscribed:
+		return nil, web.ErrAlreadySubscribed
+	case err == cs.ErrNoBalance:
+		return nil, web.ErrInsufficientBalance
+	case err != nil:
+		logrus.Errorf("Ds.Subscribe err: %s", err)
+		return nil, web.ErrSubscribeFailed
+	}
+	// 通知创作者
+	onCreateMessageEvent(&ms.Message{
+		SenderUserID:   r.User.ID,
+		ReceiverUserID: tier.UserID,
+		Type:           ms.MsgTypeSystem,
+		Brief:          fmt.Sprintf("订阅了你的「%s」", tier.Title),
+	})
+	return (*web.SubscribeResp)(subscription), nil
+}
+
+func (s *subscriptionSrv) UpdateSubscriptionRenew(r *web.UpdateSubscriptionRenewReq) error {
+	if r.User == nil {
+		return xerror.UnauthorizedTokenError
+	}
+	subscription, err := s.Ds.GetSubscription(r.User.ID, r.CreatorID)
+	if err != nil {
+		logrus.Errorf("Ds.GetSubscription err: %s", err)
+		return web.ErrUpdateSubscription
+	} else if subscription == nil {
+		return web.ErrNoExistSubscription
+	}
+	if err = s.Ds.SetSubscriptionAutoRenew(subscription, r.AutoRenew); err != nil {
+		logrus.Errorf("Ds.SetSubscriptionAutoRenew err: %s", err)
+		return web.ErrUpdateSubscription
+	}
+	return nil
+}
+
+func (s *subscriptionSrv) UserSubscriptions(r *web.UserSubscriptionsReq) (*web.UserSubscriptionsResp, error) {
+	if r.User == nil {
+		return nil, xerror.UnauthorizedTokenError
+	}
+	subscriptions, total, err := s.Ds.ListUserSubscriptions(r.User.ID, (r.Page-1)*r.PageSize, r.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListUserSubscriptions err: %s", err)
+		return nil, web.ErrGetSubscriptionsFailed
+	}
+	items, err := s.formatSubscriptions(subscriptions, func(s *ms.Subscription) int64 { return s.CreatorID })
+	if err != nil {
+		return nil, err
+	}
+	resp := base.PageRespFrom(items, r.Page, r.PageSize, total)
+	return (*web.UserSubscriptionsResp)(resp), nil
+}
+
+func (s *subscriptionSrv) ListSubscribers(r *web.ListSubscribersReq) (*web.ListSubscribersResp, error) {
+	if r.User == nil {
+		return nil, xerror.UnauthorizedTokenError
+	}
+	subscriptions, total, err := s.Ds.ListSubscribers(r.User.ID, (r.Page-1)*r.PageSize, r.PageSize)
+	if err != nil {
+		logrus.Errorf("Ds.ListSubscribers err: %s", err)
+		return nil, web.ErrGetSubscriptionsFailed
+	}
+	items, err := s.formatSubscriptions(subscriptions, func(s *ms.Subscription) int64 { return s.UserID })
+	if err != nil {
+		return nil, err
+	}
+	resp := base.PageRespFrom(items, r.Page, r.PageSize, total)
+	return (*web.ListSubscribersResp)(resp), nil
+}
+
+// ownSubscriptionTier 获取用户自己的订阅档位
+func (s *subscriptionSrv) ownSubscriptionTier(userId int64, id int64) (*ms.SubscriptionTier, error) {
+	tier, err := s.Ds.GetSubscriptionTier(id)
+	if err == cs.ErrNotExist || (err == nil && tier.UserID != userId) {
+		return nil, web.ErrNoExistSubscriptionTier
+	} else if err != nil {
+		logrus.Errorf("Ds.GetSubscriptionTier err: %s", err)
+		return nil, web.ErrUpdateSubscriptionTier
+	}
+	return tier, nil
+}
+
+// formatSubscriptions 附上订阅者或创作者的用户信息
+func (s *subscriptionSrv) formatSubscriptions(subscriptions []*ms.Subscription, userIdOf func(*ms.Subscription) int64) ([]*ms.SubscriptionFormated, error) {
+	userIds := make([]int64, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		userIds = append(userIds, userIdOf(subscription))
+	}
+	users, err := s.Ds.GetUsersByIDs(userIds)
+	if err != nil {
+		logrus.Errorf("Ds.GetUsersByIDs err: %s", err)
+		return nil, web.ErrGetSubscriptionsFailed
+	}
+	userMap := make(map[int64]*ms.UserFormated, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user.Format()
+	}
+	items := make([]*ms.SubscriptionFormated, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		items = append(items, &ms.SubscriptionFormated{
+			Subscription: subscription,
+			User:         userMap[userIdOf(subscription)],
+		})
+	}
+	return items, nil
+}
+
+func checkSubscriptionPrice(price int64) error {
+	if price < conf.SubscriptionSetting.MinPrice || price > conf.SubscriptionSetting.MaxPrice {
+		return web.ErrSubscriptionTierPrice
+	}
+	return nil
+}
+
+func newSubscriptionSrv(s *base.DaoServant) api.Subscription {
+	return &subscriptionSrv{
+		DaoServant: s,
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rocboss/paopao-ce/internal/conf"
+	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/ms"
+	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/sirupsen/logrus"
+)
+
+// resumableUploader 断点续传上传，已接收的内容暂存在本地工作目录，完成后再保存到对象存储
+type resumableUploader struct {
+	ds           core.DataService
+	workDir      string
+	maxChunkSize int64
+	maxSessions  int64
+	expire       time.Duration
+	writing      sync.Map
+}
+
+func newResumableUploader(ds core.DataService) *resumableUploader {
+	s := conf.ResumableUploadSetting
+	return &resumableUploader{
+		ds:           ds,
+		workDir:      s.WorkDir,
+		maxChunkSize: s.MaxChunkSize << 20,
+		maxSessions:  s.MaxSessions,
+		expire:       s.Expire,
+	}
+}
+
+// filePath 上传会话在本地工作目录中的暂存文件路径
+func (u *resumableUploader) filePath(id int64) string {
+	return filepath.Join(u.workDir, strconv.FormatInt(id, 10)+".part")
+}
+
+// acquire 获取会话的写入权，同一会话同时只允许一个请求写入或完成
+func (u *resumableUploader) acquire(id int64) bool {
+	_, loaded := u.writing.LoadOrStore(id, struct{}{})
+	return !loaded
+}
+
+func (u *resumableUploader) release(id int64) {
+	u.writing.Delete(id)
+}
+
+// create 创建上传会话，每个用户同时进行中的会话数受限
+func (u *resumableUploader) create(req *web.CreateUploadReq) (*ms.UploadSession, error) {
+	count, err := u.ds.CountUserUploadSessions(req.Uid)
+	if err != nil {
+		logrus.Errorf("Ds.CountUserUploadSessions err: %s", err)
+		return nil, web.ErrFileUploadFailed
+	}
+	if count >= u.maxSessions {
+		return nil, web.ErrTooManyUploadSessions.WithDetails("最多允许" + strconv.FormatInt(u.maxSessions, 10) + "个")
+	}
+	session, err := u.ds.CreateUploadSession(&ms.UploadSession{
+		UserID:      req.Uid,
+		UploadType:  req.UploadType,
+		ContentType: req.ContentType,
+		FileExt:     req.FileExt,
+		FileSize:    req.FileSize,
+		ExpiredOn:   time.Now().Add(u.expire).Unix(),
+	})
+	if err != nil {
+		logrus.Errorf("Ds.CreateUploadSession err: %s", err)
+		return nil, web.ErrFileUploadFailed
+	}
+	if err = os.MkdirAll(u.workDir, 0755); err == nil {
+		err = os.WriteFile(u.filePath(session.ID), nil, 0644)
+	}
+	if err != nil {
+		logrus.Errorf("create upload session %d file err: %s", session.ID, err)
+		u.ds.DeleteUploadSession(session)
+		return nil, web.ErrFileUploadFailed
+	}
+	return session, nil
+}
+
+// session 获取用户未过期的上传会话
+func (u *resumableUploader) session(userId int64, id int64) (*ms.UploadSession, error) {
+	session, err := u.ds.GetUploadSession(id)
+	if err != nil || session.UserID != userId || session.IsExpired() {
+		return nil, web.ErrUploadSessionNotExist
+	}
+	return session, nil
+}
+
+// write 从offset处写入分块，超出分块上限或文件大小的内容不接收，客户端按返回的偏移量继续上传；
+// 连接中断时保留已接收的部分以便续传
+func (u *resumableUploader) write(session *ms.UploadSession, offset int64, body io.Reader) error {
+	if offset != session.ReceivedSize {
+		return web.ErrUploadOffsetMismatch.WithDetails("当前偏移量为" + strconv.FormatInt(session.ReceivedSize, 10))
+	}
+	n, err := u.writeAt(session.ID, offset, io.LimitReader(body, min(u.maxChunkSize, session.FileSize-offset)))
+	if n > 0 {
+		if ok, xerr := u.ds.UpdateUploadReceived(session, offset+n); xerr != nil {
+			logrus.Errorf("Ds.UpdateUploadReceived err: %s", xerr)
+			return web.ErrFileUploadFailed
+		} else if !ok {
+			return web.ErrUploadOffsetMismatch
+		}
+	}
+	if err != nil {
+		logrus.Debugf("write upload session %d interrupted at %d: %s", session.ID, session.ReceivedSize, err)
+		return web.ErrFileUploadFailed
+	}
+	return nil
+}
+
+func (u *resumableUploader) writeAt(id int64, offset int64, reader io.Reader) (int64, error) {
+	file, err := os.OpenFile(u.filePath(id), os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	// 丢弃上次写入中未记录的内容
+	if err = file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.Copy(file, reader)
+}
+
+// open 打开已接收完整的暂存文件
+func (u *resumableUploader) open(session *ms.UploadSession) (*os.File, error) {
+	if session.ReceivedSize != session.FileSize {
+		return nil, web.ErrUploadIncomplete
+	}
+	file, err := os.Open(u.filePath(session.ID))
+	if err != nil {
+		logrus.Errorf("open upload session %d file err: %s", session.ID, err)
+		return nil, web.ErrFileUploadFailed
+	}
+	return file, nil
+}
+
+// remove 删除上传会话及其暂存文件
+func (u *resumableUploader) remove(session *ms.UploadSession) error {
+	if err := u.ds.DeleteUploadSession(session); err != nil {
+		return err
+	}
+	if err := os.Remove(u.filePath(session.ID)); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("remove upload session %d file failed: %s", session.ID, err)
+	}
+	return nil
+}
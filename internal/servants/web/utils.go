@@ -7,12 +7,14 @@ package web
 import (
 	"image"
 	"math/rand"
+	"regexp"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/rocboss/paopao-ce/internal/core"
+	"github.com/rocboss/paopao-ce/internal/core/cs"
 	"github.com/rocboss/paopao-ce/internal/core/ms"
 	"github.com/rocboss/paopao-ce/internal/model/web"
 	"github.com/rocboss/paopao-ce/pkg/utils"
@@ -20,6 +22,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// _mentionRegexp 匹配内容中@的用户名，用户名只包含字母与数字
+var _mentionRegexp = regexp.MustCompile(`@([a-zA-Z0-9]+)`)
+
 var defaultAvatars = []string{
 	"https://assets.paopao.info/public/avatar/default/zoe.png",
 	"https://assets.paopao.info/public/avatar/default/william.png",
@@ -99,8 +104,30 @@ func encryptPasswordAndSalt(password string) (string, string) {
 	return password, salt
 }
 
+// releaseOssObjects 释放用户对媒体内容的引用，删除不再被引用的媒体内容
+func releaseOssObjects(oss core.ObjectStorageService, userId int64, mediaContents []string) {
+	if _ds == nil || len(mediaContents) == 0 {
+		return
+	}
+	contents, err := _ds.ReleaseObjectBlobs(userId, mediaContents)
+	if err != nil {
+		logrus.Errorf("service.releaseOssObjects release object blobs failed: %s", err)
+		return
+	}
+	deleteOssObjects(oss, contents)
+}
+
 // deleteOssObjects 删除推文的媒体内容, 宽松处理错误(就是不处理), 后续完善
 func deleteOssObjects(oss core.ObjectStorageService, mediaContents []string) {
+	// 图片/视频的缩略图、转码输出等版本一并删除
+	variants := mediaVariantsFrom(mediaContents)
+	// 删除附件记录以释放用户的存储配额
+	if _ds != nil && len(mediaContents) > 0 {
+		if err := _ds.DeleteAttachmentsByContents(mediaContents); err != nil {
+			logrus.Errorf("service.deleteOssObjects delete attachments failed: %s", err)
+		}
+	}
+	mediaContents = append(mediaContents, variants...)
 	mediaContentsSize := len(mediaContents)
 	if mediaContentsSize > 1 {
 		objectKeys := make([]string, 0, mediaContentsSize)
@@ -117,7 +144,11 @@ func deleteOssObjects(oss core.ObjectStorageService, mediaContents []string) {
 // persistMediaContents 获取媒体内容并持久化
 func persistMediaContents(oss core.ObjectStorageService, contents []*web.PostContentItem) (items []string, err error) {
 	items = make([]string, 0, len(contents))
+	var medias []string
 	for _, item := range contents {
+		if item.Type == ms.ContentTypeImage || item.Type == ms.ContentTypeVideo {
+			medias = append(medias, item.Content)
+		}
 		switch item.Type {
 		case ms.ContentTypeImage,
 			ms.ContentTypeVideo,
@@ -133,9 +164,102 @@ func persistMediaContents(oss core.ObjectStorageService, contents []*web.PostCon
 			}
 		}
 	}
+	// 宽松处理媒体各版本的持久化，缺少的版本前端会回退使用原文件
+	if err == nil {
+		for _, variant := range mediaVariantsFrom(medias) {
+			if e := oss.PersistObject(oss.ObjectKey(variant)); e != nil {
+				logrus.Errorf("service.persistMediaContents persist media variant failed: %s", e)
+			}
+		}
+	}
 	return
 }
 
+// mediaVariantsFrom 获取图片/视频内容的缩略图、转码输出等版本地址
+func mediaVariantsFrom(contents []string) (items []string) {
+	if _ds == nil || len(contents) == 0 {
+		return nil
+	}
+	attachments, err := _ds.GetAttachmentsByContents(contents)
+	if err != nil {
+		logrus.Errorf("service.mediaVariantsFrom get attachments failed: %s", err)
+		return nil
+	}
+	for _, attachment := range attachments {
+		for _, variant := range attachment.ImageVariants() {
+			items = append(items, variant.Content)
+		}
+	}
+	return
+}
+
+// mediaContentsFrom 获取推文内容中的媒体内容
+func mediaContentsFrom(contents []*ms.PostContentFormated) (items []string) {
+	for _, item := range contents {
+		switch item.Type {
+		case ms.ContentTypeImage,
+			ms.ContentTypeVideo,
+			ms.ContentTypeAudio,
+			ms.ContentTypeAttachment,
+			ms.ContentTypeChargeAttachment:
+			items = append(items, item.Content)
+		}
+	}
+	return
+}
+
+// contentItemsFrom 将存储的推文内容转换为发布请求的推文内容
+func contentItemsFrom(items []*ms.PostContentFormated) []*web.PostContentItem {
+	contents := make([]*web.PostContentItem, 0, len(items))
+	for _, item := range items {
+		contents = append(contents, &web.PostContentItem{
+			Content: item.Content,
+			Type:    item.Type,
+			Sort:    item.Sort,
+		})
+	}
+	return contents
+}
+
+// excludeStrings 获取在origin中但不在target中的条目
+func excludeStrings(origin []string, target []string) (res []string) {
+	targetMap := make(map[string]struct{}, len(target))
+	for _, item := range target {
+		targetMap[item] = struct{}{}
+	}
+	for _, item := range origin {
+		if _, exist := targetMap[item]; !exist {
+			res = append(res, item)
+		}
+	}
+	return
+}
+
+// mentionsFrom 获取内容中@的用户名集合
+func mentionsFrom(text string) map[string]struct{} {
+	mentions := make(map[string]struct{})
+	for _, match := range _mentionRegexp.FindAllStringSubmatch(text, -1) {
+		mentions[match[1]] = struct{}{}
+	}
+	return mentions
+}
+
+// linksFrom 获取需要抓取预览的链接内容，超长的链接不处理
+func linksFrom(contents []*web.PostContentItem) []string {
+	linkMap := make(map[string]struct{})
+	links := make([]string, 0)
+	for _, item := range contents {
+		if item.Type != ms.ContentTypeLink || len(item.Content) > _maxPreviewLinkLength {
+			continue
+		}
+		if _, exist := linkMap[item.Content]; !exist {
+			linkMap[item.Content] = struct{}{}
+			links = append(links, item.Content)
+		}
+	}
+	return links
+}
+
 func fileCheck(uploadType string, size int64) error {
 	if uploadType != "public/video" &&
 		uploadType != "public/image" &&
@@ -207,6 +331,66 @@ func checkPermision(user *ms.User, targetUserId int64) error {
 	return nil
 }
 
+// checkPostSubscribePermission 订阅可见的post仅作者、管理员及订阅者可以查看评论及发表评论
+func checkPostSubscribePermission(userId int64, post *ms.Post, ds core.DataService) error {
+	if post.Visibility != core.PostVisitSubscribe || (userId > 0 && userId == post.UserID) {
+		return nil
+	}
+	if userId > 0 {
+		if ds.IsSubscriber(userId, post.UserID) {
+			return nil
+		}
+		if user, err := ds.GetUserByID(userId); err == nil && user.IsAdmin {
+			return nil
+		}
+	}
+	return web.ErrNoSubscription
+}
+
+// checkPostChargePermission 充电可见推文的完整内容仅对作者、管理员及累计充电达到门槛的用户开放
+func checkPostChargePermission(user *ms.User, post *ms.Post, ds core.DataService) error {
+	if post.Visibility != core.PostVisitCharge || (user != nil && (user.IsAdmin || user.ID == post.UserID)) {
+		return nil
+	}
+	if user == nil || !ds.IsPostCharger(user.ID, post.ID) {
+		return web.ErrNoCharge
+	}
+	return nil
+}
+
+// reviewAttachmentRefund 同意时执行退款，拒绝时仅变更状态，处理后通知购买者
+func reviewAttachmentRefund(ds core.DataService, refund *ms.AttachmentRefund, req *web.ReviewAttachmentRefundReq, brief string) error {
+	from := refund.Status
+	refund.ReviewerID, refund.Remark, refund.ReviewedOn = req.User.ID, req.Remark, time.Now().Unix()
+	var (
+		ok  bool
+		err error
+	)
+	if req.Approve {
+		ok, err = ds.RefundAttachment(refund, from)
+	} else {
+		refund.Status = ms.RefundStatusRejected
+		ok, err = ds.UpdateAttachmentRefundStatus(refund, from)
+	}
+	switch {
+	case err == cs.ErrNoBalance:
+		return web.ErrAuthorNoBalance
+	case err != nil:
+		logrus.Errorf("review attachment refund id:%d err: %s", refund.ID, err)
+		return web.ErrRefundFailed
+	case !ok:
+		return web.ErrRefundStatus
+	}
+	onCreateMessageEvent(&ms.Message{
+		ReceiverUserID: refund.UserID,
+		Type:           ms.MsgTypeSystem,
+		Brief:          brief,
+		Content:        refund.Remark,
+		PostID:         refund.PostID,
+	})
+	return nil
+}
+
 // checkPostViewPermission 检查当前用户是否可读指定post
 func checkPostViewPermission(user *ms.User, post *ms.Post, ds core.DataService) error {
 	if post.Visibility == core.PostVisitPublic {
@@ -230,6 +414,14 @@ func checkPostViewPermission(user *ms.User, post *ms.Post, ds core.DataService)
 			return web.ErrNoPermission
 		}
 	}
+
+	if post.Visibility == core.PostVisitSubscribe && !ds.IsSubscriber(user.ID, post.UserID) {
+		return web.ErrNoSubscription
+	}
+
+	if err := checkPostChargePermission(user, post, ds); err != nil {
+		return err
+	}
 	// TODO: add following check logic
 	return nil
 }
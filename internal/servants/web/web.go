@@ -15,15 +15,23 @@ import (
 	"github.com/rocboss/paopao-ce/internal/dao"
 	"github.com/rocboss/paopao-ce/internal/dao/cache"
 	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/pkg/picture"
+	"github.com/rocboss/paopao-ce/pkg/unfurl"
 )
 
 var (
 	_enablePhoneVerify    bool
 	_disallowUserRegister bool
+	_enableStorageQuota   bool
+	_enableUploadDedup    bool
 	_ds                   core.DataService
 	_ac                   core.AppCache
 	_wc                   core.WebCache
 	_oss                  core.ObjectStorageService
+	_uf                   unfurl.Unfurler
+	_pictureOptions       *picture.Options
+	_videoProcessor       *videoProcessor
+	_uploader             *resumableUploader
 	_onceInitial          sync.Once
 )
 
@@ -44,12 +52,20 @@ func RouteWeb(e *gin.Engine) {
 	api.RegisterSiteServant(e, newSiteSrv())
 	// regster servants if needed by configure
 	cfg.Be("Alipay", func() {
-		client := conf.MustAlipayClient()
 		api.RegisterAlipayPubServant(e, newAlipayPubSrv(ds))
-		api.RegisterAlipayPrivServant(e, newAlipayPrivSrv(ds, client))
 	})
+	cfg.Be("WechatPay", func() {
+		api.RegisterWechatPayPubServant(e, newWechatPayPubSrv(ds))
+	})
+	cfg.Be("Subscription", func() {
+		api.RegisterSubscriptionServant(e, newSubscriptionSrv(ds))
+	})
+	// 开启任一支付渠道时提供钱包充值服务
+	if names, providers := newPaymentProviders(); len(names) > 0 {
+		api.RegisterAlipayPrivServant(e, newAlipayPrivSrv(ds, names, providers))
+	}
 	// shedule jobs if need
-	scheduleJobs()
+	scheduleJobs(ds)
 }
 
 // lazyInitial do some package lazy initialize for performance
@@ -57,11 +73,26 @@ func lazyInitial() {
 	_onceInitial.Do(func() {
 		_enablePhoneVerify = cfg.If("Sms")
 		_disallowUserRegister = cfg.If("Web:DisallowUserRegister")
+		_enableStorageQuota = cfg.If("StorageQuota")
+		_enableUploadDedup = cfg.If("UploadDedup")
 		_maxWhisperNumDaily = conf.AppSetting.MaxWhisperDaily
 		_maxCaptchaTimes = conf.AppSetting.MaxCaptchaTimes
 		_oss = dao.ObjectStorageService()
 		_ds = dao.DataService()
 		_ac = cache.NewAppCache()
 		_wc = cache.NewWebCache()
+		if cfg.If("LinkPreview") {
+			_uf = conf.MustUnfurler()
+		}
+		if cfg.If("ImageProcess") {
+			_pictureOptions = conf.MustPictureOptions()
+		}
+		// 视频转码依赖后台任务，禁用JobManager时不转码
+		if cfg.If("VideoTranscode") && !cfg.If("DisableJobManager") {
+			_videoProcessor = newVideoProcessor(_ds, _oss, conf.MustTranscoder())
+		}
+		if cfg.If("ResumableUpload") {
+			_uploader = newResumableUploader(_ds)
+		}
 	})
 }
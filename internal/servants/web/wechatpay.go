@@ -0,0 +1,34 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	api "github.com/rocboss/paopao-ce/auto/api/v1"
+	"github.com/rocboss/paopao-ce/internal/model/web"
+	"github.com/rocboss/paopao-ce/internal/servants/base"
+	"github.com/rocboss/paopao-ce/pkg/wxpay"
+)
+
+var (
+	_ api.WechatPayPub = (*wechatPayPubSrv)(nil)
+)
+
+type wechatPayPubSrv struct {
+	api.UnimplementedWechatPayPubServant
+	*base.DaoServant
+}
+
+func (s *wechatPayPubSrv) WechatPayNotify(req *web.WechatPayNotifyReq) error {
+	if req.TradeState != wxpay.TradeStateSuccess {
+		return nil
+	}
+	return handleRechargeNotify(s.DaoServant, req.Ctx, _paymentWechatPay, req.ID, req.TradeNo, req.Amount)
+}
+
+func newWechatPayPubSrv(s *base.DaoServant) api.WechatPayPub {
+	return &wechatPayPubSrv{
+		DaoServant: s,
+	}
+}
@@ -51,7 +51,9 @@ func newWebEngine() *gin.Engine {
 	// 跨域配置
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
-	corsConfig.AddAllowHeaders("Authorization")
+	corsConfig.AddAllowHeaders("Authorization", "Upload-Offset")
+	// 断点续传上传通过响应头返回上传进度
+	corsConfig.AddExposeHeaders("Upload-Offset", "Upload-Length", "Upload-Expires")
 	e.Use(cors.New(corsConfig))
 	// 使用Sentry hook
 	if conf.UseSentryGin() {
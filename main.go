@@ -6,8 +6,10 @@ package main
 
 import (
 	"github.com/rocboss/paopao-ce/cmd"
+	_ "github.com/rocboss/paopao-ce/cmd/ledger"
 	_ "github.com/rocboss/paopao-ce/cmd/migrate"
 	_ "github.com/rocboss/paopao-ce/cmd/serve"
+	_ "github.com/rocboss/paopao-ce/cmd/storage"
 )
 
 func main() {
@@ -13,4 +13,37 @@ type Admin struct {
 	// ChangeUserStatus 管理·禁言/解封用户
 	ChangeUserStatus func(Post, web.ChangeUserStatusReq)         `mir:"admin/user/status"`
 	SiteInfo         func(Get, web.SiteInfoReq) web.SiteInfoResp `mir:"admin/site/status"`
+
+	// UserStorageQuota 管理·获取用户存储使用量及配额
+	UserStorageQuota func(Get, web.UserStorageQuotaReq) web.UserStorageQuotaResp `mir:"admin/user/quota"`
+
+	// ChangeUserStorageQuota 管理·单独设置用户存储配额
+	ChangeUserStorageQuota func(Post, web.ChangeUserStorageQuotaReq) `mir:"admin/user/quota"`
+
+	// ResetUserStorageQuota 管理·恢复用户为按角色的存储配额
+	ResetUserStorageQuota func(Delete, web.ResetUserStorageQuotaReq) `mir:"admin/user/quota"`
+
+	// ListWithdrawals 管理·获取提现申请列表
+	ListWithdrawals func(Get, web.ListWithdrawalsReq) web.ListWithdrawalsResp `mir:"admin/wallet/withdrawals"`
+
+	// ReviewWithdrawal 管理·审核提现申请
+	ReviewWithdrawal func(Post, web.ReviewWithdrawalReq) `mir:"admin/wallet/withdrawal/review"`
+
+	// PayoutWithdrawal 管理·标记提现打款结果
+	PayoutWithdrawal func(Post, web.PayoutWithdrawalReq) `mir:"admin/wallet/withdrawal/payout"`
+
+	// WalletReconcile 管理·核对用户钱包余额与账单
+	WalletReconcile func(Get, web.WalletReconcileReq) web.WalletReconcileResp `mir:"admin/wallet/reconcile"`
+
+	// ListAttachmentRefunds 管理·获取附件退款申请列表
+	ListAttachmentRefunds func(Get, web.ListAttachmentRefundsReq) web.ListAttachmentRefundsResp `mir:"admin/attachment/refunds"`
+
+	// ArbitrateAttachmentRefund 管理·处理附件退款申请及申诉
+	ArbitrateAttachmentRefund func(Post, web.ReviewAttachmentRefundReq) `mir:"admin/attachment/refund/review"`
+
+	// MergeTopic 管理·合并话题
+	MergeTopic func(Post, web.MergeTopicReq) `mir:"admin/topic/merge"`
+
+	// ChangeTopicStatus 管理·锁定/隐藏话题
+	ChangeTopicStatus func(Post, web.ChangeTopicStatusReq) `mir:"admin/topic/status"`
 }
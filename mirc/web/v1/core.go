@@ -46,6 +46,9 @@ type Core struct {
 	// ChangeAvatar 修改头像
 	ChangeAvatar func(Post, web.ChangeAvatarReq) `mir:"user/avatar"`
 
+	// GetStorageUsage 获取存储使用量及配额
+	GetStorageUsage func(Get, web.GetStorageUsageReq) web.StorageUsageResp `mir:"user/storage"`
+
 	// SuggestUsers 检索用户
 	SuggestUsers func(Get, web.SuggestUsersReq) web.SuggestUsersResp `mir:"suggest/users"`
 
@@ -57,4 +60,25 @@ type Core struct {
 
 	// TweetCollectionStatus 获取动态收藏状态
 	TweetCollectionStatus func(Get, web.TweetCollectionStatusReq) web.TweetCollectionStatusResp `mir:"post/collection"`
+
+	// ListCollectionFolders 获取收藏夹列表
+	ListCollectionFolders func(Get, web.ListCollectionFoldersReq) web.ListCollectionFoldersResp `mir:"user/collection/folders"`
+
+	// CreateCollectionFolder 创建收藏夹
+	CreateCollectionFolder func(Post, web.CreateCollectionFolderReq) web.CreateCollectionFolderResp `mir:"user/collection/folder"`
+
+	// UpdateCollectionFolder 重命名收藏夹
+	UpdateCollectionFolder func(Post, web.UpdateCollectionFolderReq) `mir:"user/collection/folder/update"`
+
+	// DeleteCollectionFolder 删除收藏夹
+	DeleteCollectionFolder func(Delete, web.DeleteCollectionFolderReq) `mir:"user/collection/folder"`
+
+	// ShareCollectionFolder 开启/关闭收藏夹公开分享
+	ShareCollectionFolder func(Post, web.ShareCollectionFolderReq) web.ShareCollectionFolderResp `mir:"user/collection/folder/share"`
+
+	// MoveCollections 移动收藏到收藏夹
+	MoveCollections func(Post, web.MoveCollectionsReq) `mir:"user/collection/move"`
+
+	// UpdateCollectionNote 更新收藏的私人备注
+	UpdateCollectionNote func(Post, web.CollectionNoteReq) `mir:"user/collection/note"`
 }
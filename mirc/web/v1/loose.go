@@ -27,4 +27,22 @@ type Loose struct {
 
 	// TweetDetail 获取动态详情
 	TweetDetail func(Get, web.TweetDetailReq) web.TweetDetailResp `mir:"post"`
+
+	// TweetRevisions 获取动态修订历史
+	TweetRevisions func(Get, web.TweetRevisionsReq) web.TweetRevisionsResp `mir:"post/revisions"`
+
+	// TweetPollVotes 获取动态投票的投票人
+	TweetPollVotes func(Get, web.TweetPollVotesReq) web.TweetPollVotesResp `mir:"post/poll/votes"`
+
+	// TweetTips 获取动态的充电记录
+	TweetTips func(Get, web.TweetTipsReq) web.TweetTipsResp `mir:"post/tips"`
+
+	// TopicDetail 获取话题主页
+	TopicDetail func(Get, web.TopicDetailReq) web.TopicDetailResp `mir:"topic"`
+
+	// TopicTweets 获取话题下的推文
+	TopicTweets func(Get, web.TopicTweetsReq) web.TopicTweetsResp `mir:"topic/tweets"`
+
+	// SharedCollections 查看分享的收藏夹
+	SharedCollections func(Get, web.SharedCollectionsReq) web.SharedCollectionsResp `mir:"collection/shared"`
 }
@@ -13,18 +13,75 @@ type Priv struct {
 	// UploadAttachment 上传资源
 	UploadAttachment func(Post, web.UploadAttachmentReq) web.UploadAttachmentResp `mir:"attachment"`
 
+	// CreateUpload 创建断点续传上传会话
+	CreateUpload func(Post, web.CreateUploadReq) web.CreateUploadResp `mir:"attachment/upload"`
+
+	// UploadChunk 断点续传上传分块
+	UploadChunk func(Patch, web.UploadChunkReq) web.UploadChunkResp `mir:"attachment/upload"`
+
+	// UploadProgress 查询断点续传上传进度
+	UploadProgress func(Head, web.UploadProgressReq) web.UploadProgressResp `mir:"attachment/upload"`
+
+	// CompleteUpload 完成断点续传上传并保存为附件
+	CompleteUpload func(Post, web.CompleteUploadReq) web.UploadAttachmentResp `mir:"attachment/upload/complete"`
+
+	// CancelUpload 取消断点续传上传
+	CancelUpload func(Delete, web.CancelUploadReq) `mir:"attachment/upload"`
+
 	// DownloadAttachmentPrecheck 下载资源预检
 	DownloadAttachmentPrecheck func(Get, web.DownloadAttachmentPrecheckReq) web.DownloadAttachmentPrecheckResp `mir:"attachment/precheck"`
 
 	// DownloadAttachment 下载资源
 	DownloadAttachment func(Get, web.DownloadAttachmentReq) web.DownloadAttachmentResp `mir:"attachment"`
 
+	// RequestAttachmentRefund 申请收费附件退款
+	RequestAttachmentRefund func(Post, web.RequestAttachmentRefundReq) web.RequestAttachmentRefundResp `mir:"attachment/refund"`
+
+	// DisputeAttachmentRefund 作者拒绝退款后申诉
+	DisputeAttachmentRefund func(Post, web.DisputeAttachmentRefundReq) `mir:"attachment/refund/dispute"`
+
+	// ReviewAttachmentRefund 作者处理退款申请
+	ReviewAttachmentRefund func(Post, web.ReviewAttachmentRefundReq) `mir:"attachment/refund/review"`
+
+	// UserAttachmentRefunds 获取用户申请的或待用户处理的退款申请
+	UserAttachmentRefunds func(Get, web.UserAttachmentRefundsReq) web.UserAttachmentRefundsResp `mir:"attachment/refunds"`
+
 	// CreateTweet 发布动态
 	CreateTweet func(Post, Chain, web.CreateTweetReq) web.CreateTweetResp `mir:"post"`
 
+	// EditTweet 编辑动态
+	EditTweet func(Post, Chain, web.EditTweetReq) web.EditTweetResp `mir:"post/edit"`
+
 	// DeleteTweet 删除动态
 	DeleteTweet func(Delete, web.DeleteTweetReq) `mir:"post"`
 
+	// UpdateScheduledTweet 修改定时发布的动态
+	UpdateScheduledTweet func(Post, web.UpdateScheduledTweetReq) web.UpdateScheduledTweetResp `mir:"post/schedule"`
+
+	// CancelScheduledTweet 取消定时发布的动态
+	CancelScheduledTweet func(Delete, web.CancelScheduledTweetReq) `mir:"post/schedule"`
+
+	// CreateDraft 创建草稿
+	CreateDraft func(Post, web.CreateDraftReq) web.CreateDraftResp `mir:"draft"`
+
+	// UpdateDraft 更新草稿(自动保存)
+	UpdateDraft func(Post, web.UpdateDraftReq) web.UpdateDraftResp `mir:"draft/update"`
+
+	// DeleteDraft 删除草稿
+	DeleteDraft func(Delete, web.DeleteDraftReq) `mir:"draft"`
+
+	// ListDrafts 获取草稿列表
+	ListDrafts func(Get, web.ListDraftsReq) web.ListDraftsResp `mir:"drafts"`
+
+	// PublishDraft 发布草稿
+	PublishDraft func(Post, Chain, web.PublishDraftReq) web.CreateTweetResp `mir:"draft/publish"`
+
+	// VotePoll 动态投票
+	VotePoll func(Post, web.VotePollReq) web.VotePollResp `mir:"post/poll/vote"`
+
+	// TipTweet 为动态充电
+	TipTweet func(Post, web.TipTweetReq) web.TipTweetResp `mir:"post/tip"`
+
 	// StarTweet 动态点赞操作
 	StarTweet func(Post, web.StarTweetReq) web.StarTweetResp `mir:"post/star"`
 
@@ -81,4 +138,25 @@ type Priv struct {
 
 	// UnfollowTopic 取消关注话题
 	UnfollowTopic func(Post, web.UnfollowTopicReq) `mir:"topic/unfollow"`
+
+	// UpdateTopic 更新话题描述及封面
+	UpdateTopic func(Post, web.UpdateTopicReq) `mir:"topic/update"`
+
+	// PinTopicTweet 置顶/取消置顶话题内的推文
+	PinTopicTweet func(Post, web.PinTopicTweetReq) web.PinTopicTweetResp `mir:"topic/tweet/pin"`
+
+	// AddTopicModerator 添加话题主持人
+	AddTopicModerator func(Post, web.TopicModeratorReq) `mir:"topic/moderator"`
+
+	// RemoveTopicModerator 移除话题主持人
+	RemoveTopicModerator func(Delete, web.TopicModeratorReq) `mir:"topic/moderator"`
+
+	// CreateWithdrawal 申请提现
+	CreateWithdrawal func(Post, web.CreateWithdrawalReq) web.CreateWithdrawalResp `mir:"user/wallet/withdrawal"`
+
+	// UserWithdrawals 获取用户提现记录
+	UserWithdrawals func(Get, web.UserWithdrawalsReq) web.UserWithdrawalsResp `mir:"user/wallet/withdrawals"`
+
+	// CancelWithdrawal 取消待审核的提现申请
+	CancelWithdrawal func(Post, web.CancelWithdrawalReq) `mir:"user/wallet/withdrawal/cancel"`
 }
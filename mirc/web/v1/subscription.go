@@ -0,0 +1,36 @@
+package v1
+
+import (
+	. "github.com/alimy/mir/v5"
+
+	"github.com/rocboss/paopao-ce/internal/model/web"
+)
+
+// Subscription 创作者订阅 服务
+type Subscription struct {
+	Schema `mir:"v1,chain"`
+
+	// ListSubscriptionTiers 获取创作者的订阅档位
+	ListSubscriptionTiers func(Get, web.ListSubscriptionTiersReq) web.ListSubscriptionTiersResp `mir:"user/subscription/tiers"`
+
+	// CreateSubscriptionTier 创建订阅档位
+	CreateSubscriptionTier func(Post, web.CreateSubscriptionTierReq) web.CreateSubscriptionTierResp `mir:"user/subscription/tier"`
+
+	// UpdateSubscriptionTier 更新订阅档位
+	UpdateSubscriptionTier func(Post, web.UpdateSubscriptionTierReq) `mir:"user/subscription/tier/update"`
+
+	// DeleteSubscriptionTier 删除订阅档位
+	DeleteSubscriptionTier func(Post, web.DeleteSubscriptionTierReq) `mir:"user/subscription/tier/delete"`
+
+	// Subscribe 订阅创作者
+	Subscribe func(Post, web.SubscribeReq) web.SubscribeResp `mir:"user/subscribe"`
+
+	// UpdateSubscriptionRenew 开启或关闭自动续费
+	UpdateSubscriptionRenew func(Post, web.UpdateSubscriptionRenewReq) `mir:"user/subscription/renew"`
+
+	// UserSubscriptions 获取用户的订阅列表
+	UserSubscriptions func(Get, web.UserSubscriptionsReq) web.UserSubscriptionsResp `mir:"user/subscriptions"`
+
+	// ListSubscribers 获取创作者的订阅者列表
+	ListSubscribers func(Get, web.ListSubscribersReq) web.ListSubscribersResp `mir:"user/subscribers"`
+}
@@ -0,0 +1,15 @@
+package v1
+
+import (
+	. "github.com/alimy/mir/v5"
+
+	"github.com/rocboss/paopao-ce/internal/model/web"
+)
+
+// WechatPayPub 微信支付相关不用授权的服务
+type WechatPayPub struct {
+	Schema `mir:"v1"`
+
+	// WechatPayNotify 微信支付回调
+	WechatPayNotify func(Post, web.WechatPayNotifyReq) `mir:"wechatpay/notify"`
+}
@@ -5,9 +5,17 @@
 package app
 
 import (
+	"sync"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rocboss/paopao-ce/internal/conf"
 	"github.com/rocboss/paopao-ce/pkg/convert"
+	"github.com/rocboss/paopao-ce/pkg/cursor"
+)
+
+var (
+	_cursorCodec     *cursor.Codec
+	_onceCursorCodec sync.Once
 )
 
 func GetPage(c *gin.Context) int {
@@ -60,3 +68,31 @@ func GetPageInfo(c *gin.Context) (page, pageSize int) {
 	}
 	return
 }
+
+// GetCursor 解析请求中的分页游标，未携带游标时返回nil；
+// 以paging=cursor请求第一页时返回第一页的游标，响应中将带上下一页的游标
+func GetCursor(c *gin.Context) (*cursor.Cursor, error) {
+	s := c.Query("cursor")
+	if s == "" {
+		if c.Query("paging") == "cursor" {
+			return cursor.Start(), nil
+		}
+		return nil, nil
+	}
+	return cursorCodec().Decode(s)
+}
+
+// EncodeCursor 编码下一页的游标，没有下一页时返回空串
+func EncodeCursor(next *cursor.Cursor) string {
+	if next == nil {
+		return ""
+	}
+	return cursorCodec().Encode(next)
+}
+
+func cursorCodec() *cursor.Codec {
+	_onceCursorCodec.Do(func() {
+		_cursorCodec = cursor.NewCodec(conf.JWTSetting.Secret)
+	})
+	return _cursorCodec
+}
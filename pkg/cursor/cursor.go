@@ -0,0 +1,91 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package cursor 键集分页的游标，游标对客户端不透明并经签名防篡改
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	_payloadSize   = 16
+	_signatureSize = 12
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor 指向上一页最后一条记录的(created_on, id)
+type Cursor struct {
+	CreatedOn int64
+	ID        int64
+}
+
+// Start 第一页的游标，不对应任何记录
+func Start() *Cursor {
+	return &Cursor{}
+}
+
+// IsStart 是否为第一页的游标
+func (c *Cursor) IsStart() bool {
+	return c.CreatedOn == 0 && c.ID == 0
+}
+
+// Codec 游标编解码器，编码结果为base64url(created_on|id|hmac-sha256截断)
+type Codec struct {
+	key []byte
+}
+
+// NewCodec 以secret派生的密钥签名游标
+func NewCodec(secret string) *Codec {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("paopao:cursor"))
+	return &Codec{
+		key: mac.Sum(nil),
+	}
+}
+
+func (c *Codec) Encode(cursor *Cursor) string {
+	buf := make([]byte, _payloadSize, _payloadSize+_signatureSize)
+	binary.BigEndian.PutUint64(buf, uint64(cursor.CreatedOn))
+	binary.BigEndian.PutUint64(buf[8:], uint64(cursor.ID))
+	buf = append(buf, c.sign(buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Decode 解码游标，格式错误或签名不符时返回ErrInvalidCursor
+func (c *Codec) Decode(s string) (*Cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) != _payloadSize+_signatureSize {
+		return nil, ErrInvalidCursor
+	}
+	payload := buf[:_payloadSize]
+	if !hmac.Equal(buf[_payloadSize:], c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{
+		CreatedOn: int64(binary.BigEndian.Uint64(payload)),
+		ID:        int64(binary.BigEndian.Uint64(payload[8:])),
+	}, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:_signatureSize]
+}
+
+// Next 多取一条记录以判断是否还有下一页，返回截取limit条后的列表及下一页的游标，
+// 没有下一页时游标为nil
+func Next[T any](items []T, limit int, cursorOf func(T) *Cursor) ([]T, *Cursor) {
+	if limit <= 0 || len(items) <= limit {
+		return items, nil
+	}
+	items = items[:limit]
+	return items, cursorOf(items[limit-1])
+}
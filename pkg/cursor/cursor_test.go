@@ -0,0 +1,70 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cursor_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rocboss/paopao-ce/pkg/cursor"
+)
+
+var _ = Describe("Cursor", func() {
+	codec := cursor.NewCodec("secret")
+
+	It("encode and decode", func() {
+		for _, c := range []*cursor.Cursor{
+			{CreatedOn: 1700000000, ID: 1080017989},
+			{CreatedOn: 0, ID: 0},
+			{CreatedOn: 1<<62 + 7, ID: 1<<63 - 1},
+		} {
+			s := codec.Encode(c)
+			Expect(s).NotTo(ContainSubstring("="))
+			res, err := codec.Decode(s)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(c))
+		}
+	})
+
+	It("reject tampered or foreign cursor", func() {
+		s := codec.Encode(&cursor.Cursor{CreatedOn: 1700000000, ID: 100})
+		buf, _ := base64.RawURLEncoding.DecodeString(s)
+		buf[15]++
+		_, err := codec.Decode(base64.RawURLEncoding.EncodeToString(buf))
+		Expect(err).To(Equal(cursor.ErrInvalidCursor))
+
+		_, err = cursor.NewCodec("other").Decode(s)
+		Expect(err).To(Equal(cursor.ErrInvalidCursor))
+
+		for _, bad := range []string{"", "abc", "!!!!", s + "A", s[:len(s)-2]} {
+			_, err = codec.Decode(bad)
+			Expect(err).To(Equal(cursor.ErrInvalidCursor))
+		}
+	})
+
+	It("start cursor", func() {
+		Expect(cursor.Start().IsStart()).To(BeTrue())
+		Expect((&cursor.Cursor{CreatedOn: 1700000000, ID: 1}).IsStart()).To(BeFalse())
+	})
+
+	It("next cursor", func() {
+		cursorOf := func(id int64) *cursor.Cursor {
+			return &cursor.Cursor{CreatedOn: id * 10, ID: id}
+		}
+		items, next := cursor.Next([]int64{5, 4, 3, 2}, 3, cursorOf)
+		Expect(items).To(Equal([]int64{5, 4, 3}))
+		Expect(next).To(Equal(&cursor.Cursor{CreatedOn: 30, ID: 3}))
+
+		items, next = cursor.Next([]int64{5, 4, 3}, 3, cursorOf)
+		Expect(items).To(HaveLen(3))
+		Expect(next).To(BeNil())
+
+		items, next = cursor.Next([]int64{}, 3, cursorOf)
+		Expect(items).To(BeEmpty())
+		Expect(next).To(BeNil())
+	})
+})
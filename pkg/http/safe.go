@@ -0,0 +1,109 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	_defaultSafeTimeout      = 10 * time.Second
+	_defaultSafeMaxRedirects = 5
+)
+
+var (
+	// ErrPrivateAddress access private network address is forbidden
+	ErrPrivateAddress = errors.New("access private network address is forbidden")
+	// ErrTooManyRedirects stopped after too many redirects
+	ErrTooManyRedirects = errors.New("stopped after too many redirects")
+	// ErrUnsupportedScheme only http/https scheme is supported
+	ErrUnsupportedScheme = errors.New("unsupported url scheme")
+)
+
+// SafeClientConf configure used to create a safe http.Client
+type SafeClientConf struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	// AllowPrivate allow access private network address, just for testing.
+	AllowPrivate bool
+}
+
+// NewSafeClient create a http.Client that deny access private network address
+// to prevent SSRF. The check is done on the resolved ip when dialing so that
+// DNS rebinding and redirect to private address are denied too.
+func NewSafeClient(conf *SafeClientConf) *http.Client {
+	timeout, maxRedirects := _defaultSafeTimeout, _defaultSafeMaxRedirects
+	if conf.Timeout > 0 {
+		timeout = conf.Timeout
+	}
+	if conf.MaxRedirects > 0 {
+		maxRedirects = conf.MaxRedirects
+	}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+	}
+	if !conf.AllowPrivate {
+		dialer.Control = safeControl
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// never use proxy from environment, it will bypass the address check
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          16,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return ErrTooManyRedirects
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedScheme
+			}
+			return nil
+		},
+	}
+}
+
+// IsPrivateIP whether ip is a loopback/private/link-local/unspecified address
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		isSharedAddress(ip)
+}
+
+// isSharedAddress carrier-grade NAT address 100.64.0.0/10
+func isSharedAddress(ip net.IP) bool {
+	ip4 := ip.To4()
+	return ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64
+}
+
+func safeControl(network, address string, _ syscall.RawConn) error {
+	if network != "tcp4" && network != "tcp6" {
+		return fmt.Errorf("%w: network %s", ErrPrivateAddress, network)
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("Safe", g.Ordered, func() {
+	var server *httptest.Server
+
+	g.BeforeAll(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/redirect":
+				http.Redirect(w, r, "/", http.StatusFound)
+			case "/loop":
+				http.Redirect(w, r, "/loop", http.StatusFound)
+			default:
+				w.Write([]byte("ok"))
+			}
+		}))
+	})
+
+	g.AfterAll(func() {
+		server.Close()
+	})
+
+	g.It("private ip", func() {
+		for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fc00::1"} {
+			m.Expect(IsPrivateIP(net.ParseIP(ip))).To(m.BeTrue(), ip)
+		}
+		for _, ip := range []string{"8.8.8.8", "1.1.1.1", "100.128.0.1", "2001:4860:4860::8888"} {
+			m.Expect(IsPrivateIP(net.ParseIP(ip))).To(m.BeFalse(), ip)
+		}
+	})
+
+	g.It("deny private address", func() {
+		client := NewSafeClient(&SafeClientConf{})
+		_, err := client.Get(server.URL)
+		m.Expect(errors.Is(err, ErrPrivateAddress)).To(m.BeTrue())
+	})
+
+	g.It("allow private address", func() {
+		client := NewSafeClient(&SafeClientConf{
+			AllowPrivate: true,
+		})
+		resp, err := client.Get(server.URL)
+		m.Expect(err).To(m.BeNil())
+		resp.Body.Close()
+		m.Expect(resp.StatusCode).To(m.Equal(http.StatusOK))
+	})
+
+	g.It("too many redirects", func() {
+		client := NewSafeClient(&SafeClientConf{
+			MaxRedirects: 1,
+			AllowPrivate: true,
+		})
+		resp, err := client.Get(server.URL + "/redirect")
+		m.Expect(err).To(m.BeNil())
+		resp.Body.Close()
+
+		_, err = client.Get(server.URL + "/loop")
+		m.Expect(errors.Is(err, ErrTooManyRedirects)).To(m.BeTrue())
+	})
+})
@@ -0,0 +1,141 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNoVideoStream the input has no video stream
+	ErrNoVideoStream = errors.New("no video stream found")
+)
+
+var _ Transcoder = (*ffmpegTranscoder)(nil)
+
+type ffmpegTranscoder struct {
+	ffmpeg  string
+	ffprobe string
+}
+
+type probeOutput struct {
+	Streams []struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+		Tags   struct {
+			Rotate string `json:"rotate"`
+		} `json:"tags"`
+		SideDataList []struct {
+			Rotation int `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+func (t *ffmpegTranscoder) Probe(ctx context.Context, input string) (*ProbeInfo, error) {
+	out, err := run(ctx, t.ffprobe, "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "stream=width,height:stream_tags=rotate:stream_side_data=rotation:format=duration", "-of", "json", input)
+	if err != nil {
+		return nil, err
+	}
+	return parseProbe(out)
+}
+
+func (t *ffmpegTranscoder) Poster(ctx context.Context, input string, at time.Duration, output string) error {
+	_, err := run(ctx, t.ffmpeg, "-v", "error", "-y", "-ss", formatSeconds(at), "-i", input,
+		"-frames:v", "1", "-q:v", "3", output)
+	return err
+}
+
+func (t *ffmpegTranscoder) HLS(ctx context.Context, input string, outputDir string, r *Rendition, segmentTime int) error {
+	args := []string{"-v", "error", "-y", "-i", input,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", fmt.Sprintf("scale=%d:%d", r.Width, r.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-ac", "2",
+	}
+	if r.VideoBitrate != "" {
+		args = append(args, "-b:v", r.VideoBitrate, "-maxrate", r.VideoBitrate, "-bufsize", r.VideoBitrate)
+	}
+	if r.AudioBitrate != "" {
+		args = append(args, "-b:a", r.AudioBitrate)
+	}
+	// 每个清晰度只生成一个分片文件，播放列表通过字节范围引用，便于对象存储管理
+	args = append(args, "-f", "hls", "-hls_time", strconv.Itoa(segmentTime),
+		"-hls_playlist_type", "vod", "-hls_flags", "single_file",
+		"-hls_segment_filename", filepath.Join(outputDir, r.Name+".ts"),
+		filepath.Join(outputDir, r.Name+".m3u8"))
+	_, err := run(ctx, t.ffmpeg, args...)
+	return err
+}
+
+// NewFFmpegTranscoder create a Transcoder that use locally installed ffmpeg/ffprobe binary
+func NewFFmpegTranscoder(ffmpeg string, ffprobe string) (Transcoder, error) {
+	ffmpegPath, err := exec.LookPath(ffmpeg)
+	if err != nil {
+		return nil, err
+	}
+	ffprobePath, err := exec.LookPath(ffprobe)
+	if err != nil {
+		return nil, err
+	}
+	return &ffmpegTranscoder{
+		ffmpeg:  ffmpegPath,
+		ffprobe: ffprobePath,
+	}, nil
+}
+
+func parseProbe(data []byte) (*ProbeInfo, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Streams) == 0 || out.Streams[0].Width <= 0 || out.Streams[0].Height <= 0 {
+		return nil, ErrNoVideoStream
+	}
+	stream := out.Streams[0]
+	info := &ProbeInfo{
+		Width:  stream.Width,
+		Height: stream.Height,
+	}
+	// 手机拍摄的视频通常带有旋转信息，ffmpeg转码时会自动旋转，这里交换宽高保持一致
+	rotation, _ := strconv.Atoi(stream.Tags.Rotate)
+	for _, sd := range stream.SideDataList {
+		if sd.Rotation != 0 {
+			rotation = sd.Rotation
+		}
+	}
+	if rotation%180 != 0 {
+		info.Width, info.Height = info.Height, info.Width
+	}
+	if seconds, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil {
+		info.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	return info, nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", filepath.Base(name), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// package media probe and transcode uploaded video to HLS renditions.
+package media
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MasterPlaylist HLS主播放列表文件名
+	MasterPlaylist = "master.m3u8"
+	// PosterName 视频封面文件名
+	PosterName = "poster.jpg"
+)
+
+// ProbeInfo video probe information
+type ProbeInfo struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+}
+
+// Rendition HLS rendition, output files are Name.m3u8 and Name.ts
+type Rendition struct {
+	Name         string
+	Width        int
+	Height       int
+	VideoBitrate string
+	AudioBitrate string
+}
+
+// Transcoder video transcoder, input can be local file path or http url
+type Transcoder interface {
+	Probe(ctx context.Context, input string) (*ProbeInfo, error)
+	Poster(ctx context.Context, input string, at time.Duration, output string) error
+	HLS(ctx context.Context, input string, outputDir string, rendition *Rendition, segmentTime int) error
+}
+
+// SelectRenditions select renditions that not higher than source video, the lowest
+// rendition is always selected so every video has one rendition at least.
+// Rendition width is calculated by source aspect ratio.
+func SelectRenditions(info *ProbeInfo, renditions []*Rendition) []*Rendition {
+	if info.Width <= 0 || info.Height <= 0 || len(renditions) == 0 {
+		return nil
+	}
+	candidates := make([]*Rendition, len(renditions))
+	copy(candidates, renditions)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Height < candidates[j].Height
+	})
+	res := make([]*Rendition, 0, len(candidates))
+	for i, r := range candidates {
+		if i > 0 && r.Height > info.Height {
+			break
+		}
+		height := r.Height
+		if height > info.Height {
+			height = info.Height
+		}
+		res = append(res, &Rendition{
+			Name:         r.Name,
+			Width:        evenOf(info.Width * height / info.Height),
+			Height:       evenOf(height),
+			VideoBitrate: r.VideoBitrate,
+			AudioBitrate: r.AudioBitrate,
+		})
+	}
+	return res
+}
+
+// BuildMasterPlaylist build HLS master playlist that reference each rendition's playlist
+func BuildMasterPlaylist(renditions []*Rendition) string {
+	var sb strings.Builder
+	// 单文件分片使用了EXT-X-BYTERANGE，需要版本4
+	sb.WriteString("#EXTM3U\n#EXT-X-VERSION:4\n")
+	for _, r := range renditions {
+		fmt.Fprintf(&sb, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n%s.m3u8\n",
+			bandwidthOf(r.VideoBitrate)+bandwidthOf(r.AudioBitrate), r.Width, r.Height, r.Name)
+	}
+	return sb.String()
+}
+
+// PosterTime choose the time of poster frame, avoid black frame at beginning
+func PosterTime(duration time.Duration) time.Duration {
+	if at := duration / 2; at < time.Second {
+		return at
+	}
+	return time.Second
+}
+
+func evenOf(n int) int {
+	if n < 2 {
+		return 2
+	}
+	return n &^ 1
+}
+
+// bandwidthOf parse bitrate like 800k/2M to bits per second
+func bandwidthOf(bitrate string) int {
+	var n float64
+	var unit string
+	fmt.Sscanf(strings.TrimSpace(bitrate), "%f%s", &n, &unit)
+	switch strings.ToLower(unit) {
+	case "k":
+		n *= 1000
+	case "m":
+		n *= 1000 * 1000
+	}
+	return int(n)
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package media
+
+import (
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("Media", func() {
+	renditions := []*Rendition{
+		{Name: "720p", Height: 720, VideoBitrate: "2800k", AudioBitrate: "128k"},
+		{Name: "360p", Height: 360, VideoBitrate: "800k", AudioBitrate: "96k"},
+		{Name: "1080p", Height: 1080, VideoBitrate: "5M", AudioBitrate: "192k"},
+	}
+
+	g.It("select renditions not higher than source", func() {
+		res := SelectRenditions(&ProbeInfo{Width: 1280, Height: 720}, renditions)
+		m.Expect(res).To(m.HaveLen(2))
+		m.Expect(res[0].Name).To(m.Equal("360p"))
+		m.Expect(res[0].Width).To(m.Equal(640))
+		m.Expect(res[1].Name).To(m.Equal("720p"))
+		m.Expect(res[1].Width).To(m.Equal(1280))
+
+		// 低于最低清晰度的视频也会保留一个原尺寸的版本
+		res = SelectRenditions(&ProbeInfo{Width: 321, Height: 241}, renditions)
+		m.Expect(res).To(m.HaveLen(1))
+		m.Expect(res[0].Width).To(m.Equal(320))
+		m.Expect(res[0].Height).To(m.Equal(240))
+
+		m.Expect(SelectRenditions(&ProbeInfo{}, renditions)).To(m.BeEmpty())
+	})
+
+	g.It("build master playlist", func() {
+		res := SelectRenditions(&ProbeInfo{Width: 720, Height: 1280}, renditions)
+		m.Expect(BuildMasterPlaylist(res)).To(m.Equal("#EXTM3U\n#EXT-X-VERSION:4\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=896000,RESOLUTION=202x360\n360p.m3u8\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=404x720\n720p.m3u8\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=5192000,RESOLUTION=606x1080\n1080p.m3u8\n"))
+	})
+
+	g.It("parse probe output", func() {
+		info, err := parseProbe([]byte(`{"streams":[{"width":1920,"height":1080,"side_data_list":[{"rotation":-90}]}],"format":{"duration":"12.500000"}}`))
+		m.Expect(err).To(m.BeNil())
+		m.Expect(info.Width).To(m.Equal(1080))
+		m.Expect(info.Height).To(m.Equal(1920))
+		m.Expect(info.Duration).To(m.Equal(12500 * time.Millisecond))
+
+		info, err = parseProbe([]byte(`{"streams":[{"width":640,"height":480,"tags":{"rotate":"180"}}],"format":{}}`))
+		m.Expect(err).To(m.BeNil())
+		m.Expect(info.Width).To(m.Equal(640))
+		m.Expect(info.Duration).To(m.BeZero())
+
+		_, err = parseProbe([]byte(`{"streams":[],"format":{"duration":"1.0"}}`))
+		m.Expect(err).To(m.Equal(ErrNoVideoStream))
+	})
+
+	g.It("choose poster time", func() {
+		m.Expect(PosterTime(10 * time.Second)).To(m.Equal(time.Second))
+		m.Expect(PosterTime(time.Second)).To(m.Equal(500 * time.Millisecond))
+		m.Expect(bandwidthOf("2.5M")).To(m.Equal(2500000))
+	})
+})
@@ -0,0 +1,209 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	_chunkSignaturePrefix = "chunk-signature="
+	_trailerSignatureKey  = "x-amz-trailer-signature"
+	_maxChunkLineLength   = 4096
+	_maxChunkSize         = 16 << 20
+)
+
+var (
+	// ErrMalformedChunk the aws-chunked payload is malformed
+	ErrMalformedChunk = errors.New("the aws-chunked payload is malformed")
+)
+
+// chunkedReader decode aws-chunked payload, chunk signature is verified if ctx is not nil
+type chunkedReader struct {
+	r        *bufio.Reader
+	ctx      *signContext
+	trailer  bool
+	prevSig  string
+	chunkSig string
+	remain   int64
+	inChunk  bool
+	sum      hash.Hash
+	err      error
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.remain == 0 {
+		if c.err = c.nextChunk(); c.err != nil {
+			return 0, c.err
+		}
+	}
+	if int64(len(p)) > c.remain {
+		p = p[:c.remain]
+	}
+	n, err := c.r.Read(p)
+	c.sum.Write(p[:n])
+	c.remain -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	c.err = err
+	return n, err
+}
+
+// nextChunk 结束当前分块并读取下一个分块头，最后一个分块返回io.EOF
+func (c *chunkedReader) nextChunk() error {
+	if c.inChunk {
+		if err := c.expectCRLF(); err != nil {
+			return err
+		}
+		if err := c.verifyChunk(); err != nil {
+			return err
+		}
+		c.inChunk = false
+	}
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	sizeStr, ext, _ := strings.Cut(line, ";")
+	size, err := strconv.ParseInt(sizeStr, 16, 64)
+	if err != nil || size < 0 || size > _maxChunkSize {
+		return ErrMalformedChunk
+	}
+	c.chunkSig = strings.TrimPrefix(ext, _chunkSignaturePrefix)
+	if c.ctx != nil && (c.chunkSig == "" || c.chunkSig == ext) {
+		return ErrMalformedChunk
+	}
+	c.sum.Reset()
+	if size > 0 {
+		c.remain, c.inChunk = size, true
+		return nil
+	}
+	// 最后一个空分块
+	if err = c.verifyChunk(); err != nil {
+		return err
+	}
+	if c.trailer {
+		err = c.readTrailer()
+	} else {
+		err = c.expectCRLF()
+	}
+	if err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+func (c *chunkedReader) verifyChunk() error {
+	if c.ctx == nil {
+		return nil
+	}
+	toSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256-PAYLOAD",
+		c.ctx.amzDate,
+		c.ctx.scope,
+		c.prevSig,
+		_emptySHA256,
+		hexSum(c.sum),
+	}, "\n")
+	expected := signature(c.ctx.signingKey, toSign)
+	if !hmac.Equal([]byte(expected), []byte(c.chunkSig)) {
+		return ErrSignatureMismatch
+	}
+	c.prevSig = expected
+	return nil
+}
+
+// readTrailer 读取分块后的trailer，签名的trailer需要校验x-amz-trailer-signature
+func (c *chunkedReader) readTrailer() error {
+	var trailers bytes.Buffer
+	trailerSig := ""
+	for {
+		line, err := c.readLine()
+		if err == io.EOF && (c.ctx == nil || trailerSig != "") {
+			break
+		} else if err != nil {
+			return err
+		}
+		if line == "" {
+			if c.ctx == nil || trailerSig != "" {
+				break
+			}
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(k, _trailerSignatureKey) {
+			trailerSig = v
+			continue
+		}
+		trailers.WriteString(line + "\n")
+	}
+	if c.ctx == nil {
+		return nil
+	}
+	sum := sha256.Sum256(trailers.Bytes())
+	toSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256-TRAILER",
+		c.ctx.amzDate,
+		c.ctx.scope,
+		c.prevSig,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+	if !hmac.Equal([]byte(signature(c.ctx.signingKey, toSign)), []byte(trailerSig)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull || len(line) > _maxChunkLineLength {
+		return "", ErrMalformedChunk
+	} else if err == io.EOF && len(line) == 0 {
+		return "", io.EOF
+	} else if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+func (c *chunkedReader) expectCRLF() error {
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, buf); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	if buf[0] != '\r' || buf[1] != '\n' {
+		return ErrMalformedChunk
+	}
+	return nil
+}
+
+// newChunkedReader create an aws-chunked payload reader by the payload hash of request
+func newChunkedReader(r io.Reader, ctx *signContext) io.Reader {
+	c := &chunkedReader{
+		r:   bufio.NewReaderSize(r, _maxChunkLineLength),
+		sum: sha256.New(),
+	}
+	switch ctx.payloadHash {
+	case _streamingPayload:
+		c.ctx, c.prevSig = ctx, ctx.seedSignature
+	case _streamingPayloadTrailer:
+		c.ctx, c.prevSig, c.trailer = ctx, ctx.seedSignature, true
+	case _streamingUnsignedTrailer:
+		c.trailer = true
+	}
+	return c
+}
@@ -0,0 +1,681 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_xmlns          = "http://s3.amazonaws.com/doc/2006-03-01/"
+	_timeFormat     = "2006-01-02T15:04:05.000Z"
+	_defaultMaxKeys = 1000
+	_maxDeleteKeys  = 1000
+	_maxXmlBodySize = 1 << 20
+)
+
+var (
+	_ http.Handler = (*handler)(nil)
+
+	// errNotImplemented the requested api is not implemented
+	errNotImplemented = errors.New("a header or query you provided implies functionality that is not implemented")
+	// errMalformedXML the xml you provided was not well-formed
+	errMalformedXML = errors.New("the xml you provided was not well-formed or did not validate")
+	// errContentSHA256Mismatch the provided x-amz-content-sha256 header does not match what was computed
+	errContentSHA256Mismatch = errors.New("the provided x-amz-content-sha256 header does not match what was computed")
+)
+
+// Config configure used to create a NativeOBS http handler
+type Config struct {
+	Region    string
+	AccessKey string
+	SecretKey string
+	// PublicPrefixes object that key has these prefixes could be read anonymously
+	PublicPrefixes []string
+}
+
+type handler struct {
+	storage        Storage
+	signer         *Signer
+	region         string
+	publicPrefixes []string
+}
+
+type apiError struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestId string   `xml:"RequestId"`
+}
+
+type objectXml struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type prefixXml struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name     `xml:"ListBucketResult"`
+	Xmlns                 string       `xml:"xmlns,attr"`
+	Name                  string       `xml:"Name"`
+	Prefix                string       `xml:"Prefix"`
+	Delimiter             string       `xml:"Delimiter,omitempty"`
+	MaxKeys               int          `xml:"MaxKeys"`
+	IsTruncated           bool         `xml:"IsTruncated"`
+	Marker                *string      `xml:"Marker,omitempty"`
+	NextMarker            string       `xml:"NextMarker,omitempty"`
+	KeyCount              *int         `xml:"KeyCount,omitempty"`
+	ContinuationToken     string       `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string       `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string       `xml:"StartAfter,omitempty"`
+	Contents              []*objectXml `xml:"Contents"`
+	CommonPrefixes        []*prefixXml `xml:"CommonPrefixes"`
+}
+
+type bucketXml struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name     `xml:"ListAllMyBucketsResult"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Buckets []*bucketXml `xml:"Buckets>Bucket"`
+}
+
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"LocationConstraint"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:",chardata"`
+}
+
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadId string   `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+type partXml struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+type listPartsResult struct {
+	XMLName     xml.Name   `xml:"ListPartsResult"`
+	Xmlns       string     `xml:"xmlns,attr"`
+	Bucket      string     `xml:"Bucket"`
+	Key         string     `xml:"Key"`
+	UploadId    string     `xml:"UploadId"`
+	MaxParts    int        `xml:"MaxParts"`
+	IsTruncated bool       `xml:"IsTruncated"`
+	Parts       []*partXml `xml:"Part"`
+}
+
+type deleteObjectsRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deletedXml struct {
+	Key string `xml:"Key"`
+}
+
+type deleteErrorXml struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name          `xml:"DeleteResult"`
+	Xmlns   string            `xml:"xmlns,attr"`
+	Deleted []*deletedXml     `xml:"Deleted"`
+	Errors  []*deleteErrorXml `xml:"Error"`
+}
+
+// verifyReader 读取结束时校验内容长度与摘要
+type verifyReader struct {
+	r            io.Reader
+	size         int64
+	expectedSize int64
+	sha256       hash.Hash
+	sha256Hex    string
+	md5          hash.Hash
+	md5Base64    string
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.size += int64(n)
+	if v.sha256 != nil {
+		v.sha256.Write(p[:n])
+	}
+	if v.md5 != nil {
+		v.md5.Write(p[:n])
+	}
+	if err == io.EOF {
+		if v.expectedSize >= 0 && v.size != v.expectedSize {
+			return n, ErrIncompleteBody
+		}
+		if v.sha256 != nil && hexSum(v.sha256) != v.sha256Hex {
+			return n, errContentSHA256Mismatch
+		}
+		if v.md5 != nil && base64.StdEncoding.EncodeToString(v.md5.Sum(nil)) != v.md5Base64 {
+			return n, ErrBadDigest
+		}
+	}
+	return n, err
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	var ctx *signContext
+	if isAnonymous(r) {
+		if !h.isPublicRead(r, key) {
+			h.writeError(w, r, ErrAccessDenied)
+			return
+		}
+	} else {
+		var err error
+		if ctx, err = h.signer.Verify(r); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+	query := r.URL.Query()
+	switch {
+	case bucket == "" && r.Method == http.MethodGet:
+		h.listBuckets(w, r)
+	case bucket == "":
+		h.writeError(w, r, errNotImplemented)
+	case key == "":
+		h.serveBucket(w, r, bucket, query, ctx)
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		h.listObjectParts(w, r, bucket, key, query)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		h.getObject(w, r, bucket, key, query, ctx != nil)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		h.putObjectPart(w, r, bucket, key, query, ctx)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		h.copyObject(w, r, bucket, key)
+	case r.Method == http.MethodPut:
+		h.putObject(w, r, bucket, key, ctx)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		h.newMultipartUpload(w, r, bucket, key)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		h.completeMultipartUpload(w, r, bucket, key, query, ctx)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		if err := h.storage.AbortMultipartUpload(bucket, key, query.Get("uploadId")); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		if err := h.storage.DeleteObject(bucket, key); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		h.writeError(w, r, errNotImplemented)
+	}
+}
+
+func (h *handler) serveBucket(w http.ResponseWriter, r *http.Request, bucket string, query url.Values, ctx *signContext) {
+	switch {
+	case r.Method == http.MethodHead:
+		if exist, err := h.storage.BucketExists(bucket); err != nil || !exist {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && query.Has("location"):
+		if exist, err := h.storage.BucketExists(bucket); err != nil || !exist {
+			h.writeError(w, r, ErrNoSuchBucket)
+			return
+		}
+		h.writeXml(w, http.StatusOK, &locationConstraint{
+			Xmlns:    _xmlns,
+			Location: h.region,
+		})
+	case r.Method == http.MethodGet:
+		h.listObjects(w, r, bucket, query)
+	case r.Method == http.MethodPut:
+		// 忽略请求中的位置约束，所有桶都在同一个区域
+		io.Copy(io.Discard, io.LimitReader(h.payloadReader(r, ctx), _maxXmlBodySize))
+		if err := h.storage.MakeBucket(bucket); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Location", "/"+bucket)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPost && query.Has("delete"):
+		h.deleteObjects(w, r, bucket, ctx)
+	default:
+		h.writeError(w, r, errNotImplemented)
+	}
+}
+
+func (h *handler) listBuckets(w http.ResponseWriter, r *http.Request) {
+	buckets, err := h.storage.ListBuckets()
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res := &listAllMyBucketsResult{
+		Xmlns: _xmlns,
+	}
+	for _, bucket := range buckets {
+		res.Buckets = append(res.Buckets, &bucketXml{
+			Name:         bucket,
+			CreationDate: time.Unix(0, 0).UTC().Format(_timeFormat),
+		})
+	}
+	h.writeXml(w, http.StatusOK, res)
+}
+
+func (h *handler) listObjects(w http.ResponseWriter, r *http.Request, bucket string, query url.Values) {
+	maxKeys := _defaultMaxKeys
+	if v := query.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.writeError(w, r, ErrInvalidArgument)
+			return
+		}
+		maxKeys = min(n, _defaultMaxKeys)
+	}
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	res := &listBucketResult{
+		Xmlns:     _xmlns,
+		Name:      bucket,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		MaxKeys:   maxKeys,
+	}
+	isV2, marker := query.Get("list-type") == "2", query.Get("marker")
+	if isV2 {
+		marker = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			data, err := base64.StdEncoding.DecodeString(token)
+			if err != nil {
+				h.writeError(w, r, ErrInvalidArgument)
+				return
+			}
+			marker, res.ContinuationToken = string(data), token
+		}
+		res.StartAfter = query.Get("start-after")
+	} else {
+		res.Marker = &marker
+	}
+	objects, err := h.storage.ListObjects(bucket, prefix, delimiter, marker, maxKeys)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res.IsTruncated = objects.IsTruncated
+	for _, info := range objects.Objects {
+		res.Contents = append(res.Contents, &objectXml{
+			Key:          info.Key,
+			LastModified: info.ModTime.UTC().Format(_timeFormat),
+			ETag:         `"` + info.ETag + `"`,
+			Size:         info.Size,
+			StorageClass: "STANDARD",
+		})
+	}
+	for _, prefix := range objects.CommonPrefixes {
+		res.CommonPrefixes = append(res.CommonPrefixes, &prefixXml{
+			Prefix: prefix,
+		})
+	}
+	if isV2 {
+		keyCount := len(res.Contents) + len(res.CommonPrefixes)
+		res.KeyCount = &keyCount
+		if objects.IsTruncated {
+			res.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(objects.NextMarker))
+		}
+	} else {
+		res.NextMarker = objects.NextMarker
+	}
+	h.writeXml(w, http.StatusOK, res)
+}
+
+func (h *handler) getObject(w http.ResponseWriter, r *http.Request, bucket, key string, query url.Values, signed bool) {
+	info, reader, err := h.storage.GetObject(bucket, key)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	defer reader.Close()
+	header := w.Header()
+	header.Set("ETag", `"`+info.ETag+`"`)
+	header.Set("Content-Type", info.ContentType)
+	if info.ContentType == "" {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+	// 签名请求允许覆盖响应头，便于生成带下载文件名的链接
+	if signed {
+		if v := query.Get("response-content-type"); v != "" {
+			header.Set("Content-Type", v)
+		}
+		if v := query.Get("response-content-disposition"); v != "" {
+			header.Set("Content-Disposition", v)
+		}
+		if v := query.Get("response-cache-control"); v != "" {
+			header.Set("Cache-Control", v)
+		}
+	}
+	http.ServeContent(w, r, "", info.ModTime, reader)
+}
+
+func (h *handler) putObject(w http.ResponseWriter, r *http.Request, bucket, key string, ctx *signContext) {
+	info, err := h.storage.PutObject(bucket, key, h.payloadReader(r, ctx), r.Header.Get("Content-Type"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", `"`+info.ETag+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *handler) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	source, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		h.writeError(w, r, ErrInvalidArgument)
+		return
+	}
+	source, _, _ = strings.Cut(source, "?")
+	srcBucket, srcKey, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	// 仅支持同一个桶内复制对象
+	if srcBucket != bucket {
+		h.writeError(w, r, errNotImplemented)
+		return
+	}
+	info, err := h.storage.CopyObject(bucket, srcKey, key)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeXml(w, http.StatusOK, &copyObjectResult{
+		Xmlns:        _xmlns,
+		LastModified: info.ModTime.UTC().Format(_timeFormat),
+		ETag:         `"` + info.ETag + `"`,
+	})
+}
+
+func (h *handler) deleteObjects(w http.ResponseWriter, r *http.Request, bucket string, ctx *signContext) {
+	req := &deleteObjectsRequest{}
+	if err := xml.NewDecoder(io.LimitReader(h.payloadReader(r, ctx), _maxXmlBodySize)).Decode(req); err != nil {
+		h.writeError(w, r, errMalformedXML)
+		return
+	}
+	if len(req.Objects) > _maxDeleteKeys {
+		h.writeError(w, r, errMalformedXML)
+		return
+	}
+	res := &deleteResult{
+		Xmlns: _xmlns,
+	}
+	for _, obj := range req.Objects {
+		if err := h.storage.DeleteObject(bucket, obj.Key); err != nil {
+			code, _, message := toApiError(err)
+			res.Errors = append(res.Errors, &deleteErrorXml{
+				Key:     obj.Key,
+				Code:    code,
+				Message: message,
+			})
+		} else if !req.Quiet {
+			res.Deleted = append(res.Deleted, &deletedXml{
+				Key: obj.Key,
+			})
+		}
+	}
+	h.writeXml(w, http.StatusOK, res)
+}
+
+func (h *handler) newMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	uploadId, err := h.storage.NewMultipartUpload(bucket, key, r.Header.Get("Content-Type"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeXml(w, http.StatusOK, &initiateMultipartUploadResult{
+		Xmlns:    _xmlns,
+		Bucket:   bucket,
+		Key:      key,
+		UploadId: uploadId,
+	})
+}
+
+func (h *handler) putObjectPart(w http.ResponseWriter, r *http.Request, bucket, key string, query url.Values, ctx *signContext) {
+	number, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil {
+		h.writeError(w, r, ErrInvalidArgument)
+		return
+	}
+	part, err := h.storage.PutObjectPart(bucket, key, query.Get("uploadId"), number, h.payloadReader(r, ctx))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", `"`+part.ETag+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *handler) listObjectParts(w http.ResponseWriter, r *http.Request, bucket, key string, query url.Values) {
+	uploadId := query.Get("uploadId")
+	parts, err := h.storage.ListObjectParts(bucket, key, uploadId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res := &listPartsResult{
+		Xmlns:    _xmlns,
+		Bucket:   bucket,
+		Key:      key,
+		UploadId: uploadId,
+		MaxParts: MaxPartNumber,
+	}
+	for _, part := range parts {
+		res.Parts = append(res.Parts, &partXml{
+			PartNumber:   part.Number,
+			LastModified: part.ModTime.UTC().Format(_timeFormat),
+			ETag:         `"` + part.ETag + `"`,
+			Size:         part.Size,
+		})
+	}
+	h.writeXml(w, http.StatusOK, res)
+}
+
+func (h *handler) completeMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string, query url.Values, ctx *signContext) {
+	req := &completeMultipartUpload{}
+	if err := xml.NewDecoder(io.LimitReader(h.payloadReader(r, ctx), _maxXmlBodySize)).Decode(req); err != nil {
+		h.writeError(w, r, errMalformedXML)
+		return
+	}
+	parts := make([]CompletePart, 0, len(req.Parts))
+	for _, part := range req.Parts {
+		parts = append(parts, CompletePart{
+			Number: part.PartNumber,
+			ETag:   part.ETag,
+		})
+	}
+	info, err := h.storage.CompleteMultipartUpload(bucket, key, query.Get("uploadId"), parts)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeXml(w, http.StatusOK, &completeMultipartUploadResult{
+		Xmlns:    _xmlns,
+		Location: "/" + bucket + "/" + key,
+		Bucket:   bucket,
+		Key:      key,
+		ETag:     `"` + info.ETag + `"`,
+	})
+}
+
+// payloadReader 根据签名方式获取请求内容，需要时解码aws-chunked并校验内容摘要
+func (h *handler) payloadReader(r *http.Request, ctx *signContext) io.Reader {
+	v := &verifyReader{
+		r:            r.Body,
+		expectedSize: r.ContentLength,
+	}
+	if ctx != nil {
+		switch ctx.payloadHash {
+		case _unsignedPayload:
+		case _streamingPayload, _streamingPayloadTrailer, _streamingUnsignedTrailer:
+			v.r = newChunkedReader(r.Body, ctx)
+			v.expectedSize = -1
+			if size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64); err == nil {
+				v.expectedSize = size
+			}
+		default:
+			v.sha256, v.sha256Hex = sha256.New(), strings.ToLower(ctx.payloadHash)
+		}
+	}
+	if md5Base64 := r.Header.Get("Content-Md5"); md5Base64 != "" {
+		v.md5, v.md5Base64 = md5.New(), md5Base64
+	}
+	return v
+}
+
+func (h *handler) isPublicRead(r *http.Request, key string) bool {
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || key == "" {
+		return false
+	}
+	for _, prefix := range h.publicPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	code, status, message := toApiError(err)
+	requestId, _ := randomId()
+	w.Header().Set("X-Amz-Request-Id", requestId)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	h.writeXml(w, status, &apiError{
+		Code:      code,
+		Message:   message,
+		Resource:  r.URL.Path,
+		RequestId: requestId,
+	})
+}
+
+func (h *handler) writeXml(w http.ResponseWriter, status int, v any) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(xml.Header)+len(data)))
+	w.WriteHeader(status)
+	io.WriteString(w, xml.Header)
+	w.Write(data)
+}
+
+func isAnonymous(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "" && !r.URL.Query().Has("X-Amz-Algorithm")
+}
+
+// toApiError 转换错误为S3错误码与HTTP状态码
+func toApiError(err error) (string, int, string) {
+	for _, e := range []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{ErrNoSuchBucket, "NoSuchBucket", http.StatusNotFound},
+		{ErrNoSuchKey, "NoSuchKey", http.StatusNotFound},
+		{ErrNoSuchUpload, "NoSuchUpload", http.StatusNotFound},
+		{ErrInvalidBucketName, "InvalidBucketName", http.StatusBadRequest},
+		{ErrInvalidKey, "KeyTooLongError", http.StatusBadRequest},
+		{ErrInvalidArgument, "InvalidArgument", http.StatusBadRequest},
+		{ErrInvalidPart, "InvalidPart", http.StatusBadRequest},
+		{ErrInvalidPartOrder, "InvalidPartOrder", http.StatusBadRequest},
+		{ErrEntityTooSmall, "EntityTooSmall", http.StatusBadRequest},
+		{ErrBadDigest, "BadDigest", http.StatusBadRequest},
+		{ErrIncompleteBody, "IncompleteBody", http.StatusBadRequest},
+		{ErrMalformedChunk, "IncompleteBody", http.StatusBadRequest},
+		{io.ErrUnexpectedEOF, "IncompleteBody", http.StatusBadRequest},
+		{errContentSHA256Mismatch, "XAmzContentSHA256Mismatch", http.StatusBadRequest},
+		{errMalformedXML, "MalformedXML", http.StatusBadRequest},
+		{errNotImplemented, "NotImplemented", http.StatusNotImplemented},
+		{ErrAccessDenied, "AccessDenied", http.StatusForbidden},
+		{ErrRequestExpired, "AccessDenied", http.StatusForbidden},
+		{ErrInvalidAccessKeyId, "InvalidAccessKeyId", http.StatusForbidden},
+		{ErrSignatureMismatch, "SignatureDoesNotMatch", http.StatusForbidden},
+		{ErrRequestTimeTooSkewed, "RequestTimeTooSkewed", http.StatusForbidden},
+		{ErrAuthorizationMalformed, "AuthorizationHeaderMalformed", http.StatusBadRequest},
+	} {
+		if errors.Is(err, e.err) {
+			return e.code, e.status, e.err.Error()
+		}
+	}
+	return "InternalError", http.StatusInternalServerError, "we encountered an internal error, please try again"
+}
+
+// NewHandler create a http.Handler that provide a subset of S3 compatible api
+// in path-style for storage.
+func NewHandler(storage Storage, conf *Config) http.Handler {
+	return &handler{
+		storage:        storage,
+		signer:         NewSigner(conf.AccessKey, conf.SecretKey, conf.Region),
+		region:         conf.Region,
+		publicPrefixes: conf.PublicPrefixes,
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("Handler", g.Ordered, func() {
+	const (
+		accessKey = "paopao"
+		secretKey = "paopao-secret"
+		region    = "us-east-1"
+	)
+	var (
+		server *httptest.Server
+		client *minio.Client
+		signer *Signer
+		ctx    = context.Background()
+	)
+
+	httpGet := func(link string) (int, string) {
+		resp, err := http.Get(link)
+		m.Expect(err).To(m.BeNil())
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	g.BeforeAll(func() {
+		st, err := NewStorage(g.GinkgoT().TempDir(), "paopao")
+		m.Expect(err).To(m.BeNil())
+		server = httptest.NewServer(NewHandler(st, &Config{
+			Region:         region,
+			AccessKey:      accessKey,
+			SecretKey:      secretKey,
+			PublicPrefixes: []string{"public/"},
+		}))
+		u, _ := url.Parse(server.URL)
+		client, err = minio.New(u.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+			Region: region,
+		})
+		m.Expect(err).To(m.BeNil())
+		signer = NewSigner(accessKey, secretKey, region)
+	})
+
+	g.AfterAll(func() {
+		server.Close()
+	})
+
+	g.It("bucket", func() {
+		exist, err := client.BucketExists(ctx, "paopao")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(exist).To(m.BeTrue())
+		exist, err = client.BucketExists(ctx, "other")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(exist).To(m.BeFalse())
+		m.Expect(client.MakeBucket(ctx, "other", minio.MakeBucketOptions{})).To(m.Succeed())
+		buckets, err := client.ListBuckets(ctx)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(buckets).To(m.HaveLen(2))
+	})
+
+	g.It("put get and range", func() {
+		content := "0123456789abcdef"
+		info, err := client.PutObject(ctx, "paopao", "public/image/a b.txt", strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+			ContentType: "text/plain",
+		})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(info.Size).To(m.Equal(int64(len(content))))
+
+		stat, err := client.StatObject(ctx, "paopao", "public/image/a b.txt", minio.StatObjectOptions{})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(stat.ETag).To(m.Equal(info.ETag))
+		m.Expect(stat.ContentType).To(m.Equal("text/plain"))
+
+		opts := minio.GetObjectOptions{}
+		m.Expect(opts.SetRange(4, 9)).To(m.Succeed())
+		obj, err := client.GetObject(ctx, "paopao", "public/image/a b.txt", opts)
+		m.Expect(err).To(m.BeNil())
+		data, err := io.ReadAll(obj)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(string(data)).To(m.Equal("456789"))
+
+		// 公开前缀的对象允许匿名读取
+		code, body := httpGet(server.URL + "/paopao/public/image/a%20b.txt")
+		m.Expect(code).To(m.Equal(http.StatusOK))
+		m.Expect(body).To(m.Equal(content))
+	})
+
+	g.It("copy list and delete", func() {
+		for _, key := range []string{"attachment/1.zip", "attachment/2.zip", "attachment/3/4.zip"} {
+			_, err := client.PutObject(ctx, "paopao", key, strings.NewReader(key), int64(len(key)), minio.PutObjectOptions{})
+			m.Expect(err).To(m.BeNil())
+		}
+		_, err := client.CopyObject(ctx, minio.CopyDestOptions{Bucket: "paopao", Object: "attachment/5.zip"}, minio.CopySrcOptions{Bucket: "paopao", Object: "attachment/1.zip"})
+		m.Expect(err).To(m.BeNil())
+
+		var keys []string
+		for obj := range client.ListObjects(ctx, "paopao", minio.ListObjectsOptions{Prefix: "attachment/", MaxKeys: 2}) {
+			m.Expect(obj.Err).To(m.BeNil())
+			keys = append(keys, obj.Key)
+		}
+		m.Expect(keys).To(m.Equal([]string{"attachment/1.zip", "attachment/2.zip", "attachment/5.zip", "attachment/3/"}))
+
+		m.Expect(client.RemoveObject(ctx, "paopao", "attachment/2.zip", minio.RemoveObjectOptions{})).To(m.Succeed())
+		objectsCh := make(chan minio.ObjectInfo, 2)
+		objectsCh <- minio.ObjectInfo{Key: "attachment/1.zip"}
+		objectsCh <- minio.ObjectInfo{Key: "attachment/5.zip"}
+		close(objectsCh)
+		for err := range client.RemoveObjects(ctx, "paopao", objectsCh, minio.RemoveObjectsOptions{}) {
+			m.Expect(err.Err).To(m.BeNil())
+		}
+		keys = keys[:0]
+		for obj := range client.ListObjects(ctx, "paopao", minio.ListObjectsOptions{Prefix: "attachment/", Recursive: true}) {
+			keys = append(keys, obj.Key)
+		}
+		m.Expect(keys).To(m.Equal([]string{"attachment/3/4.zip"}))
+	})
+
+	g.It("multipart upload", func() {
+		core := minio.Core{Client: client}
+		uploadId, err := core.NewMultipartUpload(ctx, "paopao", "video/big.mp4", minio.PutObjectOptions{ContentType: "video/mp4"})
+		m.Expect(err).To(m.BeNil())
+		part1 := bytes.Repeat([]byte("v"), MinPartSize)
+		p1, err := core.PutObjectPart(ctx, "paopao", "video/big.mp4", uploadId, 1, bytes.NewReader(part1), int64(len(part1)), minio.PutObjectPartOptions{})
+		m.Expect(err).To(m.BeNil())
+		p2, err := core.PutObjectPart(ctx, "paopao", "video/big.mp4", uploadId, 2, strings.NewReader("end"), 3, minio.PutObjectPartOptions{})
+		m.Expect(err).To(m.BeNil())
+		parts, err := core.ListObjectParts(ctx, "paopao", "video/big.mp4", uploadId, 0, 100)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(parts.ObjectParts).To(m.HaveLen(2))
+		_, err = core.CompleteMultipartUpload(ctx, "paopao", "video/big.mp4", uploadId, []minio.CompletePart{
+			{PartNumber: 1, ETag: p1.ETag},
+			{PartNumber: 2, ETag: p2.ETag},
+		}, minio.PutObjectOptions{})
+		m.Expect(err).To(m.BeNil())
+		stat, err := client.StatObject(ctx, "paopao", "video/big.mp4", minio.StatObjectOptions{})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(stat.Size).To(m.Equal(int64(MinPartSize + 3)))
+		m.Expect(stat.ContentType).To(m.Equal("video/mp4"))
+
+		uploadId, err = core.NewMultipartUpload(ctx, "paopao", "video/abort.mp4", minio.PutObjectOptions{})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(core.AbortMultipartUpload(ctx, "paopao", "video/abort.mp4", uploadId)).To(m.Succeed())
+	})
+
+	g.It("presigned url", func() {
+		_, err := client.PutObject(ctx, "paopao", "attachment/secret.zip", strings.NewReader("secret"), 6, minio.PutObjectOptions{})
+		m.Expect(err).To(m.BeNil())
+		link := server.URL + "/paopao/attachment/secret.zip"
+
+		code, _ := httpGet(link)
+		m.Expect(code).To(m.Equal(http.StatusForbidden))
+
+		signed, err := signer.Presign(http.MethodGet, link, time.Minute)
+		m.Expect(err).To(m.BeNil())
+		code, body := httpGet(signed)
+		m.Expect(code).To(m.Equal(http.StatusOK))
+		m.Expect(body).To(m.Equal("secret"))
+
+		presigned, err := client.PresignedGetObject(ctx, "paopao", "attachment/secret.zip", time.Minute, nil)
+		m.Expect(err).To(m.BeNil())
+		code, _ = httpGet(presigned.String())
+		m.Expect(code).To(m.Equal(http.StatusOK))
+
+		expired, err := signer.presignAt(http.MethodGet, link, time.Minute, time.Now().UTC().Add(-time.Hour))
+		m.Expect(err).To(m.BeNil())
+		code, body = httpGet(expired)
+		m.Expect(code).To(m.Equal(http.StatusForbidden))
+		m.Expect(body).To(m.ContainSubstring("AccessDenied"))
+
+		tampered := strings.Replace(signed, "secret.zip", "other.zip", 1)
+		code, body = httpGet(tampered)
+		m.Expect(code).To(m.Equal(http.StatusForbidden))
+		m.Expect(body).To(m.ContainSubstring("SignatureDoesNotMatch"))
+
+		forged, err := NewSigner(accessKey, "wrong-secret", region).Presign(http.MethodGet, link, time.Minute)
+		m.Expect(err).To(m.BeNil())
+		code, _ = httpGet(forged)
+		m.Expect(code).To(m.Equal(http.StatusForbidden))
+	})
+
+	g.It("deny wrong credentials", func() {
+		u, _ := url.Parse(server.URL)
+		other, err := minio.New(u.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(accessKey, "wrong-secret", ""),
+			Region: region,
+		})
+		m.Expect(err).To(m.BeNil())
+		_, err = other.PutObject(ctx, "paopao", "public/x", strings.NewReader("x"), 1, minio.PutObjectOptions{})
+		m.Expect(minio.ToErrorResponse(err).Code).To(m.Equal("SignatureDoesNotMatch"))
+	})
+})
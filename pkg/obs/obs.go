@@ -0,0 +1,104 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// package obs implement a native object storage that save object content
+// addressed by sha256 on local disk and provide a subset of S3 compatible api.
+package obs
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNoSuchBucket the specified bucket does not exist
+	ErrNoSuchBucket = errors.New("the specified bucket does not exist")
+	// ErrNoSuchKey the specified key does not exist
+	ErrNoSuchKey = errors.New("the specified key does not exist")
+	// ErrNoSuchUpload the specified multipart upload does not exist
+	ErrNoSuchUpload = errors.New("the specified multipart upload does not exist")
+	// ErrInvalidBucketName the specified bucket name is not valid
+	ErrInvalidBucketName = errors.New("the specified bucket name is not valid")
+	// ErrInvalidKey the specified object key is not valid
+	ErrInvalidKey = errors.New("the specified object key is not valid")
+	// ErrInvalidArgument invalid argument
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrInvalidPart one or more of the specified parts could not be found or not match
+	ErrInvalidPart = errors.New("one or more of the specified parts could not be found")
+	// ErrInvalidPartOrder the list of parts was not in ascending order
+	ErrInvalidPartOrder = errors.New("the list of parts was not in ascending order")
+	// ErrEntityTooSmall proposed upload is smaller than the minimum allowed object size
+	ErrEntityTooSmall = errors.New("proposed upload is smaller than the minimum allowed size")
+	// ErrBadDigest the content digest did not match what we received
+	ErrBadDigest = errors.New("the content digest did not match what we received")
+	// ErrIncompleteBody did not receive the number of bytes specified by the content length
+	ErrIncompleteBody = errors.New("did not receive the number of bytes specified")
+)
+
+const (
+	// MinPartSize the minimum allowed size of multipart upload part except the last one
+	MinPartSize = 5 << 20
+	// MaxPartNumber the maximum part number of multipart upload
+	MaxPartNumber = 10000
+)
+
+// ObjectInfo object meta information
+type ObjectInfo struct {
+	Bucket      string    `json:"bucket"`
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	Hash        string    `json:"hash"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// PartInfo multipart upload part information
+type PartInfo struct {
+	Number  int       `json:"number"`
+	Size    int64     `json:"size"`
+	ETag    string    `json:"etag"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// CompletePart part used to complete a multipart upload
+type CompletePart struct {
+	Number int
+	ETag   string
+}
+
+// ListResult result of list objects
+type ListResult struct {
+	Objects        []*ObjectInfo
+	CommonPrefixes []string
+	IsTruncated    bool
+	NextMarker     string
+}
+
+// ReadSeekCloser object content reader
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// Storage native object storage interface
+type Storage interface {
+	MakeBucket(bucket string) error
+	BucketExists(bucket string) (bool, error)
+	ListBuckets() ([]string, error)
+
+	PutObject(bucket, key string, reader io.Reader, contentType string) (*ObjectInfo, error)
+	GetObject(bucket, key string) (*ObjectInfo, ReadSeekCloser, error)
+	StatObject(bucket, key string) (*ObjectInfo, error)
+	CopyObject(bucket, srcKey, dstKey string) (*ObjectInfo, error)
+	DeleteObject(bucket, key string) error
+	ListObjects(bucket, prefix, delimiter, marker string, maxKeys int) (*ListResult, error)
+
+	NewMultipartUpload(bucket, key, contentType string) (string, error)
+	PutObjectPart(bucket, key, uploadId string, number int, reader io.Reader) (*PartInfo, error)
+	ListObjectParts(bucket, key, uploadId string) ([]*PartInfo, error)
+	CompleteMultipartUpload(bucket, key, uploadId string, parts []CompletePart) (*ObjectInfo, error)
+	AbortMultipartUpload(bucket, key, uploadId string) error
+}
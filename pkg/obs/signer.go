@@ -0,0 +1,315 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_signAlgorithm            = "AWS4-HMAC-SHA256"
+	_serviceS3                = "s3"
+	_scopeTerminator          = "aws4_request"
+	_iso8601Format            = "20060102T150405Z"
+	_yyyymmddFormat           = "20060102"
+	_unsignedPayload          = "UNSIGNED-PAYLOAD"
+	_streamingPayload         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	_streamingPayloadTrailer  = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	_streamingUnsignedTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	_emptySHA256              = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	_maxClockSkew             = 15 * time.Minute
+	_maxPresignExpires        = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrAccessDenied access denied
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidAccessKeyId the access key id you provided does not exist
+	ErrInvalidAccessKeyId = errors.New("the access key id you provided does not exist")
+	// ErrSignatureMismatch the request signature we calculated does not match the signature you provided
+	ErrSignatureMismatch = errors.New("the request signature we calculated does not match the signature you provided")
+	// ErrRequestExpired the presigned request has expired
+	ErrRequestExpired = errors.New("request has expired")
+	// ErrRequestTimeTooSkewed the difference between the request time and the server's time is too large
+	ErrRequestTimeTooSkewed = errors.New("the difference between the request time and the current time is too large")
+	// ErrAuthorizationMalformed the authorization of request is malformed
+	ErrAuthorizationMalformed = errors.New("the authorization of request is malformed")
+)
+
+// Signer sign and verify request by AWS Signature Version 4
+type Signer struct {
+	accessKey string
+	secretKey string
+	region    string
+}
+
+// signContext information of a verified request used to verify streaming payload
+type signContext struct {
+	signingKey    []byte
+	amzDate       string
+	scope         string
+	seedSignature string
+	payloadHash   string
+}
+
+// Presign return a presigned GET/HEAD url that is valid within expires
+func (s *Signer) Presign(method string, rawURL string, expires time.Duration) (string, error) {
+	return s.presignAt(method, rawURL, expires, time.Now().UTC())
+}
+
+func (s *Signer) presignAt(method string, rawURL string, expires time.Duration, now time.Time) (string, error) {
+	if expires <= 0 || expires > _maxPresignExpires {
+		return "", ErrAuthorizationMalformed
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	amzDate, scope := now.Format(_iso8601Format), s.scope(now)
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", _signAlgorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonical := canonicalRequest(method, u.Path, query, []string{"host"}, func(string) string {
+		return u.Host
+	}, _unsignedPayload)
+	signingKey := s.signingKey(now.Format(_yyyymmddFormat))
+	query.Set("X-Amz-Signature", signature(signingKey, stringToSign(amzDate, scope, canonical)))
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+// Verify verify the signature of request that signed in header or presigned in query
+func (s *Signer) Verify(r *http.Request) (*signContext, error) {
+	if r.URL.Query().Has("X-Amz-Algorithm") {
+		return s.verifyPresigned(r, time.Now().UTC())
+	}
+	return s.verifyHeader(r, time.Now().UTC())
+}
+
+func (s *Signer) verifyHeader(r *http.Request, now time.Time) (*signContext, error) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, _signAlgorithm+" ") {
+		return nil, ErrAuthorizationMalformed
+	}
+	fields := make(map[string]string)
+	for _, item := range strings.Split(strings.TrimPrefix(auth, _signAlgorithm), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(item), "="); ok {
+			fields[k] = v
+		}
+	}
+	credential, signedHeaders, sig := fields["Credential"], fields["SignedHeaders"], fields["Signature"]
+	if credential == "" || signedHeaders == "" || sig == "" {
+		return nil, ErrAuthorizationMalformed
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	reqTime, err := time.Parse(_iso8601Format, amzDate)
+	if err != nil {
+		return nil, ErrAuthorizationMalformed
+	}
+	if d := now.Sub(reqTime); d > _maxClockSkew || d < -_maxClockSkew {
+		return nil, ErrRequestTimeTooSkewed
+	}
+	scope, err := s.checkCredential(credential, reqTime)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return nil, ErrAuthorizationMalformed
+	}
+	canonical := canonicalRequest(r.Method, r.URL.Path, r.URL.Query(), strings.Split(signedHeaders, ";"), headerValue(r), payloadHash)
+	signingKey := s.signingKey(reqTime.Format(_yyyymmddFormat))
+	if !hmac.Equal([]byte(sig), []byte(signature(signingKey, stringToSign(amzDate, scope, canonical)))) {
+		return nil, ErrSignatureMismatch
+	}
+	return &signContext{
+		signingKey:    signingKey,
+		amzDate:       amzDate,
+		scope:         scope,
+		seedSignature: sig,
+		payloadHash:   payloadHash,
+	}, nil
+}
+
+func (s *Signer) verifyPresigned(r *http.Request, now time.Time) (*signContext, error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != _signAlgorithm {
+		return nil, ErrAuthorizationMalformed
+	}
+	amzDate, sig := query.Get("X-Amz-Date"), query.Get("X-Amz-Signature")
+	reqTime, err := time.Parse(_iso8601Format, amzDate)
+	if err != nil || sig == "" {
+		return nil, ErrAuthorizationMalformed
+	}
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires <= 0 || time.Duration(expires)*time.Second > _maxPresignExpires {
+		return nil, ErrAuthorizationMalformed
+	}
+	if reqTime.Sub(now) > _maxClockSkew {
+		return nil, ErrRequestTimeTooSkewed
+	}
+	if now.After(reqTime.Add(time.Duration(expires) * time.Second)) {
+		return nil, ErrRequestExpired
+	}
+	scope, err := s.checkCredential(query.Get("X-Amz-Credential"), reqTime)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := _unsignedPayload
+	if hash := query.Get("X-Amz-Content-Sha256"); hash != "" {
+		payloadHash = hash
+	}
+	query.Del("X-Amz-Signature")
+	canonical := canonicalRequest(r.Method, r.URL.Path, query, strings.Split(query.Get("X-Amz-SignedHeaders"), ";"), headerValue(r), payloadHash)
+	signingKey := s.signingKey(reqTime.Format(_yyyymmddFormat))
+	if !hmac.Equal([]byte(sig), []byte(signature(signingKey, stringToSign(amzDate, scope, canonical)))) {
+		return nil, ErrSignatureMismatch
+	}
+	return &signContext{
+		signingKey:  signingKey,
+		amzDate:     amzDate,
+		scope:       scope,
+		payloadHash: payloadHash,
+	}, nil
+}
+
+// checkCredential 检查凭证 AccessKey/yyyymmdd/region/s3/aws4_request 并返回签名范围
+func (s *Signer) checkCredential(credential string, reqTime time.Time) (string, error) {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[3] != _serviceS3 || parts[4] != _scopeTerminator {
+		return "", ErrAuthorizationMalformed
+	}
+	if parts[0] != s.accessKey {
+		return "", ErrInvalidAccessKeyId
+	}
+	if parts[1] != reqTime.Format(_yyyymmddFormat) || parts[2] != s.region {
+		return "", ErrAuthorizationMalformed
+	}
+	return strings.Join(parts[1:], "/"), nil
+}
+
+func (s *Signer) scope(t time.Time) string {
+	return t.Format(_yyyymmddFormat) + "/" + s.region + "/" + _serviceS3 + "/" + _scopeTerminator
+}
+
+func (s *Signer) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, _serviceS3)
+	return hmacSHA256(key, _scopeTerminator)
+}
+
+func canonicalRequest(method string, path string, query url.Values, signedHeaders []string, valueFn func(string) string, payloadHash string) string {
+	if path == "" {
+		path = "/"
+	}
+	var headers strings.Builder
+	for _, name := range signedHeaders {
+		headers.WriteString(name + ":" + valueFn(name) + "\n")
+	}
+	return strings.Join([]string{
+		method,
+		awsEncode(path, false),
+		canonicalQuery(query),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalQuery 按键值排序并编码查询参数
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			items = append(items, awsEncode(k, true)+"="+awsEncode(v, true))
+		}
+	}
+	return strings.Join(items, "&")
+}
+
+func headerValue(r *http.Request) func(string) string {
+	return func(name string) string {
+		switch name {
+		case "host":
+			return r.Host
+		case "content-length":
+			// net/http将Content-Length从Header中移除
+			if r.Header.Get(name) == "" {
+				return strconv.FormatInt(r.ContentLength, 10)
+			}
+		case "transfer-encoding":
+			if r.Header.Get(name) == "" {
+				return strings.Join(r.TransferEncoding, ",")
+			}
+		}
+		var values []string
+		for _, v := range r.Header.Values(name) {
+			values = append(values, strings.Join(strings.Fields(v), " "))
+		}
+		return strings.Join(values, ",")
+	}
+}
+
+func stringToSign(amzDate string, scope string, canonicalRequest string) string {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	return _signAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+}
+
+func signature(signingKey []byte, stringToSign string) string {
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsEncode uri encode as AWS required, all characters except unreserved are encoded
+func awsEncode(s string, encodeSlash bool) string {
+	const hexUpper = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexUpper[c>>4])
+		b.WriteByte(hexUpper[c&15])
+	}
+	return b.String()
+}
+
+// NewSigner create a Signer that sign request by AWS Signature Version 4
+func NewSigner(accessKey string, secretKey string, region string) *Signer {
+	return &Signer{
+		accessKey: accessKey,
+		secretKey: secretKey,
+		region:    region,
+	}
+}
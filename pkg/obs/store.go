@@ -0,0 +1,739 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	_ Storage = (*diskStorage)(nil)
+)
+
+const (
+	_dirBuckets = "buckets"
+	_dirBlobs   = "blobs"
+	_dirUploads = "uploads"
+	_dirTemp    = "tmp"
+
+	// 对象元信息目录与文件名前缀，避免对象键 a 与 a/b 在磁盘上冲突
+	_prefixDir  = "d_"
+	_prefixFile = "f_"
+
+	_maxKeyLength     = 1024
+	_maxSegmentLength = 250
+	_uploadInfoFile   = "upload.json"
+	_partFilePrefix   = "part."
+)
+
+// diskStorage 以sha256为地址在磁盘保存对象内容，相同内容只保存一份并以引用计数管理
+type diskStorage struct {
+	mu   sync.RWMutex
+	root string
+}
+
+type uploadInfo struct {
+	Bucket      string    `json:"bucket"`
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Initiated   time.Time `json:"initiated"`
+}
+
+type tempFile struct {
+	path   string
+	size   int64
+	sha256 string
+	md5    string
+}
+
+func (s *diskStorage) MakeBucket(bucket string) error {
+	if !isValidBucketName(bucket) {
+		return ErrInvalidBucketName
+	}
+	return os.MkdirAll(s.bucketPath(bucket), 0750)
+}
+
+func (s *diskStorage) BucketExists(bucket string) (bool, error) {
+	if !isValidBucketName(bucket) {
+		return false, nil
+	}
+	fi, err := os.Stat(s.bucketPath(bucket))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return fi.IsDir(), nil
+}
+
+func (s *diskStorage) ListBuckets() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, _dirBuckets))
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			buckets = append(buckets, entry.Name())
+		}
+	}
+	return buckets, nil
+}
+
+func (s *diskStorage) PutObject(bucket, key string, reader io.Reader, contentType string) (*ObjectInfo, error) {
+	if err := s.checkObject(bucket, key); err != nil {
+		return nil, err
+	}
+	tmp, err := s.writeTemp(reader)
+	if err != nil {
+		return nil, err
+	}
+	info := &ObjectInfo{
+		Bucket:      bucket,
+		Key:         key,
+		Size:        tmp.size,
+		ContentType: contentType,
+		ETag:        tmp.md5,
+		Hash:        tmp.sha256,
+		ModTime:     time.Now().UTC(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = s.linkBlob(tmp.path, tmp.sha256); err != nil {
+		return nil, err
+	}
+	if err = s.putMeta(info); err != nil {
+		s.unrefBlob(info.Hash)
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *diskStorage) GetObject(bucket, key string) (*ObjectInfo, ReadSeekCloser, error) {
+	if err := s.checkObject(bucket, key); err != nil {
+		return nil, nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, err := s.getMeta(bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 在锁内打开文件，之后即使内容被删除也不影响读取
+	file, err := os.Open(s.blobPath(info.Hash))
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, file, nil
+}
+
+func (s *diskStorage) StatObject(bucket, key string) (*ObjectInfo, error) {
+	if err := s.checkObject(bucket, key); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getMeta(bucket, key)
+}
+
+func (s *diskStorage) CopyObject(bucket, srcKey, dstKey string) (*ObjectInfo, error) {
+	if err := s.checkObject(bucket, srcKey); err != nil {
+		return nil, err
+	}
+	if err := s.checkObject(bucket, dstKey); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, err := s.getMeta(bucket, srcKey)
+	if err != nil {
+		return nil, err
+	}
+	// 内容寻址存储，复制对象只需要增加内容的引用计数
+	if err = s.refBlob(src.Hash); err != nil {
+		return nil, err
+	}
+	info := *src
+	info.Key, info.ModTime = dstKey, time.Now().UTC()
+	if err = s.putMeta(&info); err != nil {
+		s.unrefBlob(info.Hash)
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (s *diskStorage) DeleteObject(bucket, key string) error {
+	if err := s.checkObject(bucket, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.getMeta(bucket, key)
+	if err == ErrNoSuchKey {
+		// 与S3保持一致，删除不存在的对象不报错
+		return nil
+	} else if err != nil {
+		return err
+	}
+	metaPath := s.metaPath(bucket, key)
+	if err = os.Remove(metaPath); err != nil {
+		return err
+	}
+	s.removeEmptyDirs(filepath.Dir(metaPath), s.bucketPath(bucket))
+	return s.unrefBlob(info.Hash)
+}
+
+func (s *diskStorage) ListObjects(bucket, prefix, delimiter, marker string, maxKeys int) (*ListResult, error) {
+	if exist, err := s.BucketExists(bucket); err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, ErrNoSuchBucket
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys, err := s.listKeys(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	res := &ListResult{}
+	lastPrefix, count := "", 0
+	for _, key := range keys {
+		if key <= marker {
+			continue
+		}
+		commonPrefix := ""
+		if delimiter != "" {
+			if idx := strings.Index(key[len(prefix):], delimiter); idx >= 0 {
+				commonPrefix = key[:len(prefix)+idx+len(delimiter)]
+			}
+		}
+		// 同一公共前缀只返回一次，marker为公共前缀时跳过其下所有对象
+		if commonPrefix != "" && (commonPrefix == lastPrefix || strings.HasPrefix(marker, commonPrefix)) {
+			continue
+		}
+		if count >= maxKeys {
+			res.IsTruncated = true
+			break
+		}
+		count++
+		if commonPrefix != "" {
+			lastPrefix = commonPrefix
+			res.CommonPrefixes = append(res.CommonPrefixes, commonPrefix)
+			res.NextMarker = commonPrefix
+			continue
+		}
+		info, err := s.getMeta(bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		res.Objects = append(res.Objects, info)
+		res.NextMarker = key
+	}
+	if !res.IsTruncated {
+		res.NextMarker = ""
+	}
+	return res, nil
+}
+
+func (s *diskStorage) NewMultipartUpload(bucket, key, contentType string) (string, error) {
+	if err := s.checkObject(bucket, key); err != nil {
+		return "", err
+	}
+	uploadId, err := randomId()
+	if err != nil {
+		return "", err
+	}
+	uploadDir := filepath.Join(s.root, _dirUploads, uploadId)
+	if err = os.MkdirAll(uploadDir, 0750); err != nil {
+		return "", err
+	}
+	info := &uploadInfo{
+		Bucket:      bucket,
+		Key:         key,
+		ContentType: contentType,
+		Initiated:   time.Now().UTC(),
+	}
+	if err = s.writeJson(filepath.Join(uploadDir, _uploadInfoFile), info); err != nil {
+		os.RemoveAll(uploadDir)
+		return "", err
+	}
+	return uploadId, nil
+}
+
+func (s *diskStorage) PutObjectPart(bucket, key, uploadId string, number int, reader io.Reader) (*PartInfo, error) {
+	if number < 1 || number > MaxPartNumber {
+		return nil, ErrInvalidPart
+	}
+	if _, err := s.getUpload(bucket, key, uploadId); err != nil {
+		return nil, err
+	}
+	tmp, err := s.writeTemp(reader)
+	if err != nil {
+		return nil, err
+	}
+	part := &PartInfo{
+		Number:  number,
+		Size:    tmp.size,
+		ETag:    tmp.md5,
+		ModTime: time.Now().UTC(),
+	}
+	partPath := s.partPath(uploadId, number)
+	if err = os.Rename(tmp.path, partPath); err != nil {
+		os.Remove(tmp.path)
+		return nil, err
+	}
+	if err = s.writeJson(partPath+".json", part); err != nil {
+		return nil, err
+	}
+	return part, nil
+}
+
+func (s *diskStorage) ListObjectParts(bucket, key, uploadId string) ([]*PartInfo, error) {
+	if _, err := s.getUpload(bucket, key, uploadId); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(s.root, _dirUploads, uploadId))
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]*PartInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, _partFilePrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		part := &PartInfo{}
+		if err = s.readJson(filepath.Join(s.root, _dirUploads, uploadId, name), part); err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Number < parts[j].Number
+	})
+	return parts, nil
+}
+
+func (s *diskStorage) CompleteMultipartUpload(bucket, key, uploadId string, parts []CompletePart) (*ObjectInfo, error) {
+	upload, err := s.getUpload(bucket, key, uploadId)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, ErrInvalidPart
+	}
+	uploaded, err := s.ListObjectParts(bucket, key, uploadId)
+	if err != nil {
+		return nil, err
+	}
+	partMap := make(map[int]*PartInfo, len(uploaded))
+	for _, part := range uploaded {
+		partMap[part.Number] = part
+	}
+	for i := 1; i < len(parts); i++ {
+		if parts[i].Number <= parts[i-1].Number {
+			return nil, ErrInvalidPartOrder
+		}
+	}
+	for i, part := range parts {
+		info, exist := partMap[part.Number]
+		if !exist || info.ETag != strings.Trim(part.ETag, `"`) {
+			return nil, ErrInvalidPart
+		}
+		if i < len(parts)-1 && info.Size < MinPartSize {
+			return nil, ErrEntityTooSmall
+		}
+	}
+	tmp, etag, err := s.concatParts(uploadId, parts)
+	if err != nil {
+		return nil, err
+	}
+	info := &ObjectInfo{
+		Bucket:      bucket,
+		Key:         key,
+		Size:        tmp.size,
+		ContentType: upload.ContentType,
+		ETag:        etag,
+		Hash:        tmp.sha256,
+		ModTime:     time.Now().UTC(),
+	}
+	s.mu.Lock()
+	if err = s.linkBlob(tmp.path, tmp.sha256); err == nil {
+		if err = s.putMeta(info); err != nil {
+			s.unrefBlob(info.Hash)
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	os.RemoveAll(filepath.Join(s.root, _dirUploads, uploadId))
+	return info, nil
+}
+
+func (s *diskStorage) AbortMultipartUpload(bucket, key, uploadId string) error {
+	if _, err := s.getUpload(bucket, key, uploadId); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(s.root, _dirUploads, uploadId))
+}
+
+// concatParts 合并分片内容到临时文件，ETag为各分片md5合并后的md5加分片数
+func (s *diskStorage) concatParts(uploadId string, parts []CompletePart) (*tempFile, string, error) {
+	readers := make([]io.Reader, 0, len(parts))
+	files := make([]*os.File, 0, len(parts))
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	sums := md5.New()
+	for _, part := range parts {
+		f, err := os.Open(s.partPath(uploadId, part.Number))
+		if err != nil {
+			return nil, "", ErrInvalidPart
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+		sum, err := hex.DecodeString(strings.Trim(part.ETag, `"`))
+		if err != nil {
+			return nil, "", ErrInvalidPart
+		}
+		sums.Write(sum)
+	}
+	tmp, err := s.writeTemp(io.MultiReader(readers...))
+	if err != nil {
+		return nil, "", err
+	}
+	return tmp, hex.EncodeToString(sums.Sum(nil)) + "-" + strconv.Itoa(len(parts)), nil
+}
+
+func (s *diskStorage) getUpload(bucket, key, uploadId string) (*uploadInfo, error) {
+	if !isValidUploadId(uploadId) {
+		return nil, ErrNoSuchUpload
+	}
+	info := &uploadInfo{}
+	err := s.readJson(filepath.Join(s.root, _dirUploads, uploadId, _uploadInfoFile), info)
+	if os.IsNotExist(err) {
+		return nil, ErrNoSuchUpload
+	} else if err != nil {
+		return nil, err
+	}
+	if info.Bucket != bucket || info.Key != key {
+		return nil, ErrNoSuchUpload
+	}
+	return info, nil
+}
+
+func (s *diskStorage) checkObject(bucket, key string) error {
+	if !isValidKey(key) {
+		return ErrInvalidKey
+	}
+	exist, err := s.BucketExists(bucket)
+	if err != nil {
+		return err
+	} else if !exist {
+		return ErrNoSuchBucket
+	}
+	return nil
+}
+
+// writeTemp 写入临时文件并同时计算内容的sha256与md5
+func (s *diskStorage) writeTemp(reader io.Reader) (_ *tempFile, err error) {
+	f, err := os.CreateTemp(filepath.Join(s.root, _dirTemp), "obj-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	sha256Hash, md5Hash := sha256.New(), md5.New()
+	size, err := io.Copy(io.MultiWriter(f, sha256Hash, md5Hash), reader)
+	if err != nil {
+		return nil, err
+	}
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+	return &tempFile{
+		path:   f.Name(),
+		size:   size,
+		sha256: hexSum(sha256Hash),
+		md5:    hexSum(md5Hash),
+	}, nil
+}
+
+// linkBlob 将临时文件作为内容保存，内容已存在时只增加引用计数，需持有写锁
+func (s *diskStorage) linkBlob(tmpPath string, hash string) error {
+	blobPath := s.blobPath(hash)
+	if _, err := os.Stat(blobPath); err == nil {
+		os.Remove(tmpPath)
+	} else {
+		if err = os.MkdirAll(filepath.Dir(blobPath), 0750); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		if err = os.Rename(tmpPath, blobPath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	return s.refBlob(hash)
+}
+
+func (s *diskStorage) refBlob(hash string) error {
+	refs := s.blobRefs(hash)
+	return os.WriteFile(s.blobPath(hash)+".ref", []byte(strconv.FormatInt(refs+1, 10)), 0640)
+}
+
+// unrefBlob 减少内容的引用计数，没有引用时删除内容，需持有写锁
+func (s *diskStorage) unrefBlob(hash string) error {
+	blobPath := s.blobPath(hash)
+	if refs := s.blobRefs(hash) - 1; refs > 0 {
+		return os.WriteFile(blobPath+".ref", []byte(strconv.FormatInt(refs, 10)), 0640)
+	}
+	os.Remove(blobPath + ".ref")
+	if err := os.Remove(blobPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.removeEmptyDirs(filepath.Dir(blobPath), filepath.Join(s.root, _dirBlobs))
+	return nil
+}
+
+func (s *diskStorage) blobRefs(hash string) int64 {
+	data, err := os.ReadFile(s.blobPath(hash) + ".ref")
+	if err != nil {
+		return 0
+	}
+	refs, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return refs
+}
+
+// putMeta 保存对象元信息，覆盖已有对象时释放旧内容的引用，需持有写锁
+func (s *diskStorage) putMeta(info *ObjectInfo) error {
+	old, err := s.getMeta(info.Bucket, info.Key)
+	if err != nil && err != ErrNoSuchKey {
+		return err
+	}
+	metaPath := s.metaPath(info.Bucket, info.Key)
+	if err = os.MkdirAll(filepath.Dir(metaPath), 0750); err != nil {
+		return err
+	}
+	if err = s.writeJson(metaPath, info); err != nil {
+		return err
+	}
+	if old != nil {
+		return s.unrefBlob(old.Hash)
+	}
+	return nil
+}
+
+func (s *diskStorage) getMeta(bucket, key string) (*ObjectInfo, error) {
+	info := &ObjectInfo{}
+	err := s.readJson(s.metaPath(bucket, key), info)
+	if os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR) {
+		return nil, ErrNoSuchKey
+	} else if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// listKeys 获取prefix下所有对象键，结果按字节序排列
+func (s *diskStorage) listKeys(bucket, prefix string) ([]string, error) {
+	segments := strings.Split(prefix, "/")
+	dirKey, baseDir := "", s.bucketPath(bucket)
+	for _, seg := range segments[:len(segments)-1] {
+		dirKey += seg + "/"
+		baseDir = filepath.Join(baseDir, _prefixDir+seg)
+	}
+	var keys []string
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == baseDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), _prefixFile) {
+			return nil
+		}
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return err
+		}
+		relSegs := strings.Split(filepath.ToSlash(rel), "/")
+		for i, seg := range relSegs {
+			if i < len(relSegs)-1 {
+				relSegs[i] = strings.TrimPrefix(seg, _prefixDir)
+			} else {
+				relSegs[i] = strings.TrimPrefix(seg, _prefixFile)
+			}
+		}
+		if key := dirKey + strings.Join(relSegs, "/"); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *diskStorage) removeEmptyDirs(dir string, stop string) {
+	for dir != stop && strings.HasPrefix(dir, stop) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func (s *diskStorage) writeJson(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Join(s.root, _dirTemp), "meta-")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// 先写临时文件再重命名，保证元信息文件总是完整的
+	if err = os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (s *diskStorage) readJson(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (s *diskStorage) bucketPath(bucket string) string {
+	return filepath.Join(s.root, _dirBuckets, bucket)
+}
+
+func (s *diskStorage) metaPath(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	paths := make([]string, 0, len(segments)+1)
+	paths = append(paths, s.bucketPath(bucket))
+	for _, seg := range segments[:len(segments)-1] {
+		paths = append(paths, _prefixDir+seg)
+	}
+	paths = append(paths, _prefixFile+segments[len(segments)-1])
+	return filepath.Join(paths...)
+}
+
+func (s *diskStorage) blobPath(hash string) string {
+	return filepath.Join(s.root, _dirBlobs, hash[:2], hash[2:4], hash)
+}
+
+func (s *diskStorage) partPath(uploadId string, number int) string {
+	return filepath.Join(s.root, _dirUploads, uploadId, _partFilePrefix+strconv.Itoa(number))
+}
+
+func isValidBucketName(bucket string) bool {
+	if len(bucket) < 3 || len(bucket) > 63 {
+		return false
+	}
+	for i, c := range bucket {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+		if !isAlnum && (i == 0 || i == len(bucket)-1 || (c != '-' && c != '.')) {
+			return false
+		}
+	}
+	return !strings.Contains(bucket, "..")
+}
+
+func isValidKey(key string) bool {
+	if key == "" || len(key) > _maxKeyLength || !utf8.ValidString(key) || strings.ContainsRune(key, 0) {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if len(seg) > _maxSegmentLength {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidUploadId(uploadId string) bool {
+	if len(uploadId) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(uploadId)
+	return err == nil
+}
+
+func randomId() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hexSum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewStorage create a native object storage that save object on root directory
+func NewStorage(root string, buckets ...string) (Storage, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{_dirBuckets, _dirBlobs, _dirUploads, _dirTemp} {
+		if err = os.MkdirAll(filepath.Join(root, dir), 0750); err != nil {
+			return nil, fmt.Errorf("create storage directory %s failed: %w", dir, err)
+		}
+	}
+	s := &diskStorage{
+		root: root,
+	}
+	for _, bucket := range buckets {
+		if err = s.MakeBucket(bucket); err != nil {
+			return nil, fmt.Errorf("make bucket %s failed: %w", bucket, err)
+		}
+	}
+	return s, nil
+}
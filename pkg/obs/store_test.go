@@ -0,0 +1,158 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package obs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("Storage", func() {
+	var (
+		root string
+		st   Storage
+	)
+
+	readObject := func(key string) string {
+		_, reader, err := st.GetObject("paopao", key)
+		m.Expect(err).To(m.BeNil())
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		m.Expect(err).To(m.BeNil())
+		return string(data)
+	}
+
+	blobCount := func() int {
+		count := 0
+		filepath.WalkDir(filepath.Join(root, _dirBlobs), func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && !strings.HasSuffix(path, ".ref") {
+				count++
+			}
+			return nil
+		})
+		return count
+	}
+
+	g.BeforeEach(func() {
+		root = g.GinkgoT().TempDir()
+		var err error
+		st, err = NewStorage(root, "paopao")
+		m.Expect(err).To(m.BeNil())
+	})
+
+	g.It("put and get object", func() {
+		info, err := st.PutObject("paopao", "public/image/a.png", strings.NewReader("hello"), "image/png")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(info.Size).To(m.Equal(int64(5)))
+		m.Expect(info.ETag).To(m.Equal("5d41402abc4b2a76b9719d911017c592"))
+		m.Expect(readObject("public/image/a.png")).To(m.Equal("hello"))
+
+		_, err = st.StatObject("paopao", "public/image/b.png")
+		m.Expect(err).To(m.Equal(ErrNoSuchKey))
+		_, err = st.StatObject("paopao", "public/image")
+		m.Expect(err).To(m.Equal(ErrNoSuchKey))
+		_, err = st.PutObject("nobucket", "a.png", strings.NewReader("hello"), "")
+		m.Expect(err).To(m.Equal(ErrNoSuchBucket))
+	})
+
+	g.It("dedup content by refcount", func() {
+		_, err := st.PutObject("paopao", "a", strings.NewReader("same"), "")
+		m.Expect(err).To(m.BeNil())
+		_, err = st.PutObject("paopao", "a/b", strings.NewReader("same"), "")
+		m.Expect(err).To(m.BeNil())
+		_, err = st.CopyObject("paopao", "a", "c")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(blobCount()).To(m.Equal(1))
+
+		m.Expect(st.DeleteObject("paopao", "a")).To(m.Succeed())
+		m.Expect(st.DeleteObject("paopao", "a/b")).To(m.Succeed())
+		m.Expect(readObject("c")).To(m.Equal("same"))
+		m.Expect(blobCount()).To(m.Equal(1))
+
+		// 覆盖对象时释放旧内容
+		_, err = st.PutObject("paopao", "c", strings.NewReader("other"), "")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(readObject("c")).To(m.Equal("other"))
+		m.Expect(blobCount()).To(m.Equal(1))
+
+		m.Expect(st.DeleteObject("paopao", "c")).To(m.Succeed())
+		m.Expect(st.DeleteObject("paopao", "c")).To(m.Succeed())
+		m.Expect(blobCount()).To(m.Equal(0))
+	})
+
+	g.It("list objects", func() {
+		for _, key := range []string{"a.txt", "a/1", "a/2", "b/c/3", "b/d", "ab"} {
+			_, err := st.PutObject("paopao", key, strings.NewReader(key), "")
+			m.Expect(err).To(m.BeNil())
+		}
+		res, err := st.ListObjects("paopao", "", "", "", 100)
+		m.Expect(err).To(m.BeNil())
+		keys := make([]string, 0, len(res.Objects))
+		for _, info := range res.Objects {
+			keys = append(keys, info.Key)
+		}
+		m.Expect(keys).To(m.Equal([]string{"a.txt", "a/1", "a/2", "ab", "b/c/3", "b/d"}))
+
+		res, err = st.ListObjects("paopao", "", "/", "", 2)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Objects).To(m.HaveLen(1))
+		m.Expect(res.CommonPrefixes).To(m.Equal([]string{"a/"}))
+		m.Expect(res.IsTruncated).To(m.BeTrue())
+
+		res, err = st.ListObjects("paopao", "", "/", res.NextMarker, 2)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Objects[0].Key).To(m.Equal("ab"))
+		m.Expect(res.CommonPrefixes).To(m.Equal([]string{"b/"}))
+		m.Expect(res.IsTruncated).To(m.BeFalse())
+
+		res, err = st.ListObjects("paopao", "b/", "/", "", 10)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Objects[0].Key).To(m.Equal("b/d"))
+		m.Expect(res.CommonPrefixes).To(m.Equal([]string{"b/c/"}))
+	})
+
+	g.It("multipart upload", func() {
+		uploadId, err := st.NewMultipartUpload("paopao", "big.bin", "application/octet-stream")
+		m.Expect(err).To(m.BeNil())
+		part1 := bytes.Repeat([]byte("a"), MinPartSize)
+		p1, err := st.PutObjectPart("paopao", "big.bin", uploadId, 1, bytes.NewReader(part1))
+		m.Expect(err).To(m.BeNil())
+		p2, err := st.PutObjectPart("paopao", "big.bin", uploadId, 2, strings.NewReader("tail"))
+		m.Expect(err).To(m.BeNil())
+		parts, err := st.ListObjectParts("paopao", "big.bin", uploadId)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(parts).To(m.HaveLen(2))
+
+		_, err = st.CompleteMultipartUpload("paopao", "big.bin", uploadId, []CompletePart{{2, p2.ETag}, {1, p1.ETag}})
+		m.Expect(err).To(m.Equal(ErrInvalidPartOrder))
+		_, err = st.CompleteMultipartUpload("paopao", "big.bin", uploadId, []CompletePart{{1, p1.ETag}, {3, p2.ETag}})
+		m.Expect(err).To(m.Equal(ErrInvalidPart))
+
+		info, err := st.CompleteMultipartUpload("paopao", "big.bin", uploadId, []CompletePart{{1, `"` + p1.ETag + `"`}, {2, p2.ETag}})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(info.Size).To(m.Equal(int64(MinPartSize + 4)))
+		m.Expect(info.ETag).To(m.HaveSuffix("-2"))
+		m.Expect(readObject("big.bin")).To(m.HaveSuffix("atail"))
+
+		_, err = st.ListObjectParts("paopao", "big.bin", uploadId)
+		m.Expect(err).To(m.Equal(ErrNoSuchUpload))
+	})
+
+	g.It("reject invalid names", func() {
+		m.Expect(st.MakeBucket("A")).To(m.Equal(ErrInvalidBucketName))
+		m.Expect(st.MakeBucket("../etc")).To(m.Equal(ErrInvalidBucketName))
+		_, err := st.PutObject("paopao", strings.Repeat("x", 251), strings.NewReader(""), "")
+		m.Expect(err).To(m.Equal(ErrInvalidKey))
+		_, err = st.PutObject("paopao", "../../escape", strings.NewReader("x"), "")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(filepath.Join(root, _dirBuckets, "paopao", "d_..", "d_..", "f_escape")).To(m.BeAnExistingFile())
+	})
+})
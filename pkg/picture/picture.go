@@ -0,0 +1,241 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// package picture process uploaded picture: fix orientation, strip metadata,
+// generate thumbnail/webp variants and blurhash/dominant color placeholders.
+package picture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"sort"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	_placeholderSize   = 32
+	_dominantColorSize = 64
+)
+
+var (
+	// ErrUnsupportedType the content type of picture is not supported
+	ErrUnsupportedType = errors.New("unsupported picture content type")
+	// ErrTooLarge the pixel count of picture exceed Options.MaxPixels
+	ErrTooLarge = errors.New("picture pixels exceed limit")
+)
+
+// Options picture process options
+type Options struct {
+	// Quality JPEG编码质量
+	Quality int
+	// ThumbnailWidths 缩略图宽度列表，只生成比原图窄的缩略图
+	ThumbnailWidths []int
+	// Webp WebP编码器，为nil时不生成WebP版本
+	Webp Encoder
+	// BlurhashX/BlurhashY blurhash的横纵分量数
+	BlurhashX int
+	BlurhashY int
+	// MaxPixels 允许处理的最大像素数(宽*高)，解码前检查以防止解压炸弹，为0时不限制
+	MaxPixels int64
+}
+
+// Variant picture variant such as thumbnail or webp version
+type Variant struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	Ext         string
+	Data        []byte
+}
+
+// Result picture process result
+type Result struct {
+	Width         int
+	Height        int
+	ContentType   string
+	Data          []byte
+	Variants      []*Variant
+	Blurhash      string
+	DominantColor string
+}
+
+// Process decode picture data and generate sanitized original and variants,
+// the returned Data is the original picture that had fixed orientation and
+// stripped EXIF(include GPS) metadata, animated gif is keep as-is.
+func Process(data []byte, contentType string, opts *Options) (*Result, error) {
+	// 先只解析图片头部获取尺寸，避免小文件解码出超大位图耗尽内存
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > opts.MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	res := &Result{
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		ContentType: contentType,
+	}
+	// 重新编码原图以去除EXIF等元数据并修正方向
+	switch contentType {
+	case "image/jpeg", "image/jpg":
+		res.ContentType = "image/jpeg"
+		res.Data, err = encodeJpeg(img, opts.Quality)
+	case "image/png":
+		res.Data, err = encodePng(img)
+	case "image/webp":
+		res.Data, err = StripWebpMetadata(data)
+	case "image/gif":
+		// gif不包含EXIF信息，保持原样以保留动画
+		res.Data = data
+	default:
+		err = ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "image/gif" {
+		if res.Variants, err = variantsOf(img, contentType, opts); err != nil {
+			return nil, err
+		}
+	}
+	placeholder := imaging.Fit(img, _placeholderSize, _placeholderSize, imaging.Box)
+	res.Blurhash = Blurhash(placeholder, opts.BlurhashX, opts.BlurhashY)
+	res.DominantColor = DominantColor(imaging.Fit(img, _dominantColorSize, _dominantColorSize, imaging.Box))
+	return res, nil
+}
+
+func variantsOf(img image.Image, contentType string, opts *Options) (variants []*Variant, err error) {
+	width := img.Bounds().Dx()
+	widths := make([]int, 0, len(opts.ThumbnailWidths))
+	for _, w := range opts.ThumbnailWidths {
+		if w > 0 && w < width {
+			widths = append(widths, w)
+		}
+	}
+	sort.Ints(widths)
+	for i, w := range widths {
+		if i > 0 && widths[i-1] == w {
+			continue
+		}
+		thumb := imaging.Resize(img, w, 0, imaging.Lanczos)
+		name := fmt.Sprintf("w%d", w)
+		variant := &Variant{
+			Name:   name,
+			Width:  thumb.Bounds().Dx(),
+			Height: thumb.Bounds().Dy(),
+		}
+		// 不透明的缩略图使用JPEG，否则使用PNG以保留透明通道
+		if thumb.Opaque() {
+			variant.ContentType, variant.Ext = "image/jpeg", ".jpg"
+			variant.Data, err = encodeJpeg(thumb, opts.Quality)
+		} else {
+			variant.ContentType, variant.Ext = "image/png", ".png"
+			variant.Data, err = encodePng(thumb)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+		if opts.Webp != nil {
+			if variant, err = webpVariant(name, thumb, opts.Webp); err != nil {
+				return nil, err
+			}
+			variants = append(variants, variant)
+		}
+	}
+	if opts.Webp != nil && contentType != "image/webp" {
+		variant, err := webpVariant("full", img, opts.Webp)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return
+}
+
+func webpVariant(name string, img image.Image, encoder Encoder) (*Variant, error) {
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	return &Variant{
+		Name:        name,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		ContentType: "image/webp",
+		Ext:         ".webp",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// DominantColor return the dominant color of image in #rrggbb format,
+// pixels are grouped by 4 bits of each channel and the most frequent group's
+// average color is the dominant color.
+func DominantColor(img image.Image) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[int]*bucket)
+	var dominant *bucket
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			// 忽略大部分透明的像素
+			if a < 0x8000 {
+				continue
+			}
+			r, g, b = r>>8, g>>8, b>>8
+			key := int(r>>4)<<8 | int(g>>4)<<4 | int(b>>4)
+			bk, exist := buckets[key]
+			if !exist {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.count++
+			bk.r, bk.g, bk.b = bk.r+int(r), bk.g+int(g), bk.b+int(b)
+			if dominant == nil || bk.count > dominant.count {
+				dominant = bk
+			}
+		}
+	}
+	if dominant == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", dominant.r/dominant.count, dominant.g/dominant.count, dominant.b/dominant.count)
+}
+
+func encodeJpeg(img image.Image, quality int) ([]byte, error) {
+	if quality <= 0 || quality > 100 {
+		quality = jpeg.DefaultQuality
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePng(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
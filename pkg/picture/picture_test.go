@@ -0,0 +1,162 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package picture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+)
+
+type fakeWebpEncoder struct{}
+
+func (fakeWebpEncoder) Encode(w io.Writer, img image.Image) error {
+	_, err := w.Write([]byte("RIFF-fake-webp"))
+	return err
+}
+
+func newImage(width, height int, fill func(x, y int) color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill(x, y))
+		}
+	}
+	return img
+}
+
+// jpegWithOrientation 生成带有EXIF方向信息(顺时针旋转90度)的JPEG图片
+func jpegWithOrientation(img image.Image) []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, nil)
+	data := buf.Bytes()
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00\x12\x01\x03\x00\x01\x00\x00\x00\x06\x00\x00\x00\x00\x00\x00\x00")
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	app1 = append(app1, payload...)
+	res := append([]byte{}, data[:2]...)
+	res = append(res, app1...)
+	return append(res, data[2:]...)
+}
+
+func webpChunk(fourCC string, payload []byte) []byte {
+	chunk := []byte(fourCC)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(payload)))
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+var _ = g.Describe("Picture", func() {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	g.It("fix orientation and strip exif", func() {
+		data := jpegWithOrientation(newImage(40, 20, func(x, y int) color.Color { return red }))
+		m.Expect(bytes.Contains(data, []byte("Exif"))).To(m.BeTrue())
+		res, err := Process(data, "image/jpg", &Options{})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Width).To(m.Equal(20))
+		m.Expect(res.Height).To(m.Equal(40))
+		m.Expect(res.ContentType).To(m.Equal("image/jpeg"))
+		m.Expect(bytes.Contains(res.Data, []byte("Exif"))).To(m.BeFalse())
+		m.Expect(res.Variants).To(m.BeEmpty())
+	})
+
+	g.It("generate thumbnails and webp variants", func() {
+		var buf bytes.Buffer
+		jpeg.Encode(&buf, newImage(800, 400, func(x, y int) color.Color { return blue }), nil)
+		res, err := Process(buf.Bytes(), "image/jpeg", &Options{
+			ThumbnailWidths: []int{1280, 320, 640, 320},
+			Webp:            fakeWebpEncoder{},
+			BlurhashX:       4,
+			BlurhashY:       3,
+		})
+		m.Expect(err).To(m.BeNil())
+		names := make([]string, 0, len(res.Variants))
+		for _, v := range res.Variants {
+			names = append(names, v.Name+v.Ext)
+		}
+		m.Expect(names).To(m.Equal([]string{"w320.jpg", "w320.webp", "w640.jpg", "w640.webp", "full.webp"}))
+		m.Expect(res.Variants[0].Width).To(m.Equal(320))
+		m.Expect(res.Variants[0].Height).To(m.Equal(160))
+		m.Expect(res.Variants[4].Width).To(m.Equal(800))
+		m.Expect(res.Blurhash).To(m.HaveLen(28))
+		m.Expect(res.DominantColor).To(m.Equal("#0000fe"))
+	})
+
+	g.It("keep alpha channel in png thumbnails", func() {
+		var buf bytes.Buffer
+		png.Encode(&buf, newImage(100, 100, func(x, y int) color.Color { return color.NRGBA{G: 255, A: uint8(x)} }))
+		res, err := Process(buf.Bytes(), "image/png", &Options{ThumbnailWidths: []int{50}})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Variants).To(m.HaveLen(1))
+		m.Expect(res.Variants[0].ContentType).To(m.Equal("image/png"))
+		m.Expect(res.Blurhash).To(m.BeEmpty())
+	})
+
+	g.It("reject unsupported content type", func() {
+		var buf bytes.Buffer
+		png.Encode(&buf, newImage(10, 10, func(x, y int) color.Color { return red }))
+		_, err := Process(buf.Bytes(), "image/bmp", &Options{})
+		m.Expect(err).To(m.Equal(ErrUnsupportedType))
+	})
+
+	g.It("reject picture exceed max pixels before decode", func() {
+		var buf bytes.Buffer
+		png.Encode(&buf, newImage(100, 50, func(x, y int) color.Color { return red }))
+		_, err := Process(buf.Bytes(), "image/png", &Options{MaxPixels: 4999})
+		m.Expect(err).To(m.Equal(ErrTooLarge))
+		res, err := Process(buf.Bytes(), "image/png", &Options{MaxPixels: 5000})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(res.Width).To(m.Equal(100))
+	})
+
+	g.It("strip webp metadata", func() {
+		body := webpChunk("VP8X", []byte{0x10 | _vp8xFlagExif | _vp8xFlagXmp, 0, 0, 0, 9, 0, 0, 9, 0, 0})
+		body = append(body, webpChunk("VP8L", []byte("odd"))...)
+		body = append(body, webpChunk("EXIF", []byte("gps-data"))...)
+		body = append(body, webpChunk("XMP ", []byte("xmp"))...)
+		data := binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(len(body)+4))
+		data = append(append(data, "WEBP"...), body...)
+
+		res, err := StripWebpMetadata(data)
+		m.Expect(err).To(m.BeNil())
+		m.Expect(bytes.Contains(res, []byte("EXIF"))).To(m.BeFalse())
+		m.Expect(bytes.Contains(res, []byte("XMP "))).To(m.BeFalse())
+		m.Expect(res[20]).To(m.Equal(byte(0x10)))
+		m.Expect(binary.LittleEndian.Uint32(res[4:8])).To(m.Equal(uint32(len(res) - 8)))
+		m.Expect(bytes.HasSuffix(res, []byte("odd\x00"))).To(m.BeTrue())
+
+		_, err = StripWebpMetadata(data[:len(data)-3])
+		m.Expect(err).To(m.Equal(ErrInvalidWebp))
+	})
+
+	g.It("blurhash and dominant color", func() {
+		img := newImage(10, 10, func(x, y int) color.Color {
+			if x < 7 {
+				return red
+			}
+			return blue
+		})
+		m.Expect(DominantColor(img)).To(m.Equal("#ff0000"))
+		m.Expect(Blurhash(img, 1, 1)).To(m.HaveLen(6))
+		m.Expect(Blurhash(img, 0, 1)).To(m.BeEmpty())
+		// 第1位为分量数，第3到6位为直流分量即平均颜色
+		solid := Blurhash(newImage(8, 8, func(x, y int) color.Color { return color.White }), 4, 3)
+		m.Expect(solid[:1]).To(m.Equal("L"))
+		m.Expect(solid[2:6]).To(m.Equal("TSUA"))
+	})
+})
@@ -0,0 +1,132 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package picture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	_vp8xFlagExif = 0x08
+	_vp8xFlagXmp  = 0x04
+
+	_cwebpTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalidWebp the data is not a valid webp image
+	ErrInvalidWebp = errors.New("invalid webp image")
+)
+
+var _ Encoder = (*cwebpEncoder)(nil)
+
+// Encoder image encoder
+type Encoder interface {
+	Encode(w io.Writer, img image.Image) error
+}
+
+type cwebpEncoder struct {
+	bin     string
+	quality int
+}
+
+func (e *cwebpEncoder) Encode(w io.Writer, img image.Image) error {
+	dir, err := os.MkdirTemp("", "paopao-cwebp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	input, output := filepath.Join(dir, "in.png"), filepath.Join(dir, "out.webp")
+	file, err := os.Create(input)
+	if err != nil {
+		return err
+	}
+	// 使用最快的压缩级别，中间文件只用于传递给cwebp
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err = encoder.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), _cwebpTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, e.bin, "-quiet", "-metadata", "none", "-q", strconv.Itoa(e.quality), input, "-o", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cwebp encode failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// NewCwebpEncoder create a webp encoder that use cwebp command to encode image
+func NewCwebpEncoder(bin string, quality int) (Encoder, error) {
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, err
+	}
+	if quality <= 0 || quality > 100 {
+		quality = 80
+	}
+	return &cwebpEncoder{
+		bin:     path,
+		quality: quality,
+	}, nil
+}
+
+// StripWebpMetadata remove EXIF and XMP chunks from webp image without re-encoding
+func StripWebpMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrInvalidWebp
+	}
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for rest := data[12:]; len(rest) > 0; {
+		if len(rest) < 8 {
+			return nil, ErrInvalidWebp
+		}
+		fourCC := string(rest[:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		// 分块内容按偶数字节对齐
+		chunkLen := 8 + size + size&1
+		if size < 0 || chunkLen > len(rest) {
+			return nil, ErrInvalidWebp
+		}
+		chunk := rest[:chunkLen]
+		rest = rest[chunkLen:]
+		switch fourCC {
+		case "EXIF", "XMP ":
+			continue
+		case "VP8X":
+			if size < 1 {
+				return nil, ErrInvalidWebp
+			}
+			start := len(out)
+			out = append(out, chunk...)
+			out[start+8] &^= _vp8xFlagExif | _vp8xFlagXmp
+			continue
+		}
+		out = append(out, chunk...)
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
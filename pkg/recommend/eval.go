@@ -0,0 +1,49 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recommend
+
+import (
+	"math"
+)
+
+// PrecisionAtK 前k条推荐中相关推文的比例
+func PrecisionAtK(ranked []int64, relevant map[int64]bool, k int) float64 {
+	if k <= 0 {
+		return 0
+	}
+	hits := 0
+	for _, id := range ranked[:min(k, len(ranked))] {
+		if relevant[id] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(k)
+}
+
+// NDCGAtK 前k条推荐的归一化折损累计增益，相关推文的增益为1
+func NDCGAtK(ranked []int64, relevant map[int64]bool, k int) float64 {
+	dcg, idcg := 0.0, 0.0
+	for i, id := range ranked[:min(k, len(ranked))] {
+		if relevant[id] {
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+	for i := 0; i < min(k, len(relevant)); i++ {
+		idcg += 1 / math.Log2(float64(i+2))
+	}
+	if idcg == 0 {
+		return 0
+	}
+	return dcg / idcg
+}
+
+// AuthorCoverage 前k条推荐中不同作者的数量
+func AuthorCoverage(ranked []*Candidate, k int) int {
+	authors := make(map[int64]struct{})
+	for _, c := range ranked[:min(k, len(ranked))] {
+		authors[c.AuthorID] = struct{}{}
+	}
+	return len(authors)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recommend
+
+import (
+	"math"
+	"sort"
+)
+
+// Weights 推荐打分的各项权重
+type Weights struct {
+	Following      float64 // 作者为关注的用户
+	Friend         float64 // 作者为好友
+	Topic          float64 // 每个匹配的关注话题
+	FriendOfFriend float64 // 关注的用户中同样关注作者的人数，取对数
+	Engagement     float64 // 近期对作者推文的互动次数，取对数
+	Popularity     float64 // 推文热度，取对数
+	HalfLife       float64 // 时间衰减的半衰期，单位小时，不大于0时不衰减
+	MaxPerAuthor   int     // 每个作者最多推荐的推文数，不大于0时不限制
+}
+
+// Candidate 候选推文及其推荐特征
+type Candidate struct {
+	PostID         int64
+	AuthorID       int64
+	CreatedOn      int64
+	Following      bool
+	Friend         bool
+	TopicHits      int
+	FriendOfFriend int
+	Engagement     int
+	RankScore      int64
+	Score          float64
+}
+
+// Score 候选推文的推荐分，相关度与热度之和按发布时长衰减
+func (w *Weights) Score(c *Candidate, now int64) float64 {
+	score := w.Topic*float64(c.TopicHits) +
+		w.FriendOfFriend*math.Log1p(float64(c.FriendOfFriend)) +
+		w.Engagement*math.Log1p(float64(c.Engagement)) +
+		w.Popularity*math.Log1p(float64(max(c.RankScore, 0)))
+	if c.Friend {
+		score += w.Friend
+	} else if c.Following {
+		score += w.Following
+	}
+	return score * w.Decay(now-c.CreatedOn)
+}
+
+// Decay 发布age秒后的时间衰减系数
+func (w *Weights) Decay(age int64) float64 {
+	if w.HalfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/3600/w.HalfLife)
+}
+
+// Rank 按推荐分从高到低排序，同分时新发布的在前，并限制每个作者的推文数
+func Rank(candidates []*Candidate, w *Weights, now int64) []*Candidate {
+	for _, c := range candidates {
+		c.Score = w.Score(c, now)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.CreatedOn != b.CreatedOn {
+			return a.CreatedOn > b.CreatedOn
+		}
+		return a.PostID > b.PostID
+	})
+	if w.MaxPerAuthor <= 0 {
+		return candidates
+	}
+	res := make([]*Candidate, 0, len(candidates))
+	counts := make(map[int64]int)
+	for _, c := range candidates {
+		if counts[c.AuthorID] < w.MaxPerAuthor {
+			counts[c.AuthorID]++
+			res = append(res, c)
+		}
+	}
+	return res
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recommend_test
+
+import (
+	"math/rand"
+	"sort"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rocboss/paopao-ce/pkg/recommend"
+)
+
+const _now = int64(1700000000)
+
+var _defaultWeights = recommend.Weights{
+	Following:      4,
+	Friend:         5,
+	Topic:          3,
+	FriendOfFriend: 1.5,
+	Engagement:     2,
+	Popularity:     0.5,
+	HalfLife:       24,
+	MaxPerAuthor:   2,
+}
+
+// dataset 离线评估用的模拟数据，用户对作者的偏好及感兴趣的话题是隐藏的，
+// 关注、好友、互动等推荐特征只是带噪声的观测
+type dataset struct {
+	candidates []*recommend.Candidate
+	relevant   map[int64]bool
+}
+
+func newDataset(seed int64) *dataset {
+	r := rand.New(rand.NewSource(seed))
+	interests := map[int]bool{0: true, 1: true}
+	ds := &dataset{relevant: make(map[int64]bool)}
+	postId := int64(0)
+	for authorId := int64(1); authorId <= 60; authorId++ {
+		affinity := r.Float64()
+		following := affinity+r.NormFloat64()*0.1 > 0.75
+		friend := following && r.Float64() < 0.3
+		fof := int(affinity * float64(r.Intn(6)))
+		engagement := int(affinity * affinity * float64(r.Intn(10)))
+		for i := 0; i < 5; i++ {
+			postId++
+			topic := r.Intn(10)
+			c := &recommend.Candidate{
+				PostID:         postId,
+				AuthorID:       authorId,
+				CreatedOn:      _now - r.Int63n(96*3600),
+				Following:      following,
+				Friend:         friend,
+				FriendOfFriend: fof,
+				Engagement:     engagement,
+				RankScore:      r.Int63n(200),
+			}
+			if interests[topic] {
+				c.TopicHits = 1
+			}
+			ds.candidates = append(ds.candidates, c)
+			// 用户真正会感兴趣的是两天内偏好作者或感兴趣话题的推文
+			if _now-c.CreatedOn < 48*3600 && (affinity > 0.7 || interests[topic]) {
+				ds.relevant[c.PostID] = true
+			}
+		}
+	}
+	return ds
+}
+
+func (ds *dataset) clone() []*recommend.Candidate {
+	res := make([]*recommend.Candidate, 0, len(ds.candidates))
+	for _, c := range ds.candidates {
+		item := *c
+		res = append(res, &item)
+	}
+	return res
+}
+
+func postIdsOf(candidates []*recommend.Candidate) []int64 {
+	ids := make([]int64, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.PostID)
+	}
+	return ids
+}
+
+func newestOf(candidates []*recommend.Candidate) []int64 {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedOn > candidates[j].CreatedOn
+	})
+	return postIdsOf(candidates)
+}
+
+func hotsOf(candidates []*recommend.Candidate) []int64 {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].RankScore > candidates[j].RankScore
+	})
+	return postIdsOf(candidates)
+}
+
+var _ = Describe("Recommend", func() {
+	It("score signals", func() {
+		w := &recommend.Weights{Following: 4, Friend: 5, Topic: 3}
+		Expect(w.Score(&recommend.Candidate{CreatedOn: _now}, _now)).To(BeZero())
+		Expect(w.Score(&recommend.Candidate{CreatedOn: _now, Following: true}, _now)).To(Equal(4.0))
+		Expect(w.Score(&recommend.Candidate{CreatedOn: _now, Following: true, Friend: true}, _now)).To(Equal(5.0))
+		Expect(w.Score(&recommend.Candidate{CreatedOn: _now, TopicHits: 2}, _now)).To(Equal(6.0))
+	})
+
+	It("time decay", func() {
+		w := &recommend.Weights{HalfLife: 24}
+		Expect(w.Decay(0)).To(Equal(1.0))
+		Expect(w.Decay(24 * 3600)).To(BeNumerically("~", 0.5, 1e-9))
+		Expect(w.Decay(48 * 3600)).To(BeNumerically("~", 0.25, 1e-9))
+		Expect((&recommend.Weights{}).Decay(48 * 3600)).To(Equal(1.0))
+	})
+
+	It("rank with diversity", func() {
+		candidates := []*recommend.Candidate{
+			{PostID: 1, AuthorID: 1, CreatedOn: _now, Following: true},
+			{PostID: 2, AuthorID: 1, CreatedOn: _now - 1, Following: true},
+			{PostID: 3, AuthorID: 1, CreatedOn: _now - 2, Following: true},
+			{PostID: 4, AuthorID: 2, CreatedOn: _now - 3},
+			{PostID: 5, AuthorID: 3, CreatedOn: _now - 4, TopicHits: 1},
+		}
+		res := recommend.Rank(candidates, &recommend.Weights{Following: 4, Topic: 3, MaxPerAuthor: 2}, _now)
+		Expect(postIdsOf(res)).To(Equal([]int64{1, 2, 5, 4}))
+		res = recommend.Rank(candidates, &recommend.Weights{Following: 4, Topic: 3}, _now)
+		Expect(res).To(HaveLen(5))
+	})
+
+	It("evaluation metrics", func() {
+		relevant := map[int64]bool{1: true, 3: true}
+		Expect(recommend.PrecisionAtK([]int64{1, 2, 3, 4}, relevant, 4)).To(Equal(0.5))
+		Expect(recommend.PrecisionAtK([]int64{1}, relevant, 4)).To(Equal(0.25))
+		Expect(recommend.NDCGAtK([]int64{1, 3, 2}, relevant, 3)).To(BeNumerically("~", 1.0, 1e-9))
+		Expect(recommend.NDCGAtK([]int64{2, 4}, relevant, 2)).To(BeZero())
+	})
+
+	It("offline evaluation", func() {
+		const k = 20
+		for seed := int64(1); seed <= 5; seed++ {
+			ds := newDataset(seed)
+			ranked := recommend.Rank(ds.clone(), &_defaultWeights, _now)
+			recommendIds := postIdsOf(ranked)
+			newestIds, hotsIds := newestOf(ds.clone()), hotsOf(ds.clone())
+
+			precision := recommend.PrecisionAtK(recommendIds, ds.relevant, k)
+			Expect(precision).To(BeNumerically(">", recommend.PrecisionAtK(newestIds, ds.relevant, k)))
+			Expect(precision).To(BeNumerically(">", recommend.PrecisionAtK(hotsIds, ds.relevant, k)))
+			ndcg := recommend.NDCGAtK(recommendIds, ds.relevant, k)
+			Expect(ndcg).To(BeNumerically(">", recommend.NDCGAtK(newestIds, ds.relevant, k)))
+			Expect(ndcg).To(BeNumerically(">", recommend.NDCGAtK(hotsIds, ds.relevant, k)))
+			Expect(recommend.AuthorCoverage(ranked, k)).To(BeNumerically(">=", k/_defaultWeights.MaxPerAuthor))
+		}
+	})
+})
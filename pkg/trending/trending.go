@@ -0,0 +1,67 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package trending
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// 统计话题引用次数的时间窗口，单位秒
+	WindowHour = int64(3600)
+	WindowDay  = 24 * WindowHour
+	WindowWeek = 7 * WindowDay
+)
+
+// Options 热议话题打分的参数
+type Options struct {
+	MinUsage  int64   // 最近一天的引用次数不少于此值才参与排行
+	Smoothing float64 // 平滑系数，避免基线接近0的新话题分数过高
+	MaxSize   int     // 最多保留的话题数，不大于0时不限制
+}
+
+// Usage 话题在各时间窗口内的引用次数
+type Usage struct {
+	TagID int64
+	Hour  int64
+	Day   int64
+	Week  int64
+	Score float64
+}
+
+// Bucket 时间戳所在的统计桶，按小时分桶
+func Bucket(ts int64) int64 {
+	return ts - ts%WindowHour
+}
+
+// Score 话题的热议分，最近的引用速度相对于之前六天基线的倍数，再乘以最近一天引用次数的对数
+func (o *Options) Score(u *Usage) float64 {
+	recent := float64(u.Hour) + float64(u.Day)/24
+	baseline := float64(max(u.Week-u.Day, 0)) / float64((WindowWeek-WindowDay)/WindowHour)
+	return recent / (baseline + o.Smoothing) * math.Log1p(float64(u.Day))
+}
+
+// Rank 过滤引用次数不足的话题，按热议分从高到低排序，同分时新话题在前
+func Rank(usages []*Usage, o *Options) []*Usage {
+	res := make([]*Usage, 0, len(usages))
+	for _, u := range usages {
+		if u.Day < max(o.MinUsage, 1) {
+			continue
+		}
+		u.Score = o.Score(u)
+		res = append(res, u)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].TagID > res[j].TagID
+	})
+	if o.MaxSize > 0 && len(res) > o.MaxSize {
+		res = res[:o.MaxSize]
+	}
+	return res
+}
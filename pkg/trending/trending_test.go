@@ -0,0 +1,68 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package trending_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rocboss/paopao-ce/pkg/trending"
+)
+
+var _ = Describe("Trending", func() {
+	opts := &trending.Options{
+		MinUsage:  3,
+		Smoothing: 1,
+		MaxSize:   3,
+	}
+
+	It("bucket by hour", func() {
+		Expect(trending.Bucket(1700000000)).To(Equal(int64(1699999200)))
+		Expect(trending.Bucket(1699999200)).To(Equal(int64(1699999200)))
+	})
+
+	It("spiking tag beats steady popular tag", func() {
+		steady := &trending.Usage{TagID: 1, Hour: 4, Day: 100, Week: 700}
+		spiking := &trending.Usage{TagID: 2, Hour: 50, Day: 300, Week: 900}
+		Expect(opts.Score(spiking)).To(BeNumerically(">", opts.Score(steady)))
+	})
+
+	It("new tag does not explode without baseline", func() {
+		fresh := &trending.Usage{TagID: 1, Hour: 3, Day: 3, Week: 3}
+		spiking := &trending.Usage{TagID: 2, Hour: 50, Day: 300, Week: 900}
+		Expect(opts.Score(fresh)).To(BeNumerically("<", opts.Score(spiking)))
+	})
+
+	It("more usage in same window scores higher", func() {
+		a := &trending.Usage{TagID: 1, Hour: 5, Day: 20, Week: 40}
+		b := &trending.Usage{TagID: 2, Hour: 10, Day: 20, Week: 40}
+		Expect(opts.Score(b)).To(BeNumerically(">", opts.Score(a)))
+	})
+
+	It("rank filters, orders and truncates", func() {
+		res := trending.Rank([]*trending.Usage{
+			{TagID: 1, Hour: 1, Day: 2, Week: 2},
+			{TagID: 2, Hour: 4, Day: 100, Week: 700},
+			{TagID: 3, Hour: 50, Day: 300, Week: 900},
+			{TagID: 4, Hour: 4, Day: 100, Week: 700},
+			{TagID: 5, Hour: 0, Day: 3, Week: 500},
+		}, opts)
+		ids := make([]int64, 0, len(res))
+		for _, u := range res {
+			ids = append(ids, u.TagID)
+		}
+		Expect(ids).To(Equal([]int64{3, 4, 2}))
+		Expect(res[0].Score).To(BeNumerically(">", 0))
+	})
+
+	It("rank without limit keeps all qualified tags", func() {
+		res := trending.Rank([]*trending.Usage{
+			{TagID: 1, Hour: 0, Day: 0, Week: 10},
+			{TagID: 2, Hour: 1, Day: 1, Week: 1},
+		}, &trending.Options{Smoothing: 1})
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].TagID).To(Equal(int64(2)))
+	})
+})
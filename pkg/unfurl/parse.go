@@ -0,0 +1,148 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package unfurl
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	_maxTitleLength       = 255
+	_maxDescriptionLength = 512
+	_maxSiteNameLength    = 128
+)
+
+// metadata page metadata extracted from html head
+type metadata struct {
+	meta   map[string]string
+	title  string
+	oembed string
+}
+
+// fill fill preview by priority: OpenGraph > Twitter Card > html
+func (m *metadata) fill(preview *Preview, base *url.URL) {
+	preview.Title = truncate(firstNonEmpty(m.meta["og:title"], m.meta["twitter:title"], m.title), _maxTitleLength)
+	preview.Description = truncate(firstNonEmpty(m.meta["og:description"], m.meta["twitter:description"], m.meta["description"]), _maxDescriptionLength)
+	preview.SiteName = truncate(firstNonEmpty(m.meta["og:site_name"], m.meta["twitter:site"]), _maxSiteNameLength)
+	if image := firstNonEmpty(m.meta["og:image:secure_url"], m.meta["og:image"], m.meta["og:image:url"], m.meta["twitter:image"], m.meta["twitter:image:src"]); image != "" {
+		preview.Image = resolveURL(base, image)
+	}
+}
+
+// parseHTML extract metadata from html head, contentType used to detect charset
+func parseHTML(data []byte, contentType string) *metadata {
+	m := &metadata{
+		meta: make(map[string]string),
+	}
+	var r io.Reader = bytes.NewReader(data)
+	if cr, err := charset.NewReader(r, contentType); err == nil {
+		r = cr
+	}
+	z := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return m
+		case html.TextToken:
+			if inTitle && m.title == "" {
+				m.title = strings.TrimSpace(html.UnescapeString(string(z.Text())))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				// 只关心head中的元信息
+				return m
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = true
+			case "body":
+				return m
+			case "meta":
+				if hasAttr {
+					m.parseMeta(attrsOf(z))
+				}
+			case "link":
+				if hasAttr {
+					m.parseLink(attrsOf(z))
+				}
+			}
+		}
+	}
+}
+
+func (m *metadata) parseMeta(attrs map[string]string) {
+	key := firstNonEmpty(attrs["property"], attrs["name"])
+	content := strings.TrimSpace(attrs["content"])
+	if key == "" || content == "" {
+		return
+	}
+	key = strings.ToLower(key)
+	// 以第一次出现的值为准
+	if _, exist := m.meta[key]; !exist {
+		m.meta[key] = content
+	}
+}
+
+func (m *metadata) parseLink(attrs map[string]string) {
+	if m.oembed == "" && strings.EqualFold(attrs["rel"], "alternate") &&
+		strings.EqualFold(attrs["type"], "application/json+oembed") {
+		m.oembed = attrs["href"]
+	}
+}
+
+func attrsOf(z *html.Tokenizer) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		key, val, more := z.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(val)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+// resolveURL resolve ref relative to base, only http/https url is accepted
+func resolveURL(base *url.URL, ref string) string {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+func firstNonEmpty(items ...string) string {
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			return item
+		}
+	}
+	return ""
+}
+
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// package unfurl fetch web page and extract link preview from
+// OpenGraph/Twitter Card/oEmbed metadata.
+
+package unfurl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	hx "github.com/rocboss/paopao-ce/pkg/http"
+	"github.com/rocboss/paopao-ce/pkg/json"
+)
+
+const (
+	_defaultMaxBodySize  = 1 << 20
+	_defaultMaxImageSize = 5 << 20
+	_defaultUserAgent    = "Mozilla/5.0 (compatible; paopao-ce unfurl)"
+)
+
+var (
+	_ Unfurler = (*unfurler)(nil)
+)
+
+var (
+	// ErrInvalidURL invalid link url
+	ErrInvalidURL = errors.New("invalid url")
+	// ErrUnsupportedContent content type of link is not supported
+	ErrUnsupportedContent = errors.New("unsupported content type")
+	// ErrTooLarge response body is too large
+	ErrTooLarge = errors.New("response body is too large")
+)
+
+// PreviewFn a function used to handle the result of Unfurl
+type PreviewFn func(preview *Preview, err error)
+
+// ImageFn a function used to handle the result of FetchImage
+type ImageFn func(img *Image, err error)
+
+// Preview link preview information
+type Preview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+	SiteName    string `json:"site_name"`
+}
+
+// Image fetched image data
+type Image struct {
+	Data        []byte
+	ContentType string
+}
+
+// Unfurler asynchronous link unfurl interface
+type Unfurler interface {
+	Unfurl(link string, fn PreviewFn)
+	FetchImage(link string, fn ImageFn)
+}
+
+// Config configure used to create an Unfurler instance
+type Config struct {
+	MaxBodySize  int64
+	MaxImageSize int64
+	UserAgent    string
+}
+
+type unfurler struct {
+	client       hx.AsyncClient
+	maxBodySize  int64
+	maxImageSize int64
+	userAgent    string
+}
+
+type oembed struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func (s *unfurler) Unfurl(link string, fn PreviewFn) {
+	req, err := s.newRequest(link, "text/html,application/xhtml+xml")
+	if err != nil {
+		fn(nil, err)
+		return
+	}
+	s.client.Do(req, func(req *http.Request, resp *http.Response, err error) {
+		data, contentType, err := s.readResponse(resp, err, s.maxBodySize)
+		if err != nil {
+			fn(nil, err)
+			return
+		}
+		// 响应的最终地址，用于解析相对路径
+		base := req.URL
+		if resp.Request != nil && resp.Request.URL != nil {
+			base = resp.Request.URL
+		}
+		preview := &Preview{
+			URL: link,
+		}
+		if strings.HasPrefix(contentType, "image/") {
+			preview.Image = base.String()
+			fn(preview, nil)
+			return
+		}
+		if contentType != "text/html" && contentType != "application/xhtml+xml" {
+			fn(nil, ErrUnsupportedContent)
+			return
+		}
+		meta := parseHTML(data, resp.Header.Get("Content-Type"))
+		meta.fill(preview, base)
+		// 缺少标题或预览图时尝试使用oEmbed补全
+		if meta.oembed == "" || (preview.Title != "" && preview.Image != "") {
+			fn(preview, nil)
+			return
+		}
+		s.fetchOembed(resolveURL(base, meta.oembed), preview, fn)
+	})
+}
+
+func (s *unfurler) FetchImage(link string, fn ImageFn) {
+	req, err := s.newRequest(link, "image/*")
+	if err != nil {
+		fn(nil, err)
+		return
+	}
+	s.client.Do(req, func(req *http.Request, resp *http.Response, err error) {
+		data, contentType, err := s.readResponse(resp, err, s.maxImageSize)
+		if err != nil {
+			fn(nil, err)
+			return
+		}
+		if !strings.HasPrefix(contentType, "image/") {
+			fn(nil, ErrUnsupportedContent)
+			return
+		}
+		fn(&Image{
+			Data:        data,
+			ContentType: contentType,
+		}, nil)
+	})
+}
+
+func (s *unfurler) fetchOembed(link string, preview *Preview, fn PreviewFn) {
+	req, err := s.newRequest(link, "application/json")
+	if err != nil {
+		fn(preview, nil)
+		return
+	}
+	s.client.Do(req, func(req *http.Request, resp *http.Response, err error) {
+		data, _, err := s.readResponse(resp, err, s.maxBodySize)
+		if err != nil {
+			// oEmbed只是补充信息，失败时仍返回已有的预览
+			fn(preview, nil)
+			return
+		}
+		info := &oembed{}
+		if err = json.Unmarshal(data, info); err == nil {
+			preview.Title = firstNonEmpty(preview.Title, info.Title)
+			preview.SiteName = firstNonEmpty(preview.SiteName, info.ProviderName, info.AuthorName)
+			if preview.Image == "" && info.ThumbnailURL != "" {
+				preview.Image = resolveURL(req.URL, info.ThumbnailURL)
+			}
+		}
+		fn(preview, nil)
+	})
+}
+
+func (s *unfurler) newRequest(link string, accept string) (*http.Request, error) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("Accept", accept)
+	return req, nil
+}
+
+// readResponse read response body limited by maxSize and return the media type of body
+func (s *unfurler) readResponse(resp *http.Response, err error, maxSize int64) ([]byte, string, error) {
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, "", ErrTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", ErrTooLarge
+	}
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return data, contentType, nil
+}
+
+// NewUnfurler create an Unfurler instance that do request by client
+func NewUnfurler(client hx.AsyncClient, conf *Config) Unfurler {
+	s := &unfurler{
+		client:       client,
+		maxBodySize:  _defaultMaxBodySize,
+		maxImageSize: _defaultMaxImageSize,
+		userAgent:    _defaultUserAgent,
+	}
+	if conf.MaxBodySize > 0 {
+		s.maxBodySize = conf.MaxBodySize
+	}
+	if conf.MaxImageSize > 0 {
+		s.maxImageSize = conf.MaxImageSize
+	}
+	if conf.UserAgent != "" {
+		s.userAgent = conf.UserAgent
+	}
+	return s
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package unfurl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	hx "github.com/rocboss/paopao-ce/pkg/http"
+)
+
+const (
+	_ogPage = `<!DOCTYPE html>
+<html><head>
+<title>Html Title</title>
+<meta property="og:title" content="OG Title">
+<meta property="og:description" content="OG &amp; Description">
+<meta property="og:image" content="/static/cover.png">
+<meta property="og:site_name" content="PaoPao">
+<meta name="twitter:title" content="Twitter Title">
+</head><body><meta property="og:title" content="Body Title"></body></html>`
+
+	_twitterPage = `<html><head>
+<title>  Html Title  </title>
+<meta name="twitter:title" content="Twitter Title">
+<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+<meta name="description" content="Html Description">
+</head></html>`
+
+	_oembedPage = `<html><head>
+<title>Video Page</title>
+<link rel="alternate" type="application/json+oembed" href="/oembed?url=video">
+</head></html>`
+
+	_oembedJson = `{"type":"video","title":"oEmbed Title","provider_name":"VideoSite","thumbnail_url":"/thumb.jpg"}`
+)
+
+var _ = g.Describe("Unfurl", g.Ordered, func() {
+	var (
+		server *httptest.Server
+		uf     Unfurler
+	)
+
+	unfurl := func(link string) (preview *Preview, err error) {
+		done := make(chan struct{})
+		uf.Unfurl(link, func(p *Preview, e error) {
+			preview, err = p, e
+			close(done)
+		})
+		m.Eventually(done).WithTimeout(5 * time.Second).Should(m.BeClosed())
+		return
+	}
+
+	g.BeforeAll(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/og", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, _ogPage)
+		})
+		mux.HandleFunc("/twitter", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, _twitterPage)
+		})
+		mux.HandleFunc("/video", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, _oembedPage)
+		})
+		mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, _oembedJson)
+		})
+		mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><head><title>"+strings.Repeat("x", 2048)+"</title></head></html>")
+		})
+		mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		})
+		mux.HandleFunc("/file.zip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("PK"))
+		})
+		mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/og", http.StatusFound)
+		})
+		server = httptest.NewServer(mux)
+		client := hx.NewSafeClient(&hx.SafeClientConf{
+			AllowPrivate: true,
+		})
+		uf = NewUnfurler(hx.NewAsyncClient(client, &hx.AsyncClientConf{}), &Config{
+			MaxBodySize: 1024,
+		})
+	})
+
+	g.AfterAll(func() {
+		server.Close()
+	})
+
+	g.It("opengraph", func() {
+		preview, err := unfurl(server.URL + "/og")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(preview.URL).To(m.Equal(server.URL + "/og"))
+		m.Expect(preview.Title).To(m.Equal("OG Title"))
+		m.Expect(preview.Description).To(m.Equal("OG & Description"))
+		m.Expect(preview.Image).To(m.Equal(server.URL + "/static/cover.png"))
+		m.Expect(preview.SiteName).To(m.Equal("PaoPao"))
+	})
+
+	g.It("twitter card", func() {
+		preview, err := unfurl(server.URL + "/twitter")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(preview.Title).To(m.Equal("Twitter Title"))
+		m.Expect(preview.Description).To(m.Equal("Html Description"))
+		m.Expect(preview.Image).To(m.Equal("https://cdn.example.com/card.jpg"))
+	})
+
+	g.It("oembed", func() {
+		preview, err := unfurl(server.URL + "/video")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(preview.Title).To(m.Equal("Video Page"))
+		m.Expect(preview.SiteName).To(m.Equal("VideoSite"))
+		m.Expect(preview.Image).To(m.Equal(server.URL + "/thumb.jpg"))
+	})
+
+	g.It("follow redirect", func() {
+		preview, err := unfurl(server.URL + "/redirect")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(preview.URL).To(m.Equal(server.URL + "/redirect"))
+		m.Expect(preview.Image).To(m.Equal(server.URL + "/static/cover.png"))
+	})
+
+	g.It("direct image", func() {
+		preview, err := unfurl(server.URL + "/image.png")
+		m.Expect(err).To(m.BeNil())
+		m.Expect(preview.Image).To(m.Equal(server.URL + "/image.png"))
+	})
+
+	g.It("invalid link", func() {
+		_, err := unfurl("ftp://example.com/file")
+		m.Expect(errors.Is(err, ErrInvalidURL)).To(m.BeTrue())
+
+		_, err = unfurl(server.URL + "/large")
+		m.Expect(errors.Is(err, ErrTooLarge)).To(m.BeTrue())
+
+		_, err = unfurl(server.URL + "/file.zip")
+		m.Expect(errors.Is(err, ErrUnsupportedContent)).To(m.BeTrue())
+
+		_, err = unfurl(server.URL + "/not-found")
+		m.Expect(err).NotTo(m.BeNil())
+	})
+
+	g.It("fetch image", func() {
+		done := make(chan struct{})
+		var (
+			img *Image
+			err error
+		)
+		uf.FetchImage(server.URL+"/image.png", func(i *Image, e error) {
+			img, err = i, e
+			close(done)
+		})
+		m.Eventually(done).WithTimeout(5 * time.Second).Should(m.BeClosed())
+		m.Expect(err).To(m.BeNil())
+		m.Expect(img.ContentType).To(m.Equal("image/png"))
+		m.Expect(img.Data).To(m.HaveLen(8))
+	})
+
+	g.It("deny private address", func() {
+		client := hx.NewSafeClient(&hx.SafeClientConf{})
+		safe := NewUnfurler(hx.NewAsyncClient(client, &hx.AsyncClientConf{}), &Config{})
+		done := make(chan struct{})
+		var err error
+		safe.Unfurl(server.URL+"/og", func(_ *Preview, e error) {
+			err = e
+			close(done)
+		})
+		m.Eventually(done).WithTimeout(5 * time.Second).Should(m.BeClosed())
+		m.Expect(errors.Is(err, hx.ErrPrivateAddress)).To(m.BeTrue())
+	})
+})
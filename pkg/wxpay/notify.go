@@ -0,0 +1,125 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package wxpay
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rocboss/paopao-ce/pkg/json"
+)
+
+const (
+	// TradeStateSuccess transaction is paid
+	TradeStateSuccess = "SUCCESS"
+	// EventTransactionSuccess event type of paid transaction notification
+	EventTransactionSuccess = "TRANSACTION.SUCCESS"
+
+	_notifyMaxSkew = 5 * time.Minute
+)
+
+var (
+	// ErrExpiredNotify timestamp of notification is too old or too new
+	ErrExpiredNotify = errors.New("wxpay: notification expired")
+)
+
+// Resource encrypted resource of notification
+type Resource struct {
+	Algorithm      string `json:"algorithm"`
+	Ciphertext     string `json:"ciphertext"`
+	AssociatedData string `json:"associated_data"`
+	Nonce          string `json:"nonce"`
+	OriginalType   string `json:"original_type"`
+}
+
+// Notify notification of WeChat Pay
+type Notify struct {
+	ID           string    `json:"id"`
+	CreateTime   string    `json:"create_time"`
+	EventType    string    `json:"event_type"`
+	ResourceType string    `json:"resource_type"`
+	Summary      string    `json:"summary"`
+	Resource     *Resource `json:"resource"`
+}
+
+// TransactionAmount amount of paid transaction
+type TransactionAmount struct {
+	Total         int64  `json:"total"`
+	PayerTotal    int64  `json:"payer_total"`
+	Currency      string `json:"currency"`
+	PayerCurrency string `json:"payer_currency"`
+}
+
+// Transaction transaction of paid notification
+type Transaction struct {
+	AppID         string             `json:"appid"`
+	MchID         string             `json:"mchid"`
+	OutTradeNo    string             `json:"out_trade_no"`
+	TransactionID string             `json:"transaction_id"`
+	TradeType     string             `json:"trade_type"`
+	TradeState    string             `json:"trade_state"`
+	SuccessTime   string             `json:"success_time"`
+	Amount        *TransactionAmount `json:"amount"`
+}
+
+// ParseNotify verify the signature of notification request and decrypt the transaction
+func (c *Client) ParseNotify(r *http.Request) (*Notify, *Transaction, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, _maxResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	timestamp, err := strconv.ParseInt(r.Header.Get("Wechatpay-Timestamp"), 10, 64)
+	if err != nil {
+		return nil, nil, ErrInvalidSignature
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > _notifyMaxSkew || skew < -_notifyMaxSkew {
+		return nil, nil, ErrExpiredNotify
+	}
+	if err = c.verify(r.Header, body); err != nil {
+		return nil, nil, err
+	}
+	notify := &Notify{}
+	if err = json.Unmarshal(body, notify); err != nil {
+		return nil, nil, err
+	}
+	if notify.Resource == nil {
+		return nil, nil, errors.New("wxpay: notification resource is missing")
+	}
+	plaintext, err := c.decrypt(notify.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	trans := &Transaction{}
+	if err = json.Unmarshal(plaintext, trans); err != nil {
+		return nil, nil, err
+	}
+	return notify, trans, nil
+}
+
+// decrypt decrypt the resource by AEAD_AES_256_GCM with APIv3 key
+func (c *Client) decrypt(res *Resource) ([]byte, error) {
+	if res.Algorithm != "AEAD_AES_256_GCM" {
+		return nil, errors.New("wxpay: unsupported resource algorithm " + res.Algorithm)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(res.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher([]byte(c.conf.APIv3Key))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCMWithNonceSize(block, len(res.Nonce))
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, []byte(res.Nonce), ciphertext, []byte(res.AssociatedData))
+}
@@ -0,0 +1,304 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// package wxpay a minimal WeChat Pay API v3 client that support native/H5
+// transactions and signed notification verification.
+
+package wxpay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rocboss/paopao-ce/pkg/json"
+)
+
+const (
+	// DefaultGateway WeChat Pay API v3 gateway
+	DefaultGateway = "https://api.mch.weixin.qq.com"
+
+	_authSchema      = "WECHATPAY2-SHA256-RSA2048"
+	_maxResponseSize = 1 << 20
+)
+
+var (
+	// ErrInvalidSignature signature of response or notification is invalid
+	ErrInvalidSignature = errors.New("wxpay: invalid signature")
+	// ErrInvalidKey key is not valid PEM encoded RSA key
+	ErrInvalidKey = errors.New("wxpay: invalid rsa key")
+)
+
+// Config merchant configuration of WeChat Pay
+type Config struct {
+	AppID      string
+	MchID      string
+	SerialNo   string          // 商户API证书序列号
+	PrivateKey *rsa.PrivateKey // 商户API私钥
+	APIv3Key   string          // APIv3密钥，用于解密回调通知
+	PublicKey  *rsa.PublicKey  // 微信支付公钥或平台证书公钥，用于验证应答及回调签名
+	PublicID   string          // 微信支付公钥ID或平台证书序列号，为空时不校验
+	Gateway    string
+	Timeout    time.Duration
+}
+
+// Amount order amount in cent
+type Amount struct {
+	Total    int64  `json:"total"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// H5Info scene of H5 transaction
+type H5Info struct {
+	Type string `json:"type"`
+}
+
+// SceneInfo scene of transaction
+type SceneInfo struct {
+	PayerClientIP string  `json:"payer_client_ip"`
+	H5Info        *H5Info `json:"h5_info,omitempty"`
+}
+
+// Order prepay order of native/H5 transaction
+type Order struct {
+	AppID       string     `json:"appid"`
+	MchID       string     `json:"mchid"`
+	Description string     `json:"description"`
+	OutTradeNo  string     `json:"out_trade_no"`
+	NotifyURL   string     `json:"notify_url"`
+	Amount      *Amount    `json:"amount"`
+	SceneInfo   *SceneInfo `json:"scene_info,omitempty"`
+}
+
+// APIError error response of WeChat Pay API
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("wxpay: status %d code %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Client WeChat Pay API v3 client
+type Client struct {
+	conf   Config
+	client *http.Client
+}
+
+// New create a WeChat Pay client
+func New(conf Config) (*Client, error) {
+	if conf.MchID == "" || conf.SerialNo == "" || conf.PrivateKey == nil || conf.PublicKey == nil {
+		return nil, errors.New("wxpay: mchid/serial no/private key/public key is required")
+	}
+	if len(conf.APIv3Key) != 32 {
+		return nil, errors.New("wxpay: apiv3 key must be 32 bytes")
+	}
+	if conf.Gateway == "" {
+		conf.Gateway = DefaultGateway
+	}
+	conf.Gateway = strings.TrimSuffix(conf.Gateway, "/")
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10 * time.Second
+	}
+	return &Client{
+		conf: conf,
+		client: &http.Client{
+			Timeout: conf.Timeout,
+		},
+	}, nil
+}
+
+// NativePrepay create a native transaction and return the code url for QR code
+func (c *Client) NativePrepay(order *Order) (string, error) {
+	var resp struct {
+		CodeURL string `json:"code_url"`
+	}
+	if err := c.prepay("/v3/pay/transactions/native", order, &resp); err != nil {
+		return "", err
+	}
+	return resp.CodeURL, nil
+}
+
+// H5Prepay create a H5 transaction and return the url to redirect
+func (c *Client) H5Prepay(order *Order) (string, error) {
+	if order.SceneInfo == nil || order.SceneInfo.PayerClientIP == "" {
+		return "", errors.New("wxpay: payer client ip is required for h5 transaction")
+	}
+	if order.SceneInfo.H5Info == nil {
+		order.SceneInfo.H5Info = &H5Info{Type: "Wap"}
+	}
+	var resp struct {
+		H5URL string `json:"h5_url"`
+	}
+	if err := c.prepay("/v3/pay/transactions/h5", order, &resp); err != nil {
+		return "", err
+	}
+	return resp.H5URL, nil
+}
+
+func (c *Client) prepay(path string, order *Order, resp any) error {
+	if order.AppID == "" {
+		order.AppID = c.conf.AppID
+	}
+	if order.MchID == "" {
+		order.MchID = c.conf.MchID
+	}
+	if order.Amount != nil && order.Amount.Currency == "" {
+		order.Amount.Currency = "CNY"
+	}
+	return c.do(http.MethodPost, path, order, resp)
+}
+
+// do send a signed request and verify the signature of response
+func (c *Client) do(method string, path string, body any, resp any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.conf.Gateway+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	authorization, err := c.authorization(method, path, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	respData, err := io.ReadAll(io.LimitReader(res.Body, _maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: res.StatusCode}
+		json.Unmarshal(respData, apiErr)
+		return apiErr
+	}
+	if err = c.verify(res.Header, respData); err != nil {
+		return err
+	}
+	if resp != nil && len(respData) > 0 {
+		return json.Unmarshal(respData, resp)
+	}
+	return nil
+}
+
+// authorization build the Authorization header value of request
+func (c *Client) authorization(method string, path string, body []byte) (string, error) {
+	nonce, err := nonceStr()
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	message := method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + string(body) + "\n"
+	signature, err := c.sign(message)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`%s mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		_authSchema, c.conf.MchID, nonce, signature, timestamp, c.conf.SerialNo), nil
+}
+
+func (c *Client) sign(message string) (string, error) {
+	digest := sha256.Sum256([]byte(message))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.conf.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// verify verify the Wechatpay-Signature header of response or notification
+func (c *Client) verify(header http.Header, body []byte) error {
+	timestamp, nonce := header.Get("Wechatpay-Timestamp"), header.Get("Wechatpay-Nonce")
+	signature, serial := header.Get("Wechatpay-Signature"), header.Get("Wechatpay-Serial")
+	if timestamp == "" || nonce == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+	if c.conf.PublicID != "" && serial != c.conf.PublicID {
+		return ErrInvalidSignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	message := timestamp + "\n" + nonce + "\n" + string(body) + "\n"
+	digest := sha256.Sum256([]byte(message))
+	if err = rsa.VerifyPKCS1v15(c.conf.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParsePrivateKey parse PEM encoded PKCS#8 or PKCS#1 RSA private key
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, ErrInvalidKey
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// ParsePublicKey parse PEM encoded PKIX RSA public key or the public key of certificate
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	var key any
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	} else {
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	}
+	if rsaKey, ok := key.(*rsa.PublicKey); ok {
+		return rsaKey, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+func nonceStr() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(buf)), nil
+}
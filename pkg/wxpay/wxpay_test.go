@@ -0,0 +1,235 @@
+// Copyright 2023 ROC. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package wxpay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	m "github.com/onsi/gomega"
+	"github.com/rocboss/paopao-ce/pkg/json"
+)
+
+const (
+	_apiV3Key = "0123456789abcdef0123456789abcdef"
+	_publicID = "PUB_KEY_ID_0001"
+)
+
+// mockGateway 模拟微信支付网关，校验请求签名并对应答签名
+type mockGateway struct {
+	mchKey      *rsa.PublicKey
+	platformKey *rsa.PrivateKey
+	lastPath    string
+	lastOrder   Order
+}
+
+func (gw *mockGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !gw.verifyAuthorization(r, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"SIGN_ERROR","message":"签名错误"}`))
+		return
+	}
+	gw.lastPath = r.URL.Path
+	json.Unmarshal(body, &gw.lastOrder)
+	var resp string
+	switch r.URL.Path {
+	case "/v3/pay/transactions/native":
+		resp = `{"code_url":"weixin://wxpay/bizpayurl?pr=mock"}`
+	case "/v3/pay/transactions/h5":
+		resp = `{"h5_url":"https://wx.tenpay.com/cgi-bin/mmpayweb-bin/checkmweb?prepay_id=mock"}`
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	signHeader(w.Header(), gw.platformKey, []byte(resp), time.Now())
+	w.Write([]byte(resp))
+}
+
+func (gw *mockGateway) verifyAuthorization(r *http.Request, body []byte) bool {
+	auth, found := strings.CutPrefix(r.Header.Get("Authorization"), _authSchema+" ")
+	if !found {
+		return false
+	}
+	params := make(map[string]string)
+	for _, kv := range strings.Split(auth, ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			params[k] = strings.Trim(v, `"`)
+		}
+	}
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return false
+	}
+	message := r.Method + "\n" + r.URL.Path + "\n" + params["timestamp"] + "\n" + params["nonce_str"] + "\n" + string(body) + "\n"
+	digest := sha256.Sum256([]byte(message))
+	return params["mchid"] == "1900000001" && rsa.VerifyPKCS1v15(gw.mchKey, crypto.SHA256, digest[:], sig) == nil
+}
+
+func signHeader(header http.Header, key *rsa.PrivateKey, body []byte, now time.Time) {
+	timestamp, nonce := strconv.FormatInt(now.Unix(), 10), "mocknonce"
+	digest := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + string(body) + "\n"))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	header.Set("Wechatpay-Timestamp", timestamp)
+	header.Set("Wechatpay-Nonce", nonce)
+	header.Set("Wechatpay-Signature", base64.StdEncoding.EncodeToString(sig))
+	header.Set("Wechatpay-Serial", _publicID)
+}
+
+func notifyBody(trans *Transaction) []byte {
+	plaintext, _ := json.Marshal(trans)
+	block, _ := aes.NewCipher([]byte(_apiV3Key))
+	aead, _ := cipher.NewGCM(block)
+	nonce, ad := "0123456789ab", "transaction"
+	body, _ := json.Marshal(&Notify{
+		ID:           "EV-2018022511223320873",
+		EventType:    EventTransactionSuccess,
+		ResourceType: "encrypt-resource",
+		Resource: &Resource{
+			Algorithm:      "AEAD_AES_256_GCM",
+			Ciphertext:     base64.StdEncoding.EncodeToString(aead.Seal(nil, []byte(nonce), plaintext, []byte(ad))),
+			AssociatedData: ad,
+			Nonce:          nonce,
+			OriginalType:   "transaction",
+		},
+	})
+	return body
+}
+
+var _ = g.Describe("Wxpay", g.Ordered, func() {
+	var (
+		server      *httptest.Server
+		gateway     *mockGateway
+		client      *Client
+		platformKey *rsa.PrivateKey
+	)
+
+	g.BeforeAll(func() {
+		mchKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		m.Expect(err).To(m.BeNil())
+		platformKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		m.Expect(err).To(m.BeNil())
+		gateway = &mockGateway{
+			mchKey:      &mchKey.PublicKey,
+			platformKey: platformKey,
+		}
+		server = httptest.NewServer(gateway)
+		client, err = New(Config{
+			AppID:      "wxd678efh567hg6787",
+			MchID:      "1900000001",
+			SerialNo:   "5157F09EFDC096DE15EBE81A47057A7232F1B8E1",
+			PrivateKey: mchKey,
+			APIv3Key:   _apiV3Key,
+			PublicKey:  &platformKey.PublicKey,
+			PublicID:   _publicID,
+			Gateway:    server.URL,
+		})
+		m.Expect(err).To(m.BeNil())
+	})
+
+	g.AfterAll(func() {
+		server.Close()
+	})
+
+	g.It("create native transaction", func() {
+		codeURL, err := client.NativePrepay(&Order{
+			Description: "PaoPao用户钱包充值",
+			OutTradeNo:  "10023",
+			NotifyURL:   "https://example.com/v1/wechatpay/notify",
+			Amount:      &Amount{Total: 100},
+		})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(codeURL).To(m.Equal("weixin://wxpay/bizpayurl?pr=mock"))
+		m.Expect(gateway.lastPath).To(m.Equal("/v3/pay/transactions/native"))
+		m.Expect(gateway.lastOrder.AppID).To(m.Equal("wxd678efh567hg6787"))
+		m.Expect(gateway.lastOrder.Amount.Currency).To(m.Equal("CNY"))
+	})
+
+	g.It("create h5 transaction", func() {
+		_, err := client.H5Prepay(&Order{OutTradeNo: "10024", Amount: &Amount{Total: 100}})
+		m.Expect(err).NotTo(m.BeNil())
+
+		h5URL, err := client.H5Prepay(&Order{
+			OutTradeNo: "10024",
+			Amount:     &Amount{Total: 100},
+			SceneInfo:  &SceneInfo{PayerClientIP: "127.0.0.1"},
+		})
+		m.Expect(err).To(m.BeNil())
+		m.Expect(h5URL).To(m.HavePrefix("https://wx.tenpay.com/"))
+		m.Expect(gateway.lastOrder.SceneInfo.H5Info.Type).To(m.Equal("Wap"))
+	})
+
+	g.It("reject response with invalid signature", func() {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		m.Expect(err).To(m.BeNil())
+		gateway.platformKey = otherKey
+		defer func() { gateway.platformKey = platformKey }()
+		_, err = client.NativePrepay(&Order{OutTradeNo: "10025", Amount: &Amount{Total: 100}})
+		m.Expect(err).To(m.Equal(ErrInvalidSignature))
+	})
+
+	g.It("return api error", func() {
+		c := *client
+		c.conf.MchID = "1900000002"
+		_, err := c.NativePrepay(&Order{OutTradeNo: "10026", Amount: &Amount{Total: 100}})
+		m.Expect(err).To(m.BeAssignableToTypeOf(&APIError{}))
+		m.Expect(err.(*APIError).Code).To(m.Equal("SIGN_ERROR"))
+	})
+
+	g.Describe("parse notify", func() {
+		trans := &Transaction{
+			MchID:         "1900000001",
+			OutTradeNo:    "10023",
+			TransactionID: "4200000001201712012345678901",
+			TradeState:    TradeStateSuccess,
+			Amount:        &TransactionAmount{Total: 100, PayerTotal: 100, Currency: "CNY"},
+		}
+		request := func(body []byte, now time.Time) *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/v1/wechatpay/notify", bytes.NewReader(body))
+			signHeader(r.Header, platformKey, body, now)
+			return r
+		}
+
+		g.It("decrypt valid notify", func() {
+			notify, got, err := client.ParseNotify(request(notifyBody(trans), time.Now()))
+			m.Expect(err).To(m.BeNil())
+			m.Expect(notify.EventType).To(m.Equal(EventTransactionSuccess))
+			m.Expect(got).To(m.Equal(trans))
+		})
+
+		g.It("reject tampered notify", func() {
+			r := request(notifyBody(trans), time.Now())
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(bytes.Replace(body, []byte("encrypt-resource"), []byte("encrypt-resourcE"), 1)))
+			_, _, err := client.ParseNotify(r)
+			m.Expect(err).To(m.Equal(ErrInvalidSignature))
+		})
+
+		g.It("reject expired notify", func() {
+			_, _, err := client.ParseNotify(request(notifyBody(trans), time.Now().Add(-time.Hour)))
+			m.Expect(err).To(m.Equal(ErrExpiredNotify))
+		})
+
+		g.It("reject notify of other public key id", func() {
+			r := request(notifyBody(trans), time.Now())
+			r.Header.Set("Wechatpay-Serial", "PUB_KEY_ID_0002")
+			_, _, err := client.ParseNotify(r)
+			m.Expect(err).To(m.Equal(ErrInvalidSignature))
+		})
+	})
+})
@@ -0,0 +1,21 @@
+CREATE TABLE `p_post_schedule` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '定时发布ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`contents` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '推文内容(JSON)',
+	`tags` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '标签',
+	`users` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '@用户',
+	`attachment_price` BIGINT NOT NULL DEFAULT '0' COMMENT '附件价格(分)',
+	`visibility` tinyint NOT NULL DEFAULT '0' COMMENT '可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开',
+	`ip` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP地址',
+	`ip_loc` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP城市地址',
+	`publish_at` BIGINT NOT NULL DEFAULT '0' COMMENT '计划发布时间',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '状态: 0待发布 1已发布 2已取消 3发布失败',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '发布后的POST ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_schedule_user_id` (`user_id`) USING BTREE,
+	KEY `idx_post_schedule_status_publish_at` (`status`, `publish_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章定时发布';
@@ -0,0 +1,42 @@
+CREATE TABLE `p_post_poll` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '投票ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT 'POST ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '发起投票的用户ID',
+	`multiple` tinyint NOT NULL DEFAULT '0' COMMENT '是否多选 0 为单选、1 为多选',
+	`anonymous` tinyint NOT NULL DEFAULT '0' COMMENT '是否匿名 0 为公开、1 为匿名',
+	`vote_count` BIGINT NOT NULL DEFAULT '0' COMMENT '投票人数',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '截止时间',
+	`closed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '结束时间 0 为进行中',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_post_poll_post_id` (`post_id`) USING BTREE,
+	KEY `idx_post_poll_expired_on` (`closed_on`, `expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票';
+CREATE TABLE `p_post_poll_option` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '选项ID',
+	`poll_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票ID',
+	`content` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '选项内容',
+	`sort` BIGINT NOT NULL DEFAULT '0' COMMENT '排序，越小越靠前',
+	`vote_count` BIGINT NOT NULL DEFAULT '0' COMMENT '得票数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_poll_option_poll_id` (`poll_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票选项';
+CREATE TABLE `p_post_poll_vote` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '投票记录ID',
+	`poll_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票用户ID',
+	`option_ids` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '选中的选项ID列表',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_post_poll_vote_poll_user` (`poll_id`, `user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票记录';
@@ -0,0 +1,4 @@
+ALTER TABLE `p_attachment` ADD COLUMN `blurhash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '图片blurhash占位';
+ALTER TABLE `p_attachment` ADD COLUMN `dominant_color` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '图片主色调';
+ALTER TABLE `p_attachment` ADD COLUMN `variants` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '图片缩略图/WebP等版本(JSON)';
+CREATE INDEX `idx_attachment_content` ON `p_attachment` (`content`);
@@ -0,0 +1,5 @@
+ALTER TABLE `p_attachment` ADD COLUMN `duration` BIGINT NOT NULL DEFAULT '0' COMMENT '视频时长(毫秒)';
+ALTER TABLE `p_attachment` ADD COLUMN `poster` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '视频封面';
+ALTER TABLE `p_attachment` ADD COLUMN `playlist` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '视频HLS主播放列表';
+ALTER TABLE `p_attachment` ADD COLUMN `status` tinyint NOT NULL DEFAULT '0' COMMENT '媒体处理状态 0就绪、1等待处理、2处理中、3处理失败';
+CREATE INDEX `idx_attachment_status` ON `p_attachment` (`status`);
@@ -0,0 +1,17 @@
+CREATE TABLE `p_upload_session` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '上传会话ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`upload_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '上传类型',
+	`content_type` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '文件MIME类型',
+	`file_ext` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '文件扩展名',
+	`file_size` BIGINT NOT NULL DEFAULT '0' COMMENT '文件总大小',
+	`received_size` BIGINT NOT NULL DEFAULT '0' COMMENT '已接收大小',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '过期时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_upload_session_user_id` (`user_id`) USING BTREE,
+	KEY `idx_upload_session_expired_on` (`expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='断点续传上传会话';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `p_user_storage_quota`;
@@ -0,0 +1,12 @@
+CREATE TABLE `p_user_storage_quota` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '配额ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`max_size` BIGINT NOT NULL DEFAULT '0' COMMENT '最大存储空间(字节) 0 为不限制',
+	`max_files` BIGINT NOT NULL DEFAULT '0' COMMENT '最大文件数 0 为不限制',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_user_storage_quota_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户存储配额，管理员单独设置';
@@ -0,0 +1,15 @@
+CREATE TABLE `p_object_blob` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '对象ID',
+	`sha256` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '内容的SHA256',
+	`upload_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '上传类型',
+	`content` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '对象地址',
+	`file_size` BIGINT NOT NULL DEFAULT '0' COMMENT '文件大小',
+	`ref_count` BIGINT NOT NULL DEFAULT '0' COMMENT '引用计数(上传次数)',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_object_blob_sha256` (`sha256`, `upload_type`) USING BTREE,
+	KEY `idx_object_blob_content` (`content`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='按内容SHA256去重的对象';
@@ -0,0 +1 @@
+ALTER TABLE `p_wallet_recharge` ADD COLUMN `provider` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'alipay' COMMENT '支付渠道 alipay、wechatpay';
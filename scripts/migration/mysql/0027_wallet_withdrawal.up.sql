@@ -0,0 +1,22 @@
+CREATE TABLE `p_wallet_withdrawal` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '提现ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '提现金额(含手续费)',
+	`fee` BIGINT NOT NULL DEFAULT '0' COMMENT '手续费',
+	`account_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款渠道 alipay、wechatpay、bank',
+	`account` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款账号',
+	`account_name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款人姓名',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '提现状态 0待审核、1待打款、2已打款、3已拒绝、4打款失败、5已取消',
+	`reviewer_id` BIGINT NOT NULL DEFAULT '0' COMMENT '审核人ID',
+	`trade_no` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '打款流水号',
+	`remark` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '审核备注',
+	`reviewed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '审核时间',
+	`paid_on` BIGINT NOT NULL DEFAULT '0' COMMENT '打款时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_wallet_withdrawal_user_id` (`user_id`) USING BTREE,
+	KEY `idx_wallet_withdrawal_status` (`status`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包提现';
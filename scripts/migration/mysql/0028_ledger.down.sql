@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `p_ledger_entry`;
+DROP TABLE IF EXISTS `p_ledger_journal`;
+DROP TABLE IF EXISTS `p_ledger_account`;
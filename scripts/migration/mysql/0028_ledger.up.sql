@@ -0,0 +1,40 @@
+CREATE TABLE `p_ledger_account` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '账户ID',
+	`code` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '账户编码 user:<用户ID>、system:<用途>、channel:<支付渠道>',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID，系统账户为0',
+	`balance` BIGINT NOT NULL DEFAULT '0' COMMENT '账户余额，为所有分录金额之和',
+	`version` BIGINT NOT NULL DEFAULT '0' COMMENT '乐观锁版本号',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_ledger_account_code` (`code`) USING BTREE,
+	KEY `idx_ledger_account_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本账户';
+CREATE TABLE `p_ledger_journal` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '凭证ID',
+	`idempotency_key` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '幂等键，相同的业务只记账一次',
+	`kind` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '业务类型',
+	`memo` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '备注',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_ledger_journal_idempotency_key` (`idempotency_key`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本凭证';
+CREATE TABLE `p_ledger_entry` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '分录ID',
+	`journal_id` BIGINT NOT NULL DEFAULT '0' COMMENT '凭证ID',
+	`account_id` BIGINT NOT NULL DEFAULT '0' COMMENT '账户ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '变动金额，同一凭证的分录金额之和为0',
+	`balance_snapshot` BIGINT NOT NULL DEFAULT '0' COMMENT '变动后的账户余额',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_ledger_entry_journal_id` (`journal_id`) USING BTREE,
+	KEY `idx_ledger_entry_account_id` (`account_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本分录';
@@ -0,0 +1,30 @@
+CREATE TABLE `p_subscription_tier` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '档位ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '创作者ID',
+	`title` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '档位名称',
+	`description` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '档位说明',
+	`price` BIGINT NOT NULL DEFAULT '0' COMMENT '每期价格(分)',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_subscription_tier_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='创作者订阅档位';
+CREATE TABLE `p_subscription` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '订阅ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '订阅者ID',
+	`creator_id` BIGINT NOT NULL DEFAULT '0' COMMENT '创作者ID',
+	`tier_id` BIGINT NOT NULL DEFAULT '0' COMMENT '订阅档位ID',
+	`price` BIGINT NOT NULL DEFAULT '0' COMMENT '续费价格(分)',
+	`auto_renew` tinyint NOT NULL DEFAULT '0' COMMENT '是否自动续费 0否、1是',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '到期时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_subscription_user_creator` (`user_id`, `creator_id`) USING BTREE,
+	KEY `idx_subscription_creator_id` (`creator_id`) USING BTREE,
+	KEY `idx_subscription_expired_on` (`expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户订阅';
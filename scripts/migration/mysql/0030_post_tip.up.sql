@@ -0,0 +1,15 @@
+CREATE TABLE `p_post_tip` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '充电ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '充电用户ID',
+	`author_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文作者ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '充电金额(分)',
+	`message` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '充电留言',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_tip_post_id` (`post_id`) USING BTREE,
+	KEY `idx_post_tip_user_post` (`user_id`, `post_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='推文充电';
@@ -0,0 +1,22 @@
+CREATE TABLE `p_attachment_refund` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '退款申请ID',
+	`bill_id` BIGINT NOT NULL DEFAULT '0' COMMENT '附件购买记录ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '购买者ID',
+	`author_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文作者ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '退款金额(分)',
+	`reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '退款原因',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '状态 0待作者处理、1已退款、2已拒绝、3申诉中',
+	`reviewer_id` BIGINT NOT NULL DEFAULT '0' COMMENT '处理人ID',
+	`remark` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '处理备注',
+	`reviewed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '处理时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_attachment_refund_bill_id` (`bill_id`) USING BTREE,
+	KEY `idx_attachment_refund_user_id` (`user_id`) USING BTREE,
+	KEY `idx_attachment_refund_author_status` (`author_id`, `status`) USING BTREE,
+	KEY `idx_attachment_refund_status` (`status`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='附件退款申请';
@@ -0,0 +1,28 @@
+CREATE TABLE `p_tag_usage` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '统计ID',
+	`tag_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`bucket` BIGINT NOT NULL DEFAULT '0' COMMENT '统计桶的起始时间，按小时分桶',
+	`quote_num` BIGINT NOT NULL DEFAULT '0' COMMENT '引用次数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_tag_usage_tag_bucket` (`tag_id`, `bucket`) USING BTREE,
+	KEY `idx_tag_usage_bucket` (`bucket`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题引用统计';
+
+CREATE TABLE `p_tag_trending` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '排行ID',
+	`tag_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`score` DOUBLE NOT NULL DEFAULT '0' COMMENT '热议分',
+	`hour_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近1小时引用次数',
+	`day_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近24小时引用次数',
+	`week_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近7天引用次数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_tag_trending_score` (`score`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='热议话题排行';
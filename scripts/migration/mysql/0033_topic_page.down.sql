@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS `p_topic_moderator`;
+DROP TABLE IF EXISTS `p_topic_pin`;
+DROP INDEX `idx_tag_alias_of` ON `p_tag`;
+ALTER TABLE `p_tag` DROP COLUMN `description`;
+ALTER TABLE `p_tag` DROP COLUMN `cover`;
+ALTER TABLE `p_tag` DROP COLUMN `alias_of`;
+ALTER TABLE `p_tag` DROP COLUMN `is_locked`;
+ALTER TABLE `p_tag` DROP COLUMN `is_hidden`;
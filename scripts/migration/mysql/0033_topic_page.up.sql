@@ -0,0 +1,31 @@
+ALTER TABLE `p_tag` ADD COLUMN `description` varchar(255) NOT NULL DEFAULT '' COMMENT '话题描述';
+ALTER TABLE `p_tag` ADD COLUMN `cover` varchar(255) NOT NULL DEFAULT '' COMMENT '话题封面';
+ALTER TABLE `p_tag` ADD COLUMN `alias_of` BIGINT NOT NULL DEFAULT '0' COMMENT '合并到的话题ID，0为非别名';
+ALTER TABLE `p_tag` ADD COLUMN `is_locked` tinyint NOT NULL DEFAULT '0' COMMENT '是否锁定 0 为未锁定、1 为已锁定';
+ALTER TABLE `p_tag` ADD COLUMN `is_hidden` tinyint NOT NULL DEFAULT '0' COMMENT '是否在话题推荐中隐藏 0 为否、1 为是';
+CREATE INDEX `idx_tag_alias_of` ON `p_tag` (`alias_of`) USING BTREE;
+
+CREATE TABLE `p_topic_moderator` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主持人ID',
+	`topic_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_topic_moderator_topic_user` (`topic_id`, `user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题主持人';
+
+CREATE TABLE `p_topic_pin` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '置顶ID',
+	`topic_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '操作者ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_topic_pin_topic_post` (`topic_id`, `post_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题置顶推文';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `p_collection_folder`;
+DROP INDEX `idx_post_collection_user_folder` ON `p_post_collection`;
+ALTER TABLE `p_post_collection` DROP COLUMN `folder_id`;
+ALTER TABLE `p_post_collection` DROP COLUMN `note`;
@@ -0,0 +1,17 @@
+ALTER TABLE `p_post_collection` ADD COLUMN `folder_id` BIGINT NOT NULL DEFAULT '0' COMMENT '收藏夹ID，0为未归入收藏夹';
+ALTER TABLE `p_post_collection` ADD COLUMN `note` varchar(255) NOT NULL DEFAULT '' COMMENT '私人备注';
+CREATE INDEX `idx_post_collection_user_folder` ON `p_post_collection` (`user_id`, `folder_id`) USING BTREE;
+
+CREATE TABLE `p_collection_folder` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '收藏夹ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`name` varchar(64) NOT NULL DEFAULT '' COMMENT '收藏夹名称',
+	`share_key` varchar(64) NOT NULL DEFAULT '' COMMENT '分享链接标识，为空表示未公开分享',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_collection_folder_user_id` (`user_id`) USING BTREE,
+	KEY `idx_collection_folder_share_key` (`share_key`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='收藏夹';
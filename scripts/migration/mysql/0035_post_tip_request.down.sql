@@ -0,0 +1,2 @@
+DROP INDEX `idx_post_tip_user_request` ON `p_post_tip`;
+ALTER TABLE `p_post_tip` DROP COLUMN `request_id`;
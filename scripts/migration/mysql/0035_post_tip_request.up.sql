@@ -0,0 +1,2 @@
+ALTER TABLE `p_post_tip` ADD COLUMN `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端请求ID，用于幂等' AFTER `message`;
+CREATE INDEX `idx_post_tip_user_request` ON `p_post_tip` (`user_id`, `request_id`) USING BTREE;
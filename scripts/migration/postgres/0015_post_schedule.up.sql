@@ -0,0 +1,20 @@
+CREATE TABLE p_post_schedule (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0,
+	contents TEXT NOT NULL DEFAULT '', -- 推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '',
+	users VARCHAR(2000) NOT NULL DEFAULT '', -- @用户
+	attachment_price BIGINT NOT NULL DEFAULT 0, -- 附件价格(分)
+	visibility SMALLINT NOT NULL DEFAULT 0, -- 可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开
+	ip VARCHAR(64) NOT NULL DEFAULT '', -- IP地址
+	ip_loc VARCHAR(64) NOT NULL DEFAULT '', -- IP城市地址
+	publish_at BIGINT NOT NULL DEFAULT 0, -- 计划发布时间
+	status SMALLINT NOT NULL DEFAULT 0, -- 状态: 0待发布 1已发布 2已取消 3发布失败
+	post_id BIGINT NOT NULL DEFAULT 0, -- 发布后的POST ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_schedule_user_id ON p_post_schedule USING btree (user_id);
+CREATE INDEX idx_post_schedule_status_publish_at ON p_post_schedule USING btree (status, publish_at);
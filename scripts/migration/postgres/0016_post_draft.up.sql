@@ -0,0 +1,14 @@
+CREATE TABLE p_post_draft (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	contents TEXT NOT NULL DEFAULT '', -- 推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '', -- 标签
+	users VARCHAR(2000) NOT NULL DEFAULT '', -- @用户
+	attachment_price BIGINT NOT NULL DEFAULT 0, -- 附件价格(分)
+	visibility SMALLINT NOT NULL DEFAULT 0, -- 可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_draft_user_id ON p_post_draft USING btree (user_id);
@@ -0,0 +1,14 @@
+CREATE TABLE p_post_revision (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- POST ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	revision BIGINT NOT NULL DEFAULT 0, -- 修订版本号
+	contents TEXT NOT NULL DEFAULT '', -- 修订前的推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '', -- 修订前的标签
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_revision_post_id ON p_post_revision USING btree (post_id);
+ALTER TABLE p_post ADD COLUMN edited_on BIGINT NOT NULL DEFAULT 0; -- 最后编辑时间
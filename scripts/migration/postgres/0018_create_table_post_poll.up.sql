@@ -0,0 +1,39 @@
+CREATE TABLE p_post_poll (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- POST ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 发起投票的用户ID
+	multiple SMALLINT NOT NULL DEFAULT 0, -- 是否多选 0 为单选、1 为多选
+	anonymous SMALLINT NOT NULL DEFAULT 0, -- 是否匿名 0 为公开、1 为匿名
+	vote_count BIGINT NOT NULL DEFAULT 0, -- 投票人数
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 截止时间
+	closed_on BIGINT NOT NULL DEFAULT 0, -- 结束时间 0 为进行中
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_post_poll_post_id ON p_post_poll USING btree (post_id);
+CREATE INDEX idx_post_poll_expired_on ON p_post_poll USING btree (closed_on, expired_on);
+CREATE TABLE p_post_poll_option (
+	id BIGSERIAL PRIMARY KEY,
+	poll_id BIGINT NOT NULL DEFAULT 0, -- 投票ID
+	content VARCHAR(255) NOT NULL DEFAULT '', -- 选项内容
+	sort BIGINT NOT NULL DEFAULT 0, -- 排序，越小越靠前
+	vote_count BIGINT NOT NULL DEFAULT 0, -- 得票数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_poll_option_poll_id ON p_post_poll_option USING btree (poll_id);
+CREATE TABLE p_post_poll_vote (
+	id BIGSERIAL PRIMARY KEY,
+	poll_id BIGINT NOT NULL DEFAULT 0, -- 投票ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 投票用户ID
+	option_ids VARCHAR(255) NOT NULL DEFAULT '', -- 选中的选项ID列表
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_post_poll_vote_poll_user ON p_post_poll_vote USING btree (poll_id, user_id);
@@ -0,0 +1,16 @@
+CREATE TABLE p_link_preview (
+	id BIGSERIAL PRIMARY KEY,
+	url_hash VARCHAR(64) NOT NULL DEFAULT '', -- 链接地址的SHA256
+	url VARCHAR(2048) NOT NULL DEFAULT '', -- 链接地址
+	title VARCHAR(255) NOT NULL DEFAULT '', -- 标题
+	description VARCHAR(512) NOT NULL DEFAULT '', -- 描述
+	image VARCHAR(255) NOT NULL DEFAULT '', -- 预览图(已转存OSS)
+	site_name VARCHAR(128) NOT NULL DEFAULT '', -- 站点名称
+	status SMALLINT NOT NULL DEFAULT 0, -- 抓取状态 0 为成功、1 为失败
+	fetched_on BIGINT NOT NULL DEFAULT 0, -- 抓取时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_link_preview_url_hash ON p_link_preview USING btree (url_hash);
@@ -0,0 +1,4 @@
+ALTER TABLE p_attachment ADD COLUMN blurhash VARCHAR(64) NOT NULL DEFAULT ''; -- 图片blurhash占位
+ALTER TABLE p_attachment ADD COLUMN dominant_color VARCHAR(16) NOT NULL DEFAULT ''; -- 图片主色调
+ALTER TABLE p_attachment ADD COLUMN variants TEXT NOT NULL DEFAULT ''; -- 图片缩略图/WebP等版本(JSON)
+CREATE INDEX idx_attachment_content ON p_attachment USING btree (content);
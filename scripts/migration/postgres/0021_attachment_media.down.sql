@@ -0,0 +1,5 @@
+ALTER TABLE p_attachment DROP COLUMN status;
+ALTER TABLE p_attachment DROP COLUMN playlist;
+ALTER TABLE p_attachment DROP COLUMN poster;
+ALTER TABLE p_attachment DROP COLUMN duration;
+DROP INDEX IF EXISTS idx_attachment_status;
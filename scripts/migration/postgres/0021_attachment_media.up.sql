@@ -0,0 +1,5 @@
+ALTER TABLE p_attachment ADD COLUMN duration BIGINT NOT NULL DEFAULT 0; -- 视频时长(毫秒)
+ALTER TABLE p_attachment ADD COLUMN poster VARCHAR(255) NOT NULL DEFAULT ''; -- 视频封面
+ALTER TABLE p_attachment ADD COLUMN playlist VARCHAR(255) NOT NULL DEFAULT ''; -- 视频HLS主播放列表
+ALTER TABLE p_attachment ADD COLUMN status SMALLINT NOT NULL DEFAULT 0; -- 媒体处理状态 0就绪、1等待处理、2处理中、3处理失败
+CREATE INDEX idx_attachment_status ON p_attachment USING btree (status);
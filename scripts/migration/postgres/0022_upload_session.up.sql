@@ -0,0 +1,16 @@
+CREATE TABLE p_upload_session (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	upload_type VARCHAR(32) NOT NULL DEFAULT '', -- 上传类型
+	content_type VARCHAR(128) NOT NULL DEFAULT '', -- 文件MIME类型
+	file_ext VARCHAR(16) NOT NULL DEFAULT '', -- 文件扩展名
+	file_size BIGINT NOT NULL DEFAULT 0, -- 文件总大小
+	received_size BIGINT NOT NULL DEFAULT 0, -- 已接收大小
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 过期时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_upload_session_user_id ON p_upload_session USING btree (user_id);
+CREATE INDEX idx_upload_session_expired_on ON p_upload_session USING btree (expired_on);
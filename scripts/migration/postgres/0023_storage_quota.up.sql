@@ -0,0 +1,11 @@
+CREATE TABLE p_user_storage_quota (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	max_size BIGINT NOT NULL DEFAULT 0, -- 最大存储空间(字节) 0 为不限制
+	max_files BIGINT NOT NULL DEFAULT 0, -- 最大文件数 0 为不限制
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_user_storage_quota_user_id ON p_user_storage_quota USING btree (user_id);
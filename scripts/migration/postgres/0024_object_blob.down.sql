@@ -0,0 +1 @@
+DROP TABLE IF EXISTS p_object_blob;
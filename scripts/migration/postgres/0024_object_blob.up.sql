@@ -0,0 +1,14 @@
+CREATE TABLE p_object_blob (
+	id BIGSERIAL PRIMARY KEY,
+	sha256 VARCHAR(64) NOT NULL DEFAULT '', -- 内容的SHA256
+	upload_type VARCHAR(32) NOT NULL DEFAULT '', -- 上传类型
+	content VARCHAR(255) NOT NULL DEFAULT '', -- 对象地址
+	file_size BIGINT NOT NULL DEFAULT 0, -- 文件大小
+	ref_count BIGINT NOT NULL DEFAULT 0, -- 引用计数(上传次数)
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_object_blob_sha256 ON p_object_blob USING btree (sha256, upload_type);
+CREATE INDEX idx_object_blob_content ON p_object_blob USING btree (content);
@@ -0,0 +1 @@
+ALTER TABLE p_wallet_recharge DROP COLUMN provider;
@@ -0,0 +1 @@
+ALTER TABLE p_wallet_recharge ADD COLUMN provider VARCHAR(32) NOT NULL DEFAULT 'alipay'; -- 支付渠道 alipay、wechatpay
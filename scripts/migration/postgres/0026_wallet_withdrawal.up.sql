@@ -0,0 +1,21 @@
+CREATE TABLE p_wallet_withdrawal (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 提现金额(含手续费)
+	fee BIGINT NOT NULL DEFAULT 0, -- 手续费
+	account_type VARCHAR(32) NOT NULL DEFAULT '', -- 收款渠道 alipay、wechatpay、bank
+	account VARCHAR(128) NOT NULL DEFAULT '', -- 收款账号
+	account_name VARCHAR(64) NOT NULL DEFAULT '', -- 收款人姓名
+	status SMALLINT NOT NULL DEFAULT 0, -- 提现状态 0待审核、1待打款、2已打款、3已拒绝、4打款失败、5已取消
+	reviewer_id BIGINT NOT NULL DEFAULT 0, -- 审核人ID
+	trade_no VARCHAR(64) NOT NULL DEFAULT '', -- 打款流水号
+	remark VARCHAR(255) NOT NULL DEFAULT '', -- 审核备注
+	reviewed_on BIGINT NOT NULL DEFAULT 0, -- 审核时间
+	paid_on BIGINT NOT NULL DEFAULT 0, -- 打款时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_wallet_withdrawal_user_id ON p_wallet_withdrawal USING btree (user_id);
+CREATE INDEX idx_wallet_withdrawal_status ON p_wallet_withdrawal USING btree (status);
@@ -0,0 +1,37 @@
+CREATE TABLE p_ledger_account (
+	id BIGSERIAL PRIMARY KEY,
+	code VARCHAR(64) NOT NULL DEFAULT '', -- 账户编码 user:<用户ID>、system:<用途>、channel:<支付渠道>
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID，系统账户为0
+	balance BIGINT NOT NULL DEFAULT 0, -- 账户余额，为所有分录金额之和
+	version BIGINT NOT NULL DEFAULT 0, -- 乐观锁版本号
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_ledger_account_code ON p_ledger_account USING btree (code);
+CREATE INDEX idx_ledger_account_user_id ON p_ledger_account USING btree (user_id);
+CREATE TABLE p_ledger_journal (
+	id BIGSERIAL PRIMARY KEY,
+	idempotency_key VARCHAR(128) NOT NULL DEFAULT '', -- 幂等键，相同的业务只记账一次
+	kind VARCHAR(32) NOT NULL DEFAULT '', -- 业务类型
+	memo VARCHAR(255) NOT NULL DEFAULT '', -- 备注
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_ledger_journal_idempotency_key ON p_ledger_journal USING btree (idempotency_key);
+CREATE TABLE p_ledger_entry (
+	id BIGSERIAL PRIMARY KEY,
+	journal_id BIGINT NOT NULL DEFAULT 0, -- 凭证ID
+	account_id BIGINT NOT NULL DEFAULT 0, -- 账户ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 变动金额，同一凭证的分录金额之和为0
+	balance_snapshot BIGINT NOT NULL DEFAULT 0, -- 变动后的账户余额
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_ledger_entry_journal_id ON p_ledger_entry USING btree (journal_id);
+CREATE INDEX idx_ledger_entry_account_id ON p_ledger_entry USING btree (account_id);
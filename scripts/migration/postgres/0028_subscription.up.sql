@@ -0,0 +1,28 @@
+CREATE TABLE p_subscription_tier (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 创作者ID
+	title VARCHAR(64) NOT NULL DEFAULT '', -- 档位名称
+	description VARCHAR(255) NOT NULL DEFAULT '', -- 档位说明
+	price BIGINT NOT NULL DEFAULT 0, -- 每期价格(分)
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_subscription_tier_user_id ON p_subscription_tier USING btree (user_id);
+CREATE TABLE p_subscription (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 订阅者ID
+	creator_id BIGINT NOT NULL DEFAULT 0, -- 创作者ID
+	tier_id BIGINT NOT NULL DEFAULT 0, -- 订阅档位ID
+	price BIGINT NOT NULL DEFAULT 0, -- 续费价格(分)
+	auto_renew SMALLINT NOT NULL DEFAULT 0, -- 是否自动续费 0否、1是
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 到期时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_subscription_user_creator ON p_subscription USING btree (user_id, creator_id);
+CREATE INDEX idx_subscription_creator_id ON p_subscription USING btree (creator_id);
+CREATE INDEX idx_subscription_expired_on ON p_subscription USING btree (expired_on);
@@ -0,0 +1,14 @@
+CREATE TABLE p_post_tip (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 充电用户ID
+	author_id BIGINT NOT NULL DEFAULT 0, -- 推文作者ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 充电金额(分)
+	message VARCHAR(255) NOT NULL DEFAULT '', -- 充电留言
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_tip_post_id ON p_post_tip USING btree (post_id);
+CREATE INDEX idx_post_tip_user_post ON p_post_tip USING btree (user_id, post_id);
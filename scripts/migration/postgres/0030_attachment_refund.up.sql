@@ -0,0 +1,21 @@
+CREATE TABLE p_attachment_refund (
+	id BIGSERIAL PRIMARY KEY,
+	bill_id BIGINT NOT NULL DEFAULT 0, -- 附件购买记录ID
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 购买者ID
+	author_id BIGINT NOT NULL DEFAULT 0, -- 推文作者ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 退款金额(分)
+	reason VARCHAR(255) NOT NULL DEFAULT '', -- 退款原因
+	status SMALLINT NOT NULL DEFAULT 0, -- 状态 0待作者处理、1已退款、2已拒绝、3申诉中
+	reviewer_id BIGINT NOT NULL DEFAULT 0, -- 处理人ID
+	remark VARCHAR(255) NOT NULL DEFAULT '', -- 处理备注
+	reviewed_on BIGINT NOT NULL DEFAULT 0, -- 处理时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_attachment_refund_bill_id ON p_attachment_refund USING btree (bill_id);
+CREATE INDEX idx_attachment_refund_user_id ON p_attachment_refund USING btree (user_id);
+CREATE INDEX idx_attachment_refund_author_status ON p_attachment_refund USING btree (author_id, status);
+CREATE INDEX idx_attachment_refund_status ON p_attachment_refund USING btree (status);
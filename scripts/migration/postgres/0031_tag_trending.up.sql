@@ -0,0 +1,26 @@
+CREATE TABLE p_tag_usage (
+	id BIGSERIAL PRIMARY KEY,
+	tag_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	bucket BIGINT NOT NULL DEFAULT 0, -- 统计桶的起始时间，按小时分桶
+	quote_num BIGINT NOT NULL DEFAULT 0, -- 引用次数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_tag_usage_tag_bucket ON p_tag_usage USING btree (tag_id, bucket);
+CREATE INDEX idx_tag_usage_bucket ON p_tag_usage USING btree (bucket);
+
+CREATE TABLE p_tag_trending (
+	id BIGSERIAL PRIMARY KEY,
+	tag_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	score DOUBLE PRECISION NOT NULL DEFAULT 0, -- 热议分
+	hour_num BIGINT NOT NULL DEFAULT 0, -- 最近1小时引用次数
+	day_num BIGINT NOT NULL DEFAULT 0, -- 最近24小时引用次数
+	week_num BIGINT NOT NULL DEFAULT 0, -- 最近7天引用次数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_tag_trending_score ON p_tag_trending USING btree (score);
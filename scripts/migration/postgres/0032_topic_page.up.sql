@@ -0,0 +1,29 @@
+ALTER TABLE p_tag ADD COLUMN description VARCHAR(255) NOT NULL DEFAULT ''; -- 话题描述
+ALTER TABLE p_tag ADD COLUMN cover VARCHAR(255) NOT NULL DEFAULT ''; -- 话题封面
+ALTER TABLE p_tag ADD COLUMN alias_of BIGINT NOT NULL DEFAULT 0; -- 合并到的话题ID，0为非别名
+ALTER TABLE p_tag ADD COLUMN is_locked SMALLINT NOT NULL DEFAULT 0; -- 是否锁定
+ALTER TABLE p_tag ADD COLUMN is_hidden SMALLINT NOT NULL DEFAULT 0; -- 是否在话题推荐中隐藏
+CREATE INDEX idx_tag_alias_of ON p_tag USING btree (alias_of);
+
+CREATE TABLE p_topic_moderator (
+	id BIGSERIAL PRIMARY KEY,
+	topic_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_topic_moderator_topic_user ON p_topic_moderator USING btree (topic_id, user_id);
+
+CREATE TABLE p_topic_pin (
+	id BIGSERIAL PRIMARY KEY,
+	topic_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 操作者ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_topic_pin_topic_post ON p_topic_pin USING btree (topic_id, post_id);
@@ -0,0 +1,16 @@
+ALTER TABLE p_post_collection ADD COLUMN folder_id BIGINT NOT NULL DEFAULT 0; -- 收藏夹ID，0为未归入收藏夹
+ALTER TABLE p_post_collection ADD COLUMN note VARCHAR(255) NOT NULL DEFAULT ''; -- 私人备注
+CREATE INDEX idx_post_collection_user_folder ON p_post_collection USING btree (user_id, folder_id);
+
+CREATE TABLE p_collection_folder (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	name VARCHAR(64) NOT NULL DEFAULT '', -- 收藏夹名称
+	share_key VARCHAR(64) NOT NULL DEFAULT '', -- 分享链接标识，为空表示未公开分享
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_collection_folder_user_id ON p_collection_folder USING btree (user_id);
+CREATE INDEX idx_collection_folder_share_key ON p_collection_folder USING btree (share_key);
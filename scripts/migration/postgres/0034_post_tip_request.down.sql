@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_tip_user_request;
+ALTER TABLE p_post_tip DROP COLUMN request_id;
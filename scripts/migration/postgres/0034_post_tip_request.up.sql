@@ -0,0 +1,2 @@
+ALTER TABLE p_post_tip ADD COLUMN request_id VARCHAR(64) NOT NULL DEFAULT ''; -- 客户端请求ID，用于幂等
+CREATE INDEX idx_post_tip_user_request ON p_post_tip USING btree (user_id, request_id);
@@ -0,0 +1,29 @@
+CREATE TABLE "p_post_schedule" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "users" text(2000) NOT NULL DEFAULT '',
+  "attachment_price" integer NOT NULL DEFAULT 0,
+  "visibility" integer NOT NULL DEFAULT 0,
+  "ip" text(64) NOT NULL DEFAULT '',
+  "ip_loc" text(64) NOT NULL DEFAULT '',
+  "publish_at" integer NOT NULL DEFAULT 0,
+  "status" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_post_schedule_user_id"
+ON "p_post_schedule" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_post_schedule_status_publish_at"
+ON "p_post_schedule" (
+  "status" ASC,
+  "publish_at" ASC
+);
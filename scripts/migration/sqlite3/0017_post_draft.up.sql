@@ -0,0 +1,19 @@
+CREATE TABLE "p_post_draft" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "users" text(2000) NOT NULL DEFAULT '',
+  "attachment_price" integer NOT NULL DEFAULT 0,
+  "visibility" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_post_draft_user_id"
+ON "p_post_draft" (
+  "user_id" ASC
+);
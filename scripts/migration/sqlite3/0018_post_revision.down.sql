@@ -0,0 +1,2 @@
+ALTER TABLE "p_post" DROP COLUMN "edited_on";
+DROP TABLE IF EXISTS "p_post_revision";
@@ -0,0 +1,19 @@
+CREATE TABLE "p_post_revision" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "revision" integer NOT NULL DEFAULT 0,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_post_revision_post_id"
+ON "p_post_revision" (
+  "post_id" ASC
+);
+ALTER TABLE "p_post" ADD COLUMN "edited_on" integer NOT NULL DEFAULT 0;
@@ -0,0 +1,59 @@
+CREATE TABLE "p_post_poll" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "multiple" integer NOT NULL DEFAULT 0,
+  "anonymous" integer NOT NULL DEFAULT 0,
+  "vote_count" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "closed_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_post_poll_post_id"
+ON "p_post_poll" (
+  "post_id" ASC
+);
+CREATE INDEX "idx_post_poll_expired_on"
+ON "p_post_poll" (
+  "closed_on" ASC,
+  "expired_on" ASC
+);
+CREATE TABLE "p_post_poll_option" (
+  "id" integer NOT NULL,
+  "poll_id" integer NOT NULL DEFAULT 0,
+  "content" text(255) NOT NULL DEFAULT '',
+  "sort" integer NOT NULL DEFAULT 0,
+  "vote_count" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_post_poll_option_poll_id"
+ON "p_post_poll_option" (
+  "poll_id" ASC
+);
+CREATE TABLE "p_post_poll_vote" (
+  "id" integer NOT NULL,
+  "poll_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "option_ids" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_post_poll_vote_poll_user"
+ON "p_post_poll_vote" (
+  "poll_id" ASC,
+  "user_id" ASC
+);
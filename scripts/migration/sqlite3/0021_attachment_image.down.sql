@@ -0,0 +1,4 @@
+ALTER TABLE "p_attachment" DROP COLUMN "variants";
+ALTER TABLE "p_attachment" DROP COLUMN "dominant_color";
+ALTER TABLE "p_attachment" DROP COLUMN "blurhash";
+DROP INDEX IF EXISTS "idx_attachment_content";
@@ -0,0 +1,7 @@
+ALTER TABLE "p_attachment" ADD COLUMN "blurhash" text(64) NOT NULL DEFAULT '';
+ALTER TABLE "p_attachment" ADD COLUMN "dominant_color" text(16) NOT NULL DEFAULT '';
+ALTER TABLE "p_attachment" ADD COLUMN "variants" text NOT NULL DEFAULT '';
+CREATE INDEX "idx_attachment_content"
+ON "p_attachment" (
+  "content" ASC
+);
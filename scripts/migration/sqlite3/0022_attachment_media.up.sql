@@ -0,0 +1,8 @@
+ALTER TABLE "p_attachment" ADD COLUMN "duration" integer NOT NULL DEFAULT 0;
+ALTER TABLE "p_attachment" ADD COLUMN "poster" text(255) NOT NULL DEFAULT '';
+ALTER TABLE "p_attachment" ADD COLUMN "playlist" text(255) NOT NULL DEFAULT '';
+ALTER TABLE "p_attachment" ADD COLUMN "status" integer NOT NULL DEFAULT 0;
+CREATE INDEX "idx_attachment_status"
+ON "p_attachment" (
+  "status" ASC
+);
@@ -0,0 +1,24 @@
+CREATE TABLE "p_upload_session" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "upload_type" text(32) NOT NULL DEFAULT '',
+  "content_type" text(128) NOT NULL DEFAULT '',
+  "file_ext" text(16) NOT NULL DEFAULT '',
+  "file_size" integer NOT NULL DEFAULT 0,
+  "received_size" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_upload_session_user_id"
+ON "p_upload_session" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_upload_session_expired_on"
+ON "p_upload_session" (
+  "expired_on" ASC
+);
@@ -0,0 +1,16 @@
+CREATE TABLE "p_user_storage_quota" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "max_size" integer NOT NULL DEFAULT 0,
+  "max_files" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_user_storage_quota_user_id"
+ON "p_user_storage_quota" (
+  "user_id" ASC
+);
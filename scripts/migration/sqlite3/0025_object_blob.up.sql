@@ -0,0 +1,23 @@
+CREATE TABLE "p_object_blob" (
+  "id" integer NOT NULL,
+  "sha256" text(64) NOT NULL DEFAULT '',
+  "upload_type" text(32) NOT NULL DEFAULT '',
+  "content" text(255) NOT NULL DEFAULT '',
+  "file_size" integer NOT NULL DEFAULT 0,
+  "ref_count" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_object_blob_sha256"
+ON "p_object_blob" (
+  "sha256" ASC,
+  "upload_type" ASC
+);
+CREATE INDEX "idx_object_blob_content"
+ON "p_object_blob" (
+  "content" ASC
+);
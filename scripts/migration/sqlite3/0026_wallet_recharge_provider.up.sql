@@ -0,0 +1 @@
+ALTER TABLE "p_wallet_recharge" ADD COLUMN "provider" text(32) NOT NULL DEFAULT 'alipay';
@@ -0,0 +1,29 @@
+CREATE TABLE "p_wallet_withdrawal" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "fee" integer NOT NULL DEFAULT 0,
+  "account_type" text(32) NOT NULL DEFAULT '',
+  "account" text(128) NOT NULL DEFAULT '',
+  "account_name" text(64) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
+  "reviewer_id" integer NOT NULL DEFAULT 0,
+  "trade_no" text(64) NOT NULL DEFAULT '',
+  "remark" text(255) NOT NULL DEFAULT '',
+  "reviewed_on" integer NOT NULL DEFAULT 0,
+  "paid_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_wallet_withdrawal_user_id"
+ON "p_wallet_withdrawal" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_wallet_withdrawal_status"
+ON "p_wallet_withdrawal" (
+  "status" ASC
+);
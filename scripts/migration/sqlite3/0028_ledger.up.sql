@@ -0,0 +1,58 @@
+CREATE TABLE "p_ledger_account" (
+  "id" integer NOT NULL,
+  "code" text(64) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL DEFAULT 0,
+  "balance" integer NOT NULL DEFAULT 0,
+  "version" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_ledger_account_code"
+ON "p_ledger_account" (
+  "code" ASC
+);
+CREATE INDEX "idx_ledger_account_user_id"
+ON "p_ledger_account" (
+  "user_id" ASC
+);
+CREATE TABLE "p_ledger_journal" (
+  "id" integer NOT NULL,
+  "idempotency_key" text(128) NOT NULL DEFAULT '',
+  "kind" text(32) NOT NULL DEFAULT '',
+  "memo" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_ledger_journal_idempotency_key"
+ON "p_ledger_journal" (
+  "idempotency_key" ASC
+);
+CREATE TABLE "p_ledger_entry" (
+  "id" integer NOT NULL,
+  "journal_id" integer NOT NULL DEFAULT 0,
+  "account_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "balance_snapshot" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_ledger_entry_journal_id"
+ON "p_ledger_entry" (
+  "journal_id" ASC
+);
+CREATE INDEX "idx_ledger_entry_account_id"
+ON "p_ledger_entry" (
+  "account_id" ASC
+);
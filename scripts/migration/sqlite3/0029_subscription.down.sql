@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "p_subscription";
+DROP TABLE IF EXISTS "p_subscription_tier";
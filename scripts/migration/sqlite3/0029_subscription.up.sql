@@ -0,0 +1,45 @@
+CREATE TABLE "p_subscription_tier" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "title" text(64) NOT NULL DEFAULT '',
+  "description" text(255) NOT NULL DEFAULT '',
+  "price" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_subscription_tier_user_id"
+ON "p_subscription_tier" (
+  "user_id" ASC
+);
+CREATE TABLE "p_subscription" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "creator_id" integer NOT NULL DEFAULT 0,
+  "tier_id" integer NOT NULL DEFAULT 0,
+  "price" integer NOT NULL DEFAULT 0,
+  "auto_renew" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_subscription_user_creator"
+ON "p_subscription" (
+  "user_id" ASC,
+  "creator_id" ASC
+);
+CREATE INDEX "idx_subscription_creator_id"
+ON "p_subscription" (
+  "creator_id" ASC
+);
+CREATE INDEX "idx_subscription_expired_on"
+ON "p_subscription" (
+  "expired_on" ASC
+);
@@ -0,0 +1,23 @@
+CREATE TABLE "p_post_tip" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "author_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "message" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_post_tip_post_id"
+ON "p_post_tip" (
+  "post_id" ASC
+);
+CREATE INDEX "idx_post_tip_user_post"
+ON "p_post_tip" (
+  "user_id" ASC,
+  "post_id" ASC
+);
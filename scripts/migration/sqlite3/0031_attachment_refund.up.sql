@@ -0,0 +1,36 @@
+CREATE TABLE "p_attachment_refund" (
+  "id" integer NOT NULL,
+  "bill_id" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "author_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "reason" text(255) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
+  "reviewer_id" integer NOT NULL DEFAULT 0,
+  "remark" text(255) NOT NULL DEFAULT '',
+  "reviewed_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_attachment_refund_bill_id"
+ON "p_attachment_refund" (
+  "bill_id" ASC
+);
+CREATE INDEX "idx_attachment_refund_user_id"
+ON "p_attachment_refund" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_attachment_refund_author_status"
+ON "p_attachment_refund" (
+  "author_id" ASC,
+  "status" ASC
+);
+CREATE INDEX "idx_attachment_refund_status"
+ON "p_attachment_refund" (
+  "status" ASC
+);
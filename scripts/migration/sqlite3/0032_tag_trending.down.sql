@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "p_tag_usage";
+DROP TABLE IF EXISTS "p_tag_trending";
@@ -0,0 +1,39 @@
+CREATE TABLE "p_tag_usage" (
+  "id" integer NOT NULL,
+  "tag_id" integer NOT NULL DEFAULT 0,
+  "bucket" integer NOT NULL DEFAULT 0,
+  "quote_num" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "p_tag_trending" (
+  "id" integer NOT NULL,
+  "tag_id" integer NOT NULL DEFAULT 0,
+  "score" real NOT NULL DEFAULT 0,
+  "hour_num" integer NOT NULL DEFAULT 0,
+  "day_num" integer NOT NULL DEFAULT 0,
+  "week_num" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_tag_usage_tag_bucket"
+ON "p_tag_usage" (
+  "tag_id" ASC,
+  "bucket" ASC
+);
+CREATE INDEX "idx_tag_usage_bucket"
+ON "p_tag_usage" (
+  "bucket" ASC
+);
+CREATE INDEX "idx_tag_trending_score"
+ON "p_tag_trending" (
+  "score" ASC
+);
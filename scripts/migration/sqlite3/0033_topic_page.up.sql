@@ -0,0 +1,43 @@
+ALTER TABLE "p_tag" ADD COLUMN "description" text(255) NOT NULL DEFAULT '';
+ALTER TABLE "p_tag" ADD COLUMN "cover" text(255) NOT NULL DEFAULT '';
+ALTER TABLE "p_tag" ADD COLUMN "alias_of" integer NOT NULL DEFAULT 0;
+ALTER TABLE "p_tag" ADD COLUMN "is_locked" integer NOT NULL DEFAULT 0;
+ALTER TABLE "p_tag" ADD COLUMN "is_hidden" integer NOT NULL DEFAULT 0;
+CREATE INDEX "idx_tag_alias_of"
+ON "p_tag" (
+  "alias_of" ASC
+);
+
+CREATE TABLE "p_topic_moderator" (
+  "id" integer NOT NULL,
+  "topic_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "p_topic_pin" (
+  "id" integer NOT NULL,
+  "topic_id" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_topic_moderator_topic_user"
+ON "p_topic_moderator" (
+  "topic_id" ASC,
+  "user_id" ASC
+);
+CREATE UNIQUE INDEX "idx_topic_pin_topic_post"
+ON "p_topic_pin" (
+  "topic_id" ASC,
+  "post_id" ASC
+);
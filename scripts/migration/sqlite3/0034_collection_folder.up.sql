@@ -0,0 +1,28 @@
+ALTER TABLE "p_post_collection" ADD COLUMN "folder_id" integer NOT NULL DEFAULT 0;
+ALTER TABLE "p_post_collection" ADD COLUMN "note" text(255) NOT NULL DEFAULT '';
+CREATE INDEX "idx_post_collection_user_folder"
+ON "p_post_collection" (
+  "user_id" ASC,
+  "folder_id" ASC
+);
+
+CREATE TABLE "p_collection_folder" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "name" text(64) NOT NULL DEFAULT '',
+  "share_key" text(64) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_collection_folder_user_id"
+ON "p_collection_folder" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_collection_folder_share_key"
+ON "p_collection_folder" (
+  "share_key" ASC
+);
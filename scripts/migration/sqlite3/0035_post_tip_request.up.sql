@@ -0,0 +1,6 @@
+ALTER TABLE "p_post_tip" ADD COLUMN "request_id" text(64) NOT NULL DEFAULT '';
+CREATE INDEX "idx_post_tip_user_request"
+ON "p_post_tip" (
+  "user_id" ASC,
+  "request_id" ASC
+);
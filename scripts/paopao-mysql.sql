@@ -13,12 +13,21 @@ CREATE TABLE `p_attachment` (
 	`img_height` BIGINT NOT NULL DEFAULT '0',
 	`type` tinyint NOT NULL DEFAULT '1' COMMENT '1图片，2视频，3其他附件',
 	`content` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '',
+	`blurhash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '图片blurhash占位',
+	`dominant_color` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '图片主色调',
+	`variants` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '图片缩略图/WebP等版本(JSON)',
+	`duration` BIGINT NOT NULL DEFAULT '0' COMMENT '视频时长(毫秒)',
+	`poster` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '视频封面',
+	`playlist` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '视频HLS主播放列表',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '媒体处理状态 0就绪、1等待处理、2处理中、3处理失败',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
 	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
 	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
 	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
 	PRIMARY KEY (`id`) USING BTREE,
-	KEY `idx_attachment_user` (`user_id`) USING BTREE
+	KEY `idx_attachment_user` (`user_id`) USING BTREE,
+	KEY `idx_attachment_content` (`content`) USING BTREE,
+	KEY `idx_attachment_status` (`status`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=100041 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='附件';
 
 -- ----------------------------
@@ -193,6 +202,7 @@ CREATE TABLE `p_post` (
 	`attachment_price` BIGINT NOT NULL DEFAULT '0' COMMENT '附件价格(分)',
 	`ip` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP地址',
 	`ip_loc` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP城市地址',
+	`edited_on` BIGINT NOT NULL DEFAULT '0' COMMENT '最后编辑时间',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
 	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
 	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
@@ -247,13 +257,16 @@ CREATE TABLE `p_post_collection` (
 	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '收藏ID',
 	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT 'POST ID',
 	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`folder_id` BIGINT NOT NULL DEFAULT '0' COMMENT '收藏夹ID，0为未归入收藏夹',
+	`note` varchar(255) NOT NULL DEFAULT '' COMMENT '私人备注',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
 	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
 	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
 	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
 	PRIMARY KEY (`id`) USING BTREE,
 	KEY `idx_post_collection_post_id` (`post_id`) USING BTREE,
-	KEY `idx_post_collection_user_id` (`user_id`) USING BTREE
+	KEY `idx_post_collection_user_id` (`user_id`) USING BTREE,
+	KEY `idx_post_collection_user_folder` (`user_id`, `folder_id`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=6000012 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章收藏';
 
 -- ----------------------------
@@ -265,7 +278,7 @@ CREATE TABLE `p_post_content` (
 	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT 'POST ID',
 	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
 	`content` varchar(4000) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '内容',
-	`type` tinyint NOT NULL DEFAULT '2' COMMENT '类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源',
+	`type` tinyint NOT NULL DEFAULT '2' COMMENT '类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源，9投票',
 	`sort` int NOT NULL DEFAULT '100' COMMENT '排序，越小越靠前',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
 	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
@@ -302,6 +315,11 @@ CREATE TABLE `p_tag` (
 	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '创建者ID',
 	`tag` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '标签名',
 	`quote_num` BIGINT NOT NULL DEFAULT '0' COMMENT '引用数',
+	`description` varchar(255) NOT NULL DEFAULT '' COMMENT '话题描述',
+	`cover` varchar(255) NOT NULL DEFAULT '' COMMENT '话题封面',
+	`alias_of` BIGINT NOT NULL DEFAULT '0' COMMENT '合并到的话题ID，0为非别名',
+	`is_locked` tinyint NOT NULL DEFAULT '0' COMMENT '是否锁定 0 为未锁定、1 为已锁定',
+	`is_hidden` tinyint NOT NULL DEFAULT '0' COMMENT '是否在话题推荐中隐藏 0 为否、1 为是',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
 	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
 	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
@@ -309,7 +327,8 @@ CREATE TABLE `p_tag` (
 	PRIMARY KEY (`id`) USING BTREE,
 	UNIQUE KEY `idx_tag_tag` (`tag`) USING BTREE,
 	KEY `idx_tag_user_id` (`user_id`) USING BTREE,
-	KEY `idx_tag_quote_num` (`quote_num`) USING BTREE
+	KEY `idx_tag_quote_num` (`quote_num`) USING BTREE,
+	KEY `idx_tag_alias_of` (`alias_of`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=9000065 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='标签';
 
 -- ----------------------------
@@ -439,6 +458,7 @@ CREATE TABLE `p_wallet_recharge` (
 	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '充值ID',
 	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
 	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '充值金额',
+	`provider` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT 'alipay' COMMENT '支付渠道 alipay、wechatpay',
 	`trade_no` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '支付宝订单号',
 	`trade_status` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '交易状态',
 	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
@@ -470,6 +490,468 @@ CREATE TABLE `p_wallet_statement` (
 	KEY `idx_wallet_statement_user_id` (`user_id`) USING BTREE
 ) ENGINE=InnoDB AUTO_INCREMENT=10010 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包流水';
 
+-- ----------------------------
+-- Table structure for p_post_schedule
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_schedule`;
+CREATE TABLE `p_post_schedule` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '定时发布ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`contents` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '推文内容(JSON)',
+	`tags` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '标签',
+	`users` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '@用户',
+	`attachment_price` BIGINT NOT NULL DEFAULT '0' COMMENT '附件价格(分)',
+	`visibility` tinyint NOT NULL DEFAULT '0' COMMENT '可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开',
+	`ip` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP地址',
+	`ip_loc` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT 'IP城市地址',
+	`publish_at` BIGINT NOT NULL DEFAULT '0' COMMENT '计划发布时间',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '状态: 0待发布 1已发布 2已取消 3发布失败 4发布中',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '发布后的POST ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_schedule_user_id` (`user_id`) USING BTREE,
+	KEY `idx_post_schedule_status_publish_at` (`status`, `publish_at`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章定时发布';
+
+-- ----------------------------
+-- Table structure for p_post_draft
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_draft`;
+CREATE TABLE `p_post_draft` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '草稿ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`contents` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '推文内容(JSON)',
+	`tags` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '标签',
+	`users` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '@用户',
+	`attachment_price` BIGINT NOT NULL DEFAULT '0' COMMENT '附件价格(分)',
+	`visibility` tinyint NOT NULL DEFAULT '0' COMMENT '可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_draft_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章草稿';
+
+-- ----------------------------
+-- Table structure for p_post_revision
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_revision`;
+CREATE TABLE `p_post_revision` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '修订ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT 'POST ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`revision` BIGINT NOT NULL DEFAULT '0' COMMENT '修订版本号',
+	`contents` text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '修订前的推文内容(JSON)',
+	`tags` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '修订前的标签',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_revision_post_id` (`post_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章修订历史';
+
+-- ----------------------------
+-- Table structure for p_post_poll
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_poll`;
+CREATE TABLE `p_post_poll` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '投票ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT 'POST ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '发起投票的用户ID',
+	`multiple` tinyint NOT NULL DEFAULT '0' COMMENT '是否多选 0 为单选、1 为多选',
+	`anonymous` tinyint NOT NULL DEFAULT '0' COMMENT '是否匿名 0 为公开、1 为匿名',
+	`vote_count` BIGINT NOT NULL DEFAULT '0' COMMENT '投票人数',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '截止时间',
+	`closed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '结束时间 0 为进行中',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_post_poll_post_id` (`post_id`) USING BTREE,
+	KEY `idx_post_poll_expired_on` (`closed_on`, `expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票';
+
+-- ----------------------------
+-- Table structure for p_post_poll_option
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_poll_option`;
+CREATE TABLE `p_post_poll_option` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '选项ID',
+	`poll_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票ID',
+	`content` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '选项内容',
+	`sort` BIGINT NOT NULL DEFAULT '0' COMMENT '排序，越小越靠前',
+	`vote_count` BIGINT NOT NULL DEFAULT '0' COMMENT '得票数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_poll_option_poll_id` (`poll_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票选项';
+
+-- ----------------------------
+-- Table structure for p_post_poll_vote
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_poll_vote`;
+CREATE TABLE `p_post_poll_vote` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '投票记录ID',
+	`poll_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '投票用户ID',
+	`option_ids` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '选中的选项ID列表',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_post_poll_vote_poll_user` (`poll_id`, `user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='冒泡/文章投票记录';
+
+-- ----------------------------
+-- Table structure for p_link_preview
+-- ----------------------------
+DROP TABLE IF EXISTS `p_link_preview`;
+CREATE TABLE `p_link_preview` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '预览ID',
+	`url_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '链接地址的SHA256',
+	`url` varchar(2048) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '链接地址',
+	`title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '标题',
+	`description` varchar(512) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '描述',
+	`image` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '预览图(已转存OSS)',
+	`site_name` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '站点名称',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '抓取状态 0 为成功、1 为失败',
+	`fetched_on` BIGINT NOT NULL DEFAULT '0' COMMENT '抓取时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_link_preview_url_hash` (`url_hash`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='链接预览缓存';
+
+-- ----------------------------
+-- Table structure for p_upload_session
+-- ----------------------------
+DROP TABLE IF EXISTS `p_upload_session`;
+CREATE TABLE `p_upload_session` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '上传会话ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`upload_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '上传类型',
+	`content_type` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '文件MIME类型',
+	`file_ext` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '文件扩展名',
+	`file_size` BIGINT NOT NULL DEFAULT '0' COMMENT '文件总大小',
+	`received_size` BIGINT NOT NULL DEFAULT '0' COMMENT '已接收大小',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '过期时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_upload_session_user_id` (`user_id`) USING BTREE,
+	KEY `idx_upload_session_expired_on` (`expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='断点续传上传会话';
+
+-- ----------------------------
+-- Table structure for p_user_storage_quota
+-- ----------------------------
+DROP TABLE IF EXISTS `p_user_storage_quota`;
+CREATE TABLE `p_user_storage_quota` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '配额ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`max_size` BIGINT NOT NULL DEFAULT '0' COMMENT '最大存储空间(字节) 0 为不限制',
+	`max_files` BIGINT NOT NULL DEFAULT '0' COMMENT '最大文件数 0 为不限制',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_user_storage_quota_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户存储配额，管理员单独设置';
+
+-- ----------------------------
+-- Table structure for p_object_blob
+-- ----------------------------
+DROP TABLE IF EXISTS `p_object_blob`;
+CREATE TABLE `p_object_blob` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '对象ID',
+	`sha256` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '内容的SHA256',
+	`upload_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '上传类型',
+	`content` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '对象地址',
+	`file_size` BIGINT NOT NULL DEFAULT '0' COMMENT '文件大小',
+	`ref_count` BIGINT NOT NULL DEFAULT '0' COMMENT '引用计数(上传次数)',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_object_blob_sha256` (`sha256`, `upload_type`) USING BTREE,
+	KEY `idx_object_blob_content` (`content`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='按内容SHA256去重的对象';
+
+-- ----------------------------
+-- Table structure for p_wallet_withdrawal
+-- ----------------------------
+DROP TABLE IF EXISTS `p_wallet_withdrawal`;
+CREATE TABLE `p_wallet_withdrawal` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '提现ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '提现金额(含手续费)',
+	`fee` BIGINT NOT NULL DEFAULT '0' COMMENT '手续费',
+	`account_type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款渠道 alipay、wechatpay、bank',
+	`account` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款账号',
+	`account_name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '收款人姓名',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '提现状态 0待审核、1待打款、2已打款、3已拒绝、4打款失败、5已取消',
+	`reviewer_id` BIGINT NOT NULL DEFAULT '0' COMMENT '审核人ID',
+	`trade_no` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '打款流水号',
+	`remark` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '审核备注',
+	`reviewed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '审核时间',
+	`paid_on` BIGINT NOT NULL DEFAULT '0' COMMENT '打款时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_wallet_withdrawal_user_id` (`user_id`) USING BTREE,
+	KEY `idx_wallet_withdrawal_status` (`status`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包提现';
+
+-- ----------------------------
+-- Table structure for p_ledger_account
+-- ----------------------------
+DROP TABLE IF EXISTS `p_ledger_account`;
+CREATE TABLE `p_ledger_account` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '账户ID',
+	`code` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '账户编码 user:<用户ID>、system:<用途>、channel:<支付渠道>',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID，系统账户为0',
+	`balance` BIGINT NOT NULL DEFAULT '0' COMMENT '账户余额，为所有分录金额之和',
+	`version` BIGINT NOT NULL DEFAULT '0' COMMENT '乐观锁版本号',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_ledger_account_code` (`code`) USING BTREE,
+	KEY `idx_ledger_account_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本账户';
+
+-- ----------------------------
+-- Table structure for p_ledger_journal
+-- ----------------------------
+DROP TABLE IF EXISTS `p_ledger_journal`;
+CREATE TABLE `p_ledger_journal` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '凭证ID',
+	`idempotency_key` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '幂等键，相同的业务只记账一次',
+	`kind` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '业务类型',
+	`memo` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '备注',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_ledger_journal_idempotency_key` (`idempotency_key`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本凭证';
+
+-- ----------------------------
+-- Table structure for p_ledger_entry
+-- ----------------------------
+DROP TABLE IF EXISTS `p_ledger_entry`;
+CREATE TABLE `p_ledger_entry` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '分录ID',
+	`journal_id` BIGINT NOT NULL DEFAULT '0' COMMENT '凭证ID',
+	`account_id` BIGINT NOT NULL DEFAULT '0' COMMENT '账户ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '变动金额，同一凭证的分录金额之和为0',
+	`balance_snapshot` BIGINT NOT NULL DEFAULT '0' COMMENT '变动后的账户余额',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_ledger_entry_journal_id` (`journal_id`) USING BTREE,
+	KEY `idx_ledger_entry_account_id` (`account_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='钱包账本分录';
+
+-- ----------------------------
+-- Table structure for p_subscription_tier
+-- ----------------------------
+DROP TABLE IF EXISTS `p_subscription_tier`;
+CREATE TABLE `p_subscription_tier` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '档位ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '创作者ID',
+	`title` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '档位名称',
+	`description` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '档位说明',
+	`price` BIGINT NOT NULL DEFAULT '0' COMMENT '每期价格(分)',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_subscription_tier_user_id` (`user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='创作者订阅档位';
+
+-- ----------------------------
+-- Table structure for p_subscription
+-- ----------------------------
+DROP TABLE IF EXISTS `p_subscription`;
+CREATE TABLE `p_subscription` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '订阅ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '订阅者ID',
+	`creator_id` BIGINT NOT NULL DEFAULT '0' COMMENT '创作者ID',
+	`tier_id` BIGINT NOT NULL DEFAULT '0' COMMENT '订阅档位ID',
+	`price` BIGINT NOT NULL DEFAULT '0' COMMENT '续费价格(分)',
+	`auto_renew` tinyint NOT NULL DEFAULT '0' COMMENT '是否自动续费 0否、1是',
+	`expired_on` BIGINT NOT NULL DEFAULT '0' COMMENT '到期时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_subscription_user_creator` (`user_id`, `creator_id`) USING BTREE,
+	KEY `idx_subscription_creator_id` (`creator_id`) USING BTREE,
+	KEY `idx_subscription_expired_on` (`expired_on`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='用户订阅';
+
+-- ----------------------------
+-- Table structure for p_post_tip
+-- ----------------------------
+DROP TABLE IF EXISTS `p_post_tip`;
+CREATE TABLE `p_post_tip` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '充电ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '充电用户ID',
+	`author_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文作者ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '充电金额(分)',
+	`message` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '充电留言',
+	`request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端请求ID，用于幂等',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_post_tip_post_id` (`post_id`) USING BTREE,
+	KEY `idx_post_tip_user_post` (`user_id`, `post_id`) USING BTREE,
+	KEY `idx_post_tip_user_request` (`user_id`, `request_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='推文充电';
+
+-- ----------------------------
+-- Table structure for p_attachment_refund
+-- ----------------------------
+DROP TABLE IF EXISTS `p_attachment_refund`;
+CREATE TABLE `p_attachment_refund` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '退款申请ID',
+	`bill_id` BIGINT NOT NULL DEFAULT '0' COMMENT '附件购买记录ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '购买者ID',
+	`author_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文作者ID',
+	`amount` BIGINT NOT NULL DEFAULT '0' COMMENT '退款金额(分)',
+	`reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '退款原因',
+	`status` tinyint NOT NULL DEFAULT '0' COMMENT '状态 0待作者处理、1已退款、2已拒绝、3申诉中',
+	`reviewer_id` BIGINT NOT NULL DEFAULT '0' COMMENT '处理人ID',
+	`remark` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '' COMMENT '处理备注',
+	`reviewed_on` BIGINT NOT NULL DEFAULT '0' COMMENT '处理时间',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_attachment_refund_bill_id` (`bill_id`) USING BTREE,
+	KEY `idx_attachment_refund_user_id` (`user_id`) USING BTREE,
+	KEY `idx_attachment_refund_author_status` (`author_id`, `status`) USING BTREE,
+	KEY `idx_attachment_refund_status` (`status`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='附件退款申请';
+
+-- ----------------------------
+-- Table structure for p_tag_usage
+-- ----------------------------
+DROP TABLE IF EXISTS `p_tag_usage`;
+CREATE TABLE `p_tag_usage` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '统计ID',
+	`tag_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`bucket` BIGINT NOT NULL DEFAULT '0' COMMENT '统计桶的起始时间，按小时分桶',
+	`quote_num` BIGINT NOT NULL DEFAULT '0' COMMENT '引用次数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_tag_usage_tag_bucket` (`tag_id`, `bucket`) USING BTREE,
+	KEY `idx_tag_usage_bucket` (`bucket`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题引用统计';
+
+-- ----------------------------
+-- Table structure for p_tag_trending
+-- ----------------------------
+DROP TABLE IF EXISTS `p_tag_trending`;
+CREATE TABLE `p_tag_trending` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '排行ID',
+	`tag_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`score` DOUBLE NOT NULL DEFAULT '0' COMMENT '热议分',
+	`hour_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近1小时引用次数',
+	`day_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近24小时引用次数',
+	`week_num` BIGINT NOT NULL DEFAULT '0' COMMENT '最近7天引用次数',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_tag_trending_score` (`score`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='热议话题排行';
+
+-- ----------------------------
+-- Table structure for p_topic_moderator
+-- ----------------------------
+DROP TABLE IF EXISTS `p_topic_moderator`;
+CREATE TABLE `p_topic_moderator` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主持人ID',
+	`topic_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_topic_moderator_topic_user` (`topic_id`, `user_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题主持人';
+
+-- ----------------------------
+-- Table structure for p_topic_pin
+-- ----------------------------
+DROP TABLE IF EXISTS `p_topic_pin`;
+CREATE TABLE `p_topic_pin` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '置顶ID',
+	`topic_id` BIGINT NOT NULL DEFAULT '0' COMMENT '话题ID',
+	`post_id` BIGINT NOT NULL DEFAULT '0' COMMENT '推文ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '操作者ID',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	UNIQUE KEY `idx_topic_pin_topic_post` (`topic_id`, `post_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='话题置顶推文';
+
+-- ----------------------------
+-- Table structure for p_collection_folder
+-- ----------------------------
+DROP TABLE IF EXISTS `p_collection_folder`;
+CREATE TABLE `p_collection_folder` (
+	`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '收藏夹ID',
+	`user_id` BIGINT NOT NULL DEFAULT '0' COMMENT '用户ID',
+	`name` varchar(64) NOT NULL DEFAULT '' COMMENT '收藏夹名称',
+	`share_key` varchar(64) NOT NULL DEFAULT '' COMMENT '分享链接标识，为空表示未公开分享',
+	`created_on` BIGINT NOT NULL DEFAULT '0' COMMENT '创建时间',
+	`modified_on` BIGINT NOT NULL DEFAULT '0' COMMENT '修改时间',
+	`deleted_on` BIGINT NOT NULL DEFAULT '0' COMMENT '删除时间',
+	`is_del` tinyint NOT NULL DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+	PRIMARY KEY (`id`) USING BTREE,
+	KEY `idx_collection_folder_user_id` (`user_id`) USING BTREE,
+	KEY `idx_collection_folder_share_key` (`share_key`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='收藏夹';
+
 DROP VIEW IF EXISTS p_post_by_media;
 CREATE VIEW p_post_by_media AS 
 SELECT post.* 
@@ -13,12 +13,21 @@ CREATE TABLE p_attachment (
 	img_height BIGINT NOT NULL DEFAULT 0,
 	"type" SMALLINT  NOT NULL DEFAULT 1, -- 1图片、2视频、3其他附件
 	content VARCHAR(255) NOT NULL DEFAULT '',
+	blurhash VARCHAR(64) NOT NULL DEFAULT '', -- 图片blurhash占位
+	dominant_color VARCHAR(16) NOT NULL DEFAULT '', -- 图片主色调
+	variants TEXT NOT NULL DEFAULT '', -- 图片缩略图/WebP等版本(JSON)
+	duration BIGINT NOT NULL DEFAULT 0, -- 视频时长(毫秒)
+	poster VARCHAR(255) NOT NULL DEFAULT '', -- 视频封面
+	playlist VARCHAR(255) NOT NULL DEFAULT '', -- 视频HLS主播放列表
+	status SMALLINT NOT NULL DEFAULT 0, -- 媒体处理状态 0就绪、1等待处理、2处理中、3处理失败
 	created_on BIGINT NOT NULL DEFAULT 0,
 	modified_on BIGINT NOT NULL DEFAULT 0,
 	deleted_on BIGINT NOT NULL DEFAULT 0,
 	is_del SMALLINT NOT NULL DEFAULT 0  -- 是否删除 0为未删除、1为已删除
 );
 CREATE INDEX idx_attachment_user_id ON p_attachment USING btree (id);
+CREATE INDEX idx_attachment_content ON p_attachment USING btree (content);
+CREATE INDEX idx_attachment_status ON p_attachment USING btree (status);
 
 DROP TABLE IF EXISTS p_captcha;
 CREATE TABLE p_captcha (
@@ -161,6 +170,7 @@ CREATE TABLE p_post (
 	attachment_price BIGINT NOT NULL DEFAULT 0, -- 附件价格(分)
 	ip VARCHAR(64) NOT NULL DEFAULT '', -- IP地址
 	ip_loc VARCHAR(64) NOT NULL DEFAULT '', -- IP城市地址
+	edited_on BIGINT NOT NULL DEFAULT 0, -- 最后编辑时间
 	created_on BIGINT NOT NULL DEFAULT 0,
 	modified_on BIGINT NOT NULL DEFAULT 0,
 	deleted_on BIGINT NOT NULL DEFAULT 0,
@@ -204,6 +214,8 @@ CREATE TABLE p_post_collection (
 	id BIGSERIAL PRIMARY KEY,
 	post_id BIGINT NOT NULL DEFAULT 0,
 	user_id BIGINT NOT NULL DEFAULT 0,
+	folder_id BIGINT NOT NULL DEFAULT 0, -- 收藏夹ID，0为未归入收藏夹
+	note VARCHAR(255) NOT NULL DEFAULT '', -- 私人备注
 	created_on BIGINT NOT NULL DEFAULT 0,
 	modified_on BIGINT NOT NULL DEFAULT 0,
 	deleted_on BIGINT NOT NULL DEFAULT 0,
@@ -211,6 +223,7 @@ CREATE TABLE p_post_collection (
 );
 CREATE INDEX idx_post_collection_post_id ON p_post_collection USING btree (post_id);
 CREATE INDEX idx_post_collection_user_id ON p_post_collection USING btree (user_id);
+CREATE INDEX idx_post_collection_user_folder ON p_post_collection USING btree (user_id, folder_id);
 
 DROP TABLE IF EXISTS p_post_content;
 CREATE TABLE p_post_content (
@@ -218,7 +231,7 @@ CREATE TABLE p_post_content (
 	post_id BIGINT NOT NULL DEFAULT 0,
 	user_id BIGINT NOT NULL DEFAULT 0,
 	content TEXT NOT NULL DEFAULT '',
-	"type" SMALLINT NOT NULL DEFAULT 2, -- 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源
+	"type" SMALLINT NOT NULL DEFAULT 2, -- 类型，1标题，2文字段落，3图片地址，4视频地址，5语音地址，6链接地址，7附件资源，8收费资源，9投票
 	sort SMALLINT NOT NULL DEFAULT 100,
 	created_on BIGINT NOT NULL DEFAULT 0,
 	modified_on BIGINT NOT NULL DEFAULT 0,
@@ -247,6 +260,11 @@ CREATE TABLE p_tag (
 	user_id BIGINT NOT NULL DEFAULT 0,
 	tag VARCHAR(255) NOT NULL,
 	quote_num BIGINT NOT NULL DEFAULT 0, -- 引用数
+	description VARCHAR(255) NOT NULL DEFAULT '', -- 话题描述
+	cover VARCHAR(255) NOT NULL DEFAULT '', -- 话题封面
+	alias_of BIGINT NOT NULL DEFAULT 0, -- 合并到的话题ID，0为非别名
+	is_locked SMALLINT NOT NULL DEFAULT 0, -- 是否锁定
+	is_hidden SMALLINT NOT NULL DEFAULT 0, -- 是否在话题推荐中隐藏
 	created_on BIGINT NOT NULL DEFAULT 0,
 	modified_on BIGINT NOT NULL DEFAULT 0,
 	deleted_on BIGINT NOT NULL DEFAULT 0,
@@ -255,6 +273,7 @@ CREATE TABLE p_tag (
 CREATE UNIQUE INDEX idx_tag_tag ON p_tag USING btree (tag);
 CREATE INDEX idx_tag_user_id ON p_tag USING btree (user_id);
 CREATE INDEX idx_tag_quote_num ON p_tag USING btree (quote_num);
+CREATE INDEX idx_tag_alias_of ON p_tag USING btree (alias_of);
 
 DROP TABLE IF EXISTS p_topic_user;
 CREATE TABLE p_topic_user (
@@ -357,6 +376,7 @@ CREATE TABLE p_wallet_recharge (
 	id BIGSERIAL PRIMARY KEY,
 	user_id BIGINT NOT NULL DEFAULT 0,
 	amount BIGINT NOT NULL DEFAULT 0, -- 充值金额
+	provider VARCHAR(32) NOT NULL DEFAULT 'alipay', -- 支付渠道 alipay、wechatpay
 	trade_no VARCHAR(64) NOT NULL DEFAULT '', -- 支付宝订单号
 	trade_status VARCHAR(32) NOT NULL DEFAULT '', -- 交易状态
 	created_on BIGINT NOT NULL DEFAULT 0,
@@ -383,6 +403,376 @@ CREATE TABLE p_wallet_statement (
 );
 CREATE INDEX idx_wallet_statement_user_id ON p_wallet_statement USING btree (user_id);
 
+DROP TABLE IF EXISTS p_post_schedule;
+CREATE TABLE p_post_schedule (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0,
+	contents TEXT NOT NULL DEFAULT '', -- 推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '',
+	users VARCHAR(2000) NOT NULL DEFAULT '', -- @用户
+	attachment_price BIGINT NOT NULL DEFAULT 0, -- 附件价格(分)
+	visibility SMALLINT NOT NULL DEFAULT 0, -- 可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开
+	ip VARCHAR(64) NOT NULL DEFAULT '', -- IP地址
+	ip_loc VARCHAR(64) NOT NULL DEFAULT '', -- IP城市地址
+	publish_at BIGINT NOT NULL DEFAULT 0, -- 计划发布时间
+	status SMALLINT NOT NULL DEFAULT 0, -- 状态: 0待发布 1已发布 2已取消 3发布失败 4发布中
+	post_id BIGINT NOT NULL DEFAULT 0, -- 发布后的POST ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_schedule_user_id ON p_post_schedule USING btree (user_id);
+CREATE INDEX idx_post_schedule_status_publish_at ON p_post_schedule USING btree (status, publish_at);
+
+DROP TABLE IF EXISTS p_post_draft;
+CREATE TABLE p_post_draft (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	contents TEXT NOT NULL DEFAULT '', -- 推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '', -- 标签
+	users VARCHAR(2000) NOT NULL DEFAULT '', -- @用户
+	attachment_price BIGINT NOT NULL DEFAULT 0, -- 附件价格(分)
+	visibility SMALLINT NOT NULL DEFAULT 0, -- 可见性: 0私密 10充电可见 20订阅可见 30保留 40保留 50好友可见 60关注可见 70保留 80保留 90公开
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_draft_user_id ON p_post_draft USING btree (user_id);
+
+DROP TABLE IF EXISTS p_post_revision;
+CREATE TABLE p_post_revision (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- POST ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	revision BIGINT NOT NULL DEFAULT 0, -- 修订版本号
+	contents TEXT NOT NULL DEFAULT '', -- 修订前的推文内容(JSON)
+	tags VARCHAR(255) NOT NULL DEFAULT '', -- 修订前的标签
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_revision_post_id ON p_post_revision USING btree (post_id);
+
+DROP TABLE IF EXISTS p_post_poll;
+CREATE TABLE p_post_poll (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- POST ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 发起投票的用户ID
+	multiple SMALLINT NOT NULL DEFAULT 0, -- 是否多选 0 为单选、1 为多选
+	anonymous SMALLINT NOT NULL DEFAULT 0, -- 是否匿名 0 为公开、1 为匿名
+	vote_count BIGINT NOT NULL DEFAULT 0, -- 投票人数
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 截止时间
+	closed_on BIGINT NOT NULL DEFAULT 0, -- 结束时间 0 为进行中
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_post_poll_post_id ON p_post_poll USING btree (post_id);
+CREATE INDEX idx_post_poll_expired_on ON p_post_poll USING btree (closed_on, expired_on);
+
+DROP TABLE IF EXISTS p_post_poll_option;
+CREATE TABLE p_post_poll_option (
+	id BIGSERIAL PRIMARY KEY,
+	poll_id BIGINT NOT NULL DEFAULT 0, -- 投票ID
+	content VARCHAR(255) NOT NULL DEFAULT '', -- 选项内容
+	sort BIGINT NOT NULL DEFAULT 0, -- 排序，越小越靠前
+	vote_count BIGINT NOT NULL DEFAULT 0, -- 得票数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_poll_option_poll_id ON p_post_poll_option USING btree (poll_id);
+
+DROP TABLE IF EXISTS p_post_poll_vote;
+CREATE TABLE p_post_poll_vote (
+	id BIGSERIAL PRIMARY KEY,
+	poll_id BIGINT NOT NULL DEFAULT 0, -- 投票ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 投票用户ID
+	option_ids VARCHAR(255) NOT NULL DEFAULT '', -- 选中的选项ID列表
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_post_poll_vote_poll_user ON p_post_poll_vote USING btree (poll_id, user_id);
+
+DROP TABLE IF EXISTS p_link_preview;
+CREATE TABLE p_link_preview (
+	id BIGSERIAL PRIMARY KEY,
+	url_hash VARCHAR(64) NOT NULL DEFAULT '', -- 链接地址的SHA256
+	url VARCHAR(2048) NOT NULL DEFAULT '', -- 链接地址
+	title VARCHAR(255) NOT NULL DEFAULT '', -- 标题
+	description VARCHAR(512) NOT NULL DEFAULT '', -- 描述
+	image VARCHAR(255) NOT NULL DEFAULT '', -- 预览图(已转存OSS)
+	site_name VARCHAR(128) NOT NULL DEFAULT '', -- 站点名称
+	status SMALLINT NOT NULL DEFAULT 0, -- 抓取状态 0 为成功、1 为失败
+	fetched_on BIGINT NOT NULL DEFAULT 0, -- 抓取时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_link_preview_url_hash ON p_link_preview USING btree (url_hash);
+
+DROP TABLE IF EXISTS p_upload_session;
+CREATE TABLE p_upload_session (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	upload_type VARCHAR(32) NOT NULL DEFAULT '', -- 上传类型
+	content_type VARCHAR(128) NOT NULL DEFAULT '', -- 文件MIME类型
+	file_ext VARCHAR(16) NOT NULL DEFAULT '', -- 文件扩展名
+	file_size BIGINT NOT NULL DEFAULT 0, -- 文件总大小
+	received_size BIGINT NOT NULL DEFAULT 0, -- 已接收大小
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 过期时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_upload_session_user_id ON p_upload_session USING btree (user_id);
+CREATE INDEX idx_upload_session_expired_on ON p_upload_session USING btree (expired_on);
+
+DROP TABLE IF EXISTS p_user_storage_quota;
+CREATE TABLE p_user_storage_quota (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	max_size BIGINT NOT NULL DEFAULT 0, -- 最大存储空间(字节) 0 为不限制
+	max_files BIGINT NOT NULL DEFAULT 0, -- 最大文件数 0 为不限制
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_user_storage_quota_user_id ON p_user_storage_quota USING btree (user_id);
+
+DROP TABLE IF EXISTS p_object_blob;
+CREATE TABLE p_object_blob (
+	id BIGSERIAL PRIMARY KEY,
+	sha256 VARCHAR(64) NOT NULL DEFAULT '', -- 内容的SHA256
+	upload_type VARCHAR(32) NOT NULL DEFAULT '', -- 上传类型
+	content VARCHAR(255) NOT NULL DEFAULT '', -- 对象地址
+	file_size BIGINT NOT NULL DEFAULT 0, -- 文件大小
+	ref_count BIGINT NOT NULL DEFAULT 0, -- 引用计数(上传次数)
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_object_blob_sha256 ON p_object_blob USING btree (sha256, upload_type);
+CREATE INDEX idx_object_blob_content ON p_object_blob USING btree (content);
+
+DROP TABLE IF EXISTS p_wallet_withdrawal;
+CREATE TABLE p_wallet_withdrawal (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 提现金额(含手续费)
+	fee BIGINT NOT NULL DEFAULT 0, -- 手续费
+	account_type VARCHAR(32) NOT NULL DEFAULT '', -- 收款渠道 alipay、wechatpay、bank
+	account VARCHAR(128) NOT NULL DEFAULT '', -- 收款账号
+	account_name VARCHAR(64) NOT NULL DEFAULT '', -- 收款人姓名
+	status SMALLINT NOT NULL DEFAULT 0, -- 提现状态 0待审核、1待打款、2已打款、3已拒绝、4打款失败、5已取消
+	reviewer_id BIGINT NOT NULL DEFAULT 0, -- 审核人ID
+	trade_no VARCHAR(64) NOT NULL DEFAULT '', -- 打款流水号
+	remark VARCHAR(255) NOT NULL DEFAULT '', -- 审核备注
+	reviewed_on BIGINT NOT NULL DEFAULT 0, -- 审核时间
+	paid_on BIGINT NOT NULL DEFAULT 0, -- 打款时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_wallet_withdrawal_user_id ON p_wallet_withdrawal USING btree (user_id);
+CREATE INDEX idx_wallet_withdrawal_status ON p_wallet_withdrawal USING btree (status);
+
+DROP TABLE IF EXISTS p_ledger_account;
+CREATE TABLE p_ledger_account (
+	id BIGSERIAL PRIMARY KEY,
+	code VARCHAR(64) NOT NULL DEFAULT '', -- 账户编码 user:<用户ID>、system:<用途>、channel:<支付渠道>
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID，系统账户为0
+	balance BIGINT NOT NULL DEFAULT 0, -- 账户余额，为所有分录金额之和
+	version BIGINT NOT NULL DEFAULT 0, -- 乐观锁版本号
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_ledger_account_code ON p_ledger_account USING btree (code);
+CREATE INDEX idx_ledger_account_user_id ON p_ledger_account USING btree (user_id);
+
+DROP TABLE IF EXISTS p_ledger_journal;
+CREATE TABLE p_ledger_journal (
+	id BIGSERIAL PRIMARY KEY,
+	idempotency_key VARCHAR(128) NOT NULL DEFAULT '', -- 幂等键，相同的业务只记账一次
+	kind VARCHAR(32) NOT NULL DEFAULT '', -- 业务类型
+	memo VARCHAR(255) NOT NULL DEFAULT '', -- 备注
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_ledger_journal_idempotency_key ON p_ledger_journal USING btree (idempotency_key);
+
+DROP TABLE IF EXISTS p_ledger_entry;
+CREATE TABLE p_ledger_entry (
+	id BIGSERIAL PRIMARY KEY,
+	journal_id BIGINT NOT NULL DEFAULT 0, -- 凭证ID
+	account_id BIGINT NOT NULL DEFAULT 0, -- 账户ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 变动金额，同一凭证的分录金额之和为0
+	balance_snapshot BIGINT NOT NULL DEFAULT 0, -- 变动后的账户余额
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_ledger_entry_journal_id ON p_ledger_entry USING btree (journal_id);
+CREATE INDEX idx_ledger_entry_account_id ON p_ledger_entry USING btree (account_id);
+
+DROP TABLE IF EXISTS p_subscription_tier;
+CREATE TABLE p_subscription_tier (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 创作者ID
+	title VARCHAR(64) NOT NULL DEFAULT '', -- 档位名称
+	description VARCHAR(255) NOT NULL DEFAULT '', -- 档位说明
+	price BIGINT NOT NULL DEFAULT 0, -- 每期价格(分)
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_subscription_tier_user_id ON p_subscription_tier USING btree (user_id);
+
+DROP TABLE IF EXISTS p_subscription;
+CREATE TABLE p_subscription (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 订阅者ID
+	creator_id BIGINT NOT NULL DEFAULT 0, -- 创作者ID
+	tier_id BIGINT NOT NULL DEFAULT 0, -- 订阅档位ID
+	price BIGINT NOT NULL DEFAULT 0, -- 续费价格(分)
+	auto_renew SMALLINT NOT NULL DEFAULT 0, -- 是否自动续费 0否、1是
+	expired_on BIGINT NOT NULL DEFAULT 0, -- 到期时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_subscription_user_creator ON p_subscription USING btree (user_id, creator_id);
+CREATE INDEX idx_subscription_creator_id ON p_subscription USING btree (creator_id);
+CREATE INDEX idx_subscription_expired_on ON p_subscription USING btree (expired_on);
+
+DROP TABLE IF EXISTS p_post_tip;
+CREATE TABLE p_post_tip (
+	id BIGSERIAL PRIMARY KEY,
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 充电用户ID
+	author_id BIGINT NOT NULL DEFAULT 0, -- 推文作者ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 充电金额(分)
+	message VARCHAR(255) NOT NULL DEFAULT '', -- 充电留言
+	request_id VARCHAR(64) NOT NULL DEFAULT '', -- 客户端请求ID，用于幂等
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_post_tip_post_id ON p_post_tip USING btree (post_id);
+CREATE INDEX idx_post_tip_user_post ON p_post_tip USING btree (user_id, post_id);
+CREATE INDEX idx_post_tip_user_request ON p_post_tip USING btree (user_id, request_id);
+
+DROP TABLE IF EXISTS p_attachment_refund;
+CREATE TABLE p_attachment_refund (
+	id BIGSERIAL PRIMARY KEY,
+	bill_id BIGINT NOT NULL DEFAULT 0, -- 附件购买记录ID
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 购买者ID
+	author_id BIGINT NOT NULL DEFAULT 0, -- 推文作者ID
+	amount BIGINT NOT NULL DEFAULT 0, -- 退款金额(分)
+	reason VARCHAR(255) NOT NULL DEFAULT '', -- 退款原因
+	status SMALLINT NOT NULL DEFAULT 0, -- 状态 0待作者处理、1已退款、2已拒绝、3申诉中
+	reviewer_id BIGINT NOT NULL DEFAULT 0, -- 处理人ID
+	remark VARCHAR(255) NOT NULL DEFAULT '', -- 处理备注
+	reviewed_on BIGINT NOT NULL DEFAULT 0, -- 处理时间
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_attachment_refund_bill_id ON p_attachment_refund USING btree (bill_id);
+CREATE INDEX idx_attachment_refund_user_id ON p_attachment_refund USING btree (user_id);
+CREATE INDEX idx_attachment_refund_author_status ON p_attachment_refund USING btree (author_id, status);
+CREATE INDEX idx_attachment_refund_status ON p_attachment_refund USING btree (status);
+
+DROP TABLE IF EXISTS p_tag_usage;
+CREATE TABLE p_tag_usage (
+	id BIGSERIAL PRIMARY KEY,
+	tag_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	bucket BIGINT NOT NULL DEFAULT 0, -- 统计桶的起始时间，按小时分桶
+	quote_num BIGINT NOT NULL DEFAULT 0, -- 引用次数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_tag_usage_tag_bucket ON p_tag_usage USING btree (tag_id, bucket);
+CREATE INDEX idx_tag_usage_bucket ON p_tag_usage USING btree (bucket);
+
+DROP TABLE IF EXISTS p_tag_trending;
+CREATE TABLE p_tag_trending (
+	id BIGSERIAL PRIMARY KEY,
+	tag_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	score DOUBLE PRECISION NOT NULL DEFAULT 0, -- 热议分
+	hour_num BIGINT NOT NULL DEFAULT 0, -- 最近1小时引用次数
+	day_num BIGINT NOT NULL DEFAULT 0, -- 最近24小时引用次数
+	week_num BIGINT NOT NULL DEFAULT 0, -- 最近7天引用次数
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_tag_trending_score ON p_tag_trending USING btree (score);
+
+DROP TABLE IF EXISTS p_topic_moderator;
+CREATE TABLE p_topic_moderator (
+	id BIGSERIAL PRIMARY KEY,
+	topic_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_topic_moderator_topic_user ON p_topic_moderator USING btree (topic_id, user_id);
+
+DROP TABLE IF EXISTS p_topic_pin;
+CREATE TABLE p_topic_pin (
+	id BIGSERIAL PRIMARY KEY,
+	topic_id BIGINT NOT NULL DEFAULT 0, -- 话题ID
+	post_id BIGINT NOT NULL DEFAULT 0, -- 推文ID
+	user_id BIGINT NOT NULL DEFAULT 0, -- 操作者ID
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_topic_pin_topic_post ON p_topic_pin USING btree (topic_id, post_id);
+
+DROP TABLE IF EXISTS p_collection_folder;
+CREATE TABLE p_collection_folder (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL DEFAULT 0, -- 用户ID
+	name VARCHAR(64) NOT NULL DEFAULT '', -- 收藏夹名称
+	share_key VARCHAR(64) NOT NULL DEFAULT '', -- 分享链接标识，为空表示未公开分享
+	created_on BIGINT NOT NULL DEFAULT 0,
+	modified_on BIGINT NOT NULL DEFAULT 0,
+	deleted_on BIGINT NOT NULL DEFAULT 0,
+	is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_collection_folder_user_id ON p_collection_folder USING btree (user_id);
+CREATE INDEX idx_collection_folder_share_key ON p_collection_folder USING btree (share_key);
+
 DROP VIEW IF EXISTS p_post_by_media;
 CREATE VIEW p_post_by_media AS 
 SELECT post.* 
@@ -12,6 +12,13 @@ CREATE TABLE "p_attachment" (
   "img_height" integer NOT NULL,
   "type" integer NOT NULL,
   "content" text(255) NOT NULL,
+  "blurhash" text(64) NOT NULL DEFAULT '',
+  "dominant_color" text(16) NOT NULL DEFAULT '',
+  "variants" text NOT NULL DEFAULT '',
+  "duration" integer NOT NULL DEFAULT 0,
+  "poster" text(255) NOT NULL DEFAULT '',
+  "playlist" text(255) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
   "created_on" integer NOT NULL,
   "modified_on" integer NOT NULL,
   "deleted_on" integer NOT NULL,
@@ -224,6 +231,7 @@ CREATE TABLE "p_post" (
   "attachment_price" integer NOT NULL,
   "ip" text(64) NOT NULL,
   "ip_loc" text(64) NOT NULL,
+  "edited_on" integer NOT NULL DEFAULT 0,
   "created_on" integer NOT NULL,
   "modified_on" integer NOT NULL,
   "deleted_on" integer NOT NULL,
@@ -274,6 +282,8 @@ CREATE TABLE "p_post_collection" (
   "id" integer NOT NULL,
   "post_id" integer NOT NULL,
   "user_id" integer NOT NULL,
+  "folder_id" integer NOT NULL DEFAULT 0,
+  "note" text(255) NOT NULL DEFAULT '',
   "created_on" integer NOT NULL,
   "modified_on" integer NOT NULL,
   "deleted_on" integer NOT NULL,
@@ -323,6 +333,11 @@ CREATE TABLE "p_tag" (
   "user_id" integer NOT NULL,
   "tag" text(255) NOT NULL,
   "quote_num" integer NOT NULL,
+  "description" text(255) NOT NULL DEFAULT '',
+  "cover" text(255) NOT NULL DEFAULT '',
+  "alias_of" integer NOT NULL DEFAULT 0,
+  "is_locked" integer NOT NULL DEFAULT 0,
+  "is_hidden" integer NOT NULL DEFAULT 0,
   "created_on" integer NOT NULL,
   "modified_on" integer NOT NULL,
   "deleted_on" integer NOT NULL,
@@ -397,6 +412,7 @@ CREATE TABLE "p_wallet_recharge" (
   "id" integer NOT NULL,
   "user_id" integer NOT NULL,
   "amount" integer NOT NULL,
+  "provider" text(32) NOT NULL DEFAULT 'alipay',
   "trade_no" text(64) NOT NULL,
   "trade_status" text(32) NOT NULL,
   "created_on" integer NOT NULL,
@@ -424,6 +440,429 @@ CREATE TABLE "p_wallet_statement" (
   PRIMARY KEY ("id")
 );
 
+-- ----------------------------
+-- Table structure for p_post_schedule
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_schedule";
+CREATE TABLE "p_post_schedule" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "users" text(2000) NOT NULL DEFAULT '',
+  "attachment_price" integer NOT NULL DEFAULT 0,
+  "visibility" integer NOT NULL DEFAULT 0,
+  "ip" text(64) NOT NULL DEFAULT '',
+  "ip_loc" text(64) NOT NULL DEFAULT '',
+  "publish_at" integer NOT NULL DEFAULT 0,
+  "status" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_draft
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_draft";
+CREATE TABLE "p_post_draft" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "users" text(2000) NOT NULL DEFAULT '',
+  "attachment_price" integer NOT NULL DEFAULT 0,
+  "visibility" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_revision
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_revision";
+CREATE TABLE "p_post_revision" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "revision" integer NOT NULL DEFAULT 0,
+  "contents" text NOT NULL DEFAULT '',
+  "tags" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_poll
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_poll";
+CREATE TABLE "p_post_poll" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "multiple" integer NOT NULL DEFAULT 0,
+  "anonymous" integer NOT NULL DEFAULT 0,
+  "vote_count" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "closed_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_poll_option
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_poll_option";
+CREATE TABLE "p_post_poll_option" (
+  "id" integer NOT NULL,
+  "poll_id" integer NOT NULL DEFAULT 0,
+  "content" text(255) NOT NULL DEFAULT '',
+  "sort" integer NOT NULL DEFAULT 0,
+  "vote_count" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_poll_vote
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_poll_vote";
+CREATE TABLE "p_post_poll_vote" (
+  "id" integer NOT NULL,
+  "poll_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "option_ids" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_link_preview
+-- ----------------------------
+DROP TABLE IF EXISTS "p_link_preview";
+CREATE TABLE "p_link_preview" (
+  "id" integer NOT NULL,
+  "url_hash" text(64) NOT NULL DEFAULT '',
+  "url" text(2048) NOT NULL DEFAULT '',
+  "title" text(255) NOT NULL DEFAULT '',
+  "description" text(512) NOT NULL DEFAULT '',
+  "image" text(255) NOT NULL DEFAULT '',
+  "site_name" text(128) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
+  "fetched_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_upload_session
+-- ----------------------------
+DROP TABLE IF EXISTS "p_upload_session";
+CREATE TABLE "p_upload_session" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "upload_type" text(32) NOT NULL DEFAULT '',
+  "content_type" text(128) NOT NULL DEFAULT '',
+  "file_ext" text(16) NOT NULL DEFAULT '',
+  "file_size" integer NOT NULL DEFAULT 0,
+  "received_size" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_user_storage_quota
+-- ----------------------------
+DROP TABLE IF EXISTS "p_user_storage_quota";
+CREATE TABLE "p_user_storage_quota" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "max_size" integer NOT NULL DEFAULT 0,
+  "max_files" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_object_blob
+-- ----------------------------
+DROP TABLE IF EXISTS "p_object_blob";
+CREATE TABLE "p_object_blob" (
+  "id" integer NOT NULL,
+  "sha256" text(64) NOT NULL DEFAULT '',
+  "upload_type" text(32) NOT NULL DEFAULT '',
+  "content" text(255) NOT NULL DEFAULT '',
+  "file_size" integer NOT NULL DEFAULT 0,
+  "ref_count" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_wallet_withdrawal
+-- ----------------------------
+DROP TABLE IF EXISTS "p_wallet_withdrawal";
+CREATE TABLE "p_wallet_withdrawal" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "fee" integer NOT NULL DEFAULT 0,
+  "account_type" text(32) NOT NULL DEFAULT '',
+  "account" text(128) NOT NULL DEFAULT '',
+  "account_name" text(64) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
+  "reviewer_id" integer NOT NULL DEFAULT 0,
+  "trade_no" text(64) NOT NULL DEFAULT '',
+  "remark" text(255) NOT NULL DEFAULT '',
+  "reviewed_on" integer NOT NULL DEFAULT 0,
+  "paid_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_ledger_account
+-- ----------------------------
+DROP TABLE IF EXISTS "p_ledger_account";
+CREATE TABLE "p_ledger_account" (
+  "id" integer NOT NULL,
+  "code" text(64) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL DEFAULT 0,
+  "balance" integer NOT NULL DEFAULT 0,
+  "version" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_ledger_journal
+-- ----------------------------
+DROP TABLE IF EXISTS "p_ledger_journal";
+CREATE TABLE "p_ledger_journal" (
+  "id" integer NOT NULL,
+  "idempotency_key" text(128) NOT NULL DEFAULT '',
+  "kind" text(32) NOT NULL DEFAULT '',
+  "memo" text(255) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_ledger_entry
+-- ----------------------------
+DROP TABLE IF EXISTS "p_ledger_entry";
+CREATE TABLE "p_ledger_entry" (
+  "id" integer NOT NULL,
+  "journal_id" integer NOT NULL DEFAULT 0,
+  "account_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "balance_snapshot" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_subscription_tier
+-- ----------------------------
+DROP TABLE IF EXISTS "p_subscription_tier";
+CREATE TABLE "p_subscription_tier" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "title" text(64) NOT NULL DEFAULT '',
+  "description" text(255) NOT NULL DEFAULT '',
+  "price" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_subscription
+-- ----------------------------
+DROP TABLE IF EXISTS "p_subscription";
+CREATE TABLE "p_subscription" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "creator_id" integer NOT NULL DEFAULT 0,
+  "tier_id" integer NOT NULL DEFAULT 0,
+  "price" integer NOT NULL DEFAULT 0,
+  "auto_renew" integer NOT NULL DEFAULT 0,
+  "expired_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_post_tip
+-- ----------------------------
+DROP TABLE IF EXISTS "p_post_tip";
+CREATE TABLE "p_post_tip" (
+  "id" integer NOT NULL,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "author_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "message" text(255) NOT NULL DEFAULT '',
+  "request_id" text(64) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_attachment_refund
+-- ----------------------------
+DROP TABLE IF EXISTS "p_attachment_refund";
+CREATE TABLE "p_attachment_refund" (
+  "id" integer NOT NULL,
+  "bill_id" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "author_id" integer NOT NULL DEFAULT 0,
+  "amount" integer NOT NULL DEFAULT 0,
+  "reason" text(255) NOT NULL DEFAULT '',
+  "status" integer NOT NULL DEFAULT 0,
+  "reviewer_id" integer NOT NULL DEFAULT 0,
+  "remark" text(255) NOT NULL DEFAULT '',
+  "reviewed_on" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_tag_usage
+-- ----------------------------
+DROP TABLE IF EXISTS "p_tag_usage";
+CREATE TABLE "p_tag_usage" (
+  "id" integer NOT NULL,
+  "tag_id" integer NOT NULL DEFAULT 0,
+  "bucket" integer NOT NULL DEFAULT 0,
+  "quote_num" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_tag_trending
+-- ----------------------------
+DROP TABLE IF EXISTS "p_tag_trending";
+CREATE TABLE "p_tag_trending" (
+  "id" integer NOT NULL,
+  "tag_id" integer NOT NULL DEFAULT 0,
+  "score" real NOT NULL DEFAULT 0,
+  "hour_num" integer NOT NULL DEFAULT 0,
+  "day_num" integer NOT NULL DEFAULT 0,
+  "week_num" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_topic_moderator
+-- ----------------------------
+DROP TABLE IF EXISTS "p_topic_moderator";
+CREATE TABLE "p_topic_moderator" (
+  "id" integer NOT NULL,
+  "topic_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_topic_pin
+-- ----------------------------
+DROP TABLE IF EXISTS "p_topic_pin";
+CREATE TABLE "p_topic_pin" (
+  "id" integer NOT NULL,
+  "topic_id" integer NOT NULL DEFAULT 0,
+  "post_id" integer NOT NULL DEFAULT 0,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
+-- ----------------------------
+-- Table structure for p_collection_folder
+-- ----------------------------
+DROP TABLE IF EXISTS "p_collection_folder";
+CREATE TABLE "p_collection_folder" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "name" text(64) NOT NULL DEFAULT '',
+  "share_key" text(64) NOT NULL DEFAULT '',
+  "created_on" integer NOT NULL DEFAULT 0,
+  "modified_on" integer NOT NULL DEFAULT 0,
+  "deleted_on" integer NOT NULL DEFAULT 0,
+  "is_del" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+
 DROP VIEW IF EXISTS p_post_by_media;
 CREATE VIEW p_post_by_media AS 
 SELECT post.* 
@@ -470,6 +909,14 @@ FROM p_following WHERE is_del=0;
 -- ----------------------------
 -- Indexes structure for table p_attachment
 -- ----------------------------
+CREATE INDEX "idx_attachment_content"
+ON "p_attachment" (
+  "content" ASC
+);
+CREATE INDEX "idx_attachment_status"
+ON "p_attachment" (
+  "status" ASC
+);
 CREATE INDEX "idx_attachment_user_id"
 ON "p_attachment" (
   "user_id" ASC
@@ -633,6 +1080,11 @@ CREATE INDEX "idx_post_collection_user_id"
 ON "p_post_collection" (
   "user_id" ASC
 );
+CREATE INDEX "idx_post_collection_user_folder"
+ON "p_post_collection" (
+  "user_id" ASC,
+  "folder_id" ASC
+);
 
 -- ----------------------------
 -- Indexes structure for table p_post_content
@@ -673,6 +1125,10 @@ CREATE INDEX "idx_tag_user_id"
 ON "p_tag" (
   "user_id" ASC
 );
+CREATE INDEX "idx_tag_alias_of"
+ON "p_tag" (
+  "alias_of" ASC
+);
 
 -- ----------------------------
 -- Indexes structure for table p_topic_user
@@ -734,4 +1190,258 @@ ON "p_wallet_statement" (
   "user_id" ASC
 );
 
+-- ----------------------------
+-- Indexes structure for table p_post_schedule
+-- ----------------------------
+CREATE INDEX "idx_post_schedule_user_id"
+ON "p_post_schedule" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_post_schedule_status_publish_at"
+ON "p_post_schedule" (
+  "status" ASC,
+  "publish_at" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_post_draft
+-- ----------------------------
+CREATE INDEX "idx_post_draft_user_id"
+ON "p_post_draft" (
+  "user_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_post_revision
+-- ----------------------------
+CREATE INDEX "idx_post_revision_post_id"
+ON "p_post_revision" (
+  "post_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_post_poll
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_post_poll_post_id"
+ON "p_post_poll" (
+  "post_id" ASC
+);
+CREATE INDEX "idx_post_poll_expired_on"
+ON "p_post_poll" (
+  "closed_on" ASC,
+  "expired_on" ASC
+);
+-- ----------------------------
+-- Indexes structure for table p_post_poll_option
+-- ----------------------------
+CREATE INDEX "idx_post_poll_option_poll_id"
+ON "p_post_poll_option" (
+  "poll_id" ASC
+);
+-- ----------------------------
+-- Indexes structure for table p_post_poll_vote
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_post_poll_vote_poll_user"
+ON "p_post_poll_vote" (
+  "poll_id" ASC,
+  "user_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_link_preview
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_link_preview_url_hash"
+ON "p_link_preview" (
+  "url_hash" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_upload_session
+-- ----------------------------
+CREATE INDEX "idx_upload_session_user_id"
+ON "p_upload_session" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_upload_session_expired_on"
+ON "p_upload_session" (
+  "expired_on" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_user_storage_quota
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_user_storage_quota_user_id"
+ON "p_user_storage_quota" (
+  "user_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_object_blob
+-- ----------------------------
+CREATE INDEX "idx_object_blob_sha256"
+ON "p_object_blob" (
+  "sha256" ASC,
+  "upload_type" ASC
+);
+CREATE INDEX "idx_object_blob_content"
+ON "p_object_blob" (
+  "content" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_wallet_withdrawal
+-- ----------------------------
+CREATE INDEX "idx_wallet_withdrawal_user_id"
+ON "p_wallet_withdrawal" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_wallet_withdrawal_status"
+ON "p_wallet_withdrawal" (
+  "status" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_ledger_account
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_ledger_account_code"
+ON "p_ledger_account" (
+  "code" ASC
+);
+CREATE INDEX "idx_ledger_account_user_id"
+ON "p_ledger_account" (
+  "user_id" ASC
+);
+-- ----------------------------
+-- Indexes structure for table p_ledger_journal
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_ledger_journal_idempotency_key"
+ON "p_ledger_journal" (
+  "idempotency_key" ASC
+);
+-- ----------------------------
+-- Indexes structure for table p_ledger_entry
+-- ----------------------------
+CREATE INDEX "idx_ledger_entry_journal_id"
+ON "p_ledger_entry" (
+  "journal_id" ASC
+);
+CREATE INDEX "idx_ledger_entry_account_id"
+ON "p_ledger_entry" (
+  "account_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_subscription_tier
+-- ----------------------------
+CREATE INDEX "idx_subscription_tier_user_id"
+ON "p_subscription_tier" (
+  "user_id" ASC
+);
+-- ----------------------------
+-- Indexes structure for table p_subscription
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_subscription_user_creator"
+ON "p_subscription" (
+  "user_id" ASC,
+  "creator_id" ASC
+);
+CREATE INDEX "idx_subscription_creator_id"
+ON "p_subscription" (
+  "creator_id" ASC
+);
+CREATE INDEX "idx_subscription_expired_on"
+ON "p_subscription" (
+  "expired_on" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_post_tip
+-- ----------------------------
+CREATE INDEX "idx_post_tip_post_id"
+ON "p_post_tip" (
+  "post_id" ASC
+);
+CREATE INDEX "idx_post_tip_user_post"
+ON "p_post_tip" (
+  "user_id" ASC,
+  "post_id" ASC
+);
+CREATE INDEX "idx_post_tip_user_request"
+ON "p_post_tip" (
+  "user_id" ASC,
+  "request_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_attachment_refund
+-- ----------------------------
+CREATE INDEX "idx_attachment_refund_bill_id"
+ON "p_attachment_refund" (
+  "bill_id" ASC
+);
+CREATE INDEX "idx_attachment_refund_user_id"
+ON "p_attachment_refund" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_attachment_refund_author_status"
+ON "p_attachment_refund" (
+  "author_id" ASC,
+  "status" ASC
+);
+CREATE INDEX "idx_attachment_refund_status"
+ON "p_attachment_refund" (
+  "status" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_tag_usage
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_tag_usage_tag_bucket"
+ON "p_tag_usage" (
+  "tag_id" ASC,
+  "bucket" ASC
+);
+CREATE INDEX "idx_tag_usage_bucket"
+ON "p_tag_usage" (
+  "bucket" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_tag_trending
+-- ----------------------------
+CREATE INDEX "idx_tag_trending_score"
+ON "p_tag_trending" (
+  "score" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_topic_moderator
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_topic_moderator_topic_user"
+ON "p_topic_moderator" (
+  "topic_id" ASC,
+  "user_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_topic_pin
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_topic_pin_topic_post"
+ON "p_topic_pin" (
+  "topic_id" ASC,
+  "post_id" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table p_collection_folder
+-- ----------------------------
+CREATE INDEX "idx_collection_folder_user_id"
+ON "p_collection_folder" (
+  "user_id" ASC
+);
+CREATE INDEX "idx_collection_folder_share_key"
+ON "p_collection_folder" (
+  "share_key" ASC
+);
+
 PRAGMA foreign_keys = true;